@@ -0,0 +1,36 @@
+package tracerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SprintAddresses returns program counters of frames of err
+// for offline symbolization of binaries stripped of symbols,
+// such as built with -ldflags="-s -w".
+//
+// Every frame with a program counter is written as a line
+// "0x<pc> 0x<entry>", where entry is an entry program counter
+// of the function or zero if it's unknown. Frames with no program counter,
+// such as custom or decoded frames, are skipped. The first column is
+// accepted by addr2line and by "list *0x<pc>" of dlv with the unstripped
+// binary of the same build, e.g.:
+//
+//	cut -d' ' -f1 addresses.txt | addr2line -f -e app.debug
+//
+// Addresses are runtime ones, so load address must be subtracted
+// for position independent executables.
+func SprintAddresses(err error) string {
+	e, ok := AsError(err)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	for _, frame := range e.StackTrace() {
+		if frame.PC == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "0x%x 0x%x\n", frame.PC, frame.Entry)
+	}
+	return b.String()
+}
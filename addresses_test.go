@@ -0,0 +1,48 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintAddresses(t *testing.T) {
+	err := tracerr.New("some error")
+	frames := err.StackTrace()
+	if frames[0].Entry == 0 || frames[0].Entry > frames[0].PC {
+		t.Fatalf("frames[0] = %#v; want entry before pc", frames[0])
+	}
+
+	output := tracerr.SprintAddresses(err)
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != len(frames) {
+		t.Fatalf("tracerr.SprintAddresses(err) = %#v; want %d lines", output, len(frames))
+	}
+	var pc, entry uintptr
+	if _, scanErr := fmt.Sscanf(lines[0], "0x%x 0x%x", &pc, &entry); scanErr != nil {
+		t.Fatalf("fmt.Sscanf(%#v) error = %v", lines[0], scanErr)
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil || fn.Name() != "github.com/kadaan/tracerr_test.TestSprintAddresses" || fn.Entry() != entry {
+		t.Errorf("runtime.FuncForPC(%#x) = %v; want the test function with entry %#x", pc, fn, entry)
+	}
+
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{err: nil, expected: ""},
+		{err: errors.New("some error"), expected: ""},
+		{err: tracerr.CustomError(errors.New("some error"), diffFrames("read")), expected: ""},
+	}
+	for i, c := range cases {
+		output := tracerr.SprintAddresses(c.err)
+		if output != c.expected {
+			t.Errorf("case #%d: tracerr.SprintAddresses(err) = %#v; want %#v", i, output, c.expected)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package tracerr
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// callersError is implemented by errors of github.com/go-errors/errors
+// and other libraries recording program counters of runtime.Callers.
+type callersError interface {
+	Callers() []uintptr
+}
+
+// stackError is implemented by errors recording stack trace
+// in runtime/debug.Stack format, such as recovered panics.
+type stackError interface {
+	Stack() []byte
+}
+
+// foreignFrames returns stack trace recorded by another library
+// for the innermost error in err tree, which is the original capture point.
+// Branches of errors wrapping multiple errors are walked in order.
+//
+// Supported errors are the ones of github.com/pkg/errors,
+// github.com/go-errors/errors, github.com/ztrue/tracerr
+// and errors with stack trace in runtime/debug.Stack format.
+func foreignFrames(err error) ([]Frame, bool) {
+	if err == nil {
+		return nil, false
+	}
+	for _, wrapped := range unwrapAll(err) {
+		if frames, ok := foreignFrames(wrapped); ok {
+			return frames, true
+		}
+	}
+	return errorFrames(err)
+}
+
+// errorFrames returns stack trace recorded by err itself.
+func errorFrames(err error) ([]Frame, bool) {
+	switch e := err.(type) {
+	case *errorData:
+		return nil, false
+	case pkgStackTracer:
+		return pkgErrorsFrames(e.StackTrace()), true
+	case callersError:
+		return resolveFrames(e.Callers(), false), true
+	case stackError:
+		frames := parseStack(string(e.Stack()), false)
+		return frames, len(frames) > 0
+	}
+	return reflectFrames(err)
+}
+
+// reflectFrames returns frames of errors with StackTrace method
+// returning frames with Func, Line and Path fields,
+// such as errors of github.com/ztrue/tracerr.
+func reflectFrames(err error) ([]Frame, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil, false
+	}
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	for name, kind := range map[string]reflect.Kind{
+		"Func": reflect.String,
+		"Line": reflect.Int,
+		"Path": reflect.String,
+	} {
+		field, ok := out.Elem().FieldByName(name)
+		if !ok || field.Type.Kind() != kind {
+			return nil, false
+		}
+	}
+	stack := method.Call(nil)[0]
+	frames := make([]Frame, 0, stack.Len())
+	for i := 0; i < stack.Len(); i++ {
+		f := stack.Index(i)
+		frames = append(frames, newDecodedFrame(
+			f.FieldByName("Func").String(),
+			f.FieldByName("Path").String(),
+			int(f.FieldByName("Line").Int()),
+		))
+	}
+	return frames, true
+}
+
+// parseStack parses stack trace in runtime/debug.Stack format.
+// Frames of panic and of stack trace capture itself are dropped,
+// frame of a goroutine creator is kept only if createdBy is true.
+func parseStack(stack string, createdBy bool) []Frame {
+	var frames []Frame
+	lines := strings.Split(stack, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		name := lines[i]
+		location := lines[i+1]
+		if !strings.HasPrefix(location, "\t") || strings.HasPrefix(name, "\t") {
+			continue
+		}
+		i++
+		isCreatedBy := strings.HasPrefix(name, "created by ")
+		if isCreatedBy && !createdBy {
+			// Frame of a goroutine creator isn't a part of the stack.
+			continue
+		}
+		if isCreatedBy {
+			name = strings.TrimPrefix(name, "created by ")
+			if end := strings.Index(name, " in goroutine "); end > 0 {
+				name = name[:end]
+			}
+		} else if end := strings.LastIndex(name, "("); end > 0 {
+			name = name[:end]
+		}
+		location = strings.TrimPrefix(location, "\t")
+		if end := strings.LastIndex(location, " +0x"); end > 0 {
+			location = location[:end]
+		}
+		colon := strings.LastIndex(location, ":")
+		if colon < 0 {
+			continue
+		}
+		line, err := strconv.Atoi(location[colon+1:])
+		if err != nil {
+			continue
+		}
+		if name == "panic" || name == "runtime/debug.Stack" {
+			// Frames above are of panic handling.
+			frames = frames[:0]
+			continue
+		}
+		frame := newDecodedFrame(name, location[:colon], line)
+		frame.CreatedBy = isCreatedBy
+		frames = append(frames, frame)
+	}
+	return frames
+}
@@ -0,0 +1,131 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+// callersError mimics errors of github.com/go-errors/errors.
+type callersError struct {
+	pcs []uintptr
+}
+
+func (e *callersError) Error() string {
+	return "callers error"
+}
+
+func (e *callersError) Callers() []uintptr {
+	return e.pcs
+}
+
+func newCallersError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	return &callersError{pcs: pcs[:n]}
+}
+
+// ztrueFrame mimics tracerr.Frame of github.com/ztrue/tracerr.
+type ztrueFrame struct {
+	Func string
+	Line int
+	Path string
+}
+
+type ztrueError struct{}
+
+func (e *ztrueError) Error() string {
+	return "ztrue error"
+}
+
+func (e *ztrueError) StackTrace() []ztrueFrame {
+	return []ztrueFrame{
+		{Func: "main.read", Line: 12, Path: "/src/main.go"},
+		{Func: "main.main", Line: 5, Path: "/src/main.go"},
+	}
+}
+
+type panicError struct {
+	stack string
+}
+
+func (e *panicError) Error() string {
+	return "panic error"
+}
+
+func (e *panicError) Stack() []byte {
+	return []byte(e.stack)
+}
+
+const panicStack = `goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:24 +0x5e
+main.recoverPanic()
+	/src/main.go:20 +0x25
+panic({0x4a1b20?, 0x4f4c10?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.(*Store).Get(0xc000012345, {0x4b2c31, 0x3})
+	/src/store.go:42 +0x1d
+main.main()
+	/src/main.go:8 +0x3b
+`
+
+type AdoptTestCase struct {
+	Error    error
+	Expected []string
+}
+
+func TestWrapAdoptsForeignStackTrace(t *testing.T) {
+	cases := []AdoptTestCase{
+		{
+			Error:    &ztrueError{},
+			Expected: []string{"/src/main.go:12 main.read()", "/src/main.go:5 main.main()"},
+		},
+		{
+			Error:    fmt.Errorf("context: %w", &ztrueError{}),
+			Expected: []string{"/src/main.go:12 main.read()", "/src/main.go:5 main.main()"},
+		},
+		{
+			Error:    &panicError{stack: panicStack},
+			Expected: []string{"/src/store.go:42 main.(*Store).Get()", "/src/main.go:8 main.main()"},
+		},
+	}
+
+	for i, c := range cases {
+		frames := tracerr.Wrap(c.Error).StackTrace()
+		if len(frames) != len(c.Expected) {
+			t.Errorf("case #%d: len(frames) = %#v; want %#v", i, len(frames), len(c.Expected))
+			continue
+		}
+		for j, frame := range frames {
+			if frame.String() != c.Expected[j] {
+				t.Errorf("case #%d: frames[%d] = %#v; want %#v", i, j, frame.String(), c.Expected[j])
+			}
+		}
+	}
+
+	frames := tracerr.Wrap(newCallersError()).StackTrace()
+	if len(frames) == 0 || frames[0].Name != "newCallersError" {
+		t.Errorf("frames of callers error = %#v; want to start at newCallersError", frames)
+	}
+}
+
+func TestWrapCallersErrorWithoutSymbols(t *testing.T) {
+	// Program counters outside of Go code, e.g. in C code called with cgo.
+	err := tracerr.Wrap(&callersError{pcs: []uintptr{1}})
+	frames := err.StackTrace()
+	if len(frames) == 0 {
+		t.Fatalf("len(frames) = 0; want placeholder frames")
+	}
+	for i, frame := range frames {
+		if frame.Func != "<cgo>" || frame.Path != "?" {
+			t.Errorf("frames[%d] = %#v; want placeholder frame", i, frame)
+		}
+	}
+	expected := "callers error\n\n?:0 <cgo>()\ntracerr: unknown line of <cgo>\n"
+	if output := tracerr.SprintSource(err, 1); output != expected {
+		t.Errorf("tracerr.SprintSource(err, 1) = %#v; want %#v", output, expected)
+	}
+}
@@ -0,0 +1,260 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fingerprint returns an identifier of the place where err was traced.
+//
+// Errors traced at the same stack have the same fingerprint
+// no matter what their messages are, so messages with variable data
+// are grouped together.
+// Fingerprint of an error without stack trace is based on its message,
+// or on its message template, see NewT.
+// Fingerprint of other TracedError implementations is returned by their
+// Fingerprint method.
+// Errors captured by Tracerr with WithFingerprint are fingerprinted
+// by its strategy.
+// Type arguments of generic functions are ignored, so instantiations
+// with different shapes have the same fingerprint.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	d, ok := err.(*errorData)
+	if !ok {
+		if t, ok := err.(TracedError); ok {
+			return t.Fingerprint()
+		}
+	} else if d != nil && d.fingerprint != nil {
+		return d.fingerprint.Fingerprint(d)
+	}
+	return DefaultFingerprint.Fingerprint(err)
+}
+
+// AggregateGroup is a group of errors with the same fingerprint.
+type AggregateGroup struct {
+	// Fingerprint is a fingerprint of errors in the group.
+	Fingerprint string
+	// Count is a number of errors added to the group.
+	Count int
+	// Err is the first error of the group.
+	Err Error
+	// LastSeen is when the last error was added to the group.
+	LastSeen time.Time
+}
+
+// Aggregator groups duplicate errors by fingerprint and counts them,
+// so an error failing in a loop is reported once.
+//
+// It's safe for concurrent use.
+type Aggregator struct {
+	mutex  sync.Mutex
+	groups map[string]*AggregateGroup
+	order  []string
+	sizes  map[string]int
+	size   int
+	retain retention
+	// equaler groups errors instead of fingerprints, if it's set.
+	equaler Equaler
+}
+
+// NewAggregator creates an empty Aggregator,
+// opts bound its groups, see WithTTL and WithMaxBytes.
+func NewAggregator(opts ...RetentionOption) *Aggregator {
+	return &Aggregator{
+		groups: map[string]*AggregateGroup{},
+		sizes:  map[string]int{},
+		retain: newRetention(opts),
+	}
+}
+
+// Add adds err to its group and returns a number of errors in the group,
+// which is 1 for the first occurrence. Nil error is ignored and 0 is returned.
+func (a *Aggregator) Add(err error) int {
+	if err == nil {
+		return 0
+	}
+	fingerprint := Fingerprint(err)
+	now := time.Now()
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.expire(now)
+	if a.equaler != nil {
+		fingerprint = a.equalKey(fingerprint, err)
+	}
+	group, ok := a.groups[fingerprint]
+	if !ok {
+		e, ok := err.(Error)
+		if !ok {
+			e = CustomError(err, nil)
+		}
+		group = &AggregateGroup{
+			Fingerprint: fingerprint,
+			Err:         e,
+		}
+		a.add(group)
+	}
+	group.Count++
+	group.LastSeen = now
+	for len(a.order) > 1 && a.retain.exceeded(a.size) {
+		a.dropLeastRecent(fingerprint)
+	}
+	return group.Count
+}
+
+// SetEqualer makes errors grouped by eq instead of fingerprints,
+// e.g. by EqualMessageAndCode, so repeats traced at different places
+// are counted together. Fingerprint of a group is the one of its first
+// error, with a number appended if another group has the same.
+// Nil eq restores grouping by fingerprints. Groups added before are kept.
+func (a *Aggregator) SetEqualer(eq Equaler) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.equaler = eq
+}
+
+// equalKey returns fingerprint of the group of an error equal to err,
+// or a fingerprint not taken by other groups.
+func (a *Aggregator) equalKey(fingerprint string, err error) string {
+	for _, key := range a.order {
+		if a.equaler.Equal(a.groups[key].Err, err) {
+			return key
+		}
+	}
+	return uniqueKey(fingerprint, func(key string) bool {
+		_, ok := a.groups[key]
+		return ok
+	})
+}
+
+func (a *Aggregator) add(group *AggregateGroup) {
+	size := errorSize(group.Err)
+	a.groups[group.Fingerprint] = group
+	a.order = append(a.order, group.Fingerprint)
+	a.sizes[group.Fingerprint] = size
+	a.size += size
+}
+
+// expire drops groups expired at now.
+func (a *Aggregator) expire(now time.Time) {
+	if a.retain.ttl <= 0 {
+		return
+	}
+	order := a.order[:0]
+	for _, fingerprint := range a.order {
+		if a.retain.expired(a.groups[fingerprint].LastSeen, now) {
+			a.drop(fingerprint)
+			continue
+		}
+		order = append(order, fingerprint)
+	}
+	clear(a.order[len(order):])
+	a.order = order
+}
+
+// dropLeastRecent drops the least recently added group except for kept one.
+func (a *Aggregator) dropLeastRecent(kept string) {
+	oldest := -1
+	for i, fingerprint := range a.order {
+		if fingerprint == kept {
+			continue
+		}
+		if oldest < 0 || a.groups[fingerprint].LastSeen.Before(a.groups[a.order[oldest]].LastSeen) {
+			oldest = i
+		}
+	}
+	a.drop(a.order[oldest])
+	a.order = slices.Delete(a.order, oldest, oldest+1)
+}
+
+// drop removes group of fingerprint, but not from order.
+func (a *Aggregator) drop(fingerprint string) {
+	a.size -= a.sizes[fingerprint]
+	delete(a.sizes, fingerprint)
+	delete(a.groups, fingerprint)
+}
+
+// Groups returns groups ordered by count, most frequent first.
+// Groups with equal count are in order of their first occurrence.
+func (a *Aggregator) Groups() []AggregateGroup {
+	groups := a.orderedGroups()
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
+// orderedGroups returns groups in order of their first occurrence.
+func (a *Aggregator) orderedGroups() []AggregateGroup {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.expire(time.Now())
+	groups := make([]AggregateGroup, 0, len(a.order))
+	for _, fingerprint := range a.order {
+		groups = append(groups, *a.groups[fingerprint])
+	}
+	return groups
+}
+
+// Reset removes all groups.
+func (a *Aggregator) Reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.reset()
+}
+
+func (a *Aggregator) reset() {
+	a.groups = map[string]*AggregateGroup{}
+	a.order = nil
+	a.sizes = map[string]int{}
+	a.size = 0
+}
+
+// Sprint returns summary of groups, each one is a number of occurrences
+// followed by error output by the same rules as Print.
+func (a *Aggregator) Sprint() string {
+	groups := a.Groups()
+	rows := make([]string, 0, len(groups))
+	for _, group := range groups {
+		rows = append(rows, fmt.Sprintf("%d x %s", group.Count, Sprint(group.Err)))
+	}
+	return strings.Join(rows, "\n\n")
+}
+
+// Fprint writes summary of groups to w, see Sprint.
+func (a *Aggregator) Fprint(w io.Writer) (int, error) {
+	return fmt.Fprintln(w, a.Sprint())
+}
+
+// aggregateGroupJSON is a JSON form of AggregateGroup.
+type aggregateGroupJSON struct {
+	Fingerprint string    `json:"fingerprint"`
+	Count       int       `json:"count"`
+	LastSeen    time.Time `json:"lastSeen"`
+	Error       *TreeNode `json:"error"`
+}
+
+// MarshalJSON encodes groups ordered as by Groups as JSON array of objects
+// with fingerprint, count, lastSeen and error tree, see Tree,
+// e.g. to compare errors of two builds by tracerr-diff command.
+func (a *Aggregator) MarshalJSON() ([]byte, error) {
+	groups := a.Groups()
+	list := make([]aggregateGroupJSON, len(groups))
+	for i, group := range groups {
+		list[i] = aggregateGroupJSON{
+			Fingerprint: group.Fingerprint,
+			Count:       group.Count,
+			LastSeen:    group.LastSeen,
+			Error:       Tree(group.Err),
+		}
+	}
+	return json.Marshal(list)
+}
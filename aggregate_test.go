@@ -0,0 +1,141 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func failInLoop() error {
+	return tracerr.Errorf("attempt failed")
+}
+
+func failOnce() error {
+	return tracerr.New("other failure")
+}
+
+func TestAggregator(t *testing.T) {
+	a := tracerr.NewAggregator()
+	for i := 1; i <= 3; i++ {
+		count := a.Add(failInLoop())
+		if count != i {
+			t.Errorf("a.Add(failInLoop()) = %#v; want %#v", count, i)
+		}
+	}
+	if count := a.Add(failOnce()); count != 1 {
+		t.Errorf("a.Add(failOnce()) = %#v; want %#v", count, 1)
+	}
+	if count := a.Add(errors.New("plain")); count != 1 {
+		t.Errorf("a.Add(plain) = %#v; want %#v", count, 1)
+	}
+	if count := a.Add(errors.New("plain")); count != 2 {
+		t.Errorf("a.Add(plain) = %#v; want %#v", count, 2)
+	}
+	if count := a.Add(nil); count != 0 {
+		t.Errorf("a.Add(nil) = %#v; want %#v", count, 0)
+	}
+
+	groups := a.Groups()
+	if len(groups) != 3 {
+		t.Fatalf("len(a.Groups()) = %#v; want %#v", len(groups), 3)
+	}
+	wantCounts := []int{3, 2, 1}
+	wantMessages := []string{"attempt failed", "plain", "other failure"}
+	for i, group := range groups {
+		if group.Count != wantCounts[i] {
+			t.Errorf("groups[%d].Count = %#v; want %#v", i, group.Count, wantCounts[i])
+		}
+		if group.Err.Error() != wantMessages[i] {
+			t.Errorf("groups[%d].Err.Error() = %#v; want %#v", i, group.Err.Error(), wantMessages[i])
+		}
+		if group.Fingerprint != tracerr.Fingerprint(group.Err) {
+			t.Errorf(
+				"groups[%d].Fingerprint = %#v; want %#v",
+				i, group.Fingerprint, tracerr.Fingerprint(group.Err),
+			)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := a.Fprint(&buf); err != nil {
+		t.Fatalf("a.Fprint() error = %#v; want nil", err)
+	}
+	if !strings.HasPrefix(buf.String(), "3 x attempt failed\n") {
+		t.Errorf("a.Fprint() output = %#v; want prefix %#v", buf.String(), "3 x attempt failed\n")
+	}
+
+	a.Reset()
+	if len(a.Groups()) != 0 {
+		t.Errorf("len(a.Groups()) after Reset = %#v; want %#v", len(a.Groups()), 0)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if fingerprint := tracerr.Fingerprint(nil); fingerprint != "" {
+		t.Errorf("tracerr.Fingerprint(nil) = %#v; want %#v", fingerprint, "")
+	}
+	if tracerr.Fingerprint(failInLoop()) != tracerr.Fingerprint(failInLoop()) {
+		t.Errorf("fingerprints of errors traced at the same stack differ")
+	}
+	if tracerr.Fingerprint(failInLoop()) == tracerr.Fingerprint(failOnce()) {
+		t.Errorf("fingerprints of errors traced at different stacks are equal")
+	}
+}
+
+func TestAggregatorSetEqualer(t *testing.T) {
+	a := tracerr.NewAggregator()
+	a.SetEqualer(tracerr.EqualerFunc(tracerr.EqualMessageAndCode))
+	read := tracerr.CustomError(errors.New("failed"), diffFrames("read"))
+	a.Add(read)
+	a.Add(tracerr.CustomError(errors.New("failed"), diffFrames("write")))
+	if count := a.Add(tracerr.CustomError(errors.New("other"), diffFrames("read"))); count != 1 {
+		t.Errorf("a.Add(other) = %#v; want %#v", count, 1)
+	}
+
+	groups := a.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("len(a.Groups()) = %#v; want %#v", len(groups), 2)
+	}
+	if groups[0].Count != 2 || groups[0].Err != read || groups[0].Fingerprint != tracerr.Fingerprint(read) {
+		t.Errorf("groups[0] = %#v; want 2 errors with the same message", groups[0])
+	}
+	if expected := tracerr.Fingerprint(read) + "-2"; groups[1].Fingerprint != expected {
+		t.Errorf("groups[1].Fingerprint = %#v; want %#v", groups[1].Fingerprint, expected)
+	}
+}
+
+func TestAggregatorMarshalJSON(t *testing.T) {
+	a := tracerr.NewAggregator()
+	read := tracerr.CustomError(errors.New("read failed"), diffFrames("read"))
+	a.Add(tracerr.CustomError(errors.New("write failed"), diffFrames("write")))
+	a.Add(read)
+	a.Add(read)
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("json.Marshal(a) error = %#v; want nil", err)
+	}
+	var groups []struct {
+		Fingerprint string `json:"fingerprint"`
+		Count       int    `json:"count"`
+		Error       struct {
+			Message string   `json:"message"`
+			Frames  []string `json:"frames"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &groups); err != nil {
+		t.Fatalf("json.Unmarshal() error = %#v; want nil", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) = %#v; want %#v", len(groups), 2)
+	}
+	first := groups[0]
+	if first.Fingerprint != tracerr.Fingerprint(read) || first.Count != 2 ||
+		first.Error.Message != "read failed" || len(first.Error.Frames) != 1 {
+		t.Errorf("groups[0] = %#v; want the most frequent group", first)
+	}
+}
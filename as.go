@@ -0,0 +1,36 @@
+package tracerr
+
+// AsError finds the first Error in err tree and reports whether it's found.
+//
+// Tree is walked in depth-first order through both Unwrap() error
+// and Unwrap() []error, so errors joined by errors.Join or wrapped
+// by fmt.Errorf with multiple %w verbs are supported.
+// errors.As(err, &e) with e of type Error works the same way.
+func AsError(err error) (Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if e, ok := err.(Error); ok {
+		return e, true
+	}
+	for _, err := range unwrapAll(err) {
+		if e, ok := AsError(err); ok {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// unwrapAll returns errors wrapped by err
+// either with Unwrap() error or with Unwrap() []error.
+func unwrapAll(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if err := u.Unwrap(); err != nil {
+			return []error{err}
+		}
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+// joinError wraps several errors like errors.Join.
+type joinError []error
+
+func (e joinError) Error() string {
+	return fmt.Sprint([]error(e))
+}
+
+func (e joinError) Unwrap() []error {
+	return e
+}
+
+type AsErrorTestCase struct {
+	Error    error
+	Expected tracerr.Error
+}
+
+func TestAsError(t *testing.T) {
+	traced := tracerr.New("traced")
+	plain := errors.New("plain")
+	cases := []AsErrorTestCase{
+		{
+			Error:    nil,
+			Expected: nil,
+		},
+		{
+			Error:    plain,
+			Expected: nil,
+		},
+		{
+			Error:    traced,
+			Expected: traced,
+		},
+		{
+			Error:    fmt.Errorf("one: %w", fmt.Errorf("two: %w", traced)),
+			Expected: traced,
+		},
+		{
+			Error:    joinError{plain, fmt.Errorf("wrapped: %w", traced)},
+			Expected: traced,
+		},
+		{
+			Error:    fmt.Errorf("context: %w", joinError{plain, joinError{traced}}),
+			Expected: traced,
+		},
+		{
+			Error:    joinError{plain, fmt.Errorf("wrapped: %w", plain)},
+			Expected: nil,
+		},
+	}
+
+	for i, c := range cases {
+		e, ok := tracerr.AsError(c.Error)
+		if e != c.Expected || ok != (c.Expected != nil) {
+			t.Errorf("case #%d: tracerr.AsError(err) = %#v, %#v; want %#v, %#v", i, e, ok, c.Expected, c.Expected != nil)
+		}
+		var target tracerr.Error
+		if errors.As(c.Error, &target) != (c.Expected != nil) || target != c.Expected {
+			t.Errorf("case #%d: errors.As(err, &target) target = %#v; want %#v", i, target, c.Expected)
+		}
+	}
+}
+
+func TestWrapDeepTracedError(t *testing.T) {
+	traced := tracerr.New("traced")
+	err := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", traced))
+	wrapped := tracerr.Wrap(err)
+	if wrapped.Error() != "outer: inner: traced" {
+		t.Errorf("wrapped.Error() = %#v; want %#v", wrapped.Error(), "outer: inner: traced")
+	}
+	if wrapped.StackTrace()[0] != traced.StackTrace()[0] {
+		t.Errorf(
+			"wrapped.StackTrace()[0] = %#v; want %#v",
+			wrapped.StackTrace()[0], traced.StackTrace()[0],
+		)
+	}
+}
+
+func TestWrapMultiWrappedError(t *testing.T) {
+	traced := tracerr.New("traced")
+	err := fmt.Errorf("context: %w", joinError{errors.New("plain"), traced})
+	wrapped := tracerr.Wrap(err)
+	if wrapped.Unwrap() != err {
+		t.Errorf("wrapped.Unwrap() = %#v; want %#v", wrapped.Unwrap(), err)
+	}
+	if wrapped.StackTrace()[0] != traced.StackTrace()[0] {
+		t.Errorf(
+			"wrapped.StackTrace()[0] = %#v; want %#v",
+			wrapped.StackTrace()[0], traced.StackTrace()[0],
+		)
+	}
+
+	frames := tracerr.Wrap(joinError{errors.New("plain"), &ztrueError{}}).StackTrace()
+	if len(frames) != 2 || frames[0].Func != "main.read" {
+		t.Errorf("frames = %#v; want frames of ztrueError", frames)
+	}
+}
@@ -0,0 +1,237 @@
+package tracerr
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Versions of binary encoding of errors,
+// binaryTraceVersion is used for errors with trace context.
+const (
+	binaryVersion      = 1
+	binaryTraceVersion = 2
+)
+
+// errInvalidBinary is returned for malformed binary encoding.
+var errInvalidBinary = errors.New("tracerr: invalid binary encoding")
+
+// errNilReceiver is returned by decoding into a nil error.
+var errNilReceiver = errors.New("tracerr: decoding into nil error")
+
+// MarshalBinary encodes error message and stack trace of err in a compact form
+// for storing errors in Kafka, Redis and the like, where JSON is too large.
+//
+// Function names and paths are written once per error, line numbers
+// are delta-encoded varints. Original error type and program counters
+// of frames are not kept, the same as with GobEncode.
+// Trace context is kept, see Trace.
+func MarshalBinary(err error) ([]byte, error) {
+	if err == nil {
+		return nil, errors.New("tracerr: nil error")
+	}
+	var frames []Frame
+	if e, ok := err.(Error); ok {
+		frames = redactStackTrace(RawFrames(e))
+	}
+	trace, _ := Trace(err)
+	return marshalBinary(ScrubMessage(err.Error()), trace, frames), nil
+}
+
+// UnmarshalBinary decodes error encoded by MarshalBinary,
+// original error is replaced with an error with the same message.
+func UnmarshalBinary(data []byte) (Error, error) {
+	e := &errorData{}
+	if err := e.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// MarshalBinary encodes error by the same rules as MarshalBinary function.
+func (e *errorData) MarshalBinary() ([]byte, error) {
+	trace, _ := Trace(e)
+	return marshalBinary(ScrubMessage(e.Error()), trace, redactStackTrace(e.RawFrames())), nil
+}
+
+// UnmarshalBinary decodes error encoded by MarshalBinary.
+func (e *errorData) UnmarshalBinary(data []byte) error {
+	if e == nil {
+		return errNilReceiver
+	}
+	message, trace, frames, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	e.err = errors.New(message)
+	e.frames = frames
+	e.trace = trace
+	return nil
+}
+
+// marshalBinary returns encoding of message, trace context and frames:
+//
+//	version byte
+//	message string
+//	trace ID and span ID strings, only for binaryTraceVersion
+//	count of table strings, table strings
+//	count of frames, for each frame:
+//		index of function name << 1 | created by flag
+//		index of path
+//		line delta from the previous frame as signed varint
+//
+// Strings are prefixed by length, all numbers are varints.
+func marshalBinary(message string, trace TraceContext, frames []Frame) []byte {
+	index := make(map[string]uint64)
+	var table []string
+	add := func(s string) uint64 {
+		i, ok := index[s]
+		if !ok {
+			i = uint64(len(table))
+			index[s] = i
+			table = append(table, s)
+		}
+		return i
+	}
+	body := binary.AppendUvarint(nil, uint64(len(frames)))
+	line := 0
+	for _, frame := range frames {
+		fn := add(frame.Func) << 1
+		if frame.CreatedBy {
+			fn |= 1
+		}
+		body = binary.AppendUvarint(body, fn)
+		body = binary.AppendUvarint(body, add(frame.Path))
+		body = binary.AppendVarint(body, int64(frame.Line-line))
+		line = frame.Line
+	}
+	b := []byte{binaryVersion}
+	b = appendString(b, message)
+	if trace.TraceID != "" {
+		b[0] = binaryTraceVersion
+		b = appendTrace(b, trace)
+	}
+	b = binary.AppendUvarint(b, uint64(len(table)))
+	for _, s := range table {
+		b = appendString(b, s)
+	}
+	return append(b, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendTrace(b []byte, trace TraceContext) []byte {
+	b = appendString(b, trace.TraceID)
+	return appendString(b, trace.SpanID)
+}
+
+// binaryReader reads encoding of marshalBinary,
+// the first malformed value sets err and the rest are zero.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+// finish returns error of reading, which is also reported
+// if data is left after the last value.
+func (r *binaryReader) finish() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) > 0 {
+		return errInvalidBinary
+	}
+	return nil
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errInvalidBinary
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errInvalidBinary
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *binaryReader) string() string {
+	n := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if n > uint64(len(r.data)) {
+		r.err = errInvalidBinary
+		return ""
+	}
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+	return s
+}
+
+func (r *binaryReader) trace() TraceContext {
+	return TraceContext{TraceID: r.string(), SpanID: r.string()}
+}
+
+// count reads a number of following items, each taking at least one byte.
+func (r *binaryReader) count() int {
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.data)) {
+		r.err = errInvalidBinary
+		return 0
+	}
+	return int(n)
+}
+
+func unmarshalBinary(data []byte) (string, TraceContext, []Frame, error) {
+	if len(data) == 0 || data[0] != binaryVersion && data[0] != binaryTraceVersion {
+		return "", TraceContext{}, nil, errInvalidBinary
+	}
+	r := &binaryReader{data: data[1:]}
+	message := r.string()
+	var trace TraceContext
+	if data[0] == binaryTraceVersion {
+		trace = r.trace()
+	}
+	table := make([]string, r.count())
+	for i := range table {
+		table[i] = r.string()
+	}
+	frames := make([]Frame, 0, r.count())
+	line := 0
+	for i := 0; i < cap(frames) && r.err == nil; i++ {
+		fn := r.uvarint()
+		path := r.uvarint()
+		line += int(r.varint())
+		if r.err != nil {
+			break
+		}
+		if fn>>1 >= uint64(len(table)) || path >= uint64(len(table)) {
+			return "", TraceContext{}, nil, errInvalidBinary
+		}
+		frame := newDecodedFrame(table[fn>>1], table[path], line)
+		frame.CreatedBy = fn&1 == 1
+		frames = append(frames, frame)
+	}
+	if err := r.finish(); err != nil {
+		return "", TraceContext{}, nil, err
+	}
+	return message, trace, frames, nil
+}
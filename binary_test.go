@@ -0,0 +1,81 @@
+package tracerr_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("github.com/foo/bar.(*Thing).Do", "/src/github.com/foo/bar/thing.go", 42),
+		tracerr.NewFrame("github.com/foo/bar.(*Thing).Run", "/src/github.com/foo/bar/thing.go", 30),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+	}
+	frames[2].CreatedBy = true
+	err := tracerr.CustomError(errors.New("some error"), frames)
+
+	b, e := tracerr.MarshalBinary(err)
+	if e != nil {
+		t.Fatalf("tracerr.MarshalBinary(err) error = %v", e)
+	}
+	if j, _ := json.Marshal(frames); len(b) >= len(j) {
+		t.Errorf("len(b) = %d; want less than JSON %d", len(b), len(j))
+	}
+	decoded, e := tracerr.UnmarshalBinary(b)
+	if e != nil {
+		t.Fatalf("tracerr.UnmarshalBinary(b) error = %v", e)
+	}
+	if decoded.Error() != "some error" {
+		t.Errorf("decoded.Error() = %#v; want %#v", decoded.Error(), "some error")
+	}
+	if !reflect.DeepEqual(decoded.StackTrace(), frames) {
+		t.Errorf("decoded.StackTrace() = %#v; want %#v", decoded.StackTrace(), frames)
+	}
+
+	m, ok := err.(encoding.BinaryMarshaler)
+	if !ok {
+		t.Fatalf("err doesn't implement encoding.BinaryMarshaler")
+	}
+	if mb, _ := m.MarshalBinary(); !reflect.DeepEqual(mb, b) {
+		t.Errorf("err.MarshalBinary() = %v; want %v", mb, b)
+	}
+}
+
+func TestMarshalBinaryWithoutStackTrace(t *testing.T) {
+	b, err := tracerr.MarshalBinary(errors.New("some error"))
+	if err != nil {
+		t.Fatalf("tracerr.MarshalBinary(err) error = %v", err)
+	}
+	decoded, err := tracerr.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatalf("tracerr.UnmarshalBinary(b) error = %v", err)
+	}
+	if decoded.Error() != "some error" || len(decoded.StackTrace()) != 0 {
+		t.Errorf("decoded = %#v; want message only", decoded)
+	}
+	if _, err := tracerr.MarshalBinary(nil); err == nil {
+		t.Errorf("tracerr.MarshalBinary(nil) error = nil; want error")
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	valid, _ := tracerr.MarshalBinary(tracerr.New("some error"))
+	cases := [][]byte{
+		nil,
+		{2},
+		valid[:len(valid)-1],
+		append(append([]byte(nil), valid...), 0),
+		{1, 0, 0, 1, 0, 0, 0},
+		{1, 0, 0, 0xff},
+	}
+	for i, data := range cases {
+		if _, err := tracerr.UnmarshalBinary(data); err == nil {
+			t.Errorf("case #%d: tracerr.UnmarshalBinary(%v) error = nil; want error", i, data)
+		}
+	}
+}
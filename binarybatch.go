@@ -0,0 +1,275 @@
+package tracerr
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// binaryBatchVersion is a version of binary encoding of error batches.
+const binaryBatchVersion = 1
+
+// binaryBatchFramesPerByte bounds a number of frames decoded from a batch
+// per byte of its encoding, as entries expand shared nodes of deep stack traces.
+const binaryBatchFramesPerByte = 16
+
+// MarshalBinaryBatch encodes error messages and stack traces of errs
+// in a form compressed across the batch, for persisting thousands of errors
+// which bottom out in the same stack, such as of an HTTP handler.
+//
+// Frames shared by the bottom of stack traces are written once,
+// as well as function names and paths of all errors.
+// Original error types and program counters of frames are not kept,
+// the same as with MarshalBinary. Nil errors aren't allowed.
+// Trace context is kept, see Trace.
+func MarshalBinaryBatch(errs []error) ([]byte, error) {
+	w := newBatchWriter()
+	entries := binary.AppendUvarint(nil, uint64(len(errs)))
+	for _, err := range errs {
+		if err == nil {
+			return nil, errors.New("tracerr: nil error")
+		}
+		var frames []Frame
+		if e, ok := err.(Error); ok {
+			frames = redactStackTrace(RawFrames(e))
+		}
+		trace, _ := Trace(err)
+		entries = appendString(entries, ScrubMessage(err.Error()))
+		entries = appendTrace(entries, trace)
+		entries = binary.AppendUvarint(entries, w.add(frames))
+	}
+	return w.bytes(entries), nil
+}
+
+// UnmarshalBinaryBatch decodes errors encoded by MarshalBinaryBatch,
+// original errors are replaced with errors with the same messages.
+func UnmarshalBinaryBatch(data []byte) ([]Error, error) {
+	r, nodes, err := readBatch(data)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]Error, 0, r.count())
+	for i := 0; i < cap(errs) && r.err == nil; i++ {
+		message := r.string()
+		trace := r.trace()
+		frames, err := nodes.stackTrace(r.uvarint())
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, &errorData{err: errors.New(message), frames: frames, trace: trace})
+	}
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// MarshalBinary encodes fingerprints, counts, times and errors of groups
+// by the same rules as MarshalBinaryBatch.
+func (a *Aggregator) MarshalBinary() ([]byte, error) {
+	groups := a.orderedGroups()
+	w := newBatchWriter()
+	entries := binary.AppendUvarint(nil, uint64(len(groups)))
+	for _, group := range groups {
+		trace, _ := Trace(group.Err)
+		entries = appendString(entries, ScrubMessage(group.Err.Error()))
+		entries = appendTrace(entries, trace)
+		entries = binary.AppendUvarint(entries, w.add(redactStackTrace(RawFrames(group.Err))))
+		entries = appendString(entries, group.Fingerprint)
+		entries = binary.AppendUvarint(entries, uint64(group.Count))
+		entries = binary.AppendVarint(entries, group.LastSeen.UnixNano())
+	}
+	return w.bytes(entries), nil
+}
+
+// UnmarshalBinary replaces groups with groups encoded by MarshalBinary,
+// original errors are replaced with errors with the same messages.
+func (a *Aggregator) UnmarshalBinary(data []byte) error {
+	r, nodes, err := readBatch(data)
+	if err != nil {
+		return err
+	}
+	var groups []*AggregateGroup
+	seen := make(map[string]bool)
+	count := r.count()
+	for i := 0; i < count && r.err == nil; i++ {
+		message := r.string()
+		trace := r.trace()
+		frames, err := nodes.stackTrace(r.uvarint())
+		if err != nil {
+			return err
+		}
+		group := &AggregateGroup{
+			Err:         &errorData{err: errors.New(message), frames: frames, trace: trace},
+			Fingerprint: r.string(),
+			Count:       int(r.uvarint()),
+			LastSeen:    time.Unix(0, r.varint()),
+		}
+		if seen[group.Fingerprint] && r.err == nil {
+			return errInvalidBinary
+		}
+		seen[group.Fingerprint] = true
+		groups = append(groups, group)
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.reset()
+	for _, group := range groups {
+		a.add(group)
+	}
+	return nil
+}
+
+// batchNode is a frame shared by stack traces of a batch,
+// parent is a number of the node of the frame below it, or 0 for none.
+type batchNode struct {
+	parent uint64
+	fn     uint64
+	path   uint64
+	line   int
+}
+
+// batchWriter writes encoding of a batch:
+//
+//	version byte
+//	count of table strings, table strings
+//	count of nodes, for each node:
+//		number of parent node, 0 for none
+//		index of function name << 1 | created by flag
+//		index of path
+//		line as signed varint
+//	entries
+//
+// Entries start with message, trace ID and span ID strings.
+// Nodes are numbered from 1 in order, parents precede their children.
+// Entries refer to the node of the top frame, or 0 for no stack trace.
+type batchWriter struct {
+	index map[string]uint64
+	table []string
+	nodes map[batchNode]uint64
+	body  []byte
+}
+
+func newBatchWriter() *batchWriter {
+	return &batchWriter{
+		index: map[string]uint64{},
+		nodes: map[batchNode]uint64{},
+	}
+}
+
+func (w *batchWriter) string(s string) uint64 {
+	i, ok := w.index[s]
+	if !ok {
+		i = uint64(len(w.table))
+		w.index[s] = i
+		w.table = append(w.table, s)
+	}
+	return i
+}
+
+// add adds nodes of frames, which aren't added yet,
+// and returns number of the node of the top frame.
+func (w *batchWriter) add(frames []Frame) uint64 {
+	var parent uint64
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		node := batchNode{
+			parent: parent,
+			fn:     w.string(frame.Func) << 1,
+			path:   w.string(frame.Path),
+			line:   frame.Line,
+		}
+		if frame.CreatedBy {
+			node.fn |= 1
+		}
+		n, ok := w.nodes[node]
+		if !ok {
+			n = uint64(len(w.nodes) + 1)
+			w.nodes[node] = n
+			w.body = binary.AppendUvarint(w.body, node.parent)
+			w.body = binary.AppendUvarint(w.body, node.fn)
+			w.body = binary.AppendUvarint(w.body, node.path)
+			w.body = binary.AppendVarint(w.body, int64(node.line))
+		}
+		parent = n
+	}
+	return parent
+}
+
+func (w *batchWriter) bytes(entries []byte) []byte {
+	b := []byte{binaryBatchVersion}
+	b = binary.AppendUvarint(b, uint64(len(w.table)))
+	for _, s := range w.table {
+		b = appendString(b, s)
+	}
+	b = binary.AppendUvarint(b, uint64(len(w.nodes)))
+	b = append(b, w.body...)
+	return append(b, entries...)
+}
+
+// batchFrames are decoded nodes of a batch,
+// budget is a number of frames stack traces may still take.
+type batchFrames struct {
+	frames  []Frame
+	parents []uint64
+	budget  int
+}
+
+// stackTrace returns frames starting at node n.
+func (b *batchFrames) stackTrace(n uint64) ([]Frame, error) {
+	if n > uint64(len(b.frames)) {
+		return nil, errInvalidBinary
+	}
+	frames := make([]Frame, 0)
+	for ; n > 0; n = b.parents[n-1] {
+		if b.budget == 0 {
+			return nil, errInvalidBinary
+		}
+		b.budget--
+		frames = append(frames, b.frames[n-1])
+	}
+	return frames, nil
+}
+
+// readBatch reads table and nodes of a batch,
+// and returns reader of its entries.
+func readBatch(data []byte) (*binaryReader, *batchFrames, error) {
+	if len(data) == 0 || data[0] != binaryBatchVersion {
+		return nil, nil, errInvalidBinary
+	}
+	r := &binaryReader{data: data[1:]}
+	table := make([]string, r.count())
+	for i := range table {
+		table[i] = r.string()
+	}
+	count := r.count()
+	nodes := &batchFrames{
+		frames:  make([]Frame, 0, count),
+		parents: make([]uint64, 0, count),
+		budget:  len(data) * binaryBatchFramesPerByte,
+	}
+	for i := 0; i < count && r.err == nil; i++ {
+		parent := r.uvarint()
+		fn := r.uvarint()
+		path := r.uvarint()
+		line := int(r.varint())
+		if r.err != nil {
+			break
+		}
+		// Parents precede their children, so stack traces have no cycles.
+		if parent > uint64(i) || fn>>1 >= uint64(len(table)) || path >= uint64(len(table)) {
+			return nil, nil, errInvalidBinary
+		}
+		frame := newDecodedFrame(table[fn>>1], table[path], line)
+		frame.CreatedBy = fn&1 == 1
+		nodes.frames = append(nodes.frames, frame)
+		nodes.parents = append(nodes.parents, parent)
+	}
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+	return r, nodes, nil
+}
@@ -0,0 +1,166 @@
+package tracerr_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestMarshalBinaryBatch(t *testing.T) {
+	handler := []tracerr.Frame{
+		tracerr.NewFrame("net/http.HandlerFunc.ServeHTTP", "/go/src/net/http/server.go", 2220),
+		tracerr.NewFrame("net/http.(*conn).serve", "/go/src/net/http/server.go", 2092),
+	}
+	errs := []error{
+		tracerr.CustomError(errors.New("read failed"), append(diffFrames("read", "handle"), handler...)),
+		tracerr.CustomError(errors.New("write failed"), append(diffFrames("write", "handle"), handler...)),
+		errors.New("plain"),
+	}
+
+	b, err := tracerr.MarshalBinaryBatch(errs)
+	if err != nil {
+		t.Fatalf("tracerr.MarshalBinaryBatch(errs) error = %v", err)
+	}
+	size := 0
+	for _, e := range errs {
+		single, _ := tracerr.MarshalBinary(e)
+		size += len(single)
+	}
+	if len(b) >= size {
+		t.Errorf("len(b) = %d; want less than separate encodings %d", len(b), size)
+	}
+
+	decoded, err := tracerr.UnmarshalBinaryBatch(b)
+	if err != nil {
+		t.Fatalf("tracerr.UnmarshalBinaryBatch(b) error = %v", err)
+	}
+	if len(decoded) != len(errs) {
+		t.Fatalf("len(decoded) = %d; want %d", len(decoded), len(errs))
+	}
+	for i, e := range errs {
+		if decoded[i].Error() != e.Error() {
+			t.Errorf("decoded[%d].Error() = %#v; want %#v", i, decoded[i].Error(), e.Error())
+		}
+		frames := tracerr.StackTrace(e)
+		if frames == nil {
+			frames = []tracerr.Frame{}
+		}
+		if !reflect.DeepEqual(decoded[i].StackTrace(), frames) {
+			t.Errorf("decoded[%d].StackTrace() = %#v; want %#v", i, decoded[i].StackTrace(), frames)
+		}
+	}
+
+	if _, err := tracerr.MarshalBinaryBatch([]error{nil}); err == nil {
+		t.Errorf("tracerr.MarshalBinaryBatch(nil) error = nil; want error")
+	}
+}
+
+func TestUnmarshalBinaryBatchInvalid(t *testing.T) {
+	valid, _ := tracerr.MarshalBinaryBatch([]error{tracerr.New("some error")})
+	cases := [][]byte{
+		nil,
+		{2},
+		valid[:len(valid)-1],
+		append(append([]byte(nil), valid...), 0),
+		// Node refers to a parent after it.
+		{1, 1, 0, 1, 1, 0, 0, 0, 0},
+		// Entry refers to a missing node.
+		{1, 0, 0, 1, 0, 1},
+	}
+	for i, data := range cases {
+		if _, err := tracerr.UnmarshalBinaryBatch(data); err == nil {
+			t.Errorf("case #%d: tracerr.UnmarshalBinaryBatch(%v) error = nil; want error", i, data)
+		}
+	}
+}
+
+// deepBatch returns encoding of a batch of entries sharing a chain of depth nodes,
+// fingerprinted adds fields of Aggregator groups to entries.
+func deepBatch(depth, entries int, fingerprinted bool) []byte {
+	b := []byte{1, 2, 1, 'f', 1, 'p'}
+	b = binary.AppendUvarint(b, uint64(depth))
+	for i := 0; i < depth; i++ {
+		b = binary.AppendUvarint(b, uint64(i))
+		b = append(b, 0, 1, 2)
+	}
+	b = binary.AppendUvarint(b, uint64(entries))
+	for i := 0; i < entries; i++ {
+		b = append(b, 0, 0, 0)
+		b = binary.AppendUvarint(b, uint64(depth))
+		if fingerprinted {
+			fingerprint := strconv.Itoa(i)
+			b = binary.AppendUvarint(b, uint64(len(fingerprint)))
+			b = append(b, fingerprint...)
+			b = append(b, 1, 0)
+		}
+	}
+	return b
+}
+
+func TestUnmarshalBinaryBatchDeep(t *testing.T) {
+	shallow, err := tracerr.UnmarshalBinaryBatch(deepBatch(20, 100, false))
+	if err != nil {
+		t.Fatalf("tracerr.UnmarshalBinaryBatch(shallow) error = %v", err)
+	}
+	if len(shallow) != 100 || len(shallow[0].StackTrace()) != 20 {
+		t.Errorf("len(shallow) = %d, len(shallow[0].StackTrace()) = %d; want 100, 20", len(shallow), len(shallow[0].StackTrace()))
+	}
+
+	// Every entry expands the whole chain, which takes far more frames than bytes.
+	deep := deepBatch(2000, 2000, false)
+	if _, err := tracerr.UnmarshalBinaryBatch(deep); err == nil {
+		t.Errorf("tracerr.UnmarshalBinaryBatch(deep) error = nil; want error")
+	}
+	a := tracerr.NewAggregator()
+	if err := a.UnmarshalBinary(deepBatch(20, 100, true)); err != nil {
+		t.Fatalf("a.UnmarshalBinary(shallow) error = %v", err)
+	}
+	if err := a.UnmarshalBinary(deepBatch(2000, 2000, true)); err == nil {
+		t.Errorf("a.UnmarshalBinary(deep) error = nil; want error")
+	}
+}
+
+func TestAggregatorMarshalBinary(t *testing.T) {
+	loop, once := failInLoop(), failOnce()
+	a := tracerr.NewAggregator()
+	a.Add(loop)
+	a.Add(once)
+	a.Add(loop)
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("a.MarshalBinary() error = %v", err)
+	}
+
+	decoded := tracerr.NewAggregator()
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatalf("decoded.UnmarshalBinary(b) error = %v", err)
+	}
+	groups, decodedGroups := a.Groups(), decoded.Groups()
+	if len(decodedGroups) != len(groups) {
+		t.Fatalf("len(decoded.Groups()) = %d; want %d", len(decodedGroups), len(groups))
+	}
+	for i, group := range groups {
+		got := decodedGroups[i]
+		if got.Fingerprint != group.Fingerprint || got.Count != group.Count || got.Err.Error() != group.Err.Error() || !got.LastSeen.Equal(group.LastSeen) {
+			t.Errorf("decoded.Groups()[%d] = %#v; want %#v", i, got, group)
+		}
+		if !reflect.DeepEqual(got.Err.StackTrace()[0].Func, group.Err.StackTrace()[0].Func) {
+			t.Errorf("decoded.Groups()[%d] frames = %#v; want %#v", i, got.Err.StackTrace(), group.Err.StackTrace())
+		}
+	}
+	// Decoded groups keep counting by fingerprints of original errors.
+	if count := decoded.Add(once); count != 2 {
+		t.Errorf("decoded.Add(once) = %#v; want %#v", count, 2)
+	}
+
+	if err := decoded.UnmarshalBinary(nil); err == nil {
+		t.Errorf("decoded.UnmarshalBinary(nil) error = nil; want error")
+	}
+	if len(decoded.Groups()) != len(groups) {
+		t.Errorf("len(decoded.Groups()) = %d after error; want %d", len(decoded.Groups()), len(groups))
+	}
+}
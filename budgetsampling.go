@@ -0,0 +1,80 @@
+package tracerr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// WithBudgetSampling makes Tracerr capture stack trace of the first k errors
+// of each fingerprint per interval, and only a fraction of the rest,
+// rate is between 0 and 1. So capture overhead of an error failing
+// in a loop is bounded, while errors of new failure sites are never missed.
+//
+// Fingerprint is approximated before capture by type of the error and
+// program counters of the stack, so errors of the same stack match,
+// the same as with Fingerprint. It takes precedence over WithSampling.
+//
+// Use WrapAlways for call sites, where stack trace is always needed.
+func WithBudgetSampling(k int, interval time.Duration, rate float64) Option {
+	return func(t *tracerr) {
+		if rate < 0 {
+			rate = 0
+		}
+		t.budget = &samplingBudget{
+			k:        k,
+			interval: interval,
+			rate:     rate,
+			counts:   map[uint64]int{},
+		}
+	}
+}
+
+// samplingBudget counts errors per fingerprint in the current interval.
+type samplingBudget struct {
+	k        int
+	interval time.Duration
+	rate     float64
+	mutex    sync.Mutex
+	start    time.Time
+	counts   map[uint64]int
+}
+
+// allow reports whether stack trace of err should be captured,
+// extraSkip is the same as in trace.
+func (b *samplingBudget) allow(err error, extraSkip int) bool {
+	key := budgetKey(err, extraSkip)
+	now := time.Now()
+	b.mutex.Lock()
+	// Counts are dropped at the end of interval, so they don't pile up.
+	if now.Sub(b.start) >= b.interval {
+		clear(b.counts)
+		b.start = now
+	}
+	count := b.counts[key]
+	if count < b.k {
+		b.counts[key] = count + 1
+	}
+	b.mutex.Unlock()
+	return count < b.k || rand.Float64() < b.rate
+}
+
+// budgetKey returns hash of type of err and program counters
+// of the caller's stack without resolving them to frames.
+func budgetKey(err error, extraSkip int) uint64 {
+	var pcs [32]uintptr
+	// Skip runtime.Callers, budgetKey and allow.
+	n := runtime.Callers(3+extraSkip, pcs[:])
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%T", Unwrap(err))
+	b := make([]byte, 0, 8*n)
+	for _, pc := range pcs[:n] {
+		b = binary.LittleEndian.AppendUint64(b, uint64(pc))
+	}
+	h.Write(b)
+	return h.Sum64()
+}
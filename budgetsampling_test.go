@@ -0,0 +1,76 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithBudgetSampling(t *testing.T) {
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithBudgetSampling(3, time.Hour, 0),
+	)
+	err := errors.New("some error")
+	wrapFirst := func() tracerr.Error { return tr.Wrap(err) }
+	wrapSecond := func() tracerr.Error { return tr.Wrap(err) }
+
+	captured := 0
+	for i := 0; i < 100; i++ {
+		if len(wrapFirst().StackTrace()) > 0 {
+			captured++
+		}
+	}
+	if captured != 3 {
+		t.Errorf("captured = %#v; want %#v", captured, 3)
+	}
+	// A new failure site has its own budget.
+	if len(wrapSecond().StackTrace()) == 0 {
+		t.Errorf("len(wrapSecond().StackTrace()) = 0; want > 0")
+	}
+	if len(tr.WrapAlways(err).StackTrace()) == 0 {
+		t.Errorf("len(tr.WrapAlways(err).StackTrace()) = 0; want > 0")
+	}
+}
+
+func TestWithBudgetSamplingInterval(t *testing.T) {
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithBudgetSampling(1, 10*time.Millisecond, 0),
+	)
+	err := errors.New("some error")
+	captured := func() int {
+		n := 0
+		for i := 0; i < 10; i++ {
+			if len(tr.Wrap(err).StackTrace()) > 0 {
+				n++
+			}
+		}
+		return n
+	}
+
+	if n := captured(); n != 1 {
+		t.Errorf("captured() = %#v; want %#v", n, 1)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := captured(); n != 1 {
+		t.Errorf("captured() = %#v; want %#v in the next interval", n, 1)
+	}
+}
+
+func TestWithBudgetSamplingRate(t *testing.T) {
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithBudgetSampling(0, time.Hour, 1),
+	)
+	for i := 0; i < 10; i++ {
+		if len(tr.Wrap(errors.New("some error")).StackTrace()) == 0 {
+			t.Fatalf("len(tr.Wrap(err).StackTrace()) = 0; want > 0 with rate 1")
+		}
+	}
+}
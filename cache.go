@@ -0,0 +1,113 @@
+package tracerr
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultSourceCacheEntries is a default maximum number of source files
+// cached for printing.
+const DefaultSourceCacheEntries = 1024
+
+// DefaultSourceCacheBytes is a default maximum total size of source files
+// cached for printing.
+const DefaultSourceCacheBytes = 64 << 20
+
+// SetSourceCacheLimits sets maximum number of source files and their total
+// size in bytes, which are cached for printing.
+// The least recently used files are evicted first, zero means no limit.
+func SetSourceCacheLimits(maxEntries, maxBytes int) {
+	cache.setLimits(maxEntries, maxBytes)
+}
+
+// ClearSourceCache removes all source files cached for printing.
+func ClearSourceCache() {
+	cache.clear()
+}
+
+// sourceCache is a concurrency safe LRU cache of source files,
+// such as their lines.
+type sourceCache[V any] struct {
+	mutex      sync.Mutex
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	order      *list.List
+	items      map[string]*list.Element
+}
+
+type sourceCacheEntry[V any] struct {
+	path  string
+	value V
+	size  int
+}
+
+func newSourceCache[V any](maxEntries, maxBytes int) *sourceCache[V] {
+	return &sourceCache[V]{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		items:      map[string]*list.Element{},
+	}
+}
+
+func (c *sourceCache[V]) get(path string) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	item, ok := c.items[path]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(item)
+	return item.Value.(*sourceCacheEntry[V]).value, true
+}
+
+func (c *sourceCache[V]) add(path string, value V, size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if item, ok := c.items[path]; ok {
+		c.remove(item)
+	}
+	// File, which is bigger than the whole cache, is never cached.
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return
+	}
+	c.items[path] = c.order.PushFront(&sourceCacheEntry[V]{
+		path:  path,
+		value: value,
+		size:  size,
+	})
+	c.bytes += size
+	c.evict()
+}
+
+func (c *sourceCache[V]) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.order.Init()
+	c.items = map[string]*list.Element{}
+	c.bytes = 0
+}
+
+func (c *sourceCache[V]) setLimits(maxEntries, maxBytes int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.maxEntries = maxEntries
+	c.maxBytes = maxBytes
+	c.evict()
+}
+
+func (c *sourceCache[V]) evict() {
+	for c.order.Len() > 0 &&
+		((c.maxEntries > 0 && c.order.Len() > c.maxEntries) ||
+			(c.maxBytes > 0 && c.bytes > c.maxBytes)) {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *sourceCache[V]) remove(item *list.Element) {
+	entry := c.order.Remove(item).(*sourceCacheEntry[V])
+	delete(c.items, entry.path)
+	c.bytes -= entry.size
+}
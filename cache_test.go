@@ -0,0 +1,67 @@
+package tracerr_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type countingSourceProvider struct {
+	mutex sync.Mutex
+	reads map[string]int
+}
+
+func (p *countingSourceProvider) ReadFile(path string) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.reads[path]++
+	return []byte("package main\nfunc main() {}\n"), nil
+}
+
+func TestSourceCache(t *testing.T) {
+	provider := &countingSourceProvider{reads: map[string]int{}}
+	tracerr.SetSourceProvider(provider)
+	tracerr.SetSourceCacheLimits(2, 0)
+	defer tracerr.SetSourceProvider(nil)
+	defer tracerr.SetSourceCacheLimits(tracerr.DefaultSourceCacheEntries, tracerr.DefaultSourceCacheBytes)
+
+	render := func(paths ...string) {
+		frames := make([]tracerr.Frame, 0, len(paths))
+		for _, path := range paths {
+			frames = append(frames, tracerr.Frame{Func: "main.main", Line: 2, Path: path})
+		}
+		tracerr.SprintSource(tracerr.CustomError(errors.New("some error"), frames))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			render("a.go")
+		}()
+	}
+	wg.Wait()
+	render("a.go", "b.go")
+	// The least recently used "a.go" is evicted.
+	render("c.go", "a.go")
+	tracerr.ClearSourceCache()
+	render("c.go")
+
+	expected := map[string]int{
+		"a.go": 2,
+		"b.go": 1,
+		"c.go": 2,
+	}
+	for path, count := range expected {
+		// Concurrent first reads may race, so "a.go" is read at least twice.
+		if provider.reads[path] < count || (path != "a.go" && provider.reads[path] != count) {
+			t.Errorf(
+				"provider.reads[%#v] = %#v; want %#v",
+				path, provider.reads[path], count,
+			)
+		}
+	}
+}
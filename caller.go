@@ -0,0 +1,51 @@
+package tracerr
+
+import (
+	"runtime"
+)
+
+// Caller returns a frame of the caller without creating an error,
+// e.g. to log a location or to build a custom error type.
+// Skip 0 means the function calling Caller, 1 means its caller and so on.
+// It returns zero Frame if there is no such frame.
+func Caller(skip int) Frame {
+	frames := callers(skip+1, 1)
+	if len(frames) == 0 {
+		return Frame{}
+	}
+	return frames[0]
+}
+
+// Callers returns at most max frames of the call stack without creating an error
+// by the same rules as Caller, zero max means no limit.
+func Callers(skip, max int) []Frame {
+	return callers(skip+1, max)
+}
+
+// callers returns frames of the call stack above its caller,
+// skip is a number of frames of the caller to skip.
+func callers(skip, max int) []Frame {
+	size := max
+	if max <= 0 {
+		size = DefaultFrameCapacity
+	}
+	var pcs []uintptr
+	for {
+		pcs = make([]uintptr, size)
+		// Skip runtime.Callers and callers itself.
+		n := runtime.Callers(skip+2, pcs)
+		if n < size || max > 0 {
+			pcs = pcs[:n]
+			break
+		}
+		size *= 2
+	}
+	frames := make([]Frame, 0, len(pcs))
+	for frame := range pcFrames(pcs, false) {
+		if max > 0 && len(frames) == max {
+			break
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
@@ -0,0 +1,88 @@
+package tracerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+//go:noinline
+func callerOfHelper() tracerr.Frame {
+	return tracerr.Caller(1)
+}
+
+func TestCaller(t *testing.T) {
+	frame := tracerr.Caller(0)
+	if frame.Func != "github.com/kadaan/tracerr_test.TestCaller" || !strings.HasSuffix(frame.Path, "caller_test.go") {
+		t.Errorf("tracerr.Caller(0) = %v; want TestCaller", frame)
+	}
+	if frame.Line != 16 {
+		t.Errorf("tracerr.Caller(0).Line = %d; want 16", frame.Line)
+	}
+	if frame := callerOfHelper(); frame.Func != "github.com/kadaan/tracerr_test.TestCaller" {
+		t.Errorf("tracerr.Caller(1) = %v; want TestCaller", frame)
+	}
+	if frame := tracerr.Caller(1000); frame != (tracerr.Frame{}) {
+		t.Errorf("tracerr.Caller(1000) = %v; want zero frame", frame)
+	}
+}
+
+func TestCallers(t *testing.T) {
+	frames := tracerr.Callers(0, 2)
+	if len(frames) != 2 || frames[0].Name != "TestCallers" || frames[1].Func != "testing.tRunner" {
+		t.Errorf("tracerr.Callers(0, 2) = %v; want TestCallers and testing.tRunner", frames)
+	}
+	all := tracerr.Callers(0, 0)
+	if len(all) < 3 || all[len(all)-1].Func != "runtime.goexit" {
+		t.Errorf("tracerr.Callers(0, 0) = %v; want the whole stack", all)
+	}
+	if frames := tracerr.Callers(1, 0); len(frames) != len(all)-1 || frames[0] != all[1] {
+		t.Errorf("tracerr.Callers(1, 0) = %v; want stack without TestCallers", frames)
+	}
+}
+
+//go:noinline
+func dereferenceNil(p *int) int {
+	return *p
+}
+
+func capturePanic(fn func()) (frames []tracerr.Frame) {
+	defer func() {
+		if r := recover(); r != nil {
+			frames = tracerr.CapturePanicStack()
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestCapturePanicStack(t *testing.T) {
+	cases := []struct {
+		Fn       func()
+		Expected string
+	}{
+		{
+			Fn:       panicWithCode,
+			Expected: "panicWithCode",
+		},
+		{
+			Fn:       func() { dereferenceNil(nil) },
+			Expected: "dereferenceNil",
+		},
+	}
+
+	for i, c := range cases {
+		frames := capturePanic(c.Fn)
+		if len(frames) == 0 || frames[0].Name != c.Expected {
+			t.Errorf("case #%d: tracerr.CapturePanicStack() = %#v; want to start at %s", i, frames, c.Expected)
+		}
+	}
+}
+
+func TestCapturePanicStackNoPanic(t *testing.T) {
+	frames := tracerr.CapturePanicStack()
+	if len(frames) == 0 || frames[0].Name != "TestCapturePanicStackNoPanic" {
+		t.Errorf("tracerr.CapturePanicStack() = %#v; want to start at the caller", frames)
+	}
+}
@@ -0,0 +1,57 @@
+package tracerr
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// adaptiveCapacityWeight is a weight of the last observed depth in EWMA.
+const adaptiveCapacityWeight = 0.1
+
+// adaptiveCapacityHeadroom is a factor of the average depth
+// to allocate, so deeper than average stacks rarely grow the slice.
+const adaptiveCapacityHeadroom = 1.25
+
+// WithAdaptiveCapacity makes Tracerr size the initial allocation of frames
+// by depths of stacks it captured before, instead of a fixed frameCapacity
+// passed to NewTracerr, which is only the starting estimate.
+//
+// The depth is tracked as exponentially weighted moving average,
+// and a quarter more is allocated, so a too large guess doesn't waste
+// memory of every error, and a too small one doesn't grow the slice
+// on every capture.
+func WithAdaptiveCapacity() Option {
+	return func(t *tracerr) {
+		t.capacity = newAdaptiveCapacity(t.frameCapacity)
+	}
+}
+
+// adaptiveCapacity is a concurrency safe EWMA of captured stack depths.
+type adaptiveCapacity struct {
+	// average is math.Float64bits of the average depth.
+	average atomic.Uint64
+}
+
+func newAdaptiveCapacity(initial int) *adaptiveCapacity {
+	c := &adaptiveCapacity{}
+	c.average.Store(math.Float64bits(float64(initial) / adaptiveCapacityHeadroom))
+	return c
+}
+
+// get returns capacity to allocate for frames.
+func (c *adaptiveCapacity) get() int {
+	average := math.Float64frombits(c.average.Load())
+	return max(1, int(math.Ceil(average*adaptiveCapacityHeadroom)))
+}
+
+// observe adds depth of a captured stack to the average.
+func (c *adaptiveCapacity) observe(depth int) {
+	for {
+		old := c.average.Load()
+		average := math.Float64frombits(old)
+		average += adaptiveCapacityWeight * (float64(depth) - average)
+		if c.average.CompareAndSwap(old, math.Float64bits(average)) {
+			return
+		}
+	}
+}
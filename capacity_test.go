@@ -0,0 +1,31 @@
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithAdaptiveCapacity(t *testing.T) {
+	cases := []struct {
+		Capacity int
+	}{
+		{Capacity: 200},
+		{Capacity: 1},
+	}
+	for i, c := range cases {
+		tr := tracerr.NewTracerr(c.Capacity, tracerr.DefaultFrameSkipCount, tracerr.WithAdaptiveCapacity())
+		first := tracerr.RawFrames(tr.New("some error"))
+		if cap(first) < c.Capacity {
+			t.Errorf("case #%d: cap(first frames) = %d; want at least %d", i, cap(first), c.Capacity)
+		}
+		var frames []tracerr.Frame
+		for j := 0; j < 100; j++ {
+			frames = tracerr.RawFrames(tr.New("some error"))
+		}
+		depth := len(frames)
+		if cap(frames) < depth || cap(frames) > depth*2 {
+			t.Errorf("case #%d: cap(frames) = %d; want close to depth %d", i, cap(frames), depth)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !tracerr_notrace
+
+package tracerr
+
+// captureBuilt is false if capture is turned off by tracerr_notrace build tag.
+const captureBuilt = true
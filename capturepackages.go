@@ -0,0 +1,88 @@
+package tracerr
+
+import (
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// WithCapturePackages makes Tracerr capture stack trace only for errors
+// created or wrapped in packages matching patterns, the rest of errors
+// have empty stack trace.
+//
+// Patterns follow the rules of go list, "..." matches any string,
+// e.g. "github.com/mycorp/..." matches the package and its subpackages.
+func WithCapturePackages(patterns ...string) Option {
+	return func(t *tracerr) {
+		t.capturePackages = append(t.capturePackages, compilePackagePatterns(patterns)...)
+	}
+}
+
+// WithoutCapturePackages makes Tracerr skip capture of stack trace
+// for errors created or wrapped in packages matching patterns,
+// even if they match WithCapturePackages.
+// It's useful for generated code producing a lot of low-value errors.
+//
+// Patterns follow the same rules as in WithCapturePackages.
+func WithoutCapturePackages(patterns ...string) Option {
+	return func(t *tracerr) {
+		t.skipCapturePackages = append(t.skipCapturePackages, compilePackagePatterns(patterns)...)
+	}
+}
+
+func compilePackagePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\.\.\.`, `.*`)
+		// Like in go list, "x/..." matches "x" as well.
+		if strings.HasSuffix(expr, "/.*") {
+			expr = strings.TrimSuffix(expr, "/.*") + "(/.*)?"
+		}
+		compiled = append(compiled, regexp.MustCompile("^"+expr+"$"))
+	}
+	return compiled
+}
+
+// capturePackage reports whether stack trace should be captured
+// for the caller, extraSkip is the same as in trace.
+func (t *tracerr) capturePackage(extraSkip int) bool {
+	if len(t.capturePackages) == 0 && len(t.skipCapturePackages) == 0 {
+		return true
+	}
+	pkg := t.callerPackage(extraSkip)
+	for _, pattern := range t.skipCapturePackages {
+		if pattern.MatchString(pkg) {
+			return false
+		}
+	}
+	if len(t.capturePackages) == 0 {
+		return true
+	}
+	for _, pattern := range t.capturePackages {
+		if pattern.MatchString(pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// callerPackage returns package of the first frame of stack trace
+// captured by trace without capturing it.
+func (t *tracerr) callerPackage(extraSkip int) string {
+	var pcs [16]uintptr
+	// Skip runtime.Callers, callerPackage and capturePackage.
+	n := runtime.Callers(t.stackFrameSkipCount+2, pcs[:])
+	for _, pc := range pcs[:n] {
+		// Return address points to the next instruction after call.
+		pkg, _, _ := splitFuncName(funcName(pc - 1))
+		if pkg == packageName || t.skipPackages[pkg] {
+			continue
+		}
+		if extraSkip > 0 {
+			extraSkip--
+			continue
+		}
+		return pkg
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestCapturePackages(t *testing.T) {
+	cases := []struct {
+		Options  []tracerr.Option
+		Captured bool
+	}{
+		{
+			Options:  nil,
+			Captured: true,
+		},
+		{
+			Options:  []tracerr.Option{tracerr.WithCapturePackages("github.com/kadaan/...")},
+			Captured: true,
+		},
+		{
+			Options:  []tracerr.Option{tracerr.WithCapturePackages("github.com/kadaan/tracerr_test")},
+			Captured: true,
+		},
+		{
+			Options:  []tracerr.Option{tracerr.WithCapturePackages("github.com/mycorp/...")},
+			Captured: false,
+		},
+		{
+			Options:  []tracerr.Option{tracerr.WithCapturePackages("github.com/kadaan/tracerr")},
+			Captured: false,
+		},
+		{
+			Options:  []tracerr.Option{tracerr.WithoutCapturePackages("github.com/.../tracerr_test")},
+			Captured: false,
+		},
+		{
+			Options: []tracerr.Option{
+				tracerr.WithCapturePackages("github.com/kadaan/..."),
+				tracerr.WithoutCapturePackages("github.com/kadaan/tracerr_test/..."),
+			},
+			Captured: false,
+		},
+	}
+
+	for i, c := range cases {
+		tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, c.Options...)
+		for j, err := range []tracerr.Error{tr.New("some error"), tr.Wrap(errors.New("some error"))} {
+			captured := len(err.StackTrace()) > 0
+			if captured != c.Captured {
+				t.Errorf("case #%d.%d: captured = %v; want %v", i, j, captured, c.Captured)
+			}
+		}
+		if len(tr.WrapAlways(errors.New("some error")).StackTrace()) == 0 {
+			t.Errorf("case #%d: tr.WrapAlways(err) has no stack trace", i)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tracerr
+
+import "sync/atomic"
+
+// CaptureStats are counters of work done by tracerr since the program start,
+// so its overhead can be monitored, see ReadCaptureStats.
+// Counters are summed over all Tracerr instances and Recorders.
+type CaptureStats struct {
+	// Captures is a number of captured stack traces.
+	Captures uint64
+	// Sampled is a number of errors, which stack trace capture
+	// was skipped by sampling, see WithSampling and WithBudgetSampling.
+	Sampled uint64
+	// CacheHits is a number of captures, which frames were found
+	// in cache, see WithFrameCache.
+	CacheHits uint64
+	// AverageDepth is an average number of frames of captured stack traces.
+	AverageDepth float64
+	// RecorderBytes is estimated memory of errors retained by Recorders,
+	// see WithMaxBytes.
+	RecorderBytes int64
+	// InternedStrings is a number of function names and paths
+	// in the process-wide intern table, which never evicts them.
+	InternedStrings int64
+}
+
+// captureStats are counters returned by ReadCaptureStats.
+var captureStats struct {
+	captures      atomic.Uint64
+	sampled       atomic.Uint64
+	cacheHits     atomic.Uint64
+	frames        atomic.Uint64
+	recorderBytes atomic.Int64
+}
+
+// ReadCaptureStats returns counters of work done by tracerr,
+// e.g. to export them as metrics.
+func ReadCaptureStats() CaptureStats {
+	stats := CaptureStats{
+		Captures:        captureStats.captures.Load(),
+		Sampled:         captureStats.sampled.Load(),
+		CacheHits:       captureStats.cacheHits.Load(),
+		RecorderBytes:   captureStats.recorderBytes.Load(),
+		InternedStrings: internSize.Load(),
+	}
+	if stats.Captures > 0 {
+		stats.AverageDepth = float64(captureStats.frames.Load()) / float64(stats.Captures)
+	}
+	return stats
+}
+
+// countCapture counts captured stack trace of e.
+func countCapture(e Error) {
+	depth := 0
+	if d, ok := e.(*errorData); ok && d.pcs != nil {
+		depth = len(d.pcs)
+	} else {
+		depth = len(RawFrames(e))
+	}
+	captureStats.captures.Add(1)
+	captureStats.frames.Add(uint64(depth))
+}
@@ -0,0 +1,44 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestReadCaptureStats(t *testing.T) {
+	sampled := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithSampling(0))
+	cached := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithFrameCache())
+	recorder := tracerr.NewRecorder(2)
+	recorded := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithRecorder(recorder))
+
+	before := tracerr.ReadCaptureStats()
+	for i := 0; i < 3; i++ {
+		sampled.New("some error")
+		cached.Wrap(errors.New("some error"))
+	}
+	recorded.New("some error")
+	after := tracerr.ReadCaptureStats()
+
+	if captures := after.Captures - before.Captures; captures != 4 {
+		t.Errorf("Captures delta = %#v; want %#v", captures, 4)
+	}
+	if skipped := after.Sampled - before.Sampled; skipped != 3 {
+		t.Errorf("Sampled delta = %#v; want %#v", skipped, 3)
+	}
+	if hits := after.CacheHits - before.CacheHits; hits != 2 {
+		t.Errorf("CacheHits delta = %#v; want %#v", hits, 2)
+	}
+	if after.AverageDepth <= 0 {
+		t.Errorf("AverageDepth = %#v; want positive", after.AverageDepth)
+	}
+	if retained := after.RecorderBytes - before.RecorderBytes; retained <= 0 {
+		t.Errorf("RecorderBytes delta = %#v; want positive", retained)
+	}
+
+	recorder.Reset()
+	if retained := tracerr.ReadCaptureStats().RecorderBytes; retained != before.RecorderBytes {
+		t.Errorf("RecorderBytes after Reset = %#v; want %#v", retained, before.RecorderBytes)
+	}
+}
@@ -0,0 +1,49 @@
+package tracerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// causeError is a cancellation error with its cause.
+type causeError struct {
+	err   error
+	cause error
+}
+
+func (e *causeError) Error() string {
+	return fmt.Sprintf("%s (cause: %s)", e.err.Error(), e.cause.Error())
+}
+
+// Unwrap returns both cancellation error and its cause,
+// so errors.Is and errors.As see either of them.
+func (e *causeError) Unwrap() []error {
+	return []error{e.err, e.cause}
+}
+
+// cancelCause returns cause of ctx, if err is caused by cancellation of ctx
+// and the cause is different from err, otherwise it returns nil.
+func cancelCause(ctx context.Context, err error) error {
+	if ctx == nil || !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	cause := context.Cause(ctx)
+	if cause == nil || errors.Is(err, cause) {
+		return nil
+	}
+	return cause
+}
+
+// withCause returns e with cause recorded and its stack trace merged.
+func withCause(e Error, cause error) Error {
+	frames := RawFrames(e)
+	if c, ok := AsError(cause); ok && len(RawFrames(c)) > 0 {
+		frames = mergeFrames(frames, RawFrames(c))
+	}
+	d, ok := e.(*errorData)
+	if !ok {
+		return CustomError(&causeError{err: e, cause: cause}, frames)
+	}
+	return d.with(&causeError{err: d.err, cause: cause}, frames)
+}
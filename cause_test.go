@@ -0,0 +1,73 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+var errShutdown = errors.New("shutting down")
+
+//go:noinline
+func cancelWithTracedCause(cancel context.CancelCauseFunc) {
+	cancel(tracerr.Wrap(errShutdown))
+}
+
+func TestWrapContextCause(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancelWithTracedCause(cancel)
+
+	err := tracerr.WrapContext(ctx, ctx.Err())
+	if err.Error() != "context canceled (cause: shutting down)" {
+		t.Errorf("err.Error() = %#v; want message with cause", err.Error())
+	}
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errShutdown) {
+		t.Errorf("errors.Is(err, ...) = false; want both context.Canceled and cause")
+	}
+	frames := err.StackTrace()
+	if len(frames) == 0 || frames[0].Name != "TestWrapContextCause" {
+		t.Fatalf("err.StackTrace() = %#v; want to start at the caller", frames)
+	}
+	marker := -1
+	for i, frame := range frames {
+		if frame.IsMarker() {
+			marker = i
+			break
+		}
+	}
+	if marker < 0 || marker+1 >= len(frames) || frames[marker+1].Name != "cancelWithTracedCause" {
+		t.Errorf("err.StackTrace() = %#v; want stack trace of cause after marker", frames)
+	}
+}
+
+func TestWrapContextUntracedCause(t *testing.T) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Nanosecond, errShutdown)
+	defer cancel()
+	<-ctx.Done()
+
+	err := tracerr.WrapContext(ctx, ctx.Err())
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errShutdown) {
+		t.Errorf("errors.Is(err, ...) = false; want both context.DeadlineExceeded and cause")
+	}
+	for _, frame := range err.StackTrace() {
+		if frame.IsMarker() {
+			t.Errorf("err.StackTrace() has marker; want no merged frames for untraced cause")
+		}
+	}
+}
+
+func TestWrapContextNoCause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := tracerr.WrapContext(ctx, ctx.Err())
+	if err.Error() != "context canceled" {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), "context canceled")
+	}
+	other := tracerr.WrapContext(ctx, errShutdown)
+	if other.Error() != "shutting down" {
+		t.Errorf("other.Error() = %#v; want %#v", other.Error(), "shutting down")
+	}
+}
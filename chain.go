@@ -0,0 +1,73 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintChain prints every error in the unwrap chain of err,
+// each with its message and stack trace if it has one, so it's visible
+// which layer added which annotation. Branches of errors wrapping
+// multiple errors are walked in order.
+//
+// Traced error and the error it wraps are shown as a single layer,
+// stack traces recorded by other libraries are shown as well.
+//
+// Output goes to os.Stdout, see SetOutput and SetPrinter to change it.
+func PrintChain(err error) {
+	printOutput(err, func(w io.Writer) {
+		FprintChain(w, err)
+	})
+}
+
+// FprintChain writes error output to w by the same rules as PrintChain.
+func FprintChain(w io.Writer, err error) (int, error) {
+	o := currentPrintOptions()
+	return fmt.Fprintln(w, sprintChain(err, o.outputWidth(w)))
+}
+
+// SprintChain returns error output by the same rules as PrintChain.
+func SprintChain(err error) string {
+	return sprintChain(err, 0)
+}
+
+func sprintChain(err error, width int) string {
+	if err == nil {
+		return ""
+	}
+	layers := chainLayers(err, nil)
+	parts := make([]string, 0, len(layers))
+	for i, layer := range layers {
+		header := fmt.Sprintf("--- %d of %d: %T ---", i+1, len(layers), layer.typ)
+		parts = append(parts, header+"\n"+sprint(layer.err, []int{0}, false, width))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// chainLayer is a single error in the unwrap chain.
+type chainLayer struct {
+	// err is printed, it's traced if the error has stack trace.
+	err error
+	// typ is the error shown as a type of the layer.
+	typ error
+}
+
+// chainLayers appends layers of err and its wrapped errors to layers.
+func chainLayers(err error, layers []chainLayer) []chainLayer {
+	layer := chainLayer{err: err, typ: err}
+	next := unwrapAll(err)
+	if e, ok := err.(Error); ok {
+		if inner := e.Unwrap(); inner != nil && inner.Error() == e.Error() {
+			layer.typ = inner
+			next = unwrapAll(inner)
+		}
+	} else if frames, ok := errorFrames(err); ok {
+		layer.err = CustomError(err, frames)
+	}
+	layers = append(layers, layer)
+	for _, wrapped := range next {
+		layers = chainLayers(wrapped, layers)
+	}
+	return layers
+}
@@ -0,0 +1,75 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintChain(t *testing.T) {
+	read := tracerr.CustomError(fmt.Errorf("read config: %w", io.EOF), []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 10),
+		tracerr.NewFrame("main.main", "/src/main.go", 20),
+	})
+	cases := []struct {
+		Err      error
+		Expected string
+	}{
+		{
+			Err:      nil,
+			Expected: "",
+		},
+		{
+			Err: io.EOF,
+			Expected: "--- 1 of 1: *errors.errorString ---\n" +
+				"EOF",
+		},
+		{
+			Err: fmt.Errorf("load: %w", read),
+			Expected: "--- 1 of 3: *fmt.wrapError ---\n" +
+				"load: read config: EOF\n" +
+				"--- 2 of 3: *fmt.wrapError ---\n" +
+				"read config: EOF\n" +
+				"/src/main.go:10 main.read()\n" +
+				"/src/main.go:20 main.main()\n" +
+				"--- 3 of 3: *errors.errorString ---\n" +
+				"EOF",
+		},
+		{
+			Err: errors.Join(read, errors.New("other")),
+			Expected: "--- 1 of 4: *errors.joinError ---\n" +
+				"read config: EOF\nother\n" +
+				"--- 2 of 4: *fmt.wrapError ---\n" +
+				"read config: EOF\n" +
+				"/src/main.go:10 main.read()\n" +
+				"/src/main.go:20 main.main()\n" +
+				"--- 3 of 4: *errors.errorString ---\n" +
+				"EOF\n" +
+				"--- 4 of 4: *errors.errorString ---\n" +
+				"other",
+		},
+	}
+
+	for i, c := range cases {
+		output := tracerr.SprintChain(c.Err)
+		if output != c.Expected {
+			t.Errorf("case #%d: tracerr.SprintChain(err) = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
+
+func TestSprintChainForeignFrames(t *testing.T) {
+	err := fmt.Errorf("context: %w", &ztrueError{})
+	expected := "--- 1 of 2: *fmt.wrapError ---\n" +
+		"context: ztrue error\n" +
+		"--- 2 of 2: *tracerr_test.ztrueError ---\n" +
+		"ztrue error\n" +
+		"/src/main.go:12 main.read()\n" +
+		"/src/main.go:5 main.main()"
+	if output := tracerr.SprintChain(err); output != expected {
+		t.Errorf("tracerr.SprintChain(err) = %#v; want %#v", output, expected)
+	}
+}
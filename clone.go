@@ -0,0 +1,54 @@
+package tracerr
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns an independent copy of err with its own stack trace
+// and attributes, such as environment snapshot and fields,
+// so a long-lived component can retain it, while frames of err
+// are modified, e.g. by CustomError callers reusing a slice.
+// Wrapped error is shared, since errors are immutable by convention.
+//
+// Error without stack trace is returned with an empty one,
+// lazily captured frames are copied unresolved.
+// It returns nil if err is nil, the same as Wrap.
+func Clone(err error) Error {
+	if isNilError(err) {
+		return nil
+	}
+	e, ok := err.(Error)
+	if !ok {
+		return &errorData{
+			err:    err,
+			frames: []Frame{},
+		}
+	}
+	d, ok := e.(*errorData)
+	if !ok {
+		return &errorData{
+			err:    e,
+			frames: e.StackTrace(),
+		}
+	}
+	if d.pcs != nil && !d.resolved() {
+		return &errorData{
+			err:             d.err,
+			pcs:             slices.Clone(d.pcs),
+			trimEntryPoints: d.trimEntryPoints,
+			env:             d.env.clone(),
+			stats:           d.stats.clone(),
+			trace:           d.trace,
+			fields:          maps.Clone(d.fields),
+			source:          d.source,
+			fingerprint:     d.fingerprint,
+			goroutine:       d.goroutine,
+		}
+	}
+	c := d.with(d.err, d.StackTrace())
+	c.env = d.env.clone()
+	c.stats = d.stats.clone()
+	c.fields = maps.Clone(d.fields)
+	return c
+}
@@ -0,0 +1,45 @@
+package tracerr_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestClone(t *testing.T) {
+	frames := diffFrames("read", "main")
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	clone := tracerr.Clone(err)
+	frames[0].Line = 99
+	if clone.Error() != "some error" {
+		t.Errorf("clone.Error() = %#v; want %#v", clone.Error(), "some error")
+	}
+	if !reflect.DeepEqual(clone.StackTrace(), diffFrames("read", "main")) {
+		t.Errorf("clone.StackTrace() = %#v; want frames before modification", clone.StackTrace())
+	}
+	if clone.Unwrap() != err.Unwrap() {
+		t.Errorf("clone.Unwrap() = %#v; want %#v", clone.Unwrap(), err.Unwrap())
+	}
+}
+
+func TestCloneLazy(t *testing.T) {
+	lazy := tracerr.NewTracerr(10, 3, tracerr.WithLazyFrames())
+	err := lazy.New("some error")
+	clone := tracerr.Clone(err)
+	if !reflect.DeepEqual(clone.StackTrace(), err.StackTrace()) {
+		t.Errorf("clone.StackTrace() = %#v; want %#v", clone.StackTrace(), err.StackTrace())
+	}
+}
+
+func TestCloneNotTraced(t *testing.T) {
+	if clone := tracerr.Clone(nil); clone != nil {
+		t.Errorf("tracerr.Clone(nil) = %#v; want nil", clone)
+	}
+	base := errors.New("some error")
+	clone := tracerr.Clone(base)
+	if clone.Unwrap() != base || len(clone.StackTrace()) != 0 {
+		t.Errorf("tracerr.Clone(err) = %#v; want err with empty stack trace", clone)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/kadaan/tracerr/cmd
+
+go 1.23
+
+require (
+	github.com/kadaan/tracerr v0.4.0
+	github.com/kadaan/tracerr/tracerrcheck v0.4.0
+	github.com/kadaan/tracerr/tracerrpb v0.4.0
+	github.com/logrusorgru/aurora v0.0.0-20181002194514-a7b3b318ed4e
+	golang.org/x/tools v0.29.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/mod v0.22.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
+)
@@ -0,0 +1,318 @@
+// Command tracerr-diff compares errors collected from two builds,
+// e.g. stable and canary, and reports regressions of the new one:
+// fingerprints, which the base build has no errors of, and fingerprints,
+// whose share of all errors grew at least by -ratio times.
+// Shares rather than counts are compared, so builds serving
+// different traffic are comparable.
+//
+// Fingerprints hash line numbers, which differ between builds, so errors
+// are matched by frames of stack traces without line numbers, or by
+// messages if inputs have no frames, and printed with fingerprints
+// of the new build.
+//
+// Inputs are files in any of these forms:
+//
+//   - JSON or binary encoding of tracerr.Aggregator
+//   - JSON served by tracerr.Recorder, each error counts once
+//   - JSON of "tracerr.errors" expvar, see tracerr.PublishExpvar,
+//     alone or as a part of /debug/vars
+//
+// Every regression is written as a line, followed by lines
+// of fingerprints, whose share decreased or which vanished
+// from the new build, if -decreased is set.
+// Exit code is 1 if there are regressions, so it can gate deployments,
+// and 2 for invalid usage or inputs.
+//
+// Usage:
+//
+//	tracerr-diff [flags] base new
+//
+// Flags:
+//
+//	-ratio      minimal growth of share of errors reported (default 2)
+//	-min-count  minimal count of errors of the new build reported (default 1)
+//	-decreased  report fingerprints, whose share decreased by -ratio
+//	            or which vanished, as well
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kadaan/tracerr"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tracerr-diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ratio := flags.Float64("ratio", 2, "minimal growth of share of errors reported")
+	minCount := flags.Int("min-count", 1, "minimal count of errors of the new build reported")
+	decreased := flags.Bool("decreased", false, "report fingerprints, whose share decreased by -ratio or which vanished, as well")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 || *ratio <= 1 {
+		fmt.Fprintln(stderr, "usage: tracerr-diff [flags] base new")
+		return 2
+	}
+
+	base, err := readGroups(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tracerr-diff: %v\n", err)
+		return 2
+	}
+	head, err := readGroups(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "tracerr-diff: %v\n", err)
+		return 2
+	}
+	changes := compare(base, head, *ratio, *minCount)
+	regressions := 0
+	for _, c := range changes {
+		if c.kind == decrease && !*decreased {
+			continue
+		}
+		if c.kind != decrease {
+			regressions++
+		}
+		fmt.Fprintln(stdout, c)
+	}
+	if regressions > 0 {
+		return 1
+	}
+	return 0
+}
+
+// group is a number of errors with the same key, see groupKey.
+type group struct {
+	fingerprint string
+	count       int
+	message     string
+}
+
+// groups are groups of a build by key.
+type groups map[string]*group
+
+func (g groups) add(fingerprint string, count int, message string, frames []string) {
+	key := groupKey(fingerprint, message, frames)
+	if existing, ok := g[key]; ok {
+		existing.count += count
+		return
+	}
+	g[key] = &group{fingerprint: fingerprint, count: count, message: message}
+}
+
+// frameLine matches line number of a frame formatted as "path:line func()".
+var frameLine = regexp.MustCompile(`:\d+( |$)`)
+
+// groupKey returns a key matching errors of different builds:
+// frames without line numbers, message if there are no frames,
+// or fingerprint if there is neither.
+func groupKey(fingerprint, message string, frames []string) string {
+	if len(frames) > 0 {
+		keys := make([]string, len(frames))
+		for i, frame := range frames {
+			keys[i] = frameLine.ReplaceAllString(frame, "$1")
+		}
+		return "frames:" + strings.Join(keys, "\n")
+	}
+	if message != "" {
+		return "message:" + message
+	}
+	return "fingerprint:" + fingerprint
+}
+
+// treeFrames returns the first frames of node or its children.
+func treeFrames(node *tracerr.TreeNode) []string {
+	if node == nil {
+		return nil
+	}
+	if len(node.Frames) > 0 {
+		return node.Frames
+	}
+	for _, child := range node.Children {
+		if frames := treeFrames(child); len(frames) > 0 {
+			return frames
+		}
+	}
+	return nil
+}
+
+func (g groups) total() int {
+	total := 0
+	for _, group := range g {
+		total += group.count
+	}
+	return total
+}
+
+// groupJSON is a group of Aggregator, an error of Recorder,
+// or a value of expvar, which is keyed by fingerprint.
+type groupJSON struct {
+	Fingerprint string `json:"fingerprint"`
+	// Count is nil for errors of Recorder.
+	Count *int `json:"count"`
+	// Message is a message of expvar.
+	Message string            `json:"message"`
+	Error   *tracerr.TreeNode `json:"error"`
+}
+
+func (g groupJSON) count() int {
+	if g.Count == nil {
+		return 1
+	}
+	return *g.Count
+}
+
+func (g groupJSON) message() string {
+	if g.Error != nil {
+		return g.Error.Message
+	}
+	return g.Message
+}
+
+// readGroups reads groups from a file in any of supported forms.
+func readGroups(name string) (groups, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	result := groups{}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		var list []groupJSON
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, g := range list {
+			if g.Fingerprint == "" {
+				return nil, fmt.Errorf("%s: error without fingerprint", name)
+			}
+			result.add(g.Fingerprint, g.count(), g.message(), treeFrames(g.Error))
+		}
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		var vars map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &vars); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if published, ok := vars[tracerr.ExpvarName]; ok {
+			trimmed = published
+		}
+		var byFingerprint map[string]groupJSON
+		if err := json.Unmarshal(trimmed, &byFingerprint); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for fingerprint, g := range byFingerprint {
+			result.add(fingerprint, g.count(), g.message(), treeFrames(g.Error))
+		}
+	default:
+		a := tracerr.NewAggregator()
+		if err := a.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("%s: neither JSON nor binary aggregator", name)
+		}
+		for _, g := range a.Groups() {
+			result.add(g.Fingerprint, g.Count, g.Err.Error(), treeFrames(tracerr.Tree(g.Err)))
+		}
+	}
+	return result, nil
+}
+
+// changeKind is a kind of change of a fingerprint.
+type changeKind int
+
+const (
+	added changeKind = iota
+	increase
+	decrease
+)
+
+func (k changeKind) String() string {
+	switch k {
+	case added:
+		return "new"
+	case increase:
+		return "increased"
+	}
+	return "decreased"
+}
+
+// change is a change of a fingerprint between builds.
+type change struct {
+	kind      changeKind
+	group     *group
+	baseCount int
+	// ratio is a ratio of shares of errors of new and base builds.
+	ratio float64
+}
+
+func (c change) String() string {
+	if c.kind == added {
+		return fmt.Sprintf("%s fingerprint=%s count=%d message=%q", c.kind, c.group.fingerprint, c.group.count, c.group.message)
+	}
+	return fmt.Sprintf(
+		"%s fingerprint=%s count=%d base=%d ratio=%.2f message=%q",
+		c.kind, c.group.fingerprint, c.group.count, c.baseCount, c.ratio, c.group.message,
+	)
+}
+
+// compare returns changes of head relative to base: new fingerprints
+// by count, then fingerprints with increased and decreased shares
+// by ratio, all of them with at least minCount errors in head.
+// Fingerprints vanished from head with at least minCount errors in base
+// are decreased with zero ratio.
+func compare(base, head groups, ratio float64, minCount int) []change {
+	baseTotal, headTotal := base.total(), head.total()
+	var changes []change
+	for key, b := range base {
+		if _, ok := head[key]; !ok && b.count >= minCount && b.count > 0 {
+			vanished := &group{fingerprint: b.fingerprint, message: b.message}
+			changes = append(changes, change{kind: decrease, group: vanished, baseCount: b.count})
+		}
+	}
+	for key, g := range head {
+		if g.count < minCount {
+			continue
+		}
+		b, ok := base[key]
+		if !ok || b.count == 0 {
+			changes = append(changes, change{kind: added, group: g})
+			continue
+		}
+		shares := float64(g.count) / float64(headTotal) / (float64(b.count) / float64(baseTotal))
+		switch {
+		case shares >= ratio:
+			changes = append(changes, change{kind: increase, group: g, baseCount: b.count, ratio: shares})
+		case shares <= 1/ratio:
+			changes = append(changes, change{kind: decrease, group: g, baseCount: b.count, ratio: shares})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.kind == added && a.group.count != b.group.count {
+			return a.group.count > b.group.count
+		}
+		if a.kind == increase && a.ratio != b.ratio {
+			return a.ratio > b.ratio
+		}
+		if a.kind == decrease && a.ratio != b.ratio {
+			return a.ratio < b.ratio
+		}
+		return a.group.fingerprint < b.group.fingerprint
+	})
+	return changes
+}
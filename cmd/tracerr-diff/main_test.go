@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func aggregate(counts map[string]int) *tracerr.Aggregator {
+	a := tracerr.NewAggregator()
+	for name, count := range counts {
+		err := tracerr.CustomError(errors.New(name+" failed"), []tracerr.Frame{
+			tracerr.NewFrame("main."+name, "/src/main.go", 10),
+		})
+		for i := 0; i < count; i++ {
+			a.Add(err)
+		}
+	}
+	return a
+}
+
+func TestRun(t *testing.T) {
+	base := aggregate(map[string]int{"read": 10, "write": 10, "close": 80})
+	head := aggregate(map[string]int{"read": 40, "write": 1, "close": 40, "open": 3})
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headBinary, err := head.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	basePath := writeFile(t, "base.json", baseJSON)
+	headPath := writeFile(t, "head.bin", headBinary)
+	fingerprint := func(name string) string {
+		for _, group := range head.Groups() {
+			if group.Err.Error() == name+" failed" {
+				return group.Fingerprint
+			}
+		}
+		return ""
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{basePath, headPath}, &stdout, &stderr)
+	expected := `new fingerprint=` + fingerprint("open") + ` count=3 message="open failed"` + "\n" +
+		`increased fingerprint=` + fingerprint("read") + ` count=40 base=10 ratio=4.76 message="read failed"` + "\n"
+	if code != 1 || stdout.String() != expected {
+		t.Errorf("run() = %#v, %#v; want %#v, %#v, stderr: %s", code, stdout.String(), 1, expected, stderr.String())
+	}
+
+	stdout.Reset()
+	code = run([]string{"-decreased", "-min-count", "4", basePath, headPath}, &stdout, &stderr)
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if code != 1 || len(lines) != 1 || !strings.HasPrefix(lines[0], "increased ") {
+		t.Errorf("run(-decreased -min-count 4) = %#v, %#v; want only increased read", code, stdout.String())
+	}
+
+	stdout.Reset()
+	code = run([]string{"-decreased", headPath, basePath}, &stdout, &stderr)
+	if code != 1 || !strings.Contains(stdout.String(), "decreased fingerprint="+fingerprint("read")) {
+		t.Errorf("run(-decreased) = %#v, %#v; want decreased read", code, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{basePath, basePath}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("run(base, base) = %#v, %#v; want %#v, empty", code, stdout.String(), 0)
+	}
+}
+
+func TestReadGroups(t *testing.T) {
+	cases := []struct {
+		data     string
+		expected map[string]int
+	}{
+		{
+			data:     `[{"fingerprint":"a","error":{"message":"x"}},{"fingerprint":"a","error":{"message":"x"}},{"fingerprint":"b"}]`,
+			expected: map[string]int{"a": 2, "b": 1},
+		},
+		{
+			data:     `{"a":{"count":3,"message":"x"},"b":{"count":1}}`,
+			expected: map[string]int{"a": 3, "b": 1},
+		},
+		{
+			data:     `{"cmdline":["app"],"tracerr.errors":{"a":{"count":5}}}`,
+			expected: map[string]int{"a": 5},
+		},
+	}
+	for i, c := range cases {
+		g, err := readGroups(writeFile(t, "errors.json", []byte(c.data)))
+		if err != nil {
+			t.Errorf("case #%d: readGroups() error = %v; want nil", i, err)
+			continue
+		}
+		counts := map[string]int{}
+		for _, group := range g {
+			counts[group.fingerprint] = group.count
+		}
+		if !equalCounts(counts, c.expected) {
+			t.Errorf("case #%d: readGroups() = %#v; want %#v", i, counts, c.expected)
+		}
+	}
+}
+
+func TestRunMovedLines(t *testing.T) {
+	newAggregator := func(line int, names ...string) *tracerr.Aggregator {
+		a := tracerr.NewAggregator()
+		for _, name := range names {
+			err := tracerr.CustomError(errors.New(name+" failed"), []tracerr.Frame{
+				tracerr.NewFrame("main."+name, "/src/main.go", line),
+			})
+			for i := 0; i < 10; i++ {
+				a.Add(err)
+			}
+		}
+		return a
+	}
+	base, err := json.Marshal(newAggregator(10, "read", "write", "close"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := json.Marshal(newAggregator(20, "read", "close"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	basePath := writeFile(t, "base.json", base)
+	headPath := writeFile(t, "head.json", head)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{basePath, headPath}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("run(base, head) = %#v, %#v; want %#v, empty", code, stdout.String(), 0)
+	}
+	stdout.Reset()
+	code := run([]string{"-decreased", basePath, headPath}, &stdout, &stderr)
+	expected := `count=0 base=10 ratio=0.00 message="write failed"` + "\n"
+	if code != 0 || strings.Count(stdout.String(), "\n") != 1 || !strings.HasPrefix(stdout.String(), "decreased fingerprint=") || !strings.HasSuffix(stdout.String(), expected) {
+		t.Errorf("run(-decreased, base, head) = %#v, %#v; want %#v, vanished write", code, stdout.String(), 0)
+	}
+}
+
+func equalCounts(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRunInvalid(t *testing.T) {
+	valid := writeFile(t, "valid.json", []byte("[]"))
+	cases := [][]string{
+		nil,
+		{valid},
+		{"-ratio", "1", valid, valid},
+		{"/nonexistent", valid},
+		{valid, writeFile(t, "invalid.json", []byte("[{"))},
+		{valid, writeFile(t, "invalid.bin", []byte("invalid"))},
+		{valid, writeFile(t, "nofingerprint.json", []byte(`[{"count":1}]`))},
+	}
+	for i, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("case #%d: run(%#v) = %#v; want %#v", i, args, code, 2)
+		}
+	}
+}
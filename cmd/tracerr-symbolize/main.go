@@ -0,0 +1,149 @@
+// Command tracerr-symbolize resolves program counters of a stack trace
+// to frames by the unstripped binary of the same build, so production
+// binaries can be built with -ldflags="-s -w" and still be debugged.
+//
+// Input is read from a file or stdin, it's output of tracerr.SprintAddresses:
+// a line per frame with the program counter in the first column.
+// Every address is written as a frame "path:line func()",
+// or as is followed by "?" if the binary has no function at it.
+//
+// Calls inlined by the compiler are not expanded, so an address
+// resolves to its innermost location and the function it's inlined into.
+//
+// Usage:
+//
+//	tracerr-symbolize [flags] binary [file]
+//
+// Flags:
+//
+//	-offset  offset subtracted from addresses, e.g. load address
+//	         of a position independent executable (default 0)
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kadaan/tracerr"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tracerr-symbolize", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	offset := flags.String("offset", "0", "offset subtracted from addresses, e.g. load address of a position independent executable")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		fmt.Fprintln(stderr, "usage: tracerr-symbolize [flags] binary [file]")
+		return 2
+	}
+	delta, err := strconv.ParseUint(*offset, 0, 64)
+	if err != nil {
+		fmt.Fprintf(stderr, "tracerr-symbolize: invalid offset %q\n", *offset)
+		return 2
+	}
+
+	table, err := openTable(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tracerr-symbolize: %v\n", err)
+		return 1
+	}
+	input := stdin
+	if flags.NArg() == 2 {
+		f, err := os.Open(flags.Arg(1))
+		if err != nil {
+			fmt.Fprintf(stderr, "tracerr-symbolize: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	}
+	if err := symbolize(table, input, stdout, delta); err != nil {
+		fmt.Fprintf(stderr, "tracerr-symbolize: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// symbolize writes a frame for every address of input.
+func symbolize(table *gosym.Table, input io.Reader, output io.Writer, offset uint64) error {
+	scanner := bufio.NewScanner(input)
+	w := bufio.NewWriter(output)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		pc, err := strconv.ParseUint(fields[0], 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid address %q", fields[0])
+		}
+		path, line, fn := table.PCToLine(pc - offset)
+		if fn == nil {
+			fmt.Fprintf(w, "%s ?\n", fields[0])
+			continue
+		}
+		fmt.Fprintln(w, tracerr.NewFrame(fn.Name, path, line))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// openTable reads a symbol table of Go binary in ELF or Mach-O format.
+func openTable(name string) (*gosym.Table, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var pclntab []byte
+	var text uint64
+	if f, err := elf.NewFile(bytes.NewReader(data)); err == nil {
+		pclntab, text, err = elfTable(f)
+		if err != nil {
+			return nil, err
+		}
+	} else if f, err := macho.NewFile(bytes.NewReader(data)); err == nil {
+		pclntab, text, err = machoTable(f)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New("binary is neither ELF nor Mach-O")
+	}
+	return gosym.NewTable(nil, gosym.NewLineTable(pclntab, text))
+}
+
+func elfTable(f *elf.File) ([]byte, uint64, error) {
+	section, text := f.Section(".gopclntab"), f.Section(".text")
+	if section == nil || text == nil {
+		return nil, 0, errors.New("binary has no Go symbol table")
+	}
+	pclntab, err := section.Data()
+	return pclntab, text.Addr, err
+}
+
+func machoTable(f *macho.File) ([]byte, uint64, error) {
+	section, text := f.Section("__gopclntab"), f.Section("__text")
+	if section == nil || text == nil {
+		return nil, 0, errors.New("binary has no Go symbol table")
+	}
+	pclntab, err := section.Data()
+	return pclntab, text.Addr, err
+}
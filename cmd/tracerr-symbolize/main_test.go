@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRun(t *testing.T) {
+	binary, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	traced := tracerr.New("some error")
+	frame := traced.StackTrace()[0]
+	input := tracerr.SprintAddresses(traced) + "\n0x1 0x0\n"
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{binary}, strings.NewReader(input), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() = %#v; want %#v, stderr: %s", code, 0, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if lines[0] != frame.String() {
+		t.Errorf("lines[0] = %#v; want %#v", lines[0], frame.String())
+	}
+	if lines[len(lines)-1] != "0x1 ?" {
+		t.Errorf("lines[%d] = %#v; want %#v", len(lines)-1, lines[len(lines)-1], "0x1 ?")
+	}
+
+	stdout.Reset()
+	offsetInput := fmt.Sprintf("0x%x\n", frame.PC+0x1000)
+	code = run([]string{"-offset", "0x1000", binary}, strings.NewReader(offsetInput), &stdout, &stderr)
+	if code != 0 || stdout.String() != frame.String()+"\n" {
+		t.Errorf("run(-offset) = %#v, %#v; want %#v, %#v", code, stdout.String(), 0, frame.String()+"\n")
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	binary, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	cases := []struct {
+		args  []string
+		input string
+	}{
+		{args: nil, input: ""},
+		{args: []string{"-offset", "invalid", binary}, input: ""},
+		{args: []string{"/nonexistent"}, input: ""},
+		{args: []string{"main_test.go"}, input: ""},
+		{args: []string{binary}, input: "invalid\n"},
+		{args: []string{binary, "/nonexistent"}, input: ""},
+	}
+	for i, c := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := run(c.args, strings.NewReader(c.input), &stdout, &stderr); code == 0 {
+			t.Errorf("case #%d: run(%#v) = 0; want non-zero", i, c.args)
+		}
+	}
+}
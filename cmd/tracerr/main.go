@@ -0,0 +1,176 @@
+// Command tracerr renders serialized errors with source fragments
+// of a local checkout, so errors collected in production can be debugged.
+//
+// Input is read from a file or stdin, it's a TracedError of tracerrpb package
+// in JSON or binary protobuf form, or panic output of Go runtime.
+//
+// Usage:
+//
+//	tracerr [flags] [file]
+//
+// Flags:
+//
+//	-format   input format: auto, json, proto or panic (default auto)
+//	-lines    number of source lines per frame, 0 for none (default 6)
+//	-before   number of source lines before traced line, overrides its part of -lines
+//	-after    number of source lines after traced line, overrides its part of -lines
+//	-color    use color: auto, always or never (default auto)
+//	-theme    color theme: default or mono (default default)
+//	-rewrite  path rewrite from=to, may be repeated
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrpb"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// rewrites is a flag of path rewrites.
+type rewrites []tracerr.PrintOption
+
+func (r *rewrites) String() string {
+	return ""
+}
+
+func (r *rewrites) Set(value string) error {
+	from, to, ok := strings.Cut(value, "=")
+	if !ok || from == "" {
+		return errors.New("rewrite must be in from=to form")
+	}
+	*r = append(*r, tracerr.WithPathRewrite(from, to))
+	return nil
+}
+
+var themes = map[string]tracerr.Theme{
+	"default": tracerr.DefaultTheme,
+	"mono": {
+		Message:   aurora.BoldFm,
+		Path:      aurora.BoldFm,
+		Highlight: aurora.InverseFm,
+	},
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tracerr", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "auto", "input format: auto, json, proto or panic")
+	lines := flags.Int("lines", 6, "number of source lines per frame, 0 for none")
+	before := flags.Int("before", -1, "number of source lines before traced line, overrides its part of -lines")
+	after := flags.Int("after", -1, "number of source lines after traced line, overrides its part of -lines")
+	color := flags.String("color", "auto", "use color: auto, always or never")
+	themeName := flags.String("theme", "default", "color theme: default or mono")
+	var pathRewrites rewrites
+	flags.Var(&pathRewrites, "rewrite", "path rewrite from=to, may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	theme, ok := themes[*themeName]
+	if !ok {
+		fmt.Fprintf(stderr, "tracerr: unknown theme %q\n", *themeName)
+		return 2
+	}
+	input, err := readInput(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "tracerr: %v\n", err)
+		return 1
+	}
+	e, err := decode(input, *format)
+	if err != nil {
+		fmt.Fprintf(stderr, "tracerr: %v\n", err)
+		return 1
+	}
+
+	tracerr.SetPrintOptions(append(pathRewrites, tracerr.WithTheme(theme))...)
+	nums := []int{*lines}
+	if *before >= 0 || *after >= 0 {
+		nums = contextLines(*lines, *before, *after)
+	}
+	switch *color {
+	case "auto":
+		tracerr.FprintSourceColor(stdout, e, nums...)
+	case "always":
+		fmt.Fprintln(stdout, tracerr.SprintSourceColor(e, nums...))
+	case "never":
+		tracerr.FprintSource(stdout, e, nums...)
+	default:
+		fmt.Fprintf(stderr, "tracerr: unknown color mode %q\n", *color)
+		return 2
+	}
+	return 0
+}
+
+// contextLines returns numbers of source lines before and after traced
+// line, which are taken from lines as by tracerr.PrintSource if unset.
+func contextLines(lines, before, after int) []int {
+	// Extra line goes to "before" rather than "after", as in tracerr.
+	linesAfter := max(lines-1, 0) / 2
+	linesBefore := max(lines-linesAfter-1, 0)
+	if before < 0 {
+		before = linesBefore
+	}
+	if after < 0 {
+		after = linesAfter
+	}
+	return []int{before, after}
+}
+
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	switch len(args) {
+	case 0:
+		return io.ReadAll(stdin)
+	case 1:
+		return os.ReadFile(args[0])
+	}
+	return nil, errors.New("too many arguments")
+}
+
+// decode decodes input of format, auto format is detected by content.
+func decode(input []byte, format string) (tracerr.Error, error) {
+	if format == "auto" {
+		format = detectFormat(input)
+	}
+	switch format {
+	case "json":
+		var msg tracerrpb.TracedError
+		if err := protojson.Unmarshal(input, &msg); err != nil {
+			return nil, err
+		}
+		return tracerrpb.FromProto(&msg), nil
+	case "proto":
+		var msg tracerrpb.TracedError
+		if err := proto.Unmarshal(input, &msg); err != nil {
+			return nil, err
+		}
+		return tracerrpb.FromProto(&msg), nil
+	case "panic":
+		return tracerr.ParsePanic(string(input))
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+func detectFormat(input []byte) string {
+	trimmed := bytes.TrimSpace(input)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return "json"
+	}
+	if bytes.Contains(trimmed, []byte("goroutine ")) {
+		return "panic"
+	}
+	return "proto"
+}
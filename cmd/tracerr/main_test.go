@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrpb"
+)
+
+type RunTestCase struct {
+	Name     string
+	Args     []string
+	Input    []byte
+	Expected string
+}
+
+func TestRun(t *testing.T) {
+	defer tracerr.SetPrintOptions()
+
+	msg := &tracerrpb.TracedError{
+		Message: "some error",
+		Frames: []*tracerrpb.Frame{
+			{Func: "main.main", Line: 3, Path: "/build/src/main.go"},
+		},
+	}
+	jsonInput, err := protojson.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	protoInput, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	panicInput := []byte("panic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/build/src/main.go:3 +0x1d\n")
+
+	cases := []RunTestCase{
+		{
+			Name:     "json",
+			Args:     []string{"-color", "never", "-lines", "0"},
+			Input:    jsonInput,
+			Expected: "some error\n/build/src/main.go:3 main.main()\n",
+		},
+		{
+			Name:     "proto",
+			Args:     []string{"-color", "never", "-lines", "0"},
+			Input:    protoInput,
+			Expected: "some error\n/build/src/main.go:3 main.main()\n",
+		},
+		{
+			Name:     "panic",
+			Args:     []string{"-color", "never", "-lines", "0"},
+			Input:    panicInput,
+			Expected: "boom\n/build/src/main.go:3 main.main()\n",
+		},
+		{
+			Name:     "rewrite",
+			Args:     []string{"-color", "never", "-lines", "0", "-rewrite", "/build/src=/home/me/src"},
+			Input:    jsonInput,
+			Expected: "some error\n/home/me/src/main.go:3 main.main()\n",
+		},
+	}
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		code := run(c.Args, bytes.NewReader(c.Input), &stdout, &stderr)
+		if code != 0 {
+			t.Errorf("%s: run() = %#v; want %#v, stderr: %s", c.Name, code, 0, stderr.String())
+			continue
+		}
+		if stdout.String() != c.Expected {
+			t.Errorf("%s: output = %#v; want %#v", c.Name, stdout.String(), c.Expected)
+		}
+	}
+}
+
+func TestRunSource(t *testing.T) {
+	defer tracerr.SetPrintOptions()
+
+	msg := tracerrpb.ToProto(tracerr.New("some error"))
+	input, err := protojson.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-color", "always", "-before", "1", "-after", "0"}, bytes.NewReader(input), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() = %#v; want %#v, stderr: %s", code, 0, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "msg := tracerrpb.ToProto(") {
+		t.Errorf("output = %#v; want source fragment", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("output = %#v; want color", stdout.String())
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	defer tracerr.SetPrintOptions()
+
+	for _, args := range [][]string{
+		{"-theme", "unknown"},
+		{"-color", "unknown"},
+		{"-format", "unknown"},
+		{"-rewrite", "invalid"},
+		{"a", "b"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, strings.NewReader("{}"), &stdout, &stderr); code == 0 {
+			t.Errorf("run(%#v) = 0; want non-zero", args)
+		}
+	}
+}
+
+func TestContextLines(t *testing.T) {
+	cases := []struct {
+		lines, before, after int
+		expected             []int
+	}{
+		{lines: 6, before: 1, after: -1, expected: []int{1, 2}},
+		{lines: 6, before: -1, after: 4, expected: []int{3, 4}},
+		{lines: 6, before: 0, after: 0, expected: []int{0, 0}},
+		{lines: 0, before: 2, after: -1, expected: []int{2, 0}},
+	}
+	for i, c := range cases {
+		if nums := contextLines(c.lines, c.before, c.after); !reflect.DeepEqual(nums, c.expected) {
+			t.Errorf("case #%d: contextLines(%d, %d, %d) = %#v; want %#v", i, c.lines, c.before, c.after, nums, c.expected)
+		}
+	}
+}
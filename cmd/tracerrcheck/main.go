@@ -0,0 +1,21 @@
+// Command tracerrcheck reports errors of external calls returned
+// without wrapping by tracerr, see package tracerrcheck.
+//
+// Usage:
+//
+//	tracerrcheck [-ignore list] ./...
+//
+// Or as a tool of go vet:
+//
+//	go vet -vettool=$(which tracerrcheck) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/kadaan/tracerr/tracerrcheck"
+)
+
+func main() {
+	singlechecker.Main(tracerrcheck.Analyzer)
+}
@@ -0,0 +1,31 @@
+package tracerr
+
+// Code returns code of the first error in err tree,
+// which has Code() string method, or empty string.
+// Tree is walked in the same order as by AsError.
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	if code := errorCode(err); code != "" {
+		return code
+	}
+	for _, err := range unwrapAll(err) {
+		if code := Code(err); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// errorCode returns code of err, if it has Code() string method.
+// Code method of errorData is skipped, as it returns code of wrapped errors.
+func errorCode(err error) string {
+	if _, ok := err.(*errorData); ok {
+		return ""
+	}
+	if c, ok := err.(interface{ Code() string }); ok {
+		return c.Code()
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package tracerr
+
+import (
+	"fmt"
+)
+
+// outputFrame is a frame prepared for output.
+type outputFrame struct {
+	Frame
+	// Repeated is a number of consecutive occurrences of the frame,
+	// which is greater than 1 for recursive calls.
+	Repeated int
+	// Omitted is a number of frames elided in place of this entry,
+	// see WithFrameWindow. Frame is empty if it's not zero.
+	Omitted int
+	// Folded is true if Omitted frames are folded by FrameConfig.
+	Folded bool
+	// Callee is a name of function called at the frame line,
+	// it's empty for the innermost frame.
+	Callee string
+	// Top is true for the innermost frame.
+	Top bool
+	// Generated is the frame in a generated file, which a //line
+	// directive maps to Frame, see LineDirectivesBoth.
+	Generated *Frame
+}
+
+// collapseFrames merges consecutive equal frames[start:end], e.g. of
+// recursive calls, so deep recursion doesn't take screens of output.
+// Callees are taken from all frames, so they are correct next to elided ones.
+func collapseFrames(frames []Frame, start, end int) []outputFrame {
+	collapsed := make([]outputFrame, 0, end-start)
+	for i := start; i < end; i++ {
+		frame := frames[i]
+		if n := len(collapsed); n > 0 && sameFrame(collapsed[n-1].Frame, frame) {
+			collapsed[n-1].Repeated++
+			continue
+		}
+		callee := ""
+		if i > 0 && !frames[i-1].IsMarker() {
+			callee = frames[i-1].Name
+		}
+		collapsed = append(collapsed, outputFrame{
+			Frame:    frame,
+			Repeated: 1,
+			Callee:   callee,
+		})
+	}
+	return collapsed
+}
+
+func sameFrame(a, b Frame) bool {
+	return a.Func == b.Func && a.Path == b.Path && a.Line == b.Line && !b.CreatedBy
+}
+
+// outputFrames returns frames of e prepared for output with repeats collapsed,
+// middle frames elided by WithFrameWindow, mapped by WithLineDirectives
+// and ordered by WithFrameOrder.
+func (o *printOptions) outputFrames(e Error) []outputFrame {
+	frames := o.frames(e)
+	var output []outputFrame
+	if !o.window || len(frames) <= o.windowFirst+o.windowLast {
+		output = collapseFrames(frames, 0, len(frames))
+	} else {
+		omitted := len(frames) - o.windowFirst - o.windowLast
+		output = collapseFrames(frames, 0, o.windowFirst)
+		output = append(output, outputFrame{Omitted: omitted})
+		output = append(output, collapseFrames(frames, o.windowFirst+omitted, len(frames))...)
+	}
+	if config := currentFrameConfig(); config != nil {
+		output = config.fold(output)
+	}
+	if len(output) > 0 && output[0].Omitted == 0 {
+		output[0].Top = true
+	}
+	o.lineDirectiveFrames(output)
+	return orderFrames(o, output)
+}
+
+// omittedString returns elision marker of frame.
+func omittedString(frame outputFrame) string {
+	verb := "omitted"
+	if frame.Folded {
+		verb = "folded"
+	}
+	if frame.Omitted == 1 {
+		return "... 1 frame " + verb + " ..."
+	}
+	return fmt.Sprintf("... %d frames %s ...", frame.Omitted, verb)
+}
+
+// repeatedSuffix returns a note about repeats of frame or empty string.
+func repeatedSuffix(frame outputFrame) string {
+	if frame.Repeated < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (repeated %d times)", frame.Repeated)
+}
@@ -0,0 +1,63 @@
+package tracerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func recurse(depth int) error {
+	if depth == 0 {
+		return tracerr.New("too deep")
+	}
+	return recurse(depth - 1)
+}
+
+type CollapseTestCase struct {
+	Name   string
+	Sprint func(err error) string
+	Want   string
+}
+
+func TestCollapseRepeatedFrames(t *testing.T) {
+	err := recurse(10)
+	cases := []CollapseTestCase{
+		{
+			Name:   "Sprint",
+			Sprint: tracerr.Sprint,
+			Want:   "recurse() (repeated 10 times)\n",
+		},
+		{
+			Name: "SprintSource",
+			Sprint: func(err error) string {
+				return tracerr.SprintSource(err, 1)
+			},
+			Want: "recurse() (repeated 10 times)\n",
+		},
+		{
+			Name: "SprintMarkdown",
+			Sprint: func(err error) string {
+				return tracerr.SprintMarkdown(err, tracerr.WithSourceLines(0))
+			},
+			Want: "recurse()` (repeated 10 times)\n",
+		},
+		{
+			Name: "SprintHTML",
+			Sprint: func(err error) string {
+				return tracerr.SprintHTML(err, tracerr.WithSourceLines(0))
+			},
+			Want: "recurse()</span> (repeated 10 times)</summary>",
+		},
+	}
+
+	for _, c := range cases {
+		output := c.Sprint(err)
+		if n := strings.Count(output, "recurse()"); n != 2 {
+			t.Errorf("%s: number of recurse() frames = %#v; want %#v", c.Name, n, 2)
+		}
+		if !strings.Contains(output, c.Want) {
+			t.Errorf("%s: output = %#v; want to contain %#v", c.Name, output, c.Want)
+		}
+	}
+}
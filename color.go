@@ -0,0 +1,36 @@
+package tracerr
+
+import (
+	"io"
+	"os"
+)
+
+// colorEnabled reports whether colored output should be written to w.
+//
+// Color is disabled if NO_COLOR environment variable is set,
+// forced if FORCE_COLOR is set, otherwise it's enabled only for terminals.
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if force := os.Getenv("FORCE_COLOR"); force != "" {
+		if ok {
+			enableVirtualTerminal(f)
+		}
+		return force != "0" && force != "false"
+	}
+	if !ok || !isTerminal(f) {
+		return false
+	}
+	// Legacy Windows consoles are unable to render escape sequences.
+	return enableVirtualTerminal(f)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package tracerr
+
+import (
+	"os"
+)
+
+// enableVirtualTerminal reports whether terminal of f renders escape sequences,
+// which is always the case except of Windows.
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
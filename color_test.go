@@ -0,0 +1,57 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type ColorTestCase struct {
+	NoColor    string
+	ForceColor string
+	Colorized  bool
+}
+
+func TestColorEnv(t *testing.T) {
+	err := addFrameA("color error")
+	cases := []ColorTestCase{
+		{
+			NoColor:    "",
+			ForceColor: "",
+			Colorized:  false,
+		},
+		{
+			NoColor:    "",
+			ForceColor: "1",
+			Colorized:  true,
+		},
+		{
+			NoColor:    "",
+			ForceColor: "0",
+			Colorized:  false,
+		},
+		{
+			NoColor:    "1",
+			ForceColor: "1",
+			Colorized:  false,
+		},
+	}
+
+	for i, c := range cases {
+		t.Setenv("NO_COLOR", c.NoColor)
+		t.Setenv("FORCE_COLOR", c.ForceColor)
+		var buf bytes.Buffer
+		tracerr.FprintSourceColor(&buf, err, 1)
+		expected := tracerr.SprintSource(err, 1) + "\n"
+		if c.Colorized {
+			expected = tracerr.SprintSourceColor(err, 1) + "\n"
+		}
+		if buf.String() != expected {
+			t.Errorf(
+				"case #%d: output = %#v; want %#v",
+				i, buf.String(), expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tracerr
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// captureFuncs are functions, which capture stack trace,
+// so one of them is called at the line of the innermost frame.
+var captureFuncs = map[string]bool{
+	"New":        true,
+	"NewSkip":    true,
+	"Errorf":     true,
+	"Wrap":       true,
+	"WrapSkip":   true,
+	"WrapAlways": true,
+	"Wrap2":      true,
+	"Wrap3":      true,
+	"Must":       true,
+	"Check":      true,
+}
+
+// expressionSpan returns byte offsets of call expression of callee
+// in source line text, end is zero if it's not found.
+// Empty callee means a call of one of captureFuncs.
+//
+// Expression includes selectors before callee, e.g. "tracerr.Wrap(err)".
+// If arguments continue on the next lines, expression ends at the end of text.
+func expressionSpan(text, callee string) (start, end int) {
+	if i := strings.Index(callee, "["); i >= 0 {
+		// Type parameters of generic function.
+		callee = callee[:i]
+	}
+	if isClosureName(callee) {
+		return 0, 0
+	}
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(text))
+	// Errors are expected, since a line isn't a complete source file.
+	s.Init(file, []byte(text), func(token.Position, string) {}, 0)
+	// chainStart is an offset of selector chain like "a.b.c" or -1.
+	chainStart := -1
+	prevTok, prevLit, prevOffset := token.ILLEGAL, "", 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return 0, 0
+		}
+		offset := file.Offset(pos)
+		if tok == token.LPAREN && prevTok == token.IDENT &&
+			(prevLit == callee || callee == "" && captureFuncs[prevLit]) {
+			if chainStart < 0 {
+				// Selector of an expression like "f().Wrap".
+				chainStart = prevOffset
+			}
+			return chainStart, closingParen(&s, file, text)
+		}
+		switch {
+		case tok == token.IDENT && prevTok != token.PERIOD:
+			chainStart = offset
+		case tok != token.IDENT && tok != token.PERIOD:
+			chainStart = -1
+		}
+		prevTok, prevLit, prevOffset = tok, lit, offset
+	}
+}
+
+// closingParen returns offset after parenthesis closing the just scanned one,
+// or the end of text if it's not closed at this line.
+func closingParen(s *scanner.Scanner, file *token.File, text string) int {
+	depth := 1
+	for {
+		pos, tok, _ := s.Scan()
+		switch tok {
+		case token.EOF:
+			return len(strings.TrimRight(text, " \t\r"))
+		case token.LPAREN:
+			depth++
+		case token.RPAREN:
+			depth--
+			if depth == 0 {
+				return file.Offset(pos) + 1
+			}
+		}
+	}
+}
+
+// highlightExpression sets span of call expression of callee
+// at the traced line of fragment, see expressionSpan.
+func highlightExpression(fragment []sourceLine, callee string) {
+	for i := range fragment {
+		if fragment[i].Current {
+			fragment[i].Start, fragment[i].End = expressionSpan(fragment[i].Text, callee)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/logrusorgru/aurora"
+)
+
+// highlighted returns traced source line of default theme
+// with highlighted call expression.
+func highlighted(prefix, expression string) string {
+	return aurora.Red(prefix).String() + aurora.Colorize(expression, aurora.InverseFm|aurora.RedFg).String()
+}
+
+type columnStore struct{}
+
+func (s *columnStore) Get(key string, n int) error {
+	return tracerr.Wrap(errors.New("not found: " + key))
+}
+
+func columnGet(s *columnStore) error {
+	err := s.Get(strings.ToLower("KEY"), len("x")) // Get(comment)
+	return err
+}
+
+func columnClosure() error {
+	var err error
+	func() { err = tracerr.New("in closure") }()
+	return err
+}
+
+type ExpressionTestCase struct {
+	Error    error
+	Expected []string
+}
+
+func TestExpressionHighlight(t *testing.T) {
+	forceColor(t)
+	cases := []ExpressionTestCase{
+		{
+			Error: columnGet(&columnStore{}),
+			Expected: []string{
+				highlighted("21\t\treturn ", "tracerr.Wrap(errors.New(\"not found: \" + key))"),
+				highlighted("25\t\terr := ", "s.Get(strings.ToLower(\"KEY\"), len(\"x\"))") +
+					aurora.Red(" // Get(comment)").String(),
+			},
+		},
+		{
+			Error: columnClosure(),
+			Expected: []string{
+				highlighted("31\t\tfunc() { err = ", "tracerr.New(\"in closure\")") + aurora.Red(" }()").String(),
+				aurora.Red("31\t\tfunc() { err = tracerr.New(\"in closure\") }()").String(),
+			},
+		},
+	}
+
+	for i, c := range cases {
+		output := tracerr.SprintSourceColor(c.Error, 1)
+		for _, expected := range c.Expected {
+			if !strings.Contains(output, expected+"\n") {
+				t.Errorf("case #%d: output = %#v; want to contain %#v", i, output, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package tracerr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultCompactSeparator separates frames in output of SprintCompact.
+const DefaultCompactSeparator = " | "
+
+// WithCompactSeparator sets separator of frames in output of SprintCompact.
+func WithCompactSeparator(separator string) PrintOption {
+	return func(o *printOptions) {
+		o.compactSeparator = separator
+	}
+}
+
+// SprintCompact returns error output in a single line for log pipelines,
+// which don't support multiline entries, e.g.
+//
+//	err="some error" at=main.read file=/src/main.go:42 | main.main /src/main.go:10
+//
+// See WithCompactSeparator and WithMaxFrames to configure it.
+func SprintCompact(err error, options ...PrintOption) string {
+	if err == nil {
+		return ""
+	}
+	o := mergePrintOptions(options)
+	message := "err=" + strconv.Quote(err.Error())
+	e, ok := err.(Error)
+	if !ok {
+		return message
+	}
+	frames := o.outputFrames(e)
+	if len(frames) == 0 {
+		return message
+	}
+	separator := o.compactSeparator
+	if separator == "" {
+		separator = DefaultCompactSeparator
+	}
+	parts := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		separator, isSeparator := separatorString(frame)
+		switch {
+		case isSeparator:
+			parts = append(parts, separator)
+		case frame.CreatedBy:
+			parts = append(parts, createdBy+" "+o.funcName(frame.Frame)+" "+o.location(frame.Frame)+repeatedSuffix(frame))
+		case frame.Top:
+			parts = append(parts, "at="+o.funcName(frame.Frame)+" file="+o.location(frame.Frame)+repeatedSuffix(frame))
+		default:
+			parts = append(parts, o.funcName(frame.Frame)+" "+o.location(frame.Frame)+repeatedSuffix(frame))
+		}
+	}
+	// Newlines would break a single line entry.
+	return strings.ReplaceAll(message+" "+strings.Join(parts, separator), "\n", " ")
+}
@@ -0,0 +1,53 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type CompactTestCase struct {
+	Error    error
+	Options  []tracerr.PrintOption
+	Expected string
+}
+
+func TestSprintCompact(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+		tracerr.NewFrame("runtime.main", "/go/src/runtime/proc.go", 250),
+	}
+	err := tracerr.CustomError(errors.New("some \"error\"\nline"), frames)
+	cases := []CompactTestCase{
+		{
+			Error:    nil,
+			Expected: "",
+		},
+		{
+			Error:    errors.New("plain"),
+			Expected: `err="plain"`,
+		},
+		{
+			Error:    tracerr.CustomError(errors.New("no frames"), nil),
+			Expected: `err="no frames"`,
+		},
+		{
+			Error:    err,
+			Expected: `err="some \"error\"\nline" at=main.read file=/src/main.go:42 | main.main /src/main.go:10 | runtime.main /go/src/runtime/proc.go:250`,
+		},
+		{
+			Error:    err,
+			Options:  []tracerr.PrintOption{tracerr.WithCompactSeparator(" <- "), tracerr.WithMaxFrames(2)},
+			Expected: `err="some \"error\"\nline" at=main.read file=/src/main.go:42 <- main.main /src/main.go:10`,
+		},
+	}
+
+	for i, c := range cases {
+		output := tracerr.SprintCompact(c.Error, c.Options...)
+		if output != c.Expected {
+			t.Errorf("case #%d: tracerr.SprintCompact(err) = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package tracerr
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	panicReportDir   string
+	panicReportMutex sync.RWMutex
+)
+
+// SetPanicReportDir makes Group, Go and Spawn write a report by WriteReport
+// to dir for every panic they convert to error.
+// Pass empty string to disable it, which is the default.
+func SetPanicReportDir(dir string) {
+	panicReportMutex.Lock()
+	defer panicReportMutex.Unlock()
+	panicReportDir = dir
+}
+
+// writePanicReport writes report of err if it's enabled by SetPanicReportDir.
+// Reports are written on the best effort basis, as panic is being handled.
+func writePanicReport(err error) {
+	panicReportMutex.RLock()
+	dir := panicReportDir
+	panicReportMutex.RUnlock()
+	if dir != "" {
+		_, _ = WriteReport(dir, err)
+	}
+}
+
+// WriteReport writes a self-contained report of err to a new timestamped file
+// in dir for later inspection, like hs_err files of JVM, and returns its path.
+//
+// Report contains error message, stack trace with source fragments,
+// build info and summary of environment, such as OS and host name.
+// Environment variables and command line arguments are not written,
+// as they may contain secrets, except for the ones captured with redaction
+// by WithEnvironment. Runtime stats captured by WithRuntimeStats are written too.
+func WriteReport(dir string, err error) (string, error) {
+	now := time.Now()
+	f, createErr := os.CreateTemp(dir, "tracerr-"+now.Format("20060102-150405")+"-*.txt")
+	if createErr != nil {
+		return "", createErr
+	}
+	_, writeErr := f.WriteString(report(err, now))
+	if closeErr := f.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		return "", writeErr
+	}
+	return f.Name(), nil
+}
+
+// report returns content of a report file.
+func report(err error, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Time: %s\n", now.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "Fingerprint: %s\n", Fingerprint(err))
+	if trace, ok := Trace(err); ok {
+		fmt.Fprintf(&b, "Trace ID: %s\n", trace.TraceID)
+		fmt.Fprintf(&b, "Span ID: %s\n", trace.SpanID)
+	}
+	b.WriteString("\n== Error ==\n\n")
+	b.WriteString(SprintSource(err))
+	b.WriteString("\n\n== Build ==\n\n")
+	fmt.Fprintf(&b, "Go: %s\n", runtime.Version())
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&b, "Path: %s\n", info.Path)
+		fmt.Fprintf(&b, "Module: %s %s\n", info.Main.Path, info.Main.Version)
+		for _, setting := range info.Settings {
+			if strings.HasPrefix(setting.Key, "vcs.") || strings.HasPrefix(setting.Key, "GO") || setting.Key == "-tags" {
+				fmt.Fprintf(&b, "%s: %s\n", setting.Key, setting.Value)
+			}
+		}
+	}
+	b.WriteString("\n== Environment ==\n\n")
+	fmt.Fprintf(&b, "OS: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if hostname, err := os.Hostname(); err == nil {
+		fmt.Fprintf(&b, "Hostname: %s\n", hostname)
+	}
+	fmt.Fprintf(&b, "PID: %d\n", os.Getpid())
+	if executable, err := os.Executable(); err == nil {
+		fmt.Fprintf(&b, "Executable: %s\n", executable)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		fmt.Fprintf(&b, "Working directory: %s\n", wd)
+	}
+	fmt.Fprintf(&b, "CPUs: %d\n", runtime.NumCPU())
+	fmt.Fprintf(&b, "GOMAXPROCS: %d\n", runtime.GOMAXPROCS(0))
+	fmt.Fprintf(&b, "Goroutines: %d\n", runtime.NumGoroutine())
+	if env, ok := Env(err); ok {
+		b.WriteString("\n== Captured Environment ==\n\n")
+		fmt.Fprintf(&b, "Hostname: %s\n", env.Hostname)
+		fmt.Fprintf(&b, "PID: %d\n", env.PID)
+		fmt.Fprintf(&b, "OS: %s/%s\n", env.GOOS, env.GOARCH)
+		for _, name := range env.varNames() {
+			fmt.Fprintf(&b, "%s=%s\n", name, env.Vars[name])
+		}
+	}
+	if stats, ok := Stats(err); ok {
+		b.WriteString("\n== Captured Runtime Stats ==\n\n")
+		fmt.Fprintf(&b, "Heap in use: %d bytes\n", stats.HeapInUse)
+		fmt.Fprintf(&b, "Heap objects: %d\n", stats.HeapObjects)
+		fmt.Fprintf(&b, "Goroutines: %d\n", stats.Goroutines)
+		fmt.Fprintf(&b, "GC cycles: %d\n", stats.NumGC)
+		fmt.Fprintf(&b, "Last GC pause: %s\n", stats.LastGCPause)
+	}
+	return b.String()
+}
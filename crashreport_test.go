@@ -0,0 +1,73 @@
+package tracerr_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWriteReport(t *testing.T) {
+	dir := t.TempDir()
+	err := tracerr.New("some error")
+	path, writeErr := tracerr.WriteReport(dir, err)
+	if writeErr != nil {
+		t.Fatalf("tracerr.WriteReport() error: %v", writeErr)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "tracerr-") {
+		t.Errorf("tracerr.WriteReport() = %#v; want file in %#v", path, dir)
+	}
+	b, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	report := string(b)
+	for _, expected := range []string{
+		"Fingerprint: " + tracerr.Fingerprint(err) + "\n",
+		"\n== Error ==\n\n" + tracerr.SprintSource(err) + "\n",
+		"\n== Build ==\n\nGo: " + runtime.Version() + "\n",
+		"\n== Environment ==\n\nOS: " + runtime.GOOS + "/" + runtime.GOARCH + "\n",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("report = %s; want it to contain %#v", report, expected)
+		}
+	}
+
+	if _, err := tracerr.WriteReport(filepath.Join(dir, "missing"), errors.New("x")); err == nil {
+		t.Errorf("tracerr.WriteReport() to missing dir error = nil; want error")
+	}
+}
+
+func TestSetPanicReportDir(t *testing.T) {
+	dir := t.TempDir()
+	tracerr.SetPanicReportDir(dir)
+	defer tracerr.SetPanicReportDir("")
+
+	var g tracerr.Group
+	g.Go(func() error {
+		panicInWorker()
+		return nil
+	})
+	g.Go(func() error {
+		return tracerr.New("not a panic")
+	})
+	g.Wait()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d; want 1", len(entries))
+	}
+	b, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "panic: worker failed") {
+		t.Errorf("report = %s; want panic message", b)
+	}
+}
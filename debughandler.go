@@ -0,0 +1,103 @@
+package tracerr
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DebugHandler returns http.Handler, which serves errors kept by r
+// like net/http/pprof serves profiles, mount it under /debug/errors:
+//
+//	http.Handle("/debug/errors", tracerr.DebugHandler(recorder))
+//
+// It lists recent errors as HTML page with links to a page of each error
+// rendered by SprintHTML with source fragments.
+// Add ?format=json to a URL or accept application/json to get JSON output.
+// The error page is ?id=N, where N is RecordedError.ID.
+func DebugHandler(r *Recorder) http.Handler {
+	return &debugHandler{recorder: r}
+}
+
+type debugHandler struct {
+	recorder *Recorder
+}
+
+func (h *debugHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	asJSON := query.Get("format") == "json" ||
+		strings.Contains(req.Header.Get("Accept"), "application/json")
+	if query.Has("id") {
+		h.serveError(w, query.Get("id"), asJSON)
+		return
+	}
+	if asJSON {
+		h.recorder.ServeHTTP(w, req)
+		return
+	}
+	recorded := h.recorder.Errors()
+	var b strings.Builder
+	writeDebugHeader(&b, "Recent errors")
+	fmt.Fprintf(&b, `<p>%d errors, <a href="?format=json">JSON</a></p>`, len(recorded))
+	b.WriteString(`<table><tr><th>Time</th><th>Error</th><th>Location</th></tr>`)
+	for i := len(recorded) - 1; i >= 0; i-- {
+		e := recorded[i]
+		location := ""
+		if frames := RawFrames(e.Err); len(frames) > 0 {
+			location = frames[0].String()
+		}
+		fmt.Fprintf(
+			&b,
+			`<tr><td>%s</td><td><a href="?id=%d">%s</a></td><td>%s</td></tr>`,
+			e.Time.Format(time.RFC3339), e.ID, html.EscapeString(e.Err.Error()), html.EscapeString(location),
+		)
+	}
+	b.WriteString(`</table></body></html>`)
+	writeHTML(w, http.StatusOK, b.String())
+}
+
+// serveError serves a page of error with id.
+func (h *debugHandler) serveError(w http.ResponseWriter, id string, asJSON bool) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	e, ok := h.recorder.Get(n)
+	if err != nil || !ok {
+		message := fmt.Sprintf("error %s not found, it may be dropped as too old", id)
+		if asJSON {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": message})
+			return
+		}
+		http.Error(w, message, http.StatusNotFound)
+		return
+	}
+	if asJSON {
+		writeJSON(w, http.StatusOK, newRecordedErrorJSON(e))
+		return
+	}
+	var b strings.Builder
+	writeDebugHeader(&b, fmt.Sprintf("Error %d", e.ID))
+	fmt.Fprintf(
+		&b,
+		`<p>%s, fingerprint %s, <a href="?id=%d&amp;format=json">JSON</a>, <a href="?">all errors</a></p>`,
+		e.Time.Format(time.RFC3339), html.EscapeString(Fingerprint(e.Err)), e.ID,
+	)
+	b.WriteString(SprintHTML(e.Err))
+	b.WriteString(`</body></html>`)
+	writeHTML(w, http.StatusOK, b.String())
+}
+
+func writeDebugHeader(b *strings.Builder, title string) {
+	fmt.Fprintf(
+		b,
+		`<!DOCTYPE html><html><head><meta charset="utf-8"><title>%s</title></head><body><h1>%s</h1>`,
+		html.EscapeString(title), html.EscapeString(title),
+	)
+}
+
+func writeHTML(w http.ResponseWriter, status int, content string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprint(w, content)
+}
@@ -0,0 +1,93 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestDebugHandler(t *testing.T) {
+	r := tracerr.NewRecorder(2)
+	first := tracerr.New("first <error>")
+	r.Add(first)
+	r.Add(tracerr.New("second"))
+	r.Add(tracerr.New("third"))
+	h := tracerr.DebugHandler(r)
+
+	serve := func(target string, header http.Header) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", target, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/debug/errors", nil)
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("index: status %d, Content-Type %q; want 200 and HTML", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(body, `<a href="?id=3">third</a>`) || !strings.Contains(body, `<a href="?id=2">second</a>`) {
+		t.Errorf("index = %s; want links to kept errors", body)
+	}
+	if strings.Index(body, "?id=3") > strings.Index(body, "?id=2") {
+		t.Errorf("index = %s; want the newest error first", body)
+	}
+
+	w = serve("/debug/errors?id=2", nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `<div class="tracerr">`) {
+		t.Errorf("error page: status %d, body %s; want rendered error", w.Code, w.Body.String())
+	}
+
+	w = serve("/debug/errors?id=1", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("dropped error page: status %d; want 404", w.Code)
+	}
+
+	w = serve("/debug/errors?format=json", nil)
+	var list []struct {
+		ID uint64 `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 2 || list[0].ID != 3 {
+		t.Errorf("JSON index = %s; want errors 3 and 2", w.Body.String())
+	}
+
+	w = serve("/debug/errors?id=3", http.Header{"Accept": {"application/json"}})
+	var detail struct {
+		ID    uint64            `json:"id"`
+		Error *tracerr.TreeNode `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil || detail.ID != 3 || detail.Error.Message != "third" {
+		t.Errorf("JSON error page = %s; want error 3", w.Body.String())
+	}
+
+	w = serve("/debug/errors?id=x&format=json", nil)
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("JSON missing error: status %d, Content-Type %q; want 404 JSON", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestDebugHandlerEscapesFingerprint(t *testing.T) {
+	fingerprint := tracerr.FingerprintFunc(func(err error) string {
+		return `<script>alert("x")</script>`
+	})
+	tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithFingerprint(fingerprint))
+	r := tracerr.NewRecorder(1)
+	r.Add(tr.New("some error"))
+
+	w := httptest.NewRecorder()
+	tracerr.DebugHandler(r).ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors?id=1", nil))
+	body := w.Body.String()
+	if strings.Contains(body, "<script>") {
+		t.Errorf("error page = %s; want fingerprint escaped", body)
+	}
+	if !strings.Contains(body, "fingerprint &lt;script&gt;") {
+		t.Errorf("error page = %s; want to contain escaped fingerprint", body)
+	}
+}
@@ -0,0 +1,47 @@
+package tracerr
+
+// WithMaskedLines replaces line numbers of frames with "NN" in output,
+// line numbers of source fragments are kept.
+func WithMaskedLines() PrintOption {
+	return func(o *printOptions) {
+		o.maskLines = true
+	}
+}
+
+// WithMaxFrames limits number of frames in output, zero means no limit.
+// Frames are dropped silently, see WithMaxOutputBytes for output with a notice.
+func WithMaxFrames(n int) PrintOption {
+	return func(o *printOptions) {
+		o.maxFrames = n
+	}
+}
+
+// WithFrameWindow limits output of long stack traces to the first
+// and the last frames, frames in between are replaced with a marker.
+// It's useful if interesting frames are buried among frames of
+// HTTP routers and middleware.
+func WithFrameWindow(first, last int) PrintOption {
+	return func(o *printOptions) {
+		if first < 0 {
+			first = 0
+		}
+		if last < 0 {
+			last = 0
+		}
+		o.window = true
+		o.windowFirst = first
+		o.windowLast = last
+	}
+}
+
+// WithDeterministic makes output stable for golden file tests:
+// paths are trimmed as by WithTrimPaths, line numbers are masked
+// as by WithMaskedLines and number of frames is limited to maxFrames,
+// so frames of test runner can be cut off.
+func WithDeterministic(maxFrames int) PrintOption {
+	return func(o *printOptions) {
+		WithTrimPaths()(o)
+		WithMaskedLines()(o)
+		WithMaxFrames(maxFrames)(o)
+	}
+}
@@ -0,0 +1,75 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithDeterministic(t *testing.T) {
+	tracerr.SetPrintOptions(tracerr.WithDeterministic(3))
+	defer tracerr.SetPrintOptions()
+
+	output := tracerr.Sprint(addFrameA("some error"))
+	expectedRows := []string{
+		"some error",
+		"github.com/kadaan/tracerr/error_helper_test.go:NN github.com/kadaan/tracerr_test.addFrameC()",
+		"github.com/kadaan/tracerr/error_helper_test.go:NN github.com/kadaan/tracerr_test.addFrameB()",
+		"github.com/kadaan/tracerr/error_helper_test.go:NN github.com/kadaan/tracerr_test.addFrameA()",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+}
+
+func TestWithFrameWindow(t *testing.T) {
+	frames := make([]tracerr.Frame, 0, 10)
+	for i := 1; i <= 10; i++ {
+		frames = append(frames, tracerr.Frame{
+			Func: fmt.Sprintf("main.f%d", i),
+			Line: i,
+			Path: "/src/main.go",
+		})
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+
+	output := tracerr.Sprint(err)
+	if n := strings.Count(output, "\n"); n != 10 {
+		t.Errorf("number of rows without window = %#v; want %#v", n+1, 11)
+	}
+
+	tracerr.SetPrintOptions(tracerr.WithFrameWindow(2, 1))
+	defer tracerr.SetPrintOptions()
+
+	output = tracerr.Sprint(err)
+	expectedRows := []string{
+		"some error",
+		"/src/main.go:1 main.f1()",
+		"/src/main.go:2 main.f2()",
+		"... 7 frames omitted ...",
+		"/src/main.go:10 main.f10()",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	output = tracerr.SprintMarkdown(err, tracerr.WithSourceLines(0))
+	if !strings.Contains(output, "3. _... 7 frames omitted ..._\n") {
+		t.Errorf("tracerr.SprintMarkdown(err) = %#v; want to contain omitted frames", output)
+	}
+
+	output = tracerr.SprintHTML(err, tracerr.WithSourceLines(0))
+	if !strings.Contains(output, `<p class="tracerr-omitted">... 7 frames omitted ...</p>`) {
+		t.Errorf("tracerr.SprintHTML(err) = %#v; want to contain omitted frames", output)
+	}
+
+	tracerr.SetPrintOptions(tracerr.WithFrameWindow(5, 5))
+	if output := tracerr.Sprint(err); strings.Contains(output, "omitted") {
+		t.Errorf("tracerr.Sprint(err) = %#v; want no omitted frames", output)
+	}
+}
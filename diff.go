@@ -0,0 +1,69 @@
+package tracerr
+
+import (
+	"strings"
+)
+
+// Diff is a difference of two stack traces returned by DiffFrames.
+type Diff struct {
+	// Prefix are the innermost frames, which are the same in both stack traces.
+	Prefix []Frame
+	// A are frames of the first stack trace between Prefix and Suffix.
+	A []Frame
+	// B are frames of the second stack trace between Prefix and Suffix.
+	B []Frame
+	// Suffix are the outermost frames, which are the same in both stack traces.
+	Suffix []Frame
+}
+
+// Equal reports whether stack traces are the same.
+func (d Diff) Equal() bool {
+	return len(d.A) == 0 && len(d.B) == 0
+}
+
+// DiffFrames returns a difference of stack traces a and b,
+// e.g. of two occurrences of the same error, to see where code paths diverged.
+// Frames are compared by Frame.Equal with options.
+func DiffFrames(a, b []Frame, options ...EqualOption) Diff {
+	o := newEqualOptions(options)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && o.equal(a[prefix], b[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		o.equal(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+	return Diff{
+		Prefix: a[:prefix],
+		A:      a[prefix : len(a)-suffix],
+		B:      b[prefix : len(b)-suffix],
+		Suffix: a[len(a)-suffix:],
+	}
+}
+
+// SprintDiff returns d in a form of unified diff,
+// frames only in the first stack trace start with "-",
+// frames only in the second one start with "+", e.g.
+//
+//	  /src/main.go:42 main.read()
+//	- /src/main.go:20 main.load()
+//	+ /src/main.go:30 main.reload()
+//	  /src/main.go:10 main.main()
+//
+// Paths follow print options, such as WithTrimPaths.
+func SprintDiff(d Diff, options ...PrintOption) string {
+	o := mergePrintOptions(options)
+	rows := make([]string, 0, len(d.Prefix)+len(d.A)+len(d.B)+len(d.Suffix))
+	add := func(prefix string, frames []Frame) {
+		for _, frame := range frames {
+			rows = append(rows, prefix+o.frameString(frame))
+		}
+	}
+	add("  ", d.Prefix)
+	add("- ", d.A)
+	add("+ ", d.B)
+	add("  ", d.Suffix)
+	return strings.Join(rows, "\n")
+}
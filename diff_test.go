@@ -0,0 +1,69 @@
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func diffFrames(names ...string) []tracerr.Frame {
+	frames := make([]tracerr.Frame, 0, len(names))
+	for i, name := range names {
+		frames = append(frames, tracerr.NewFrame("main."+name, "/src/main.go", 10*(i+1)))
+	}
+	return frames
+}
+
+func TestDiffFrames(t *testing.T) {
+	cases := []struct {
+		A, B     []tracerr.Frame
+		Equal    bool
+		Expected string
+	}{
+		{
+			A:     diffFrames("read", "load", "main"),
+			B:     diffFrames("read", "load", "main"),
+			Equal: true,
+			Expected: "  /src/main.go:10 main.read()\n" +
+				"  /src/main.go:20 main.load()\n" +
+				"  /src/main.go:30 main.main()",
+		},
+		{
+			A:     diffFrames("read", "load", "main"),
+			B:     diffFrames("read", "reload", "main"),
+			Equal: false,
+			Expected: "  /src/main.go:10 main.read()\n" +
+				"- /src/main.go:20 main.load()\n" +
+				"+ /src/main.go:20 main.reload()\n" +
+				"  /src/main.go:30 main.main()",
+		},
+		{
+			A:     diffFrames("read", "main"),
+			B:     append(diffFrames("read"), tracerr.NewFrame("main.main", "/src/main.go", 20), tracerr.NewFrame("main.init", "/src/main.go", 30)),
+			Equal: false,
+			Expected: "  /src/main.go:10 main.read()\n" +
+				"  /src/main.go:20 main.main()\n" +
+				"+ /src/main.go:30 main.init()",
+		},
+		{
+			A:        nil,
+			B:        nil,
+			Equal:    true,
+			Expected: "",
+		},
+	}
+
+	for i, c := range cases {
+		diff := tracerr.DiffFrames(c.A, c.B)
+		if diff.Equal() != c.Equal {
+			t.Errorf("case #%d: diff.Equal() = %v; want %v", i, diff.Equal(), c.Equal)
+		}
+		if len(diff.Prefix)+len(diff.A)+len(diff.Suffix) != len(c.A) || len(diff.Prefix)+len(diff.B)+len(diff.Suffix) != len(c.B) {
+			t.Errorf("case #%d: diff = %#v; want all frames", i, diff)
+		}
+		output := tracerr.SprintDiff(diff)
+		if output != c.Expected {
+			t.Errorf("case #%d: tracerr.SprintDiff(diff) = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package tracerr
+
+import (
+	"sync/atomic"
+)
+
+// disabled is true if capture is turned off by Disable.
+var disabled atomic.Bool
+
+// Disable turns off capture of stack traces, errors created and wrapped
+// afterwards have empty stack trace, which makes them nearly as cheap
+// as errors without tracerr. It also disables WrapAlways.
+//
+// Build with tracerr_notrace tag to turn off capture at compile time.
+func Disable() {
+	disabled.Store(true)
+}
+
+// Enable turns on capture of stack traces turned off by Disable.
+// It has no effect if built with tracerr_notrace tag.
+func Enable() {
+	disabled.Store(false)
+}
+
+// Enabled reports whether stack traces are captured.
+func Enabled() bool {
+	return captureBuilt && !disabled.Load()
+}
@@ -0,0 +1,33 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestDisable(t *testing.T) {
+	tracerr.Disable()
+	defer tracerr.Enable()
+	if tracerr.Enabled() {
+		t.Errorf("tracerr.Enabled() = true; want false")
+	}
+	for i, err := range []tracerr.Error{
+		tracerr.New("some error"),
+		tracerr.Errorf("some error"),
+		tracerr.Wrap(errors.New("some error")),
+		tracerr.WrapAlways(errors.New("some error")),
+	} {
+		if err.Error() != "some error" {
+			t.Errorf("case #%d: err.Error() = %#v; want %#v", i, err.Error(), "some error")
+		}
+		if frames := err.StackTrace(); len(frames) != 0 {
+			t.Errorf("case #%d: err.StackTrace() = %v; want empty", i, frames)
+		}
+	}
+	tracerr.Enable()
+	if frames := tracerr.New("some error").StackTrace(); len(frames) == 0 {
+		t.Errorf("err.StackTrace() = %v; want frames after tracerr.Enable()", frames)
+	}
+}
@@ -0,0 +1,60 @@
+package tracerr
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// doubleTraces is a number of errors detected by WithDoubleTraceDetection.
+var doubleTraces atomic.Int64
+
+// DoubleTrace describes an error, which got a second stack trace
+// though it wraps a traced error, see WithDoubleTraceDetection.
+type DoubleTrace struct {
+	// Err is an error with the second stack trace.
+	Err Error
+	// First is a wrapped error with the first stack trace.
+	First Error
+}
+
+// String returns locations of both captures.
+func (d DoubleTrace) String() string {
+	return fmt.Sprintf(
+		"tracerr: error traced twice, first at %s, again at %s",
+		captureLocation(d.First), captureLocation(d.Err),
+	)
+}
+
+func captureLocation(err Error) string {
+	frames := RawFrames(err)
+	if len(frames) == 0 {
+		return "unknown location"
+	}
+	return frames[0].String()
+}
+
+// WithDoubleTraceDetection is a debug option, which detects errors
+// getting a second stack trace, e.g. by Errorf wrapping a traced error
+// with %w, where Wrap would reuse the first one.
+// Such call sites waste capture and their stack traces may be misleading.
+//
+// Detected errors are passed to report, which may be nil,
+// and counted by DoubleTraceCount.
+func WithDoubleTraceDetection(report func(d DoubleTrace)) Option {
+	return WithOnCapture(func(err Error) {
+		first, ok := AsError(err.Unwrap())
+		if !ok || len(RawFrames(first)) == 0 {
+			return
+		}
+		doubleTraces.Add(1)
+		if report != nil {
+			report(DoubleTrace{Err: err, First: first})
+		}
+	})
+}
+
+// DoubleTraceCount returns a number of errors detected by
+// WithDoubleTraceDetection since start of the program.
+func DoubleTraceCount() int64 {
+	return doubleTraces.Load()
+}
@@ -0,0 +1,44 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithDoubleTraceDetection(t *testing.T) {
+	var reports []tracerr.DoubleTrace
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithDoubleTraceDetection(func(d tracerr.DoubleTrace) {
+			reports = append(reports, d)
+		}),
+	)
+	count := tracerr.DoubleTraceCount()
+
+	inner := tr.New("inner")
+	tr.Wrap(fmt.Errorf("wrapped: %w", inner))
+	tr.Errorf("not wrapped: %v", inner)
+	if len(reports) != 0 {
+		t.Fatalf("reports = %v; want none", reports)
+	}
+
+	outer := tr.Errorf("outer: %w", inner)
+	if len(reports) != 1 {
+		t.Fatalf("len(reports) = %d; want 1", len(reports))
+	}
+	if reports[0].Err != outer || reports[0].First != inner {
+		t.Errorf("reports[0] = %#v; want outer and inner errors", reports[0])
+	}
+	report := reports[0].String()
+	expected := fmt.Sprintf("first at %s, again at %s", inner.StackTrace()[0], outer.StackTrace()[0])
+	if !strings.HasSuffix(report, expected) {
+		t.Errorf("reports[0].String() = %#v; want suffix %#v", report, expected)
+	}
+	if n := tracerr.DoubleTraceCount() - count; n != 1 {
+		t.Errorf("tracerr.DoubleTraceCount() increased by %d; want 1", n)
+	}
+}
@@ -0,0 +1,51 @@
+package tracerr
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// WithEnclosingFunction shows the whole function enclosing traced line
+// of the top frame instead of a fixed number of lines,
+// if the function is not longer than maxLines.
+// Closures are shown instead of functions they are declared in.
+//
+// It has no effect if output has no source fragments.
+func WithEnclosingFunction(maxLines int) PrintOption {
+	return func(o *printOptions) {
+		o.enclosingMaxLines = maxLines
+	}
+}
+
+// enclosingFunc returns the first and the last lines
+// of the innermost function enclosing traced line of frame.
+func (o *printOptions) enclosingFunc(frame Frame) (start, end int, ok bool) {
+	lines, err := readLines(o.rewritePath(frame.Path))
+	if err != nil {
+		return 0, 0, false
+	}
+	fset := token.NewFileSet()
+	// Partial syntax tree is good enough for a file with errors.
+	file, _ := parser.ParseFile(fset, "", strings.Join(lines, "\n"), parser.SkipObjectResolution)
+	if file == nil {
+		return 0, 0, false
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil {
+			return false
+		}
+		nodeStart := fset.Position(node.Pos()).Line
+		nodeEnd := fset.Position(node.End()).Line
+		if frame.Line < nodeStart || frame.Line > nodeEnd {
+			return false
+		}
+		switch node.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			start, end, ok = nodeStart, nodeEnd, true
+		}
+		return true
+	})
+	return start, end, ok
+}
@@ -0,0 +1,134 @@
+package tracerr
+
+import (
+	"maps"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Environment is a snapshot of environment taken at capture,
+// see WithEnvironment. It's suitable for encoding/json.
+type Environment struct {
+	// Hostname is a host name, it's empty if it's unknown.
+	Hostname string `json:"hostname,omitempty"`
+	// PID is a process ID.
+	PID int `json:"pid"`
+	// GOOS and GOARCH are the operating system and architecture.
+	GOOS   string `json:"goos"`
+	GOARCH string `json:"goarch"`
+	// Vars are selected environment variables with redacted values.
+	Vars map[string]string `json:"vars,omitempty"`
+}
+
+// redactedValue replaces values of secret environment variables.
+const redactedValue = "[REDACTED]"
+
+// secretEnvNames are parts of names of environment variables,
+// which values are redacted by RedactSecretEnv.
+var secretEnvNames = []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "AUTH"}
+
+// RedactSecretEnv returns "[REDACTED]" for variables, which names look secret,
+// such as API_TOKEN or DB_PASSWORD, and value as is for the others.
+// It's the default redaction of WithEnvironment.
+func RedactSecretEnv(name, value string) string {
+	upper := strings.ToUpper(name)
+	for _, secret := range secretEnvNames {
+		if strings.Contains(upper, secret) {
+			return redactedValue
+		}
+	}
+	return value
+}
+
+// WithEnvironment attaches a snapshot of environment to captured errors
+// for incident triage: host name, PID, GOOS and GOARCH, and environment
+// variables matching patterns, such as "APP_*", in path.Match syntax.
+//
+// Values are passed through redact before they are kept,
+// nil redact means RedactSecretEnv. Return "" from redact to drop a variable.
+//
+// Snapshot is returned by Env, and included in WriteReport
+// and JSON of Recorder.
+func WithEnvironment(patterns []string, redact func(name, value string) string) Option {
+	if redact == nil {
+		redact = RedactSecretEnv
+	}
+	return func(t *tracerr) {
+		t.envPatterns = patterns
+		t.envRedact = redact
+		t.env = true
+	}
+}
+
+// snapshotEnvironment returns environment selected by options of t.
+func (t *tracerr) snapshotEnvironment() *Environment {
+	env := &Environment{
+		PID:    os.Getpid(),
+		GOOS:   runtime.GOOS,
+		GOARCH: runtime.GOARCH,
+	}
+	env.Hostname, _ = os.Hostname()
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !matchEnv(t.envPatterns, name) {
+			continue
+		}
+		if value = t.envRedact(name, value); value == "" {
+			continue
+		}
+		if env.Vars == nil {
+			env.Vars = make(map[string]string)
+		}
+		env.Vars[name] = value
+	}
+	return env
+}
+
+func matchEnv(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Env returns environment snapshot attached to err or any error it wraps,
+// see WithEnvironment. It reports false if there is none.
+func Env(err error) (*Environment, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if d, ok := err.(*errorData); ok && d != nil && d.env != nil {
+		return d.env, true
+	}
+	for _, wrapped := range unwrapAll(err) {
+		if env, ok := Env(wrapped); ok {
+			return env, true
+		}
+	}
+	return nil, false
+}
+
+// clone returns a copy of env, which is nil if env is nil.
+func (env *Environment) clone() *Environment {
+	if env == nil {
+		return nil
+	}
+	c := *env
+	c.Vars = maps.Clone(env.Vars)
+	return &c
+}
+
+// varNames returns sorted names of environment variables.
+func (env *Environment) varNames() []string {
+	names := make([]string, 0, len(env.Vars))
+	for name := range env.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
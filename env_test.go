@@ -0,0 +1,87 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithEnvironment(t *testing.T) {
+	t.Setenv("TRACERR_TEST_REGION", "eu-1")
+	t.Setenv("TRACERR_TEST_TOKEN", "secret")
+	t.Setenv("TRACERR_OTHER", "other")
+	tracer := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithEnvironment([]string{"TRACERR_TEST_*"}, nil),
+	)
+	err := fmt.Errorf("context: %w", tracer.New("some error"))
+
+	env, ok := tracerr.Env(err)
+	if !ok {
+		t.Fatalf("tracerr.Env(err) = nil, false; want environment")
+	}
+	if env.PID != os.Getpid() || env.GOOS != runtime.GOOS || env.GOARCH != runtime.GOARCH {
+		t.Errorf("env = %#v; want process info", env)
+	}
+	expected := map[string]string{
+		"TRACERR_TEST_REGION": "eu-1",
+		"TRACERR_TEST_TOKEN":  "[REDACTED]",
+	}
+	if fmt.Sprint(env.Vars) != fmt.Sprint(expected) {
+		t.Errorf("env.Vars = %#v; want %#v", env.Vars, expected)
+	}
+
+	// Environment is kept by wrapping and copying.
+	for i, wrapped := range []error{
+		tracerr.Wrap(err),
+		tracerr.Clone(tracerr.Wrap(err)),
+		tracerr.Merge(tracerr.Wrap(err), diffFrames("main")),
+	} {
+		if wrappedEnv, ok := tracerr.Env(wrapped); !ok || wrappedEnv.Vars["TRACERR_TEST_REGION"] != "eu-1" {
+			t.Errorf("case #%d: tracerr.Env(err) = %#v, %v; want environment", i, wrappedEnv, ok)
+		}
+	}
+
+	dir := t.TempDir()
+	path, writeErr := tracerr.WriteReport(dir, err)
+	if writeErr != nil {
+		t.Fatalf("tracerr.WriteReport() error = %v", writeErr)
+	}
+	report, _ := os.ReadFile(path)
+	if !strings.Contains(string(report), "TRACERR_TEST_REGION=eu-1\n") || strings.Contains(string(report), "secret") {
+		t.Errorf("report = %s; want captured environment with redaction", report)
+	}
+}
+
+func TestWithEnvironmentRedact(t *testing.T) {
+	t.Setenv("TRACERR_TEST_REGION", "eu-1")
+	t.Setenv("TRACERR_TEST_ZONE", "a")
+	tracer := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithEnvironment([]string{"TRACERR_TEST_*"}, func(name, value string) string {
+			if name == "TRACERR_TEST_ZONE" {
+				return ""
+			}
+			return strings.ToUpper(value)
+		}),
+	)
+	env, _ := tracerr.Env(tracer.New("some error"))
+	if len(env.Vars) != 1 || env.Vars["TRACERR_TEST_REGION"] != "EU-1" {
+		t.Errorf("env.Vars = %#v; want redacted by custom function", env.Vars)
+	}
+}
+
+func TestEnvNotCaptured(t *testing.T) {
+	for i, err := range []error{nil, errors.New("some error"), tracerr.New("some error")} {
+		if env, ok := tracerr.Env(err); ok {
+			t.Errorf("case #%d: tracerr.Env(err) = %#v, true; want nil, false", i, env)
+		}
+	}
+}
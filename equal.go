@@ -0,0 +1,113 @@
+package tracerr
+
+import "fmt"
+
+// EqualOption configures comparison of frames by Frame.Equal,
+// SameTrace and DiffFrames.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	ignoreLines bool
+	ignorePaths bool
+}
+
+// IgnoreLines makes frames equal regardless of line numbers,
+// so stack traces stay equal when code above is edited.
+func IgnoreLines() EqualOption {
+	return func(o *equalOptions) {
+		o.ignoreLines = true
+	}
+}
+
+// IgnorePaths makes frames equal regardless of file paths,
+// so stack traces of binaries built in different directories are equal.
+func IgnorePaths() EqualOption {
+	return func(o *equalOptions) {
+		o.ignorePaths = true
+	}
+}
+
+func newEqualOptions(options []EqualOption) equalOptions {
+	var o equalOptions
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// Equal reports whether f and other are the same step in stack trace,
+// they have the same function, path and line.
+// Program counters are not compared, since custom frames have none.
+func (f Frame) Equal(other Frame, options ...EqualOption) bool {
+	o := newEqualOptions(options)
+	return o.equal(f, other)
+}
+
+func (o equalOptions) equal(a, b Frame) bool {
+	return a.Func == b.Func &&
+		(o.ignorePaths || a.Path == b.Path) &&
+		(o.ignoreLines || a.Line == b.Line) &&
+		a.CreatedBy == b.CreatedBy
+}
+
+// SameTrace reports whether a and b have equal stack traces,
+// see Frame.Equal. Stack traces are taken from the first Error in chains
+// of a and b, see AsError. Errors without stack trace have empty one.
+func SameTrace(a, b error, options ...EqualOption) bool {
+	o := newEqualOptions(options)
+	fa, fb := rawFrames(a), rawFrames(b)
+	if len(fa) != len(fb) {
+		return false
+	}
+	for i := range fa {
+		if !o.equal(fa[i], fb[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// rawFrames returns stack trace of Error in err chain without copying,
+// or nil if there is none, see AsError.
+func rawFrames(err error) []Frame {
+	e, ok := AsError(err)
+	if !ok {
+		return nil
+	}
+	return RawFrames(e)
+}
+
+// Equaler decides whether two errors are repeats of the same error,
+// so Aggregator and RateLimitedPrinter deduplicate them by semantic
+// identity instead of fingerprint, see SetEqualer methods.
+type Equaler interface {
+	Equal(a, b error) bool
+}
+
+// EqualerFunc is an adapter to use a function as Equaler.
+type EqualerFunc func(a, b error) bool
+
+// Equal returns f(a, b).
+func (f EqualerFunc) Equal(a, b error) bool {
+	return f(a, b)
+}
+
+// EqualMessageAndCode reports whether a and b have the same message
+// and the same code returned by Code, no matter where they were traced.
+// It can be used as Equaler with EqualerFunc.
+func EqualMessageAndCode(a, b error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Error() == b.Error() && Code(a) == Code(b)
+}
+
+// uniqueKey returns fingerprint, or fingerprint with a number
+// if it's taken by another group of errors.
+func uniqueKey(fingerprint string, taken func(key string) bool) string {
+	key := fingerprint
+	for n := 2; taken(key); n++ {
+		key = fmt.Sprintf("%s-%d", fingerprint, n)
+	}
+	return key
+}
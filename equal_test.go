@@ -0,0 +1,95 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type FrameEqualTestCase struct {
+	A, B     tracerr.Frame
+	Options  []tracerr.EqualOption
+	Expected bool
+}
+
+func TestFrameEqual(t *testing.T) {
+	frame := tracerr.NewFrame("main.read", "/src/main.go", 10)
+	cases := []FrameEqualTestCase{
+		{A: frame, B: frame, Expected: true},
+		{A: frame, B: tracerr.NewFrame("main.load", "/src/main.go", 10), Expected: false},
+		{A: frame, B: tracerr.NewFrame("main.read", "/src/main.go", 12), Expected: false},
+		{
+			A:        frame,
+			B:        tracerr.NewFrame("main.read", "/src/main.go", 12),
+			Options:  []tracerr.EqualOption{tracerr.IgnoreLines()},
+			Expected: true,
+		},
+		{A: frame, B: tracerr.NewFrame("main.read", "/build/main.go", 10), Expected: false},
+		{
+			A:        frame,
+			B:        tracerr.NewFrame("main.read", "/build/main.go", 10),
+			Options:  []tracerr.EqualOption{tracerr.IgnorePaths()},
+			Expected: true,
+		},
+		{
+			A:        frame,
+			B:        tracerr.NewFrame("main.load", "/build/main.go", 12),
+			Options:  []tracerr.EqualOption{tracerr.IgnorePaths(), tracerr.IgnoreLines()},
+			Expected: false,
+		},
+	}
+
+	for i, c := range cases {
+		if equal := c.A.Equal(c.B, c.Options...); equal != c.Expected {
+			t.Errorf("case #%d: a.Equal(b) = %v; want %v", i, equal, c.Expected)
+		}
+	}
+}
+
+func TestSameTrace(t *testing.T) {
+	a := tracerr.CustomError(errors.New("a"), diffFrames("read", "main"))
+	b := tracerr.CustomError(errors.New("b"), []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 11),
+		tracerr.NewFrame("main.main", "/src/main.go", 21),
+	})
+	if tracerr.SameTrace(a, b) {
+		t.Errorf("tracerr.SameTrace(a, b) = true; want false")
+	}
+	if !tracerr.SameTrace(a, b, tracerr.IgnoreLines()) {
+		t.Errorf("tracerr.SameTrace(a, b, tracerr.IgnoreLines()) = false; want true")
+	}
+	if tracerr.SameTrace(a, tracerr.CustomError(errors.New("c"), diffFrames("read"))) {
+		t.Errorf("tracerr.SameTrace() = true for stack traces of different length; want false")
+	}
+	if tracerr.SameTrace(fmt.Errorf("wrapped: %w", a), b) {
+		t.Errorf("tracerr.SameTrace() = true for wrapped error with different stack trace; want false")
+	}
+	if !tracerr.SameTrace(fmt.Errorf("wrapped: %w", a), a) {
+		t.Errorf("tracerr.SameTrace() = false for wrapped error with the same stack trace; want true")
+	}
+	if !tracerr.SameTrace(errors.New("a"), errors.New("b")) {
+		t.Errorf("tracerr.SameTrace() = false for errors without stack trace; want true")
+	}
+}
+
+func TestEqualMessageAndCode(t *testing.T) {
+	cases := []struct {
+		a, b     error
+		expected bool
+	}{
+		{nil, nil, true},
+		{io.EOF, nil, false},
+		{tracerr.New("failed"), tracerr.CustomError(errors.New("failed"), diffFrames("read")), true},
+		{tracerr.New("failed"), tracerr.New("other"), false},
+		{tracerr.Wrap(&codeError{code: "A"}), tracerr.Wrap(&codeError{code: "A"}), true},
+		{&codeError{code: "A"}, fmt.Errorf("%w", &codeError{code: "B"}), false},
+	}
+	for i, c := range cases {
+		if equal := tracerr.EqualMessageAndCode(c.a, c.b); equal != c.expected {
+			t.Errorf("case #%d: tracerr.EqualMessageAndCode(%v, %v) = %t; want %t", i, c.a, c.b, equal, c.expected)
+		}
+	}
+}
@@ -7,7 +7,13 @@ package tracerr
 import (
 	"errors"
 	"fmt"
+	"iter"
+	"regexp"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // DefaultFrameCapacity is a default capacity for frames array.
@@ -22,26 +28,112 @@ var DefaultFrameSkipCount = 2
 type Tracerr interface {
 	CustomError(err error, frames []Frame) Error
 	Errorf(message string, args ...interface{}) Error
+	NewT(template string, args ...interface{}) Error
 	New(message string) Error
+	NewSkip(message string, skip int) Error
 	Wrap(err error) Error
+	WrapSkip(err error, skip int) Error
+	WrapAlways(err error) Error
+	Wrapf(err error, format string, args ...interface{}) Error
 	Unwrap(err error) error
 }
 
-func NewTracerr(frameCapacity int, stackFrameSkipCount int) Tracerr {
-	return &tracerr{
+func NewTracerr(frameCapacity int, stackFrameSkipCount int, options ...Option) Tracerr {
+	t := &tracerr{
 		frameCapacity:       frameCapacity,
 		stackFrameSkipCount: stackFrameSkipCount,
 	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// Option configures Tracerr created by NewTracerr.
+type Option func(t *tracerr)
+
+// WithSkipPackages trims frames of provided packages
+// from the top of stack trace, no matter how many of them there are.
+//
+// It is useful for packages of helpers built on top of tracerr.
+func WithSkipPackages(packages ...string) Option {
+	return func(t *tracerr) {
+		if t.skipPackages == nil {
+			t.skipPackages = make(map[string]bool, len(packages))
+		}
+		for _, pkg := range packages {
+			t.skipPackages[pkg] = true
+		}
+	}
+}
+
+// WithLazyFrames makes Tracerr capture only program counters of frames,
+// which are resolved to Frame on the first call of Error.StackTrace.
+//
+// Capture uses a pooled buffer and allocates only a copy of
+// program counters, stack trace depth is limited by LazyFramesMaxDepth.
+// It's useful for hot paths, where most of errors are never printed.
+func WithLazyFrames() Option {
+	return func(t *tracerr) {
+		t.lazyFrames = true
+	}
+}
+
+// WithTrimEntryPoints stops stack trace at the entry point of a program
+// or a test, so bootstrap frames of runtime and testing are dropped.
+// Frame of main.main is kept, while frames of runtime.main,
+// testing.tRunner and runtime.goexit are not.
+func WithTrimEntryPoints() Option {
+	return func(t *tracerr) {
+		t.trimEntryPoints = true
+	}
+}
+
+// WithOnCapture adds hook, which is called with every error
+// after its stack trace is captured, e.g. to increment metrics
+// or to forward errors to a reporter.
+// Hooks are called in order, errors skipped by sampling are not passed.
+func WithOnCapture(hook func(err Error)) Option {
+	return func(t *tracerr) {
+		t.onCapture = append(t.onCapture, hook)
+	}
 }
 
 type tracerr struct {
 	frameCapacity       int
 	stackFrameSkipCount int
+	skipPackages        map[string]bool
+	lazyFrames          bool
+	sampling            bool
+	sampleRate          float64
+	budget              *samplingBudget
+	trimEntryPoints     bool
+	onCapture           []func(err Error)
+	capturePackages     []*regexp.Regexp
+	skipCapturePackages []*regexp.Regexp
+	sourceHashes        bool
+	env                 bool
+	envPatterns         []string
+	envRedact           func(name, value string) string
+	frameEncoding       FrameEncoding
+	inlineSource        bool
+	inlineSourceLines   int
+	runtimeStats        bool
+	fingerprint         FingerprintStrategy
+	frameCache          *frameCache
+	goroutineIDs        bool
+	capacity            *adaptiveCapacity
 }
 
 var Default = NewTracerr(DefaultFrameCapacity, DefaultFrameSkipCount)
 
 func (t *tracerr) CustomError(err error, frames []Frame) Error {
+	if err == nil {
+		return nil
+	}
+	if frames == nil {
+		frames = []Frame{}
+	}
 	return &errorData{
 		err:    err,
 		frames: frames,
@@ -49,32 +141,77 @@ func (t *tracerr) CustomError(err error, frames []Frame) Error {
 }
 
 func (t *tracerr) Errorf(message string, args ...interface{}) Error {
-	return t.trace(fmt.Errorf(message, args...))
+	return t.trace(fmt.Errorf(message, args...), 0, false)
+}
+
+func (t *tracerr) NewT(template string, args ...interface{}) Error {
+	return t.trace(newTemplateError(template, args), 0, false)
 }
 
 func (t *tracerr) New(message string) Error {
-	return t.trace(fmt.Errorf(message))
+	return t.trace(errors.New(message), 0, false)
+}
+
+func (t *tracerr) NewSkip(message string, skip int) Error {
+	return t.trace(errors.New(message), skip, false)
 }
 
 func (t *tracerr) Wrap(err error) Error {
-	if err == nil {
+	return t.wrap(err, 0, false)
+}
+
+func (t *tracerr) WrapSkip(err error, skip int) Error {
+	return t.wrap(err, skip, false)
+}
+
+func (t *tracerr) WrapAlways(err error) Error {
+	return t.wrap(err, 0, true)
+}
+
+func (t *tracerr) wrap(err error, skip int, always bool) Error {
+	if isNilError(err) {
 		return nil
 	}
 	e, ok := err.(Error)
 	if ok {
+		if d, ok := e.(*errorData); ok && t.goroutineIDs {
+			return t.receive(d, skip)
+		}
 		return e
 	}
-	if wrapped := errors.Unwrap(err); wrapped != nil {
-		e, ok := wrapped.(*errorData)
-		err := fmt.Errorf("%w", Unwrap(err))
-		if ok {
-			return &errorData{
-				err:    err,
-				frames: e.frames,
+	if !Enabled() {
+		return &errorData{
+			err:    err,
+			frames: []Frame{},
+		}
+	}
+	if e, ok := AsError(err); ok {
+		// Stack trace of a wrapped traced error is more accurate,
+		// it's found in any branch of errors wrapping multiple errors.
+		d := &errorData{
+			err:    err,
+			frames: RawFrames(e),
+		}
+		d.env, _ = Env(e)
+		d.stats, _ = Stats(e)
+		d.trace, _ = Trace(e)
+		d.source = inlineSourceOf(e)
+		if traced, ok := e.(*errorData); ok {
+			d.fingerprint = traced.fingerprint
+			d.goroutine = traced.goroutine
+			if t.goroutineIDs {
+				return t.receive(d, skip)
 			}
 		}
+		return d
 	}
-	return t.trace(err)
+	if frames, ok := foreignFrames(err); ok {
+		return &errorData{
+			err:    err,
+			frames: frames,
+		}
+	}
+	return t.trace(err, skip, always)
 }
 
 func (t *tracerr) Unwrap(err error) error {
@@ -88,22 +225,97 @@ func (t *tracerr) Unwrap(err error) error {
 	return e.Unwrap()
 }
 
-func (t *tracerr) trace(err error) Error {
+// trace captures stack trace of the caller,
+// extraSkip is a number of caller's frames to skip in addition.
+// If always is false, capture may be skipped by sampling
+// or by package of the caller.
+func (t *tracerr) trace(err error, extraSkip int, always bool) Error {
+	if !Enabled() || !always && (!t.sample(err, extraSkip) || !t.capturePackage(extraSkip)) {
+		return &errorData{
+			err:    err,
+			frames: []Frame{},
+		}
+	}
+	var e Error
+	switch {
+	case t.frameCache != nil:
+		// Sources of cached frames are hashed once, before they are shared.
+		e = t.traceCached(err, extraSkip)
+	case t.lazyFrames:
+		e = t.traceLazy(err, extraSkip)
+	default:
+		e = t.traceFrames(err, extraSkip)
+	}
+	countCapture(e)
+	if t.sourceHashes && t.frameCache == nil {
+		hashSources(e)
+	}
+	if t.inlineSource {
+		if d, ok := e.(*errorData); ok {
+			d.source = snapshotSource(d.RawFrames(), t.inlineSourceLines)
+		}
+	}
+	if t.env {
+		if d, ok := e.(*errorData); ok {
+			d.env = t.snapshotEnvironment()
+		}
+	}
+	if t.runtimeStats {
+		if d, ok := e.(*errorData); ok {
+			d.stats = snapshotRuntimeStats()
+		}
+	}
+	if t.fingerprint != nil {
+		if d, ok := e.(*errorData); ok {
+			d.fingerprint = t.fingerprint
+		}
+	}
+	if t.goroutineIDs {
+		if d, ok := e.(*errorData); ok {
+			d.goroutine = currentGoroutineID()
+		}
+	}
+	for _, hook := range t.onCapture {
+		hook(e)
+	}
+	return e
+}
+
+// traceFrames captures stack trace of the caller by the same rules as trace.
+func (t *tracerr) traceFrames(err error, extraSkip int) Error {
 	skip := t.stackFrameSkipCount
-	frames := make([]Frame, 0, t.frameCapacity)
+	capacity := t.frameCapacity
+	if t.capacity != nil {
+		capacity = t.capacity.get()
+	}
+	frames := make([]Frame, 0, capacity)
 	for {
 		pc, path, line, ok := runtime.Caller(skip)
 		if !ok {
 			break
 		}
-		fn := runtime.FuncForPC(pc)
-		frame := Frame{
-			Func: fn.Name(),
-			Line: line,
-			Path: path,
+		frame := newFrame(pc, path, line)
+		skip++
+		if len(frames) == 0 {
+			// Frames of tracerr itself are never interesting to the caller.
+			if frame.Package == packageName || t.skipPackages[frame.Package] {
+				continue
+			}
+			if extraSkip > 0 {
+				extraSkip--
+				continue
+			}
+		}
+		if t.trimEntryPoints && isBootstrapFunc(frame.Func) {
+			break
 		}
 		frames = append(frames, frame)
-		skip++
+		if t.trimEntryPoints && frame.Func == "main.main" {
+			break
+		}
+	}
+	if t.capacity != nil {
+		t.capacity.observe(len(frames))
 	}
 	return &errorData{
 		err:    err,
@@ -114,6 +326,7 @@ func (t *tracerr) trace(err error) Error {
 // Error is an error with stack trace.
 type Error interface {
 	Error() string
+	// StackTrace returns a copy of stack trace, which is safe to modify.
 	StackTrace() []Frame
 	Unwrap() error
 }
@@ -123,30 +336,110 @@ type errorData struct {
 	err error
 	// frames contains stack trace of an error.
 	frames []Frame
+	// pcs contains program counters of stack trace,
+	// which are resolved to frames lazily.
+	pcs []uintptr
+	// resolve guards resolving of pcs.
+	resolve sync.Once
+	// trimEntryPoints is true if resolved frames stop at entry point.
+	trimEntryPoints bool
+	// isResolved is set when pcs are resolved to frames.
+	isResolved atomic.Bool
+	// env is environment snapshot, see WithEnvironment.
+	env *Environment
+	// stats is runtime stats snapshot, see WithRuntimeStats.
+	stats *RuntimeStats
+	// trace is trace context recorded by WrapContext, see Trace.
+	trace TraceContext
+	// fields are attached by WrapContext, see Fields.
+	fields map[string]interface{}
+	// source is copied at capture, see WithInlineSource.
+	source inlineSource
+	// fingerprint is set by WithFingerprint, see Fingerprint.
+	fingerprint FingerprintStrategy
+	// goroutine is ID of capturing goroutine, see WithGoroutineIDs.
+	goroutine uint64
 }
 
-// CustomError creates an error with provided frames.
-func CustomError(err error, frames []Frame) Error {
+// with returns errorData with err and frames,
+// which keeps other attributes of d.
+func (d *errorData) with(err error, frames []Frame) *errorData {
+	if d == nil {
+		return &errorData{err: err, frames: frames}
+	}
 	return &errorData{
-		err:    err,
-		frames: frames,
+		err:         err,
+		frames:      frames,
+		env:         d.env,
+		stats:       d.stats,
+		trace:       d.trace,
+		fields:      d.fields,
+		source:      d.source,
+		fingerprint: d.fingerprint,
+		goroutine:   d.goroutine,
 	}
 }
 
+// resolved reports whether pcs are resolved to frames.
+func (e *errorData) resolved() bool {
+	return e != nil && e.isResolved.Load()
+}
+
+// CustomError creates an error with provided frames.
+// It returns nil if err is nil, nil frames are stored as empty stack trace.
+func CustomError(err error, frames []Frame) Error {
+	return Default.CustomError(err, frames)
+}
+
 // Errorf creates new error with stacktrace and formatted message.
 // Formatting works the same way as in fmt.Errorf.
 func Errorf(message string, args ...interface{}) Error {
-	return Default.Errorf(message, args...)
+	return callerTracerr().Errorf(message, args...)
+}
+
+// NewT creates new error with stacktrace and message formatted
+// the same way as by Errorf, while template and args are kept
+// separately, see Template and Args.
+//
+// Errors created with the same template can be grouped together,
+// even if their arguments differ.
+func NewT(template string, args ...interface{}) Error {
+	return callerTracerr().NewT(template, args...)
 }
 
 // New creates new error with stacktrace.
 func New(message string) Error {
-	return Default.New(message)
+	return callerTracerr().New(message)
+}
+
+// NewSkip creates new error with stacktrace,
+// which starts skip frames above the caller.
+//
+// It is useful for helpers built on top of tracerr,
+// pass 1 to make stacktrace start at the helper's caller.
+func NewSkip(message string, skip int) Error {
+	return callerTracerr().NewSkip(message, skip)
 }
 
 // Wrap adds stacktrace to existing error.
+//
+// Wrapped error is kept as is, so errors.Is and errors.As
+// see through the stack trace, e.g. errors.Is(Wrap(io.EOF), io.EOF) is true.
 func Wrap(err error) Error {
-	return Default.Wrap(err)
+	return callerTracerr().Wrap(err)
+}
+
+// WrapSkip adds stacktrace to existing error,
+// which starts skip frames above the caller.
+// See NewSkip for details.
+func WrapSkip(err error, skip int) Error {
+	return callerTracerr().WrapSkip(err, skip)
+}
+
+// WrapAlways adds stacktrace to existing error
+// even if capture is sampled, see WithSampling.
+func WrapAlways(err error) Error {
+	return callerTracerr().WrapAlways(err)
 }
 
 // Unwrap returns the original error.
@@ -154,29 +447,260 @@ func Unwrap(err error) error {
 	return Default.Unwrap(err)
 }
 
-// Error returns error message.
+// Error returns error message,
+// it's empty for nil or zero value error.
 func (e *errorData) Error() string {
-	return e.err.Error()
+	if e == nil || isNilError(e.err) {
+		return ""
+	}
+	return e.message()
 }
 
-// StackTrace returns stack trace of an error.
+// StackTrace returns a copy of stack trace of an error.
 func (e *errorData) StackTrace() []Frame {
+	return slices.Clone(e.RawFrames())
+}
+
+// RawFrames returns stack trace of an error,
+// which must not be modified.
+func (e *errorData) RawFrames() []Frame {
+	if e == nil {
+		return nil
+	}
+	if e.pcs != nil {
+		e.resolve.Do(func() {
+			e.frames = resolveFrames(e.pcs, e.trimEntryPoints)
+			e.isResolved.Store(true)
+		})
+	}
 	return e.frames
 }
 
+// Frames returns iterator over stack trace.
+// Frames captured with WithLazyFrames are resolved on demand
+// unless stack trace has been resolved already.
+func (e *errorData) Frames() iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		if e == nil {
+			return
+		}
+		if e.pcs == nil || e.resolved() {
+			for _, frame := range e.RawFrames() {
+				if !yield(frame) {
+					return
+				}
+			}
+			return
+		}
+		for frame := range pcFrames(e.pcs, e.trimEntryPoints) {
+			if !yield(frame) {
+				return
+			}
+		}
+	}
+}
+
+// PCs returns a copy of program counters captured with WithLazyFrames.
+// They aren't trimmed by WithTrimEntryPoints.
+func (e *errorData) PCs() []uintptr {
+	if e == nil || e.pcs == nil {
+		return nil
+	}
+	return append(make([]uintptr, 0, len(e.pcs)), e.pcs...)
+}
+
 // Unwrap returns the original error.
 func (e *errorData) Unwrap() error {
+	if e == nil {
+		return nil
+	}
 	return e.err
 }
 
 // Frame is a single step in stack trace.
 type Frame struct {
-	// Func contains a function name.
+	// Func contains a fully qualified function name.
+	// It is "<cgo>" for code without Go symbol table, such as C code,
+	// or a C symbol name if it's reported by cgo traceback.
 	Func string
 	// Line contains a line number.
 	Line int
 	// Path contains a file path.
 	Path string
+	// PC contains a program counter, it is zero for custom frames.
+	PC uintptr
+	// Entry contains an entry program counter of the function,
+	// it is zero if it's unknown, see SprintAddresses.
+	Entry uintptr
+	// Package contains an import path of the function's package.
+	Package string
+	// Receiver contains a method receiver type, e.g. "*Thing".
+	// It is empty for plain functions.
+	Receiver string
+	// Name contains a function name without package and receiver.
+	Name string
+	// CreatedBy is true if the frame started goroutine of the frames above,
+	// see Go and Spawn.
+	CreatedBy bool
+	// SourceHash is a hash of traced line at capture, see WithSourceHashes.
+	// It is zero if it's unknown.
+	SourceHash uint32
+}
+
+// packageName is an import path of this package.
+const packageName = "github.com/kadaan/tracerr"
+
+// NewFrame creates a custom frame with no program counter,
+// Package, Receiver and Name are parsed from fully qualified function name.
+// Its strings are interned, see NewDecodedFrame for frames of untrusted input.
+func NewFrame(name, path string, line int) Frame {
+	return newNamedFrame(0, name, path, line)
+}
+
+// NewDecodedFrame is the same as NewFrame, but its strings aren't interned,
+// so frames of errors decoded from another process or parsed from
+// untrusted input can't fill the process-wide intern table.
+func NewDecodedFrame(name, path string, line int) Frame {
+	return newDecodedFrame(name, path, line)
+}
+
+func newFrame(pc uintptr, path string, line int) Frame {
+	if frame, ok := symbolize(pc); ok {
+		return frame
+	}
+	var name string
+	var entry uintptr
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		name, entry = fn.Name(), fn.Entry()
+	}
+	return newCapturedFrame(pc, entry, name, path, line)
+}
+
+// cgoFuncName is a placeholder name of a function with no symbol.
+const cgoFuncName = "<cgo>"
+
+// newCapturedFrame creates a frame of captured program counter,
+// which may have no function name and path in cgo or assembly code.
+func newCapturedFrame(pc, entry uintptr, name, path string, line int) Frame {
+	if name == "" {
+		name = cgoFuncName
+	}
+	if path == "" {
+		path = "?"
+	}
+	frame := newNamedFrame(pc, name, path, line)
+	frame.Entry = entry
+	return frame
+}
+
+func funcName(pc uintptr) string {
+	if frame, ok := symbolize(pc); ok {
+		return frame.Func
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+func newNamedFrame(pc uintptr, name, path string, line int) Frame {
+	name = intern(name)
+	frame := Frame{
+		Func: name,
+		Line: line,
+		Path: intern(path),
+		PC:   pc,
+	}
+	frame.Package, frame.Receiver, frame.Name = splitFuncName(name)
+	// Package is a copy if dots of its path are unescaped.
+	frame.Package = intern(frame.Package)
+	return frame
+}
+
+// newDecodedFrame returns frame of an error decoded from untrusted input,
+// which strings aren't interned.
+func newDecodedFrame(name, path string, line int) Frame {
+	frame := Frame{
+		Func: name,
+		Line: line,
+		Path: path,
+	}
+	frame.Package, frame.Receiver, frame.Name = splitFuncName(name)
+	return frame
+}
+
+// splitFuncName splits fully qualified function name
+// like "github.com/foo/bar.(*Thing).Do" into package, receiver and name.
+func splitFuncName(name string) (pkg, receiver, short string) {
+	// Package path ends at the first dot after the last slash,
+	// dots of the last path element are escaped by the compiler.
+	// Type arguments may contain slashes of their packages.
+	slash := strings.LastIndex(name, "/")
+	if bracket := strings.Index(name, "["); bracket >= 0 {
+		slash = strings.LastIndex(name[:bracket], "/")
+	}
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", "", name
+	}
+	dot += slash + 1
+	pkg = strings.Replace(name[:dot], "%2e", ".", -1)
+	short = name[dot+1:]
+	if strings.HasPrefix(short, "(") {
+		if end := strings.Index(short, ")."); end > 0 {
+			return pkg, short[1:end], short[end+2:]
+		}
+		return pkg, "", short
+	}
+	if i := indexDot(short); i > 0 && !isClosureName(short[i+1:]) {
+		return pkg, short[:i], short[i+1:]
+	}
+	return pkg, "", short
+}
+
+// indexDot returns an index of the first dot outside of
+// type parameters brackets like "[...]", or -1.
+func indexDot(name string) int {
+	depth := 0
+	for i, r := range name {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isClosureName reports whether name starts with a compiler generated
+// name of an anonymous function, e.g. "func1" or "gowrap2".
+func isClosureName(name string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		if rest == "" || rest[0] < '0' || rest[0] > '9' {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// isBootstrapFunc reports whether name is a function,
+// which runs main.main or a test, see WithTrimEntryPoints.
+func isBootstrapFunc(name string) bool {
+	switch name {
+	case "runtime.main", "runtime.goexit", "testing.tRunner":
+		return true
+	}
+	return false
 }
 
 // StackTrace returns stack trace of an error.
@@ -189,7 +713,61 @@ func StackTrace(err error) []Frame {
 	return e.StackTrace()
 }
 
+// RawFrames returns stack trace of an error without copying,
+// it's shared with the error and must not be modified.
+// Custom implementations of Error may provide RawFrames() []Frame method,
+// a copy returned by StackTrace is used otherwise.
+// It will be empty if err is not of type Error.
+func RawFrames(err error) []Frame {
+	switch e := err.(type) {
+	case interface{ RawFrames() []Frame }:
+		return e.RawFrames()
+	case Error:
+		return e.StackTrace()
+	}
+	return nil
+}
+
+// Frames returns iterator over stack trace of an error,
+// which resolves frames captured with WithLazyFrames on demand.
+// Custom implementations of Error may provide
+// Frames() iter.Seq[Frame] method, StackTrace is iterated otherwise.
+// It will be empty if err is not of type Error.
+func Frames(err error) iter.Seq[Frame] {
+	switch e := err.(type) {
+	case interface{ Frames() iter.Seq[Frame] }:
+		return e.Frames()
+	case Error:
+		return slices.Values(e.StackTrace())
+	}
+	return func(func(Frame) bool) {}
+}
+
+// PCs returns a copy of program counters of stack trace of an error
+// captured with WithLazyFrames, or nil for other errors.
+// Custom implementations of Error may provide PCs() []uintptr method.
+func PCs(err error) []uintptr {
+	if e, ok := err.(interface{ PCs() []uintptr }); ok {
+		return e.PCs()
+	}
+	return nil
+}
+
+// CallersFrames returns runtime.CallersFrames of PCs of an error,
+// so they can be resolved by the runtime with no copies of frames,
+// or nil if there are none.
+func CallersFrames(err error) *runtime.Frames {
+	pcs := PCs(err)
+	if pcs == nil {
+		return nil
+	}
+	return runtime.CallersFrames(pcs)
+}
+
 // String formats Frame to string.
+// Path is shortened if WithTrimPaths is set by SetPrintOptions,
+// layout can be changed by WithFrameLayout.
 func (f Frame) String() string {
-	return fmt.Sprintf("%s:%d %s()", f.Path, f.Line, f.Func)
+	o := currentPrintOptions()
+	return o.frameString(f)
 }
@@ -1,9 +1,12 @@
 package tracerr_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	pkgerrors "github.com/pkg/errors"
+
 	"github.com/kadaan/tracerr"
 )
 
@@ -26,6 +29,48 @@ func BenchmarkNew(b *testing.B) {
 	}
 }
 
+type CaptureBenchmark struct {
+	Name    string
+	Capture func() error
+}
+
+func BenchmarkCapture(b *testing.B) {
+	lazy := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithLazyFrames(),
+	)
+	cached := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithFrameCache(),
+	)
+	adaptive := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithAdaptiveCapacity(),
+	)
+	base := errors.New("test error")
+	benchmarks := []CaptureBenchmark{
+		{"tracerr.New", func() error { return tracerr.New("test error") }},
+		{"tracerr.Wrap", func() error { return tracerr.Wrap(base) }},
+		{"tracerr.New/lazy", func() error { return lazy.New("test error") }},
+		{"tracerr.Wrap/lazy", func() error { return lazy.Wrap(base) }},
+		{"tracerr.Wrap/cached", func() error { return cached.Wrap(base) }},
+		{"tracerr.New/adaptive", func() error { return adaptive.New("test error") }},
+		{"pkg/errors.New", func() error { return pkgerrors.New("test error") }},
+		{"pkg/errors.WithStack", func() error { return pkgerrors.WithStack(base) }},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bm.Capture()
+			}
+		})
+	}
+}
+
 func addFrames(depth int, message string) error {
 	if depth <= 1 {
 		return tracerr.New(message)
@@ -308,3 +308,317 @@ func TestUnwrap(t *testing.T) {
 func wrapError(err error) error {
 	return tracerr.Wrap(err)
 }
+
+type FrameNameTestCase struct {
+	Error            error
+	ExpectedFunc     string
+	ExpectedPackage  string
+	ExpectedReceiver string
+	ExpectedName     string
+}
+
+func TestFrameName(t *testing.T) {
+	h := &frameHelper{}
+	cases := []FrameNameTestCase{
+		{
+			Error:            tracerr.New("plain function"),
+			ExpectedFunc:     "github.com/kadaan/tracerr_test.TestFrameName",
+			ExpectedPackage:  "github.com/kadaan/tracerr_test",
+			ExpectedReceiver: "",
+			ExpectedName:     "TestFrameName",
+		},
+		{
+			Error:            h.pointer(),
+			ExpectedFunc:     "github.com/kadaan/tracerr_test.(*frameHelper).pointer",
+			ExpectedPackage:  "github.com/kadaan/tracerr_test",
+			ExpectedReceiver: "*frameHelper",
+			ExpectedName:     "pointer",
+		},
+		{
+			Error:            h.value(),
+			ExpectedFunc:     "github.com/kadaan/tracerr_test.frameHelper.value",
+			ExpectedPackage:  "github.com/kadaan/tracerr_test",
+			ExpectedReceiver: "frameHelper",
+			ExpectedName:     "value",
+		},
+		{
+			Error:            closure(),
+			ExpectedFunc:     "github.com/kadaan/tracerr_test.closure.func1",
+			ExpectedPackage:  "github.com/kadaan/tracerr_test",
+			ExpectedReceiver: "",
+			ExpectedName:     "closure.func1",
+		},
+	}
+
+	for i, c := range cases {
+		frame := tracerr.StackTrace(c.Error)[0]
+		if frame.Func != c.ExpectedFunc {
+			t.Errorf(
+				"cases[%#v] frame.Func = %#v; want %#v",
+				i, frame.Func, c.ExpectedFunc,
+			)
+		}
+		if frame.Package != c.ExpectedPackage {
+			t.Errorf(
+				"cases[%#v] frame.Package = %#v; want %#v",
+				i, frame.Package, c.ExpectedPackage,
+			)
+		}
+		if frame.Receiver != c.ExpectedReceiver {
+			t.Errorf(
+				"cases[%#v] frame.Receiver = %#v; want %#v",
+				i, frame.Receiver, c.ExpectedReceiver,
+			)
+		}
+		if frame.Name != c.ExpectedName {
+			t.Errorf(
+				"cases[%#v] frame.Name = %#v; want %#v",
+				i, frame.Name, c.ExpectedName,
+			)
+		}
+		if frame.PC == 0 {
+			t.Errorf("cases[%#v] frame.PC = 0; want non-zero", i)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	err := newInHelper("new error")
+	assertCallerFrame(t, "tracerr.NewSkip", err, "new error", "TestSkip")
+
+	err = wrapInHelper(errors.New("wrapped error"))
+	assertCallerFrame(t, "tracerr.WrapSkip", err, "wrapped error", "TestSkip")
+
+	if err := wrapInHelper(nil); err != nil {
+		t.Errorf("tracerr.WrapSkip(nil, 1) = %#v; want nil", err)
+	}
+}
+
+func TestWithSkipPackages(t *testing.T) {
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithSkipPackages("github.com/kadaan/tracerr_test"),
+	)
+	frames := tr.New("some error").StackTrace()
+	if len(frames) == 0 {
+		t.Fatalf("len(frames) = 0; want > 0")
+	}
+	if frames[0].Package != "testing" {
+		t.Errorf(
+			"frames[0].Package = %#v; want %#v",
+			frames[0].Package, "testing",
+		)
+	}
+}
+
+func TestWithLazyFrames(t *testing.T) {
+	lazy := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithLazyFrames(),
+	)
+	eager := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+	)
+	errs := []tracerr.Error{
+		lazy.New("lazy error"), eager.New("eager error"),
+	}
+	lazyFrames := errs[0].StackTrace()
+	eagerFrames := errs[1].StackTrace()
+	if len(lazyFrames) != len(eagerFrames) {
+		t.Fatalf(
+			"len(lazyFrames) = %#v; want %#v",
+			len(lazyFrames), len(eagerFrames),
+		)
+	}
+	for i := range lazyFrames {
+		if lazyFrames[i].Func != eagerFrames[i].Func ||
+			lazyFrames[i].Path != eagerFrames[i].Path ||
+			lazyFrames[i].Line != eagerFrames[i].Line {
+			t.Errorf(
+				"lazyFrames[%#v] = %#v; want %#v",
+				i, lazyFrames[i].String(), eagerFrames[i].String(),
+			)
+		}
+	}
+	if lazyFrames[0].Name != "TestWithLazyFrames" {
+		t.Errorf(
+			"lazyFrames[0].Name = %#v; want %#v",
+			lazyFrames[0].Name, "TestWithLazyFrames",
+		)
+	}
+}
+
+func TestCallersFrames(t *testing.T) {
+	lazy := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithLazyFrames(),
+	)
+	err := lazy.New("lazy error")
+	pcs := tracerr.PCs(err)
+	if len(pcs) == 0 {
+		t.Fatalf("len(tracerr.PCs(err)) = 0; want > 0")
+	}
+	pcs[0] = 0
+	if tracerr.PCs(err)[0] == 0 {
+		t.Errorf("tracerr.PCs(err) is shared with the error; want a copy")
+	}
+	frames := err.StackTrace()
+	callers := tracerr.CallersFrames(err)
+	for i := 0; ; i++ {
+		f, more := callers.Next()
+		if i < len(frames) && (f.Function != frames[i].Func || f.Line != frames[i].Line) {
+			t.Errorf("callers[%d] = %s:%d; want %s", i, f.Function, f.Line, frames[i].String())
+		}
+		if !more {
+			break
+		}
+	}
+
+	eager := tracerr.New("eager error")
+	if pcs := tracerr.PCs(eager); pcs != nil {
+		t.Errorf("tracerr.PCs(eager) = %#v; want nil", pcs)
+	}
+	if callers := tracerr.CallersFrames(eager); callers != nil {
+		t.Errorf("tracerr.CallersFrames(eager) = %#v; want nil", callers)
+	}
+}
+
+func TestWithTrimEntryPoints(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		options := []tracerr.Option{tracerr.WithTrimEntryPoints()}
+		if lazy {
+			options = append(options, tracerr.WithLazyFrames())
+		}
+		tr := tracerr.NewTracerr(
+			tracerr.DefaultFrameCapacity,
+			tracerr.DefaultFrameSkipCount,
+			options...,
+		)
+		frames := tr.New("some error").StackTrace()
+		if len(frames) != 1 {
+			t.Fatalf("lazy = %#v: len(frames) = %#v; want %#v", lazy, len(frames), 1)
+		}
+		if frames[0].Name != "TestWithTrimEntryPoints" {
+			t.Errorf(
+				"lazy = %#v: frames[0].Name = %#v; want %#v",
+				lazy, frames[0].Name, "TestWithTrimEntryPoints",
+			)
+		}
+	}
+}
+
+func TestWithOnCapture(t *testing.T) {
+	var captured []tracerr.Error
+	calls := 0
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithOnCapture(func(err tracerr.Error) {
+			captured = append(captured, err)
+		}),
+		tracerr.WithOnCapture(func(err tracerr.Error) {
+			calls++
+		}),
+	)
+	errs := []tracerr.Error{
+		tr.New("new error"),
+		tr.Errorf("formatted %s", "error"),
+		tr.Wrap(errors.New("wrapped error")),
+	}
+	if tr.Wrap(nil) != nil {
+		t.Errorf("tr.Wrap(nil) = %#v; want nil", tr.Wrap(nil))
+	}
+	if len(captured) != len(errs) || calls != len(errs) {
+		t.Fatalf(
+			"len(captured), calls = %#v, %#v; want %#v, %#v",
+			len(captured), calls, len(errs), len(errs),
+		)
+	}
+	for i, err := range errs {
+		if captured[i] != err {
+			t.Errorf("captured[%d] = %#v; want %#v", i, captured[i], err)
+		}
+	}
+	if captured[0].StackTrace()[0].Name != "TestWithOnCapture" {
+		t.Errorf(
+			"captured[0].StackTrace()[0].Name = %#v; want %#v",
+			captured[0].StackTrace()[0].Name, "TestWithOnCapture",
+		)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		var options []tracerr.Option
+		if lazy {
+			options = append(options, tracerr.WithLazyFrames())
+		}
+		tr := tracerr.NewTracerr(
+			tracerr.DefaultFrameCapacity,
+			tracerr.DefaultFrameSkipCount,
+			options...,
+		)
+		err := tr.New("some error")
+		var frames []tracerr.Frame
+		for frame := range tracerr.Frames(err) {
+			frames = append(frames, frame)
+		}
+		stackTrace := err.StackTrace()
+		if len(frames) != len(stackTrace) {
+			t.Fatalf("lazy = %#v: len(frames) = %#v; want %#v", lazy, len(frames), len(stackTrace))
+		}
+		for i := range frames {
+			if frames[i] != stackTrace[i] {
+				t.Errorf("lazy = %#v: frames[%d] = %#v; want %#v", lazy, i, frames[i], stackTrace[i])
+			}
+		}
+		for frame := range tracerr.Frames(err) {
+			if frame.Name != "TestFrames" {
+				t.Errorf("lazy = %#v: first frame = %#v; want %#v", lazy, frame.Name, "TestFrames")
+			}
+			break
+		}
+	}
+	for frame := range tracerr.Frames(errors.New("some error")) {
+		t.Errorf("tracerr.Frames(err) yielded %#v; want none", frame)
+	}
+}
+
+func TestStackTraceCopy(t *testing.T) {
+	err := tracerr.New("some error")
+	frames := err.StackTrace()
+	name := frames[0].Name
+	frames[0].Name = "modified"
+	if err.StackTrace()[0].Name != name {
+		t.Errorf("err.StackTrace()[0].Name = %#v; want %#v", err.StackTrace()[0].Name, name)
+	}
+	if tracerr.RawFrames(err)[0].Name != name {
+		t.Errorf("tracerr.RawFrames(err)[0].Name = %#v; want %#v", tracerr.RawFrames(err)[0].Name, name)
+	}
+	if len(tracerr.RawFrames(err)) != len(frames) {
+		t.Errorf("len(tracerr.RawFrames(err)) = %#v; want %#v", len(tracerr.RawFrames(err)), len(frames))
+	}
+	if frames := tracerr.CustomError(errors.New("some error"), nil).StackTrace(); frames == nil || len(frames) != 0 {
+		t.Errorf("StackTrace() of nil frames = %#v; want empty", frames)
+	}
+}
+
+func TestCustomErrorInputs(t *testing.T) {
+	if err := tracerr.CustomError(nil, []tracerr.Frame{tracerr.NewFrame("main.main", "/src/main.go", 10)}); err != nil {
+		t.Errorf("tracerr.CustomError(nil, frames) = %#v; want nil", err)
+	}
+	if err := tracerr.Default.CustomError(nil, nil); err != nil {
+		t.Errorf("tracerr.Default.CustomError(nil, nil) = %#v; want nil", err)
+	}
+	err := tracerr.CustomError(errors.New("some error"), nil)
+	if frames := tracerr.RawFrames(err); frames == nil || len(frames) != 0 {
+		t.Errorf("err.RawFrames() = %#v; want empty", frames)
+	}
+	if output := tracerr.Sprint(err); output != "some error" {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, "some error")
+	}
+}
@@ -0,0 +1,88 @@
+package tracerr
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// DefaultExitCode is returned by ExitCode for errors with no exit code.
+const DefaultExitCode = 1
+
+var (
+	exitCodesMutex sync.RWMutex
+	// exitCodes are exit codes of error codes, see RegisterExitCode.
+	exitCodes = map[string]int{}
+	// sentinelExitCodes are exit codes of sentinel errors in order
+	// of registration, see RegisterSentinelExitCode.
+	sentinelExitCodes []sentinelExitCode
+)
+
+type sentinelExitCode struct {
+	target error
+	exit   int
+}
+
+// RegisterExitCode makes ExitCode return exit for errors of code,
+// see Code. Registering the same code again replaces its exit code.
+func RegisterExitCode(code string, exit int) {
+	exitCodesMutex.Lock()
+	defer exitCodesMutex.Unlock()
+	exitCodes[code] = exit
+}
+
+// RegisterSentinelExitCode makes ExitCode return exit for errors,
+// which match target by errors.Is, e.g. context.Canceled.
+// Sentinels are checked in order of registration before error codes.
+func RegisterSentinelExitCode(target error, exit int) {
+	exitCodesMutex.Lock()
+	defer exitCodesMutex.Unlock()
+	for i, sentinel := range sentinelExitCodes {
+		if sentinel.target == target {
+			sentinelExitCodes[i].exit = exit
+			return
+		}
+	}
+	sentinelExitCodes = append(sentinelExitCodes, sentinelExitCode{target: target, exit: exit})
+}
+
+// ExitCode returns exit code of a process failed with err:
+// zero for nil error, exit code registered for a sentinel error
+// by RegisterSentinelExitCode or for code of err by RegisterExitCode,
+// exit code of an error in err tree with ExitCode() int method,
+// such as *exec.ExitError, or DefaultExitCode otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	exitCodesMutex.RLock()
+	defer exitCodesMutex.RUnlock()
+	for _, sentinel := range sentinelExitCodes {
+		if errors.Is(err, sentinel.target) {
+			return sentinel.exit
+		}
+	}
+	if code := Code(err); code != "" {
+		if exit, ok := exitCodes[code]; ok {
+			return exit
+		}
+	}
+	var e interface{ ExitCode() int }
+	if errors.As(err, &e) {
+		return e.ExitCode()
+	}
+	return DefaultExitCode
+}
+
+// Fatal prints err with colored source fragments to os.Stderr,
+// the same as FprintSourceColor, and exits with ExitCode of err.
+// It does nothing for nil error, so it can end main of a CLI:
+//
+//	tracerr.Fatal(run())
+func Fatal(err error) {
+	if err == nil {
+		return
+	}
+	FprintSourceColor(os.Stderr, err)
+	os.Exit(ExitCode(err))
+}
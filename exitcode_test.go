@@ -0,0 +1,63 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type exitCodeError struct{}
+
+func (exitCodeError) Error() string { return "exit code error" }
+func (exitCodeError) ExitCode() int { return 7 }
+
+func TestExitCode(t *testing.T) {
+	tracerr.RegisterExitCode("E404", 4)
+	tracerr.RegisterSentinelExitCode(context.Canceled, 130)
+	tracerr.RegisterSentinelExitCode(io.EOF, 2)
+	tracerr.RegisterSentinelExitCode(io.EOF, 3)
+
+	cases := []struct {
+		Err      error
+		Expected int
+	}{
+		{Err: nil, Expected: 0},
+		{Err: errors.New("some error"), Expected: tracerr.DefaultExitCode},
+		{Err: tracerr.Wrap(&codeError{"E404"}), Expected: 4},
+		{Err: tracerr.Wrap(&codeError{"E500"}), Expected: tracerr.DefaultExitCode},
+		{Err: fmt.Errorf("context: %w", tracerr.Wrap(context.Canceled)), Expected: 130},
+		{Err: io.EOF, Expected: 3},
+		{Err: tracerr.Wrap(exitCodeError{}), Expected: 7},
+	}
+	for i, c := range cases {
+		if exit := tracerr.ExitCode(c.Err); exit != c.Expected {
+			t.Errorf("case #%d: tracerr.ExitCode(%v) = %#v; want %#v", i, c.Err, exit, c.Expected)
+		}
+	}
+}
+
+func TestFatal(t *testing.T) {
+	if os.Getenv("TRACERR_TEST_FATAL") == "1" {
+		tracerr.Fatal(nil)
+		tracerr.RegisterExitCode("E404", 4)
+		tracerr.Fatal(tracerr.Wrap(&codeError{"E404"}))
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatal$")
+	cmd.Env = append(os.Environ(), "TRACERR_TEST_FATAL=1")
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 4 {
+		t.Fatalf("tracerr.Fatal() exited with %v; want exit code 4, output:\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "TestFatal()") {
+		t.Errorf("output = %q; want stack trace", output)
+	}
+}
@@ -0,0 +1,77 @@
+package tracerr
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ExpvarName is a name of expvar variable published by PublishExpvar.
+const ExpvarName = "tracerr.errors"
+
+// ExpvarMaxGroups is a maximum number of the most frequent groups
+// published by PublishExpvar.
+var ExpvarMaxGroups = 20
+
+var (
+	expvarAggregator atomic.Pointer[Aggregator]
+	expvarOnce       sync.Once
+)
+
+// expvarGroup is a published group of errors.
+type expvarGroup struct {
+	Count    int       `json:"count"`
+	Message  string    `json:"message"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// WithAggregator adds every captured error to a.
+// Errors skipped by sampling are not added.
+func WithAggregator(a *Aggregator) Option {
+	return WithOnCapture(func(err Error) {
+		a.Add(err)
+	})
+}
+
+// PublishExpvar publishes the most frequent groups of a as expvar
+// variable named ExpvarName, a map of fingerprints to counts,
+// messages and times of the last occurrences, so it's served
+// at /debug/vars next to memstats. Messages are scrubbed,
+// see SetMessageScrubbers. Call it again to publish another aggregator,
+// nil aggregator publishes an empty map.
+//
+// Pair it with WithAggregator to publish every captured error:
+//
+//	hotspots := tracerr.NewAggregator(tracerr.WithTTL(time.Hour))
+//	tracerr.Default = tracerr.NewTracerr(
+//		tracerr.DefaultFrameCapacity,
+//		tracerr.DefaultFrameSkipCount,
+//		tracerr.WithAggregator(hotspots),
+//	)
+//	tracerr.PublishExpvar(hotspots)
+func PublishExpvar(a *Aggregator) {
+	expvarAggregator.Store(a)
+	expvarOnce.Do(func() {
+		expvar.Publish(ExpvarName, expvar.Func(expvarGroups))
+	})
+}
+
+func expvarGroups() interface{} {
+	groups := map[string]expvarGroup{}
+	a := expvarAggregator.Load()
+	if a == nil {
+		return groups
+	}
+	for i, group := range a.Groups() {
+		if i == ExpvarMaxGroups {
+			break
+		}
+		groups[group.Fingerprint] = expvarGroup{
+			Count:    group.Count,
+			Message:  ScrubMessage(group.Err.Error()),
+			LastSeen: group.LastSeen,
+		}
+	}
+	return groups
+}
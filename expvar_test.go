@@ -0,0 +1,65 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	defer tracerr.PublishExpvar(nil)
+
+	a := tracerr.NewAggregator()
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithAggregator(a),
+	)
+	var last tracerr.Error
+	for i := 0; i < 3; i++ {
+		last = tr.New("user@example.com not found")
+	}
+	tr.New("other")
+
+	defer tracerr.SetMessageScrubbers()
+	tracerr.SetMessageScrubbers(tracerr.ScrubEmails)
+	tracerr.PublishExpvar(a)
+
+	v := expvar.Get(tracerr.ExpvarName)
+	if v == nil {
+		t.Fatalf("expvar.Get(%#v) = nil; want published variable", tracerr.ExpvarName)
+	}
+	var groups map[string]struct {
+		Count    int       `json:"count"`
+		Message  string    `json:"message"`
+		LastSeen time.Time `json:"lastSeen"`
+	}
+	if err := json.Unmarshal([]byte(v.String()), &groups); err != nil {
+		t.Fatalf("json.Unmarshal() = %v; want nil", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) = %d; want 2", len(groups))
+	}
+	group := groups[tracerr.Fingerprint(last)]
+	if group.Count != 3 || group.Message != "[email] not found" {
+		t.Errorf("groups[fingerprint] = %#v; want 3 scrubbed errors", group)
+	}
+	if group.LastSeen.IsZero() {
+		t.Errorf("groups[fingerprint].LastSeen is zero; want time of the last error")
+	}
+
+	defer func(max int) { tracerr.ExpvarMaxGroups = max }(tracerr.ExpvarMaxGroups)
+	tracerr.ExpvarMaxGroups = 1
+	groups = nil
+	if err := json.Unmarshal([]byte(v.String()), &groups); err != nil || len(groups) != 1 {
+		t.Errorf("groups = %#v, %v; want the most frequent group", groups, err)
+	}
+
+	tracerr.PublishExpvar(nil)
+	if s := v.String(); s != "{}" {
+		t.Errorf("v.String() = %#v; want %#v", s, "{}")
+	}
+}
@@ -0,0 +1,199 @@
+package tracerr
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+type contextFieldsKey struct{}
+
+type contextErrorKey struct{}
+
+// ContextWithFields returns a copy of ctx with fields added to the ones
+// already stored in ctx, e.g. request ID or tenant set once by middleware.
+// Errors wrapped by WrapContext with the context inherit them.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	merged := maps.Clone(contextFields(ctx))
+	if merged == nil {
+		merged = make(map[string]interface{}, len(fields))
+	}
+	maps.Copy(merged, fields)
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// contextFields returns fields stored in ctx, which must not be modified.
+func contextFields(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// WrapContext adds stacktrace to err by the same rules as Wrap,
+// and attaches fields stored in ctx by ContextWithFields,
+// fields already attached to err take precedence. See Fields.
+//
+// Trace context of ctx, see SetTraceExtractor and ContextWithTrace,
+// is recorded unless err already has one, see Trace.
+//
+// If err is context.Canceled or context.DeadlineExceeded and ctx has
+// another cause set by context.WithCancelCause and the like,
+// the cause is recorded in the error, so errors.Is and errors.As find it,
+// and its stack trace is appended after a marker frame, see Merge.
+// It shows the code, which canceled the context.
+func WrapContext(ctx context.Context, err error) Error {
+	e := Wrap(err)
+	if e == nil {
+		return nil
+	}
+	if cause := cancelCause(ctx, err); cause != nil {
+		e = withCause(e, cause)
+	}
+	if fields := contextFields(ctx); len(fields) > 0 {
+		e = withFields(e, fields)
+	}
+	if trace, ok := contextTrace(ctx); ok {
+		e = withTrace(e, trace)
+	}
+	return e
+}
+
+// WrapFields adds stacktrace to err by the same rules as Wrap,
+// and attaches fields to it, fields already attached to err take precedence.
+// Fields belong to the layer of err, so wrapping it with another message,
+// e.g. by fmt.Errorf and WrapFields, gives the new layer its own fields,
+// see FieldLayers.
+func WrapFields(err error, fields map[string]interface{}) Error {
+	e := Wrap(err)
+	if e == nil || len(fields) == 0 {
+		return e
+	}
+	return withFields(e, fields)
+}
+
+// withFields returns e with fields added to its own ones.
+func withFields(e Error, fields map[string]interface{}) Error {
+	d, ok := e.(*errorData)
+	if !ok {
+		return &errorData{
+			err:    e,
+			frames: RawFrames(e),
+			fields: maps.Clone(fields),
+		}
+	}
+	merged := maps.Clone(fields)
+	maps.Copy(merged, d.fields)
+	c := d.with(d.err, d.RawFrames())
+	c.fields = merged
+	return c
+}
+
+// Fields returns fields attached to err and errors it wraps,
+// fields of outer errors take precedence. It returns nil if there are none.
+func Fields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	var walk func(err error)
+	walk = func(err error) {
+		for key, value := range ownFields(err) {
+			if fields == nil {
+				fields = make(map[string]interface{})
+			}
+			if _, ok := fields[key]; !ok {
+				fields[key] = value
+			}
+		}
+		for _, wrapped := range unwrapAll(err) {
+			walk(wrapped)
+		}
+	}
+	if err != nil {
+		walk(err)
+	}
+	return fields
+}
+
+// FieldLayer is fields attached to a single error of a wrap chain.
+type FieldLayer struct {
+	// Message is a message of the error.
+	Message string
+	// Fields are attached to the error itself, not to errors it wraps.
+	Fields map[string]interface{}
+}
+
+// FieldLayers returns fields attached to err and errors it wraps
+// grouped by error, from the outermost to the innermost, so it's known
+// which layer contributed which fields. Errors with no fields are skipped,
+// keys shadowed by outer layers in Fields are kept.
+func FieldLayers(err error) []FieldLayer {
+	var layers []FieldLayer
+	var walk func(err error)
+	walk = func(err error) {
+		if own := ownFields(err); len(own) > 0 {
+			layers = append(layers, FieldLayer{Message: err.Error(), Fields: own})
+		}
+		for _, wrapped := range unwrapAll(err) {
+			walk(wrapped)
+		}
+	}
+	if err != nil {
+		walk(err)
+	}
+	return layers
+}
+
+// ownFields returns fields attached to err itself.
+func ownFields(err error) map[string]interface{} {
+	switch e := err.(type) {
+	case *errorData:
+		if e != nil {
+			return e.fields
+		}
+	case TracedError:
+		return e.Fields()
+	}
+	return nil
+}
+
+// fieldRows returns fields of err for output, a row per layer
+// followed by its message, or a single row if there is one layer.
+func fieldRows(err error) []string {
+	layers := FieldLayers(err)
+	if len(layers) == 1 {
+		return []string{fieldsString(layers[0].Fields)}
+	}
+	rows := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		rows = append(rows, fmt.Sprintf("%s (%s)", fieldsString(layer.Fields), layer.Message))
+	}
+	return rows
+}
+
+// fieldsString formats fields as "key=value" pairs sorted by key.
+func fieldsString(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, fields[key]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// ContextWithError returns a copy of ctx with err wrapped by Wrap,
+// e.g. so middleware can report an error of a request handled downstream.
+func ContextWithError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, contextErrorKey{}, Wrap(err))
+}
+
+// FromContext returns error stored in ctx by ContextWithError, or nil.
+func FromContext(ctx context.Context) Error {
+	e, _ := ctx.Value(contextErrorKey{}).(Error)
+	return e
+}
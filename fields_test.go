@@ -0,0 +1,122 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWrapContext(t *testing.T) {
+	ctx := tracerr.ContextWithFields(context.Background(), map[string]interface{}{"request_id": "abc"})
+	ctx = tracerr.ContextWithFields(ctx, map[string]interface{}{"tenant": "acme"})
+
+	err := tracerr.WrapContext(ctx, io.EOF)
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(err, io.EOF) = false; want true")
+	}
+	expected := map[string]interface{}{"request_id": "abc", "tenant": "acme"}
+	if fields := tracerr.Fields(fmt.Errorf("context: %w", err)); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("tracerr.Fields(err) = %#v; want %#v", fields, expected)
+	}
+	if frames := err.StackTrace(); len(frames) == 0 || frames[0].Name != "TestWrapContext" {
+		t.Errorf("err.StackTrace() = %#v; want to start at the caller", frames)
+	}
+
+	// Fields of the error take precedence over the context.
+	other := tracerr.ContextWithFields(context.Background(), map[string]interface{}{"tenant": "other", "zone": "a"})
+	rewrapped := tracerr.WrapContext(other, err)
+	expected = map[string]interface{}{"request_id": "abc", "tenant": "acme", "zone": "a"}
+	if fields := tracerr.Fields(rewrapped); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("tracerr.Fields(rewrapped) = %#v; want %#v", fields, expected)
+	}
+	if !reflect.DeepEqual(rewrapped.StackTrace(), err.StackTrace()) {
+		t.Errorf("rewrapped.StackTrace() = %#v; want the original one", rewrapped.StackTrace())
+	}
+}
+
+func TestWrapContextWithoutFields(t *testing.T) {
+	if err := tracerr.WrapContext(context.Background(), nil); err != nil {
+		t.Errorf("tracerr.WrapContext(ctx, nil) = %#v; want nil", err)
+	}
+	err := tracerr.New("some error")
+	if wrapped := tracerr.WrapContext(context.Background(), err); wrapped != err {
+		t.Errorf("tracerr.WrapContext(ctx, err) = %#v; want err", wrapped)
+	}
+	if fields := tracerr.Fields(err); fields != nil {
+		t.Errorf("tracerr.Fields(err) = %#v; want nil", fields)
+	}
+	var ctx context.Context
+	if wrapped := tracerr.WrapContext(ctx, err); wrapped != err {
+		t.Errorf("tracerr.WrapContext(nil, err) = %#v; want err", wrapped)
+	}
+}
+
+func TestFieldsOutput(t *testing.T) {
+	ctx := tracerr.ContextWithFields(context.Background(), map[string]interface{}{"tenant": "acme", "attempt": 2})
+	err := tracerr.WrapContext(ctx, tracerr.CustomError(errors.New("some error"), diffFrames("read")))
+	expected := "some error\n" +
+		"attempt=2 tenant=acme\n" +
+		"/src/main.go:10 main.read()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+	if node := tracerr.Tree(err); node.Fields["tenant"] != "acme" {
+		t.Errorf("tracerr.Tree(err).Fields = %#v; want fields", node.Fields)
+	}
+}
+
+func TestContextWithError(t *testing.T) {
+	if err := tracerr.FromContext(context.Background()); err != nil {
+		t.Errorf("tracerr.FromContext(ctx) = %#v; want nil", err)
+	}
+	ctx := tracerr.ContextWithError(context.Background(), io.EOF)
+	err := tracerr.FromContext(ctx)
+	if !errors.Is(err, io.EOF) || len(err.StackTrace()) == 0 {
+		t.Errorf("tracerr.FromContext(ctx) = %#v; want traced io.EOF", err)
+	}
+}
+
+func TestFieldLayers(t *testing.T) {
+	inner := tracerr.WrapFields(tracerr.CustomError(errors.New("some error"), diffFrames("read")), map[string]interface{}{"path": "/etc/app", "attempt": 1})
+	outer := tracerr.WrapFields(fmt.Errorf("load config: %w", inner), map[string]interface{}{"attempt": 2, "tenant": "acme"})
+
+	expected := []tracerr.FieldLayer{
+		{Message: "load config: some error", Fields: map[string]interface{}{"attempt": 2, "tenant": "acme"}},
+		{Message: "some error", Fields: map[string]interface{}{"path": "/etc/app", "attempt": 1}},
+	}
+	if layers := tracerr.FieldLayers(outer); !reflect.DeepEqual(layers, expected) {
+		t.Errorf("tracerr.FieldLayers(outer) = %#v; want %#v", layers, expected)
+	}
+	// Fields of outer layers take precedence when flattened.
+	if fields := tracerr.Fields(outer); fields["attempt"] != 2 || fields["path"] != "/etc/app" {
+		t.Errorf("tracerr.Fields(outer) = %#v; want merged fields", fields)
+	}
+
+	output := tracerr.Sprint(outer)
+	expectedOutput := "load config: some error\n" +
+		"attempt=2 tenant=acme (load config: some error)\n" +
+		"attempt=1 path=/etc/app (some error)\n" +
+		"/src/main.go:10 main.read()"
+	if output != expectedOutput {
+		t.Errorf("tracerr.Sprint(outer) = %#v; want %#v", output, expectedOutput)
+	}
+
+	node := tracerr.Tree(outer)
+	if node.Fields["tenant"] != "acme" || len(node.Children) != 1 || node.Children[0].Fields["path"] != "/etc/app" {
+		t.Errorf("tracerr.Tree(outer) = %#v; want fields per node", node)
+	}
+
+	for i, err := range []error{nil, errors.New("some error"), tracerr.New("some error")} {
+		if layers := tracerr.FieldLayers(err); layers != nil {
+			t.Errorf("case #%d: tracerr.FieldLayers(err) = %#v; want nil", i, layers)
+		}
+	}
+	if err := tracerr.WrapFields(nil, map[string]interface{}{"a": 1}); err != nil {
+		t.Errorf("tracerr.WrapFields(nil) = %#v; want nil", err)
+	}
+}
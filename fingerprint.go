@@ -0,0 +1,111 @@
+package tracerr
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// FingerprintStrategy computes fingerprints of errors captured by Tracerr,
+// see WithFingerprint. Monitoring backends group errors differently,
+// so the strategy can be chosen to match the grouping they expect.
+type FingerprintStrategy interface {
+	// Fingerprint returns an identifier of err, which is
+	// the same for errors which belong to the same group.
+	Fingerprint(err error) string
+}
+
+// FingerprintFunc is an adapter to use a function as FingerprintStrategy.
+type FingerprintFunc func(err error) string
+
+// Fingerprint returns f(err).
+func (f FingerprintFunc) Fingerprint(err error) string {
+	return f(err)
+}
+
+var (
+	// DefaultFingerprint hashes type of the error and all frames
+	// of its stack trace, see Fingerprint.
+	DefaultFingerprint FingerprintStrategy = frameFingerprint{}
+	// AppFramesFingerprint hashes type of the error and frames
+	// of application code only, see Frame.Origin, so errors
+	// coming through different paths of dependencies are grouped together.
+	// Errors without application frames are hashed by all frames.
+	AppFramesFingerprint FingerprintStrategy = frameFingerprint{appOnly: true}
+	// TemplateFingerprint hashes message template of the error, see NewT,
+	// or its message, in addition to what DefaultFingerprint does,
+	// so different errors returned from the same stack are separated.
+	TemplateFingerprint FingerprintStrategy = frameFingerprint{message: true}
+)
+
+// TopFramesFingerprint returns strategy, which hashes type of the error
+// and only n top frames of its stack trace, so errors raised by the same
+// code are grouped together no matter where it's called from.
+func TopFramesFingerprint(n int) FingerprintStrategy {
+	return frameFingerprint{top: n}
+}
+
+// WithFingerprint makes Tracerr compute fingerprints of captured errors
+// by strategy, which is used by Fingerprint and everything built on top
+// of it, such as Aggregator and reports. Errors wrapping them keep it.
+func WithFingerprint(strategy FingerprintStrategy) Option {
+	return func(t *tracerr) {
+		t.fingerprint = strategy
+	}
+}
+
+// frameFingerprint hashes type of the error and frames of its stack trace.
+type frameFingerprint struct {
+	// top limits number of hashed frames, if it's positive.
+	top int
+	// appOnly hashes only frames of application code.
+	appOnly bool
+	// message hashes message template or message.
+	message bool
+}
+
+// Fingerprint returns hash of err by rules of f.
+func (f frameFingerprint) Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := fnv.New64a()
+	frames := StackTrace(err)
+	if f.appOnly {
+		app := make([]Frame, 0, len(frames))
+		for _, frame := range frames {
+			if frame.Origin() == FrameOriginApp {
+				app = append(app, frame)
+			}
+		}
+		if len(app) > 0 {
+			frames = app
+		}
+	}
+	if f.top > 0 && len(frames) > f.top {
+		frames = frames[:f.top]
+	}
+	switch {
+	case len(frames) == 0:
+		fmt.Fprintf(h, "%T\n%s", Unwrap(err), fingerprintMessage(err))
+	case f.message:
+		fmt.Fprintf(h, "%T\n%s\n", Unwrap(err), fingerprintMessage(err))
+	default:
+		fmt.Fprintf(h, "%T\n", Unwrap(err))
+	}
+	for _, frame := range frames {
+		if isReceivedMarker(frame.Func) {
+			// IDs of goroutines differ for the same stacks.
+			continue
+		}
+		fmt.Fprintf(h, "%s:%d %s\n", frame.Path, frame.Line, normalizeGenericName(frame.Func, GenericNamesElided))
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// fingerprintMessage returns message template of err or its message.
+func fingerprintMessage(err error) string {
+	if message := Template(err); message != "" {
+		return message
+	}
+	return err.Error()
+}
@@ -0,0 +1,75 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func newWithFingerprint(tr tracerr.Tracerr, message string) error {
+	return tr.New(message)
+}
+
+func callNewWithFingerprint(tr tracerr.Tracerr, message string) error {
+	return newWithFingerprint(tr, message)
+}
+
+func TestWithFingerprint(t *testing.T) {
+	cases := []struct {
+		Strategy     tracerr.FingerprintStrategy
+		SameCallers  bool
+		SameMessages bool
+	}{
+		{
+			Strategy:     tracerr.DefaultFingerprint,
+			SameCallers:  false,
+			SameMessages: true,
+		},
+		{
+			Strategy:     tracerr.TopFramesFingerprint(1),
+			SameCallers:  true,
+			SameMessages: true,
+		},
+		{
+			Strategy:     tracerr.TemplateFingerprint,
+			SameCallers:  false,
+			SameMessages: false,
+		},
+		{
+			Strategy:     tracerr.FingerprintFunc(func(err error) string { return "fixed" }),
+			SameCallers:  true,
+			SameMessages: true,
+		},
+	}
+	for i, c := range cases {
+		tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithFingerprint(c.Strategy))
+		var errs []error
+		for _, message := range []string{"some error", "other error"} {
+			errs = append(errs, newWithFingerprint(tr, message))
+		}
+		direct, other := errs[0], errs[1]
+		nested := callNewWithFingerprint(tr, "some error")
+		fingerprint := tracerr.Fingerprint(direct)
+		if same := tracerr.Fingerprint(nested) == fingerprint; same != c.SameCallers {
+			t.Errorf("case #%d: fingerprints of different callers are same = %#v; want %#v", i, same, c.SameCallers)
+		}
+		if same := tracerr.Fingerprint(other) == fingerprint; same != c.SameMessages {
+			t.Errorf("case #%d: fingerprints of different messages are same = %#v; want %#v", i, same, c.SameMessages)
+		}
+	}
+
+	tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithFingerprint(tracerr.FingerprintFunc(func(err error) string {
+		return "fixed"
+	})))
+	wrapped := tracerr.Wrap(fmt.Errorf("context: %w", tr.New("some error")))
+	if fingerprint := tracerr.Fingerprint(wrapped); fingerprint != "fixed" {
+		t.Errorf("tracerr.Fingerprint(wrapped) = %#v; want %#v", fingerprint, "fixed")
+	}
+
+	err := tracerr.CustomError(errors.New("some error"), nil)
+	if fingerprint := tracerr.AppFramesFingerprint.Fingerprint(err); fingerprint != tracerr.Fingerprint(err) {
+		t.Errorf("tracerr.AppFramesFingerprint.Fingerprint(err) = %#v; want %#v", fingerprint, tracerr.Fingerprint(err))
+	}
+}
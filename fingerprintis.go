@@ -0,0 +1,49 @@
+package tracerr
+
+import "sync/atomic"
+
+// fingerprintMatching is true if errors.Is matches traced errors
+// by their origin, see SetFingerprintMatching.
+var fingerprintMatching atomic.Bool
+
+// SetFingerprintMatching makes errors.Is report true for two traced errors
+// failing at the same place: with the same origin frame, see Origin,
+// or the innermost frame if there is no application frame,
+// and the same code, see Code. Messages may differ, so dedup logic like
+// errors.Is(err, lastErr) works for flapping failures with variable details.
+// It's off by default, errors.Is compares errors by identity then.
+func SetFingerprintMatching(enabled bool) {
+	fingerprintMatching.Store(enabled)
+}
+
+// Is reports whether target fails at the same place as e,
+// if it's turned on by SetFingerprintMatching.
+func (e *errorData) Is(target error) bool {
+	if e == nil || !fingerprintMatching.Load() {
+		return false
+	}
+	t, ok := target.(Error)
+	if !ok || IsNil(target) {
+		return false
+	}
+	frame, ok := originFrame(e)
+	if !ok {
+		return false
+	}
+	targetFrame, ok := originFrame(t)
+	return ok && frame.Func == targetFrame.Func && frame.Path == targetFrame.Path &&
+		frame.Line == targetFrame.Line && Code(e) == Code(target)
+}
+
+// originFrame returns Origin of e or its innermost frame.
+func originFrame(e Error) (Frame, bool) {
+	if frame, ok := Origin(e); ok {
+		return frame, true
+	}
+	for frame := range Frames(e) {
+		if !frame.IsMarker() {
+			return frame, true
+		}
+	}
+	return Frame{}, false
+}
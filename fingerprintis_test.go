@@ -0,0 +1,51 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func failWith(id int) error {
+	return tracerr.Errorf("user %d not found", id)
+}
+
+func TestSetFingerprintMatching(t *testing.T) {
+	first, second := failWith(1), failWith(2)
+	other := tracerr.New("other failure")
+	if errors.Is(first, second) {
+		t.Errorf("errors.Is(first, second) = true; want false by default")
+	}
+
+	tracerr.SetFingerprintMatching(true)
+	defer tracerr.SetFingerprintMatching(false)
+	cases := []struct {
+		err      error
+		target   error
+		expected bool
+	}{
+		{err: first, target: second, expected: true},
+		{err: fmt.Errorf("context: %w", first), target: second, expected: true},
+		{err: first, target: other, expected: false},
+		{err: first, target: errors.New("user 2 not found"), expected: false},
+		{err: first, target: tracerr.Wrap(&codeError{"E404"}), expected: false},
+		{
+			err:      tracerr.CustomError(&codeError{"E404"}, diffFrames("read")),
+			target:   tracerr.CustomError(&codeError{"E500"}, diffFrames("read")),
+			expected: false,
+		},
+		{
+			err:      tracerr.CustomError(&codeError{"E404"}, diffFrames("read")),
+			target:   tracerr.CustomError(&codeError{"E404"}, diffFrames("read", "main")),
+			expected: true,
+		},
+		{err: tracerr.CustomError(errors.New("a"), nil), target: tracerr.CustomError(errors.New("b"), nil), expected: false},
+	}
+	for i, c := range cases {
+		if is := errors.Is(c.err, c.target); is != c.expected {
+			t.Errorf("case #%d: errors.Is(%v, %v) = %v; want %v", i, c.err, c.target, is, c.expected)
+		}
+	}
+}
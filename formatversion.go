@@ -0,0 +1,35 @@
+package tracerr
+
+// FormatVersion is a version of text output of frames, such as of
+// Frame.String, print functions, Tree and SprintCompact.
+// Output of a version never changes, so log parsers matching it
+// don't silently break when output improvements land in a new version.
+type FormatVersion int
+
+const (
+	// FormatV1 is the default format of frames, "path:line func()",
+	// with fully qualified function name,
+	// e.g. "/src/main.go:42 github.com/foo/bar.(*Thing).Do()".
+	FormatV1 FormatVersion = iota + 1
+	// FormatV2 formats frames as "path:line func()" with function name
+	// without package path, see Frame.ShortFunc, since the path
+	// already locates the package, e.g. "/src/main.go:42 bar.(*Thing).Do()".
+	FormatV2
+)
+
+// WithFormat selects version of output format explicitly,
+// output of print functions and Frame.String is FormatV1 by default.
+// WithFrameLayout takes precedence over it for frames it formats.
+func WithFormat(version FormatVersion) PrintOption {
+	return func(o *printOptions) {
+		o.format = version
+	}
+}
+
+// funcName returns function name of frame by format version.
+func (o *printOptions) funcName(frame Frame) string {
+	if o.format >= FormatV2 {
+		return frame.ShortFunc()
+	}
+	return frame.Func
+}
@@ -0,0 +1,90 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+// formatFrames are frames covering layouts of function names.
+var formatFrames = []tracerr.Frame{
+	tracerr.NewFrame("github.com/foo/bar.(*Thing).Do", "/src/bar/thing.go", 42),
+	tracerr.NewFrame("github.com/foo/bar.Run.func1", "/src/bar/run.go", 10),
+	tracerr.NewFrame("main.main", "/src/main.go", 7),
+}
+
+// FormatCompatTestCase is expected output of a renderer by format version.
+// Expected output must never change for released versions,
+// add a new version instead.
+type FormatCompatTestCase struct {
+	Name     string
+	Render   func(err tracerr.Error) string
+	Expected map[tracerr.FormatVersion]string
+}
+
+func TestFormatCompatibility(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), formatFrames)
+	cases := []FormatCompatTestCase{
+		{
+			Name: "Frame.String",
+			Render: func(err tracerr.Error) string {
+				return err.StackTrace()[0].String()
+			},
+			Expected: map[tracerr.FormatVersion]string{
+				tracerr.FormatV1: "/src/bar/thing.go:42 github.com/foo/bar.(*Thing).Do()",
+				tracerr.FormatV2: "/src/bar/thing.go:42 bar.(*Thing).Do()",
+			},
+		},
+		{
+			Name:   "Sprint",
+			Render: func(err tracerr.Error) string { return tracerr.Sprint(err) },
+			Expected: map[tracerr.FormatVersion]string{
+				tracerr.FormatV1: "some error\n" +
+					"/src/bar/thing.go:42 github.com/foo/bar.(*Thing).Do()\n" +
+					"/src/bar/run.go:10 github.com/foo/bar.Run.func1()\n" +
+					"/src/main.go:7 main.main()",
+				tracerr.FormatV2: "some error\n" +
+					"/src/bar/thing.go:42 bar.(*Thing).Do()\n" +
+					"/src/bar/run.go:10 bar.Run.func1()\n" +
+					"/src/main.go:7 main.main()",
+			},
+		},
+		{
+			Name:   "SprintCompact",
+			Render: func(err tracerr.Error) string { return tracerr.SprintCompact(err) },
+			Expected: map[tracerr.FormatVersion]string{
+				tracerr.FormatV1: `err="some error" at=github.com/foo/bar.(*Thing).Do file=/src/bar/thing.go:42` +
+					" | github.com/foo/bar.Run.func1 /src/bar/run.go:10 | main.main /src/main.go:7",
+				tracerr.FormatV2: `err="some error" at=bar.(*Thing).Do file=/src/bar/thing.go:42` +
+					" | bar.Run.func1 /src/bar/run.go:10 | main.main /src/main.go:7",
+			},
+		},
+		{
+			Name: "Tree",
+			Render: func(err tracerr.Error) string {
+				return tracerr.Tree(err).Frames[1]
+			},
+			Expected: map[tracerr.FormatVersion]string{
+				tracerr.FormatV1: "/src/bar/run.go:10 github.com/foo/bar.Run.func1()",
+				tracerr.FormatV2: "/src/bar/run.go:10 bar.Run.func1()",
+			},
+		},
+	}
+	for _, c := range cases {
+		for version, expected := range c.Expected {
+			tracerr.SetPrintOptions(tracerr.WithFormat(version))
+			if output := c.Render(err); output != expected {
+				t.Errorf("%s of FormatV%d = %#v; want %#v", c.Name, version, output, expected)
+			}
+		}
+	}
+	tracerr.SetPrintOptions()
+
+	// FormatV1 is the default.
+	for _, c := range cases {
+		if output := c.Render(err); output != c.Expected[tracerr.FormatV1] {
+			t.Errorf("%s by default = %#v; want %#v", c.Name, output, c.Expected[tracerr.FormatV1])
+		}
+	}
+}
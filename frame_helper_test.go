@@ -0,0 +1,29 @@
+package tracerr_test
+
+import (
+	"github.com/kadaan/tracerr"
+)
+
+type frameHelper struct{}
+
+func (h *frameHelper) pointer() error {
+	return tracerr.New("pointer receiver")
+}
+
+func (h frameHelper) value() error {
+	return tracerr.New("value receiver")
+}
+
+func closure() error {
+	return func() error {
+		return tracerr.New("closure")
+	}()
+}
+
+func newInHelper(message string) error {
+	return tracerr.NewSkip(message, 1)
+}
+
+func wrapInHelper(err error) error {
+	return tracerr.WrapSkip(err, 1)
+}
@@ -0,0 +1,94 @@
+package tracerr
+
+import (
+	"hash/fnv"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// FrameCacheMaxEntries is a maximum number of stacks cached
+// by Tracerr created with WithFrameCache.
+// Stacks captured after the limit is reached are resolved every time.
+const FrameCacheMaxEntries = 4096
+
+// WithFrameCache makes Tracerr cache frames resolved for a stack,
+// keyed by the caller PC of the capture and a hash of the whole stack,
+// so errors captured at the same stack share the same frames.
+//
+// It's useful for tight retry loops, where the same stack
+// is resolved thousands of times per second.
+// Frames of cached stacks must not be modified, see Error.RawFrames.
+// Stack trace depth is limited by LazyFramesMaxDepth.
+// It's used instead of WithLazyFrames, if both are set.
+func WithFrameCache() Option {
+	return func(t *tracerr) {
+		t.frameCache = &frameCache{}
+	}
+}
+
+// frameCache is a concurrency safe cache of resolved stacks.
+type frameCache struct {
+	// stacks maps frameCacheKey to *cachedStack.
+	stacks sync.Map
+	// size is a number of cached stacks.
+	size atomic.Int64
+}
+
+// frameCacheKey identifies a stack in frameCache.
+type frameCacheKey struct {
+	pc   uintptr
+	hash uint64
+}
+
+// cachedStack is a stack resolved to frames.
+type cachedStack struct {
+	pcs    []uintptr
+	frames []Frame
+}
+
+// traceCached captures stack trace of the caller by the same rules
+// as traceFrames, frames are resolved once per stack.
+func (t *tracerr) traceCached(err error, extraSkip int) Error {
+	buf := pcPool.Get().(*[LazyFramesMaxDepth]uintptr)
+	defer pcPool.Put(buf)
+	pcs := t.callers(buf, extraSkip)
+	if len(pcs) == 0 {
+		return &errorData{
+			err:    err,
+			frames: []Frame{},
+		}
+	}
+	key := frameCacheKey{pc: pcs[0], hash: hashPCs(pcs)}
+	if v, ok := t.frameCache.stacks.Load(key); ok {
+		if stack := v.(*cachedStack); slices.Equal(stack.pcs, pcs) {
+			captureStats.cacheHits.Add(1)
+			return &errorData{
+				err:    err,
+				frames: stack.frames,
+			}
+		}
+	}
+	e := &errorData{
+		err:    err,
+		frames: resolveFrames(pcs, t.trimEntryPoints),
+	}
+	if t.sourceHashes {
+		hashSources(e)
+	}
+	if t.frameCache.size.Load() < FrameCacheMaxEntries {
+		stack := &cachedStack{pcs: slices.Clone(pcs), frames: e.frames}
+		if _, loaded := t.frameCache.stacks.LoadOrStore(key, stack); !loaded {
+			t.frameCache.size.Add(1)
+		}
+	}
+	return e
+}
+
+// hashPCs returns a hash of program counters.
+func hashPCs(pcs []uintptr) uint64 {
+	h := fnv.New64a()
+	h.Write(unsafe.Slice((*byte)(unsafe.Pointer(&pcs[0])), len(pcs)*int(unsafe.Sizeof(pcs[0]))))
+	return h.Sum64()
+}
@@ -0,0 +1,46 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func wrapCached(tr tracerr.Tracerr, err error) tracerr.Error {
+	return tr.Wrap(err)
+}
+
+func TestWithFrameCache(t *testing.T) {
+	tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithFrameCache())
+	var errs []tracerr.Error
+	for i := 0; i < 3; i++ {
+		errs = append(errs, wrapCached(tr, errors.New("some error")))
+	}
+	other := tr.Wrap(errors.New("some error"))
+
+	frames := tracerr.RawFrames(errs[0])
+	if len(frames) < 2 {
+		t.Fatalf("len(frames) = %#v; want at least 2", len(frames))
+	}
+	expected := []string{
+		"github.com/kadaan/tracerr_test.wrapCached",
+		"github.com/kadaan/tracerr_test.TestWithFrameCache",
+	}
+	for i, name := range expected {
+		if frames[i].Func != name {
+			t.Errorf("frames[%d].Func = %#v; want %#v", i, frames[i].Func, name)
+		}
+	}
+	for i, err := range errs[1:] {
+		if raw := tracerr.RawFrames(err); &raw[0] != &frames[0] {
+			t.Errorf("errs[%d] frames are not shared", i+1)
+		}
+	}
+	if raw := tracerr.RawFrames(other); &raw[0] == &frames[0] {
+		t.Errorf("frames of different stacks are shared")
+	}
+	if tracerr.RawFrames(other)[0].Func != "github.com/kadaan/tracerr_test.TestWithFrameCache" {
+		t.Errorf("tracerr.RawFrames(other)[0].Func = %#v; want %#v", tracerr.RawFrames(other)[0].Func, "github.com/kadaan/tracerr_test.TestWithFrameCache")
+	}
+}
@@ -0,0 +1,169 @@
+package tracerr
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// FrameConfigEnv is a name of environment variable with rules of FrameConfig,
+// which are loaded at startup, see ParseFrameConfig.
+// Rules can be separated by semicolons in addition to newlines.
+const FrameConfigEnv = "TRACERR_FRAME_CONFIG"
+
+// FrameConfigFileEnv is a name of environment variable with a path
+// of a file with rules of FrameConfig, which are loaded at startup.
+// They follow rules of FrameConfigEnv, if both are set.
+const FrameConfigFileEnv = "TRACERR_FRAME_CONFIG_FILE"
+
+// FrameConfig hides or folds frames of all printers, so noise of stack traces
+// can be tuned in deployed services without a rebuild.
+// Patterns match path or function name of a frame.
+type FrameConfig struct {
+	// Hide drops frames matching any of patterns, see DropFrames.
+	Hide []*regexp.Regexp
+	// Fold replaces consecutive frames matching any of patterns
+	// with a single "... N frames folded ..." line.
+	Fold []*regexp.Regexp
+}
+
+// frameConfig is set by SetFrameConfig.
+var frameConfig atomic.Pointer[FrameConfig]
+
+func init() {
+	config, err := frameConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tracerr: %v\n", err)
+		return
+	}
+	if len(config.Hide) > 0 || len(config.Fold) > 0 {
+		SetFrameConfig(config)
+	}
+}
+
+// SetFrameConfig applies config to all printers on top of their options.
+// It replaces config loaded at startup from FrameConfigEnv
+// and FrameConfigFileEnv, pass empty one to turn it off.
+func SetFrameConfig(config FrameConfig) {
+	frameConfig.Store(&config)
+}
+
+// ParseFrameConfig parses rules of FrameConfig, one per line,
+// such as "hide regexp" or "fold regexp".
+// Empty lines and lines starting with "#" are ignored,
+// rules without a regexp are an error, as it would match every frame.
+//
+//	# Middleware is never interesting.
+//	hide ^github\.com/mycorp/middleware\.
+//	fold ^net/http\.
+func ParseFrameConfig(text string) (FrameConfig, error) {
+	var config FrameConfig
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		action, pattern, _ := strings.Cut(line, " ")
+		var rules *[]*regexp.Regexp
+		switch action {
+		case "hide":
+			rules = &config.Hide
+		case "fold":
+			rules = &config.Fold
+		default:
+			return FrameConfig{}, fmt.Errorf("line %d: unknown action %q", i+1, action)
+		}
+		// Empty pattern matches every frame.
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return FrameConfig{}, fmt.Errorf("line %d: empty pattern of %q", i+1, action)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return FrameConfig{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		*rules = append(*rules, re)
+	}
+	return config, nil
+}
+
+// LoadFrameConfig parses rules of FrameConfig from file at path.
+func LoadFrameConfig(path string) (FrameConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return FrameConfig{}, err
+	}
+	config, err := ParseFrameConfig(string(b))
+	if err != nil {
+		return FrameConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// frameConfigFromEnv loads FrameConfig from FrameConfigFileEnv
+// and FrameConfigEnv.
+func frameConfigFromEnv() (FrameConfig, error) {
+	var config FrameConfig
+	if path := os.Getenv(FrameConfigFileEnv); path != "" {
+		var err error
+		if config, err = LoadFrameConfig(path); err != nil {
+			return FrameConfig{}, err
+		}
+	}
+	if text := os.Getenv(FrameConfigEnv); text != "" {
+		env, err := ParseFrameConfig(strings.ReplaceAll(text, ";", "\n"))
+		if err != nil {
+			return FrameConfig{}, fmt.Errorf("%s: %w", FrameConfigEnv, err)
+		}
+		config.Hide = append(config.Hide, env.Hide...)
+		config.Fold = append(config.Fold, env.Fold...)
+	}
+	return config, nil
+}
+
+// currentFrameConfig returns config set by SetFrameConfig or nil.
+func currentFrameConfig() *FrameConfig {
+	config := frameConfig.Load()
+	if config == nil || len(config.Hide) == 0 && len(config.Fold) == 0 {
+		return nil
+	}
+	return config
+}
+
+// hidden reports whether frame is hidden by config.
+func (config *FrameConfig) hidden(frame Frame) bool {
+	return !frame.IsMarker() && matchFrame(config.Hide, frame)
+}
+
+// fold replaces runs of frames matching Fold patterns with folded entries.
+func (config *FrameConfig) fold(frames []outputFrame) []outputFrame {
+	if len(config.Fold) == 0 {
+		return frames
+	}
+	folded := make([]outputFrame, 0, len(frames))
+	for _, frame := range frames {
+		if frame.Omitted > 0 || frame.IsMarker() || !matchFrame(config.Fold, frame.Frame) {
+			folded = append(folded, frame)
+			continue
+		}
+		if n := len(folded); n > 0 && folded[n-1].Folded {
+			folded[n-1].Omitted += frame.Repeated
+			continue
+		}
+		folded = append(folded, outputFrame{Omitted: frame.Repeated, Folded: true})
+	}
+	return folded
+}
+
+// matchFrame reports whether path or function name of frame
+// matches any of patterns.
+func matchFrame(patterns []*regexp.Regexp, frame Frame) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(frame.Path) || pattern.MatchString(frame.Func) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package tracerr_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestParseFrameConfig(t *testing.T) {
+	cases := []struct {
+		Text          string
+		ExpectedHide  int
+		ExpectedFold  int
+		ExpectedError string
+	}{
+		{
+			Text: "",
+		},
+		{
+			Text:         "# comment\n\nhide ^main\\.wrap\n  fold ^net/http\\.\nfold ^runtime\\.\n",
+			ExpectedHide: 1,
+			ExpectedFold: 2,
+		},
+		{
+			Text:          "hide ok\nskip main",
+			ExpectedError: `line 2: unknown action "skip"`,
+		},
+		{
+			Text:          "fold (",
+			ExpectedError: "line 1: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			Text:          "hide",
+			ExpectedError: `line 1: empty pattern of "hide"`,
+		},
+		{
+			Text:          "hide ok\nfold \n",
+			ExpectedError: `line 2: empty pattern of "fold"`,
+		},
+		{
+			Text:          "fold   ",
+			ExpectedError: `line 1: empty pattern of "fold"`,
+		},
+	}
+	for i, c := range cases {
+		config, err := tracerr.ParseFrameConfig(c.Text)
+		if c.ExpectedError != "" {
+			if err == nil || err.Error() != c.ExpectedError {
+				t.Errorf("case #%d: tracerr.ParseFrameConfig(text) error = %v; want %#v", i, err, c.ExpectedError)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case #%d: tracerr.ParseFrameConfig(text) error: %v", i, err)
+		}
+		if len(config.Hide) != c.ExpectedHide || len(config.Fold) != c.ExpectedFold {
+			t.Errorf("case #%d: len(Hide), len(Fold) = %d, %d; want %d, %d", i, len(config.Hide), len(config.Fold), c.ExpectedHide, c.ExpectedFold)
+		}
+	}
+}
+
+func TestSetFrameConfig(t *testing.T) {
+	config, err := tracerr.ParseFrameConfig("hide ^main\\.wrap\nfold ^net/http\\.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracerr.SetFrameConfig(config)
+	defer tracerr.SetFrameConfig(tracerr.FrameConfig{})
+
+	err = tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.wrap", "/src/main.go", 5),
+		tracerr.NewFrame("main.handle", "/src/main.go", 10),
+		tracerr.NewFrame("net/http.HandlerFunc.ServeHTTP", "/go/src/net/http/server.go", 2171),
+		tracerr.NewFrame("net/http.serverHandler.ServeHTTP", "/go/src/net/http/server.go", 3142),
+		tracerr.NewFrame("main.main", "/src/main.go", 20),
+	})
+	expected := "some error\n" +
+		"/src/main.go:10 main.handle()\n" +
+		"... 2 frames folded ...\n" +
+		"/src/main.go:20 main.main()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+	if frames := tracerr.Tree(err).Frames; len(frames) != 4 {
+		t.Errorf("len(tracerr.Tree(err).Frames) = %d; want %d", len(frames), 4)
+	}
+}
+
+func TestFrameConfigEnv(t *testing.T) {
+	if os.Getenv("TRACERR_TEST_FRAME_CONFIG") != "" {
+		err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+			tracerr.NewFrame("main.wrap", "/src/main.go", 5),
+			tracerr.NewFrame("main.handle", "/src/main.go", 10),
+			tracerr.NewFrame("main.main", "/src/main.go", 20),
+		})
+		os.Stdout.WriteString(tracerr.Sprint(err) + "\n")
+		return
+	}
+	path := filepath.Join(t.TempDir(), "frames.conf")
+	if err := os.WriteFile(path, []byte("hide ^main\\.wrap\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFrameConfigEnv$")
+	cmd.Env = append(os.Environ(),
+		"TRACERR_TEST_FRAME_CONFIG=1",
+		tracerr.FrameConfigFileEnv+"="+path,
+		tracerr.FrameConfigEnv+"=fold ^main\\.main$",
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("cmd.Output() error: %v", err)
+	}
+	expected := "some error\n" +
+		"/src/main.go:10 main.handle()\n" +
+		"... 1 frame folded ...\n"
+	if !strings.HasPrefix(string(output), expected) {
+		t.Errorf("output = %#v; want prefix %#v", string(output), expected)
+	}
+}
@@ -0,0 +1,84 @@
+package tracerr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ShortFunc returns function name without package path,
+// e.g. "bar.(*Thing).Do" for "github.com/foo/bar.(*Thing).Do".
+func (f Frame) ShortFunc() string {
+	return f.Func[strings.LastIndex(f.Func, "/")+1:]
+}
+
+// Base returns base name of the file, e.g. "main.go".
+// Both slashes and backslashes separate path elements,
+// as frames may come from another OS.
+func (f Frame) Base() string {
+	return f.Path[strings.LastIndexAny(f.Path, `/\`)+1:]
+}
+
+// Format formats frame by layout, where verbs are replaced
+// with parts of the frame:
+//
+//	%f  file path
+//	%b  file base name, see Base
+//	%l  line number
+//	%F  fully qualified function name
+//	%n  function name without package path, see ShortFunc
+//	%N  function name without package and receiver
+//	%p  package import path
+//	%%  percent sign
+//
+// Other characters, including unknown verbs, are copied as is,
+// e.g. "%b:%l %n" gives "main.go:42 main.read".
+// Print options, such as WithTrimPaths, don't apply to it.
+func (f Frame) Format(layout string) string {
+	return f.format(layout, f.Path, strconv.Itoa(f.Line))
+}
+
+// WithFrameLayout sets layout of Frame.String and frames of Tree
+// and SprintDiff by the same verbs as in Frame.Format, e.g.
+// "%F()\n\t%f:%l" for the layout of Go panics, which is hyperlinked
+// by many IDEs and log viewers. Paths and line numbers follow
+// print options, such as WithTrimPaths and WithMaskedLines.
+// Empty layout means the default "path:line func()".
+func WithFrameLayout(layout string) PrintOption {
+	return func(o *printOptions) {
+		o.frameLayout = layout
+	}
+}
+
+// format formats frame by layout as Format does with provided path and line.
+func (f Frame) format(layout, path, line string) string {
+	var b strings.Builder
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' || i+1 == len(layout) {
+			b.WriteByte(layout[i])
+			continue
+		}
+		i++
+		switch layout[i] {
+		case 'f':
+			b.WriteString(path)
+		case 'b':
+			b.WriteString(path[strings.LastIndexAny(path, `/\`)+1:])
+		case 'l':
+			b.WriteString(line)
+		case 'F':
+			b.WriteString(f.Func)
+		case 'n':
+			b.WriteString(f.ShortFunc())
+		case 'N':
+			b.WriteString(f.Name)
+		case 'p':
+			b.WriteString(f.Package)
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(layout[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestFrameFormat(t *testing.T) {
+	frame := tracerr.NewFrame("github.com/foo/bar.(*Thing).Do", "/src/github.com/foo/bar/thing.go", 42)
+	if shortFunc := frame.ShortFunc(); shortFunc != "bar.(*Thing).Do" {
+		t.Errorf("frame.ShortFunc() = %#v; want %#v", shortFunc, "bar.(*Thing).Do")
+	}
+	if base := frame.Base(); base != "thing.go" {
+		t.Errorf("frame.Base() = %#v; want %#v", base, "thing.go")
+	}
+	if base := (tracerr.Frame{}).Base(); base != "" {
+		t.Errorf("tracerr.Frame{}.Base() = %#v; want %#v", base, "")
+	}
+	if base := tracerr.NewFrame("main.main", `C:\src\main.go`, 1).Base(); base != "main.go" {
+		t.Errorf("frame.Base() of Windows path = %#v; want %#v", base, "main.go")
+	}
+	cases := []struct {
+		Layout   string
+		Expected string
+	}{
+		{
+			Layout:   "%f:%l %n",
+			Expected: "/src/github.com/foo/bar/thing.go:42 bar.(*Thing).Do",
+		},
+		{
+			Layout:   "%b:%l %F",
+			Expected: "thing.go:42 github.com/foo/bar.(*Thing).Do",
+		},
+		{
+			Layout:   "%p %N 100%% %x %",
+			Expected: "github.com/foo/bar Do 100% %x %",
+		},
+		{
+			Layout:   "",
+			Expected: "",
+		},
+	}
+
+	for i, c := range cases {
+		output := frame.Format(c.Layout)
+		if output != c.Expected {
+			t.Errorf("case #%d: frame.Format(%#v) = %#v; want %#v", i, c.Layout, output, c.Expected)
+		}
+	}
+}
+
+func TestWithFrameLayout(t *testing.T) {
+	frame := tracerr.NewFrame("github.com/foo/bar.(*Thing).Do", "/src/bar/thing.go", 42)
+	cases := []struct {
+		options  []tracerr.PrintOption
+		expected string
+	}{
+		{nil, "/src/bar/thing.go:42 github.com/foo/bar.(*Thing).Do()"},
+		{[]tracerr.PrintOption{tracerr.WithFrameLayout("%F()\n\t%f:%l")}, "github.com/foo/bar.(*Thing).Do()\n\t/src/bar/thing.go:42"},
+		{[]tracerr.PrintOption{tracerr.WithFrameLayout("%f:%l in %n")}, "/src/bar/thing.go:42 in bar.(*Thing).Do"},
+		{
+			[]tracerr.PrintOption{tracerr.WithFrameLayout("%b:%l %N"), tracerr.WithMaskedLines(), tracerr.WithPathRewrite("/src", "/home/dev")},
+			"thing.go:NN Do",
+		},
+	}
+	defer tracerr.SetPrintOptions()
+	for i, c := range cases {
+		tracerr.SetPrintOptions(c.options...)
+		if s := frame.String(); s != c.expected {
+			t.Errorf("case #%d: frame.String() = %#v; want %#v", i, s, c.expected)
+		}
+	}
+	tracerr.SetPrintOptions()
+	tree := tracerr.Tree(tracerr.CustomError(errors.New("some error"), []tracerr.Frame{frame}), tracerr.WithFrameLayout("%f:%l"))
+	if len(tree.Frames) != 1 || tree.Frames[0] != "/src/bar/thing.go:42" {
+		t.Errorf("tree.Frames = %#v; want frames by layout", tree.Frames)
+	}
+}
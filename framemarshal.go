@@ -0,0 +1,111 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FrameEncoding configures how frames are rendered by
+// Frame.MarshalText and Frame.MarshalJSON, see WithFrameEncoding.
+type FrameEncoding struct {
+	// TrimPaths shortens absolute paths to the same form
+	// as in -trimpath builds, see WithTrimPaths.
+	TrimPaths bool
+	// ShortNames removes package path from function names,
+	// see Frame.ShortFunc.
+	ShortNames bool
+}
+
+// WithFrameEncoding sets how frames are rendered by Frame.MarshalText
+// and Frame.MarshalJSON, so any encoder renders them consistently.
+//
+// Frames don't refer to Tracerr which captured them,
+// so encoding of Default applies to all of them:
+//
+//	tracerr.Default = tracerr.NewTracerr(
+//		tracerr.DefaultFrameCapacity,
+//		tracerr.DefaultFrameSkipCount,
+//		tracerr.WithFrameEncoding(tracerr.FrameEncoding{TrimPaths: true}),
+//	)
+func WithFrameEncoding(encoding FrameEncoding) Option {
+	return func(t *tracerr) {
+		t.frameEncoding = encoding
+	}
+}
+
+// currentFrameEncoding returns encoding of Default,
+// or zero encoding if it's not created by NewTracerr.
+func currentFrameEncoding() FrameEncoding {
+	if t, ok := Default.(*tracerr); ok {
+		return t.frameEncoding
+	}
+	return FrameEncoding{}
+}
+
+// encode returns path and function name of frame rendered by enc.
+func (enc FrameEncoding) encode(f Frame) (string, string) {
+	path, name := f.Path, f.Func
+	if f.IsMarker() {
+		return path, name
+	}
+	if enc.TrimPaths {
+		path = trimPath(f)
+	}
+	if enc.ShortNames {
+		name = f.ShortFunc()
+	}
+	return path, name
+}
+
+// frameJSON is a JSON form of Frame.
+type frameJSON struct {
+	Func      string `json:"func"`
+	Path      string `json:"path,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	CreatedBy bool   `json:"createdBy,omitempty"`
+}
+
+// redactedFrameFunc is a function name of a frame dropped by a redactor
+// of SetFrameRedactors, which is marshaled alone.
+const redactedFrameFunc = "<redacted>"
+
+// redactFrame returns frame with redactors set by SetFrameRedactors applied,
+// a dropped frame is replaced with a frame without a location.
+func redactFrame(f Frame) Frame {
+	for _, redactor := range currentFrameRedactors() {
+		var keep bool
+		if f, keep = redactor(f); !keep {
+			return Frame{Func: redactedFrameFunc}
+		}
+	}
+	return f
+}
+
+// MarshalText renders frame as "path:line func()",
+// following encoding set by WithFrameEncoding
+// and redactors set by SetFrameRedactors.
+func (f Frame) MarshalText() ([]byte, error) {
+	f = redactFrame(f)
+	if f.Func == redactedFrameFunc && f.Path == "" {
+		return []byte(f.Func), nil
+	}
+	path, name := currentFrameEncoding().encode(f)
+	if f.IsMarker() {
+		return []byte(name), nil
+	}
+	return []byte(fmt.Sprintf("%s:%d %s()", path, f.Line, name)), nil
+}
+
+// MarshalJSON renders frame as an object with func, path, line
+// and createdBy, following encoding set by WithFrameEncoding
+// and redactors set by SetFrameRedactors.
+func (f Frame) MarshalJSON() ([]byte, error) {
+	f = redactFrame(f)
+	path, name := currentFrameEncoding().encode(f)
+	return json.Marshal(frameJSON{
+		Func:      name,
+		Path:      path,
+		Line:      f.Line,
+		CreatedBy: f.CreatedBy,
+	})
+}
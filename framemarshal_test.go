@@ -0,0 +1,73 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestFrameMarshal(t *testing.T) {
+	frame := tracerr.NewFrame("github.com/foo/bar.(*Thing).Do", "/home/ci/go/pkg/mod/github.com/foo/bar@v1.2.0/thing.go", 42)
+	marker := tracerr.Frame{Func: tracerr.MarkerFunc}
+	cases := []struct {
+		encoding tracerr.FrameEncoding
+		frame    tracerr.Frame
+		text     string
+		json     string
+	}{
+		{
+			encoding: tracerr.FrameEncoding{},
+			frame:    frame,
+			text:     "/home/ci/go/pkg/mod/github.com/foo/bar@v1.2.0/thing.go:42 github.com/foo/bar.(*Thing).Do()",
+			json:     `{"func":"github.com/foo/bar.(*Thing).Do","path":"/home/ci/go/pkg/mod/github.com/foo/bar@v1.2.0/thing.go","line":42}`,
+		},
+		{
+			encoding: tracerr.FrameEncoding{TrimPaths: true, ShortNames: true},
+			frame:    frame,
+			text:     "github.com/foo/bar@v1.2.0/thing.go:42 bar.(*Thing).Do()",
+			json:     `{"func":"bar.(*Thing).Do","path":"github.com/foo/bar@v1.2.0/thing.go","line":42}`,
+		},
+		{
+			encoding: tracerr.FrameEncoding{TrimPaths: true, ShortNames: true},
+			frame:    marker,
+			text:     "<merged>",
+			json:     `{"func":"\u003cmerged\u003e"}`,
+		},
+	}
+	original := tracerr.Default
+	defer func() {
+		tracerr.Default = original
+	}()
+	for i, c := range cases {
+		tracerr.Default = tracerr.NewTracerr(
+			tracerr.DefaultFrameCapacity,
+			tracerr.DefaultFrameSkipCount,
+			tracerr.WithFrameEncoding(c.encoding),
+		)
+		text, err := c.frame.MarshalText()
+		if err != nil || string(text) != c.text {
+			t.Errorf("case #%d: frame.MarshalText() = %#v, %v; want %#v", i, string(text), err, c.text)
+		}
+		b, err := json.Marshal(c.frame)
+		if err != nil || string(b) != c.json {
+			t.Errorf("case #%d: json.Marshal(frame) = %#v, %v; want %#v", i, string(b), err, c.json)
+		}
+	}
+}
+
+func TestFrameUnmarshalJSON(t *testing.T) {
+	frame := tracerr.NewFrame("main.read", "/src/main.go", 10)
+	frame.CreatedBy = true
+	b, err := json.Marshal(frame)
+	if err != nil {
+		t.Fatalf("json.Marshal(frame) error = %v", err)
+	}
+	var decoded tracerr.Frame
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Func != frame.Func || decoded.Path != frame.Path || decoded.Line != frame.Line || !decoded.CreatedBy {
+		t.Errorf("decoded = %#v; want %#v", decoded, frame)
+	}
+}
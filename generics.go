@@ -0,0 +1,143 @@
+package tracerr
+
+import (
+	"strings"
+)
+
+// GenericNames is a style of type arguments in names of generic functions,
+// see WithGenericNames.
+type GenericNames int
+
+const (
+	// GenericNamesAsIs keeps names as they are captured,
+	// e.g. "pkg.Do[...]" or "pkg.Do[go.shape.int_0]".
+	GenericNamesAsIs GenericNames = iota
+	// GenericNamesReadable renders shapes of type arguments as types,
+	// e.g. "pkg.Do[int]" for "pkg.Do[go.shape.int_0]".
+	GenericNamesReadable
+	// GenericNamesElided replaces type arguments with "...",
+	// e.g. "pkg.Do[...]", which is what recent Go versions report.
+	GenericNamesElided
+)
+
+// WithGenericNames sets a style of type arguments in names of generic
+// functions in output, where shapes like "go.shape.int_0" are hardly readable
+// and vary between instantiations.
+//
+// Frames from parsed panics, other libraries or older Go versions
+// may have such names.
+func WithGenericNames(style GenericNames) PrintOption {
+	return func(o *printOptions) {
+		o.genericNames = style
+	}
+}
+
+// genericFrames returns frames with names of generic functions
+// in style set by WithGenericNames, frames are copied if they are changed.
+func (o *printOptions) genericFrames(frames []Frame) []Frame {
+	if o.genericNames == GenericNamesAsIs {
+		return frames
+	}
+	var normalized []Frame
+	for i, frame := range frames {
+		name := normalizeGenericName(frame.Func, o.genericNames)
+		if name == frame.Func {
+			continue
+		}
+		if normalized == nil {
+			normalized = append([]Frame(nil), frames...)
+		}
+		normalized[i].Func = name
+		normalized[i].Package, normalized[i].Receiver, normalized[i].Name = splitFuncName(name)
+	}
+	if normalized == nil {
+		return frames
+	}
+	return normalized
+}
+
+// normalizeGenericName returns function name with type arguments in style.
+func normalizeGenericName(name string, style GenericNames) string {
+	if style == GenericNamesAsIs || !strings.Contains(name, "[") {
+		return name
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(name, "[")
+		if start < 0 {
+			break
+		}
+		end := closingBracket(name, start)
+		if end < 0 {
+			break
+		}
+		b.WriteString(name[:start+1])
+		if style == GenericNamesElided {
+			b.WriteString("...")
+		} else {
+			b.WriteString(readableTypeArgs(name[start+1 : end]))
+		}
+		b.WriteString("]")
+		name = name[end+1:]
+	}
+	b.WriteString(name)
+	return b.String()
+}
+
+// closingBracket returns an index of "]" matching "[" at start, or -1.
+func closingBracket(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// readableTypeArgs renders comma separated shapes of type arguments as types.
+func readableTypeArgs(args string) string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i <= len(args); i++ {
+		if i < len(args) {
+			switch args[i] {
+			case '[', '(', '{':
+				depth++
+				continue
+			case ']', ')', '}':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		parts = append(parts, readableShape(args[start:i]))
+		start = i + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// readableShape turns a shape like "go.shape.int_0" into a type like "int".
+func readableShape(arg string) string {
+	const prefix = "go.shape."
+	if !strings.HasPrefix(arg, prefix) {
+		return arg
+	}
+	arg = strings.ReplaceAll(arg[len(prefix):], prefix, "")
+	// Shape ends with an index of type parameter.
+	if i := strings.LastIndex(arg, "_"); i >= 0 && i+1 < len(arg) && strings.Trim(arg[i+1:], "0123456789") == "" {
+		arg = arg[:i]
+	}
+	return arg
+}
@@ -0,0 +1,78 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithGenericNames(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("github.com/foo/bar.Do[go.shape.int_0]", "/src/bar.go", 10),
+		tracerr.NewFrame("main.Map[go.shape.string_0,go.shape.*uint8_1]", "/src/main.go", 20),
+		tracerr.NewFrame("main.(*List[go.shape.[]int_0]).Push", "/src/main.go", 30),
+		tracerr.NewFrame("main.Do[...]", "/src/main.go", 40),
+		tracerr.NewFrame("main.main", "/src/main.go", 50),
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	cases := []struct {
+		Style    tracerr.GenericNames
+		Expected string
+	}{
+		{
+			Style: tracerr.GenericNamesAsIs,
+			Expected: "some error\n" +
+				"/src/bar.go:10 github.com/foo/bar.Do[go.shape.int_0]()\n" +
+				"/src/main.go:20 main.Map[go.shape.string_0,go.shape.*uint8_1]()\n" +
+				"/src/main.go:30 main.(*List[go.shape.[]int_0]).Push()\n" +
+				"/src/main.go:40 main.Do[...]()\n" +
+				"/src/main.go:50 main.main()",
+		},
+		{
+			Style: tracerr.GenericNamesReadable,
+			Expected: "some error\n" +
+				"/src/bar.go:10 github.com/foo/bar.Do[int]()\n" +
+				"/src/main.go:20 main.Map[string,*uint8]()\n" +
+				"/src/main.go:30 main.(*List[[]int]).Push()\n" +
+				"/src/main.go:40 main.Do[...]()\n" +
+				"/src/main.go:50 main.main()",
+		},
+		{
+			Style: tracerr.GenericNamesElided,
+			Expected: "some error\n" +
+				"/src/bar.go:10 github.com/foo/bar.Do[...]()\n" +
+				"/src/main.go:20 main.Map[...]()\n" +
+				"/src/main.go:30 main.(*List[...]).Push()\n" +
+				"/src/main.go:40 main.Do[...]()\n" +
+				"/src/main.go:50 main.main()",
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.SetPrintOptions(tracerr.WithGenericNames(c.Style))
+		output := tracerr.Sprint(err)
+		tracerr.SetPrintOptions()
+		if output != c.Expected {
+			t.Errorf("case #%d: tracerr.Sprint(err) = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+	if frames[0].Func != "github.com/foo/bar.Do[go.shape.int_0]" {
+		t.Errorf("frames[0].Func = %#v; want frames unchanged", frames[0].Func)
+	}
+}
+
+func TestGenericFrameParts(t *testing.T) {
+	frame := tracerr.NewFrame("github.com/foo/bar.(*List[github.com/baz/qux.T]).Push", "/src/bar.go", 10)
+	if frame.Package != "github.com/foo/bar" || frame.Receiver != "*List[github.com/baz/qux.T]" || frame.Name != "Push" {
+		t.Errorf("frame = %#v; want package, receiver and name split outside of type arguments", frame)
+	}
+}
+
+func TestFingerprintGenericShapes(t *testing.T) {
+	a := tracerr.CustomError(errors.New("a"), []tracerr.Frame{tracerr.NewFrame("main.Do[go.shape.int_0]", "/src/main.go", 10)})
+	b := tracerr.CustomError(errors.New("b"), []tracerr.Frame{tracerr.NewFrame("main.Do[go.shape.string_0]", "/src/main.go", 10)})
+	if tracerr.Fingerprint(a) != tracerr.Fingerprint(b) {
+		t.Errorf("tracerr.Fingerprint() differs for instantiations of the same function")
+	}
+}
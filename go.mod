@@ -1,3 +1,9 @@
 module github.com/kadaan/tracerr
 
+go 1.23
+
 require github.com/logrusorgru/aurora v0.0.0-20181002194514-a7b3b318ed4e
+
+require github.com/pkg/errors v0.9.1
+
+require golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da
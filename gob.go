@@ -0,0 +1,66 @@
+package tracerr
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+func init() {
+	// Errors can be sent as values of error interface, e.g. by net/rpc.
+	gob.RegisterName("github.com/kadaan/tracerr.Error", &errorData{})
+}
+
+// gobError is a wire form of errorData.
+type gobError struct {
+	Message string
+	Frames  []gobFrame
+	TraceID string
+	SpanID  string
+}
+
+// gobFrame is a wire form of Frame, it has no methods,
+// so gob encodes all of its fields instead of Frame.MarshalText.
+type gobFrame Frame
+
+// GobEncode encodes error message and stack trace.
+// Original error type and program counters of frames are not kept,
+// since they are meaningless for another process. Trace context is kept.
+func (e *errorData) GobEncode() ([]byte, error) {
+	frames := redactStackTrace(e.RawFrames())
+	trace, _ := Trace(e)
+	data := gobError{
+		Message: ScrubMessage(e.Error()),
+		Frames:  make([]gobFrame, 0, len(frames)),
+		TraceID: trace.TraceID,
+		SpanID:  trace.SpanID,
+	}
+	for _, frame := range frames {
+		frame.PC, frame.Entry = 0, 0
+		data.Frames = append(data.Frames, gobFrame(frame))
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// GobDecode decodes error encoded by GobEncode,
+// original error is replaced with an error with the same message.
+func (e *errorData) GobDecode(b []byte) error {
+	if e == nil {
+		return errNilReceiver
+	}
+	var data gobError
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&data); err != nil {
+		return err
+	}
+	e.err = errors.New(data.Message)
+	e.trace = TraceContext{TraceID: data.TraceID, SpanID: data.SpanID}
+	e.frames = make([]Frame, 0, len(data.Frames))
+	for _, frame := range data.Frames {
+		e.frames = append(e.frames, Frame(frame))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+// gobReply is a value with error interface field like a reply of net/rpc.
+type gobReply struct {
+	Err error
+}
+
+func TestGob(t *testing.T) {
+	err := tracerr.Wrap(errors.New("some error"))
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(gobReply{Err: err}); err != nil {
+		t.Fatalf("Encode() error = %#v; want nil", err)
+	}
+	var reply gobReply
+	if err := gob.NewDecoder(&b).Decode(&reply); err != nil {
+		t.Fatalf("Decode() error = %#v; want nil", err)
+	}
+
+	decoded, ok := reply.Err.(tracerr.Error)
+	if !ok {
+		t.Fatalf("reply.Err = %#v; want tracerr.Error", reply.Err)
+	}
+	if decoded.Error() != "some error" {
+		t.Errorf("decoded.Error() = %#v; want %#v", decoded.Error(), "some error")
+	}
+	frames := err.StackTrace()
+	decodedFrames := decoded.StackTrace()
+	if len(decodedFrames) != len(frames) {
+		t.Fatalf("len(decodedFrames) = %#v; want %#v", len(decodedFrames), len(frames))
+	}
+	for i, frame := range frames {
+		frame.PC, frame.Entry = 0, 0
+		if decodedFrames[i] != frame {
+			t.Errorf("decodedFrames[%d] = %#v; want %#v", i, decodedFrames[i], frame)
+		}
+	}
+}
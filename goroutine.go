@@ -0,0 +1,75 @@
+package tracerr
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// receivedMarkerPrefix starts function name of a marker frame,
+// which separates frames of goroutines, see WithGoroutineIDs.
+const receivedMarkerPrefix = "<received on goroutine "
+
+// WithGoroutineIDs makes Tracerr record ID of goroutine capturing an error.
+//
+// When Wrap is called with an Error captured on another goroutine,
+// e.g. received from a channel of a worker, frames of the current goroutine
+// are appended to its stack trace after a marker frame
+// "<received on goroutine N>", where N is ID of the current goroutine,
+// so the wrap site isn't lost. Errors wrapping a traced error are handled
+// the same way. Recording costs a call of runtime.Stack per capture.
+func WithGoroutineIDs() Option {
+	return func(t *tracerr) {
+		t.goroutineIDs = true
+	}
+}
+
+// GoroutineID returns ID of goroutine, which captured err,
+// or received it, see WithGoroutineIDs.
+// It returns false if ID isn't recorded.
+func GoroutineID(err error) (uint64, bool) {
+	if err == nil {
+		return 0, false
+	}
+	if d, ok := err.(*errorData); ok && d != nil && d.goroutine != 0 {
+		return d.goroutine, true
+	}
+	for _, wrapped := range unwrapAll(err) {
+		if id, ok := GoroutineID(wrapped); ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// receive appends frames of the caller to stack trace of d,
+// if d was captured on another goroutine, see WithGoroutineIDs.
+// skip is a number of caller's frames to skip in addition.
+func (t *tracerr) receive(d *errorData, skip int) Error {
+	id := currentGoroutineID()
+	if d.goroutine == 0 || id == 0 || d.goroutine == id || !Enabled() {
+		return d
+	}
+	local := RawFrames(t.traceFrames(d.err, skip))
+	frames := make([]Frame, 0, len(d.RawFrames())+1+len(local))
+	frames = append(frames, d.RawFrames()...)
+	frames = append(frames, Frame{Func: fmt.Sprintf("%s%d>", receivedMarkerPrefix, id)})
+	r := d.with(d.err, append(frames, local...))
+	r.goroutine = id
+	return r
+}
+
+// currentGoroutineID returns ID of the current goroutine,
+// or zero if it can't be parsed.
+func currentGoroutineID() uint64 {
+	header := bytes.TrimPrefix(goroutineHeader(), []byte("goroutine "))
+	id, _ := strconv.ParseUint(string(bytes.TrimSpace(bytes.TrimSuffix(header, []byte("[")))), 10, 64)
+	return id
+}
+
+// isReceivedMarker reports whether name is a function name
+// of a marker frame added by WithGoroutineIDs.
+func isReceivedMarker(name string) bool {
+	return strings.HasPrefix(name, receivedMarkerPrefix) && strings.HasSuffix(name, ">")
+}
@@ -0,0 +1,71 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithGoroutineIDs(t *testing.T) {
+	tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithGoroutineIDs())
+	local := tr.New("local error")
+	id, ok := tracerr.GoroutineID(local)
+	if !ok || id == 0 {
+		t.Fatalf("tracerr.GoroutineID(local) = %#v, %#v; want non-zero ID, true", id, ok)
+	}
+	if wrapped := tr.Wrap(local); wrapped != local {
+		t.Errorf("tr.Wrap(local) = %#v; want local error as is", wrapped)
+	}
+
+	received := make(chan error)
+	go func() {
+		received <- tr.New("remote error")
+	}()
+	remote := <-received
+	remoteID, _ := tracerr.GoroutineID(remote)
+	if remoteID == id {
+		t.Fatalf("tracerr.GoroutineID(remote) = %#v; want different from %#v", remoteID, id)
+	}
+
+	cases := []error{
+		remote,
+		fmt.Errorf("context: %w", remote),
+	}
+	for i, err := range cases {
+		wrapped := tr.Wrap(err)
+		if !errors.Is(wrapped, tracerr.Unwrap(remote)) {
+			t.Errorf("case #%d: errors.Is(wrapped, tracerr.Unwrap(remote)) = false; want true", i)
+		}
+		if wrappedID, _ := tracerr.GoroutineID(wrapped); wrappedID != id {
+			t.Errorf("case #%d: tracerr.GoroutineID(wrapped) = %#v; want %#v", i, wrappedID, id)
+		}
+		frames := tracerr.RawFrames(wrapped)
+		marker := -1
+		for j, frame := range frames {
+			if frame.IsMarker() {
+				marker = j
+			}
+		}
+		if marker < 1 || marker == len(frames)-1 {
+			t.Fatalf("case #%d: marker frame index = %#v; want between frames", i, marker)
+		}
+		if expected := fmt.Sprintf("<received on goroutine %d>", id); frames[marker].Func != expected {
+			t.Errorf("case #%d: marker frame = %#v; want %#v", i, frames[marker].Func, expected)
+		}
+		if !strings.HasPrefix(frames[0].Func, "github.com/kadaan/tracerr_test.TestWithGoroutineIDs.func") {
+			t.Errorf("case #%d: frames[0].Func = %#v; want remote goroutine function", i, frames[0].Func)
+		}
+		if frames[marker+1].Func != "github.com/kadaan/tracerr_test.TestWithGoroutineIDs" {
+			t.Errorf("case #%d: frames[%d].Func = %#v; want wrap site", i, marker+1, frames[marker+1].Func)
+		}
+		if again := tr.Wrap(wrapped); len(tracerr.RawFrames(again)) != len(frames) {
+			t.Errorf("case #%d: len(tr.Wrap(wrapped).RawFrames()) = %#v; want %#v", i, len(tracerr.RawFrames(again)), len(frames))
+		}
+	}
+	if tracerr.Fingerprint(tr.Wrap(remote)) != tracerr.Fingerprint(tr.Wrap(remote)) {
+		t.Errorf("fingerprints of errors received at the same stack differ")
+	}
+}
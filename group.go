@@ -0,0 +1,157 @@
+package tracerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// GroupIndexField is a field of errors returned by Group, see Fields.
+// It's an index of the failed function in order of calls of Go and TryGo,
+// starting from 0.
+const GroupIndexField = "group.index"
+
+// GroupMode sets which errors Group.Wait returns, see Group.SetMode.
+type GroupMode int
+
+const (
+	// GroupJoinErrors makes Wait return all errors joined by errors.Join.
+	GroupJoinErrors GroupMode = iota
+	// GroupFirstError makes Wait return the first error,
+	// as errgroup.Group of golang.org/x/sync/errgroup does.
+	GroupFirstError
+)
+
+// Group runs functions in goroutines and collects their errors,
+// similar to errgroup.Group, it has SetLimit and TryGo as well.
+//
+// Errors are wrapped by stack trace and panics are converted to errors
+// with stack trace of the panic, so a failing worker doesn't crash
+// the program. Stack traces are followed by frames of the caller of Go
+// as in Spawn, and index of the failed function is attached to the error
+// as GroupIndexField. The zero Group is valid, joins all errors,
+// has no limit and doesn't cancel on error.
+type Group struct {
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	mode   GroupMode
+	mu     sync.Mutex
+	next   int
+	errs   []error
+	cancel context.CancelCauseFunc
+}
+
+// GroupWithContext returns a new Group and a derived context,
+// which is canceled when a function passed to Go fails
+// or Wait returns, whichever occurs first, as by errgroup.WithContext.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetMode sets which errors Wait returns, GroupFirstError makes Group
+// a drop-in replacement for errgroup.Group. It must be called before Go.
+func (g *Group) SetMode(mode GroupMode) {
+	g.mode = mode
+}
+
+// Go calls fn in a new goroutine, it blocks until the new goroutine
+// can be added without exceeding the limit set by SetLimit.
+func (g *Group) Go(fn func() error) {
+	spawn := spawnFrames(context.Background())
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn, spawn)
+}
+
+// TryGo calls fn in a new goroutine only if it doesn't exceed
+// the limit set by SetLimit, and reports whether it was started.
+func (g *Group) TryGo(fn func() error) bool {
+	spawn := spawnFrames(context.Background())
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn, spawn)
+	return true
+}
+
+// SetLimit limits number of active goroutines to n,
+// a negative n means no limit. It panics if called
+// while any goroutines of the group are active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("tracerr: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Wait blocks until all functions passed to Go and TryGo return,
+// then it returns nil or errors by the mode set by SetMode:
+// by default all errors joined by errors.Join, each of them keeps
+// its own stack trace, the returned Error has stack trace of the first one.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch {
+	case len(g.errs) == 0:
+		return nil
+	case len(g.errs) == 1 || g.mode == GroupFirstError:
+		return g.errs[0]
+	}
+	return Wrap(errors.Join(g.errs...))
+}
+
+// start runs fn in a new goroutine, a slot of limit is already taken.
+func (g *Group) start(fn func() error, spawn []Frame) {
+	g.mu.Lock()
+	index := g.next
+	g.next++
+	g.mu.Unlock()
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		err, panicked := runRecover(fn)
+		if err == nil {
+			return
+		}
+		e := withFields(withSpawnFrames(err, spawn), map[string]interface{}{GroupIndexField: index})
+		if panicked {
+			writePanicReport(e)
+		}
+		g.mu.Lock()
+		g.errs = append(g.errs, e)
+		g.mu.Unlock()
+		if g.cancel != nil {
+			g.cancel(e)
+		}
+	}()
+}
+
+// runRecover calls fn and converts its panic to an error,
+// panicked is true if fn panicked.
+func runRecover(fn func() error) (err error, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PanicError(r)
+			panicked = true
+		}
+	}()
+	return Wrap(fn()), false
+}
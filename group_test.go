@@ -0,0 +1,176 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestGroup(t *testing.T) {
+	errFirst := errors.New("first")
+	var g tracerr.Group
+	g.Go(func() error {
+		return nil
+	})
+	g.Go(func() error {
+		return tracerr.Wrap(errFirst)
+	})
+	g.Go(func() error {
+		panicInWorker()
+		return nil
+	})
+	err := g.Wait()
+	if !errors.Is(err, errFirst) {
+		t.Errorf("g.Wait() = %v; want wrapped %v", err, errFirst)
+	}
+	if !strings.Contains(err.Error(), "panic: worker failed") {
+		t.Errorf("g.Wait() = %v; want panic message", err)
+	}
+	var found bool
+	joined, ok := tracerr.Unwrap(err).(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("tracerr.Unwrap(g.Wait()) = %#v; want joined errors", tracerr.Unwrap(err))
+	}
+	for _, e := range joined.Unwrap() {
+		if !strings.HasPrefix(e.Error(), "panic: ") {
+			continue
+		}
+		found = true
+		frames := tracerr.StackTrace(e)
+		if len(frames) == 0 || frames[0].Name != "panicInWorker" {
+			t.Errorf("panic error frames = %v; want panicInWorker on top", frames)
+		}
+	}
+	if !found {
+		t.Errorf("g.Wait() = %v; want traced panic error", err)
+	}
+}
+
+func TestGroupNilDereference(t *testing.T) {
+	var g tracerr.Group
+	g.Go(func() error {
+		var p *struct{ n int }
+		p.n++
+		return nil
+	})
+	err := g.Wait()
+	e, ok := err.(tracerr.Error)
+	if !ok {
+		t.Fatalf("g.Wait() = %#v; want tracerr.Error", err)
+	}
+	frames := e.StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Func, "TestGroupNilDereference.func1") {
+		t.Errorf("e.StackTrace() = %v; want worker on top", frames)
+	}
+}
+
+func TestGroupNoErrors(t *testing.T) {
+	var g tracerr.Group
+	for i := 0; i < 3; i++ {
+		g.Go(func() error {
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Errorf("g.Wait() = %v; want nil", err)
+	}
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := tracerr.GroupWithContext(context.Background())
+	g.Go(func() error {
+		return tracerr.New("failed")
+	})
+	g.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	err := g.Wait()
+	if err == nil || err.Error() != "failed" {
+		t.Errorf("g.Wait() = %v; want failed", err)
+	}
+	if cause := context.Cause(ctx); cause != err {
+		t.Errorf("context.Cause(ctx) = %v; want %v", cause, err)
+	}
+}
+
+//go:noinline
+func panicInWorker() {
+	panic("worker failed")
+}
+
+func TestGroupFirstError(t *testing.T) {
+	g, ctx := tracerr.GroupWithContext(context.Background())
+	g.SetMode(tracerr.GroupFirstError)
+	errFirst := errors.New("first failure")
+	g.Go(func() error {
+		return nil
+	})
+	g.Go(func() error {
+		return errFirst
+	})
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	err := g.Wait()
+	if !errors.Is(err, errFirst) {
+		t.Fatalf("g.Wait() = %v; want the first error", err)
+	}
+	if index := tracerr.Fields(err)[tracerr.GroupIndexField]; index != 1 {
+		t.Errorf("tracerr.Fields(err)[GroupIndexField] = %#v; want 1", index)
+	}
+	if !errors.Is(context.Cause(ctx), errFirst) {
+		t.Errorf("context.Cause(ctx) = %v; want the first error", context.Cause(ctx))
+	}
+	frames := tracerr.StackTrace(err)
+	createdBy := false
+	for _, frame := range frames {
+		if frame.CreatedBy && frame.Name == "TestGroupFirstError" {
+			createdBy = true
+		}
+	}
+	if !createdBy {
+		t.Errorf("tracerr.StackTrace(err) = %#v; want frames of the caller of Go", frames)
+	}
+}
+
+func TestGroupIndex(t *testing.T) {
+	var g tracerr.Group
+	g.Go(func() error {
+		panic("worker failure")
+	})
+	err := g.Wait()
+	if value, ok := tracerr.PanicValue(err); !ok || value != "worker failure" {
+		t.Errorf("tracerr.PanicValue(err) = %#v, %#v; want %#v, true", value, ok, "worker failure")
+	}
+	if index := tracerr.Fields(err)[tracerr.GroupIndexField]; index != 0 {
+		t.Errorf("tracerr.Fields(err)[GroupIndexField] = %#v; want 0", index)
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	var g tracerr.Group
+	g.SetLimit(1)
+	release := make(chan struct{})
+	if !g.TryGo(func() error {
+		<-release
+		return nil
+	}) {
+		t.Fatalf("g.TryGo() = false; want true within limit")
+	}
+	if g.TryGo(func() error { return nil }) {
+		t.Errorf("g.TryGo() = true; want false over limit")
+	}
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Errorf("g.Wait() = %v; want nil", err)
+	}
+	if !g.TryGo(func() error { return nil }) {
+		t.Errorf("g.TryGo() = false; want true after Wait")
+	}
+	g.Wait()
+}
@@ -0,0 +1,109 @@
+package tracerr
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// htmlStyle is embedded into HTML output, so it's standalone.
+const htmlStyle = `<style>
+.tracerr{font-family:monospace;font-size:13px;color:#24292e}
+.tracerr-message{font-weight:bold;color:#cb2431;margin:0 0 8px}
+.tracerr-frame{margin:4px 0}
+.tracerr-frame summary{cursor:pointer}
+.tracerr-func{color:#6f42c1}
+.tracerr-source{background:#f6f8fa;margin:4px 0 8px;padding:4px 0}
+.tracerr-line{display:block;padding:0 8px}
+.tracerr-number{display:inline-block;min-width:4em;color:#959da5}
+.tracerr-current{background:#ffeef0}
+.tracerr-expression{text-decoration:underline wavy #cb2431}
+.tracerr-warning{color:#b08800;margin:4px 0 8px}
+.tracerr-omitted,.tracerr-created-by{color:#959da5;margin:4px 0}
+.tracerr-dimmed summary{color:#959da5}
+</style>`
+
+// SprintHTML returns error output as a standalone HTML fragment,
+// each frame is collapsible and traced source lines are highlighted.
+//
+// By default source lines follow the same rules as in PrintSource,
+// see WithSourceLines to change it.
+func SprintHTML(err error, options ...PrintOption) string {
+	if err == nil {
+		return ""
+	}
+	o := mergePrintOptions(options)
+	var b strings.Builder
+	b.WriteString(`<div class="tracerr">`)
+	b.WriteString(htmlStyle)
+	fmt.Fprintf(&b, `<p class="tracerr-message">%s</p>`, html.EscapeString(err.Error()))
+	e, ok := err.(Error)
+	if !ok {
+		b.WriteString(`</div>`)
+		return b.String()
+	}
+	o.inlineSource = inlineSourceOf(e)
+	before, after, withSource := calcRows(o.lines)
+	for _, frame := range o.outputFrames(e) {
+		if separator, ok := separatorString(frame); ok {
+			fmt.Fprintf(&b, `<p class="tracerr-omitted">%s</p>`, html.EscapeString(separator))
+			continue
+		}
+		if frame.CreatedBy {
+			fmt.Fprintf(&b, `<p class="tracerr-created-by">%s</p>`, createdBy)
+		}
+		open := ""
+		if frame.Top {
+			open = " open"
+		}
+		class := "tracerr-frame"
+		if o.dimNonApp && frame.Origin() != FrameOriginApp {
+			class += " tracerr-dimmed"
+		}
+		fmt.Fprintf(&b, `<details class="%s"%s>`, class, open)
+		fmt.Fprintf(
+			&b,
+			`<summary><span class="tracerr-path">%s</span> <span class="tracerr-func">%s()</span>%s</summary>`,
+			html.EscapeString(o.location(frame.Frame)), html.EscapeString(frame.Func),
+			html.EscapeString(repeatedSuffix(frame)),
+		)
+		if withSource {
+			before, after := o.frameRows(frame.Top, frame.Frame, before, after)
+			writeHTMLSource(&b, &o, frame.Frame, frame.Callee, before, after)
+		}
+		b.WriteString(`</details>`)
+	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+func writeHTMLSource(b *strings.Builder, o *printOptions, frame Frame, callee string, before, after int) {
+	fragment, err := o.sourceFragment(frame, before, after)
+	if err != nil {
+		fmt.Fprintf(b, `<p class="tracerr-warning">%s</p>`, html.EscapeString(err.Error()))
+		return
+	}
+	if sourceChanged(frame, fragment) {
+		fmt.Fprintf(b, `<p class="tracerr-warning">%s</p>`, staleSourceWarning)
+	}
+	highlightExpression(fragment, callee)
+	b.WriteString(`<pre class="tracerr-source">`)
+	for _, line := range fragment {
+		class := "tracerr-line"
+		if line.Current {
+			class += " tracerr-current"
+		}
+		text := html.EscapeString(line.Text)
+		if line.End > 0 {
+			text = html.EscapeString(line.Text[:line.Start]) +
+				`<span class="tracerr-expression">` + html.EscapeString(line.Text[line.Start:line.End]) + `</span>` +
+				html.EscapeString(line.Text[line.End:])
+		}
+		fmt.Fprintf(
+			b,
+			`<span class="%s"><span class="tracerr-number">%d</span>%s</span>`,
+			class, line.Number, text,
+		)
+	}
+	b.WriteString(`</pre>`)
+}
@@ -0,0 +1,76 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintHTML(t *testing.T) {
+	if output := tracerr.SprintHTML(nil); output != "" {
+		t.Errorf("tracerr.SprintHTML(nil) = %#v; want %#v", output, "")
+	}
+
+	output := tracerr.SprintHTML(errors.New("regular <error>"))
+	expected := `<p class="tracerr-message">regular &lt;error&gt;</p></div>`
+	if !strings.HasSuffix(output, expected) {
+		t.Errorf(
+			"tracerr.SprintHTML(err) = %#v; want to has suffix %#v",
+			output, expected,
+		)
+	}
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 17,
+				Path: "error_helper_test.go",
+			},
+			{
+				Func: "main.Bar",
+				Line: 42,
+				Path: "/tmp/not_exists.go",
+			},
+		},
+	)
+	output = tracerr.SprintHTML(err, tracerr.WithSourceLines(1, 1))
+	for _, expected := range []string{
+		`<div class="tracerr"><style>`,
+		`<p class="tracerr-message">some error</p>`,
+		`<details class="tracerr-frame" open><summary><span class="tracerr-path">error_helper_test.go:17</span> <span class="tracerr-func">main.Foo()</span></summary>`,
+		`<pre class="tracerr-source">` +
+			`<span class="tracerr-line"><span class="tracerr-number">16</span>func addFrameC(message string) error {</span>` +
+			`<span class="tracerr-line tracerr-current"><span class="tracerr-number">17</span>	return <span class="tracerr-expression">tracerr.New(message)</span></span>` +
+			`<span class="tracerr-line"><span class="tracerr-number">18</span>}</span>` +
+			`</pre></details>`,
+		`<details class="tracerr-frame"><summary><span class="tracerr-path">/tmp/not_exists.go:42</span> <span class="tracerr-func">main.Bar()</span></summary>` +
+			`<p class="tracerr-warning">tracerr: file /tmp/not_exists.go not found</p></details></div>`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf(
+				"tracerr.SprintHTML(err) = %#v; want to contain %#v",
+				output, expected,
+			)
+		}
+	}
+}
+
+func TestSprintHTMLWindowCallee(t *testing.T) {
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			tracerr.NewFrame("main.Foo", "/tmp/not_exists.go", 42),
+			tracerr.NewFrame("github.com/kadaan/tracerr_test.addFrameC", "error_helper_test.go", 17),
+			tracerr.NewFrame("github.com/kadaan/tracerr_test.addFrameB", "error_helper_test.go", 13),
+		},
+	)
+	output := tracerr.SprintHTML(err, tracerr.WithSourceLines(0, 0), tracerr.WithFrameWindow(1, 1))
+	expected := `return <span class="tracerr-expression">addFrameC(message)</span>`
+	if !strings.Contains(output, expected) {
+		t.Errorf("tracerr.SprintHTML(err) = %#v; want to contain %#v", output, expected)
+	}
+}
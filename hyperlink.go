@@ -0,0 +1,52 @@
+package tracerr
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Common URL templates for WithHyperlinks.
+const (
+	HyperlinkFile   = "file://{path}"
+	HyperlinkVSCode = "vscode://file/{path}:{line}"
+	HyperlinkIDEA   = "idea://open?file={path}&line={line}"
+)
+
+// WithHyperlinks makes colored output wrap path and line of each frame
+// into terminal hyperlink (OSC 8), so frames can be opened in editor by click.
+//
+// In urlTemplate {path} is replaced with a file path
+// and {line} with a line number, e.g. HyperlinkVSCode.
+// The path is escaped as a query value if {path} follows "?" in urlTemplate,
+// and as a URL path otherwise.
+// Hyperlinks are disabled by default.
+func WithHyperlinks(urlTemplate string) PrintOption {
+	return func(o *printOptions) {
+		o.hyperlink = urlTemplate
+	}
+}
+
+// hyperlinkFrame returns frame row with location linked to frame,
+// location and fn may be truncated, see WithMaxWidth.
+func (o *printOptions) hyperlinkFrame(frame Frame, location, fn string) string {
+	url := strings.NewReplacer(
+		"{path}", hyperlinkPath(o.hyperlink, o.rewritePath(frame.Path)),
+		"{line}", strconv.Itoa(frame.Line),
+	).Replace(o.hyperlink)
+	return fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\ %s()", url, location, fn)
+}
+
+// hyperlinkPath returns path escaped for its place in urlTemplate.
+func hyperlinkPath(urlTemplate, path string) string {
+	query := strings.Index(urlTemplate, "?")
+	if query >= 0 && query < strings.Index(urlTemplate, "{path}") {
+		return url.QueryEscape(path)
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
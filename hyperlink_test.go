@@ -0,0 +1,74 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithHyperlinks(t *testing.T) {
+	tracerr.SetPrintOptions(tracerr.WithHyperlinks(tracerr.HyperlinkVSCode))
+	defer tracerr.SetPrintOptions()
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 42,
+				Path: "/tmp/foo.go",
+			},
+		},
+	)
+	output := tracerr.SprintSourceColor(err, 0)
+	expectedRows := []string{
+		"some error",
+		aurora.Bold("\x1b]8;;vscode://file//tmp/foo.go:42\x1b\\/tmp/foo.go:42\x1b]8;;\x1b\\ main.Foo()").String(),
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf(
+			"tracerr.SprintSourceColor(err, 0) = %#v; want %#v",
+			output, expected,
+		)
+	}
+
+	output = tracerr.Sprint(err)
+	expected = "some error\n/tmp/foo.go:42 main.Foo()"
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+}
+
+func TestWithHyperlinksEscaping(t *testing.T) {
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 42,
+				Path: "/tmp/my dir/a#b&c.go",
+			},
+		},
+	)
+	cases := []struct {
+		urlTemplate string
+		url         string
+	}{
+		{tracerr.HyperlinkFile, "file:///tmp/my%20dir/a%23b&c.go"},
+		{tracerr.HyperlinkVSCode, "vscode://file//tmp/my%20dir/a%23b&c.go:42"},
+		{tracerr.HyperlinkIDEA, "idea://open?file=%2Ftmp%2Fmy+dir%2Fa%23b%26c.go&line=42"},
+	}
+	defer tracerr.SetPrintOptions()
+	for i, c := range cases {
+		tracerr.SetPrintOptions(tracerr.WithHyperlinks(c.urlTemplate))
+		output := tracerr.SprintSourceColor(err, 0)
+		if !strings.Contains(output, "\x1b]8;;"+c.url+"\x1b\\") {
+			t.Errorf("case #%d: tracerr.SprintSourceColor(err, 0) = %#v; want link %#v", i, output, c.url)
+		}
+	}
+}
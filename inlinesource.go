@@ -0,0 +1,74 @@
+package tracerr
+
+// inlineSource is source copied at capture, see WithInlineSource,
+// it maps file paths to line numbers to lines.
+type inlineSource map[string]map[int]string
+
+// WithInlineSource copies n source lines before and after traced line
+// of each frame into the error at capture, so source printers show them
+// when source files are not available, e.g. in deployment artifacts.
+// Source files on disk are used if they exist.
+//
+// It's off by default, as it reads source files at capture,
+// which makes capture slower and errors larger.
+// Frames captured with WithLazyFrames are resolved at capture.
+func WithInlineSource(n int) Option {
+	return func(t *tracerr) {
+		t.inlineSource = n >= 0
+		t.inlineSourceLines = max(n, 0)
+	}
+}
+
+// snapshotSource returns source around traced lines of frames,
+// n lines before and after each of them.
+func snapshotSource(frames []Frame, n int) inlineSource {
+	var source inlineSource
+	for _, frame := range frames {
+		lines, err := readLines(frame.Path)
+		if err != nil || frame.Line < 1 || frame.Line > len(lines) {
+			continue
+		}
+		if source == nil {
+			source = make(inlineSource)
+		}
+		file := source[frame.Path]
+		if file == nil {
+			file = make(map[int]string)
+			source[frame.Path] = file
+		}
+		for i := max(frame.Line-n, 1); i <= min(frame.Line+n, len(lines)); i++ {
+			file[i] = lines[i-1]
+		}
+	}
+	return source
+}
+
+// inlineSourceOf returns source copied into err at capture, or nil.
+func inlineSourceOf(err error) inlineSource {
+	if d, ok := err.(*errorData); ok && d != nil {
+		return d.source
+	}
+	return nil
+}
+
+// fragment returns lines of the fragment around traced line of frame,
+// which were copied at capture, and reports whether traced line is known.
+func (s inlineSource) fragment(frame Frame, before, after int) ([]sourceLine, bool) {
+	file := s[frame.Path]
+	if _, ok := file[frame.Line]; !ok {
+		return nil, false
+	}
+	fragment := make([]sourceLine, 0, before+after+1)
+	for i := frame.Line - before; i <= frame.Line+after; i++ {
+		text, ok := file[i]
+		if !ok {
+			continue
+		}
+		fragment = append(fragment, sourceLine{
+			Number:  i,
+			Text:    text,
+			Current: i == frame.Line,
+		})
+	}
+	return fragment, true
+}
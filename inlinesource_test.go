@@ -0,0 +1,41 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithInlineSource(t *testing.T) {
+	tracer := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithInlineSource(1))
+	err := tracer.New("inline error")
+
+	tracerr.SetSourceProvider(tracerr.NewNopSourceProvider())
+	defer tracerr.SetSourceProvider(nil)
+	tracerr.ClearSourceCache()
+	defer tracerr.ClearSourceCache()
+
+	const traced = `err := tracer.New("inline error")`
+	outputs := []string{
+		tracerr.SprintSource(err, 1, 1),
+		tracerr.SprintSource(tracerr.Wrap(fmt.Errorf("wrapped: %w", err)), 1, 1),
+		tracerr.SprintHTML(err, tracerr.WithSourceLines(1, 1)),
+		tracerr.SprintMarkdown(err, tracerr.WithSourceLines(1, 1)),
+	}
+	for i, output := range outputs {
+		if !strings.Contains(output, "tracer.New(") || strings.Contains(output, "not found") {
+			t.Errorf("case #%d: output = %#v; want traced line from inline source", i, output)
+		}
+	}
+	lines := strings.Split(outputs[0], "\n")
+	if len(lines) < 6 || !strings.HasPrefix(lines[4], "13\t") || !strings.HasSuffix(lines[4], traced) || lines[5] != "14\t" {
+		t.Errorf("tracerr.SprintSource(err) = %#v; want traced line with one line around it", outputs[0])
+	}
+
+	copied := tracerr.CustomError(err.Unwrap(), err.StackTrace())
+	if output := tracerr.SprintSource(copied, 1, 1); !strings.Contains(output, "not found") {
+		t.Errorf("tracerr.SprintSource(copied) = %#v; want missing source without inline copy", output)
+	}
+}
@@ -0,0 +1,41 @@
+package tracerr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// internMaxSize is a maximum number of strings in the intern table,
+// which never evicts them.
+const internMaxSize = 1 << 16
+
+// internMaxLength is a maximum length of interned strings,
+// which bounds memory taken by the intern table.
+const internMaxLength = 512
+
+var (
+	internTable sync.Map
+	internSize  atomic.Int64
+)
+
+// intern returns a canonical copy of s from a process-wide table,
+// so frames of many errors held in memory share equal paths and function names.
+// Strings longer than internMaxLength, as well as new strings
+// once the table has internMaxSize ones, are returned as is.
+// Strings of decoded errors aren't interned, as they are untrusted.
+func intern(s string) string {
+	if s == "" || len(s) > internMaxLength {
+		return s
+	}
+	if v, ok := internTable.Load(s); ok {
+		return v.(string)
+	}
+	if internSize.Load() >= internMaxSize {
+		return s
+	}
+	v, loaded := internTable.LoadOrStore(s, s)
+	if !loaded {
+		internSize.Add(1)
+	}
+	return v.(string)
+}
@@ -0,0 +1,54 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrhttp"
+)
+
+func sameString(a, b string) bool {
+	return unsafe.StringData(a) == unsafe.StringData(b)
+}
+
+func TestNewFrameInterned(t *testing.T) {
+	// Strings are built at run time, so they don't share memory.
+	a := tracerr.NewFrame(strings.Repeat("main.read", 1), strings.Repeat("/src/main.go", 1), 10)
+	b := tracerr.NewFrame(strings.Repeat("main.read", 1), strings.Repeat("/src/main.go", 1), 20)
+	if !sameString(a.Func, b.Func) {
+		t.Errorf("a.Func and b.Func don't share memory")
+	}
+	if !sameString(a.Path, b.Path) {
+		t.Errorf("a.Path and b.Path don't share memory")
+	}
+}
+
+func TestNewFrameLongNotInterned(t *testing.T) {
+	path := strings.Repeat("/src", 200) + "/main.go"
+	a := tracerr.NewFrame("main.read", strings.Clone(path), 10)
+	b := tracerr.NewFrame("main.read", strings.Clone(path), 20)
+	if sameString(a.Path, b.Path) {
+		t.Errorf("long a.Path and b.Path share memory; want them not interned")
+	}
+}
+
+func TestDecodedFramesNotInterned(t *testing.T) {
+	// Names and paths are new to the intern table, so interning them would grow it.
+	stack := strings.NewReplacer("main.", "decoded.", "/src/", "/decoded/").Replace(panicOutput)
+	encoded := tracerrhttp.Encode(tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewDecodedFrame("decoded.header", "/decoded/header.go", 1),
+	}))
+	before := tracerr.ReadCaptureStats().InternedStrings
+	tracerr.NewDecodedFrame("decoded.frame", "/decoded/frame.go", 1)
+	tracerr.FramesFromStack([]byte(stack))
+	tracerr.ParsePanic(stack)
+	if _, err := tracerrhttp.Decode(encoded); err != nil {
+		t.Fatalf("tracerrhttp.Decode() error = %#v; want nil", err)
+	}
+	if after := tracerr.ReadCaptureStats().InternedStrings; after != before {
+		t.Errorf("InternedStrings = %#v; want %#v", after, before)
+	}
+}
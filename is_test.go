@@ -0,0 +1,128 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/kadaan/tracerr"
+)
+
+// statusError is a custom error type wrapping a sentinel.
+type statusError struct {
+	Status int
+	Err    error
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status %d: %v", e.Status, e.Err)
+}
+
+func (e *statusError) Unwrap() error {
+	return e.Err
+}
+
+// customTraced is a custom implementation of tracerr.Error.
+type customTraced struct {
+	err tracerr.Error
+}
+
+func (e *customTraced) Error() string               { return e.err.Error() }
+func (e *customTraced) StackTrace() []tracerr.Frame { return e.err.StackTrace() }
+func (e *customTraced) RawFrames() []tracerr.Frame  { return tracerr.RawFrames(e.err) }
+func (e *customTraced) Unwrap() error               { return e.err.Unwrap() }
+
+type WrapIsTestCase struct {
+	Name string
+	Err  func(base error) error
+}
+
+func TestWrapIsAs(t *testing.T) {
+	cases := []WrapIsTestCase{
+		{"Wrap", func(base error) error { return tracerr.Wrap(base) }},
+		{"WrapSkip", func(base error) error { return tracerr.WrapSkip(base, 0) }},
+		{"WrapAlways", func(base error) error { return tracerr.WrapAlways(base) }},
+		{"Errorf", func(base error) error { return tracerr.Errorf("context: %w", base) }},
+		{"Wrap twice", func(base error) error { return tracerr.Wrap(tracerr.Wrap(base)) }},
+		{"Wrap of wrapped traced", func(base error) error {
+			return tracerr.Wrap(fmt.Errorf("context: %w", tracerr.Wrap(base)))
+		}},
+		{"Wrap of joined", func(base error) error {
+			return tracerr.Wrap(errors.Join(errors.New("other"), tracerr.Wrap(base)))
+		}},
+		{"Wrap of pkg/errors", func(base error) error { return tracerr.Wrap(pkgerrors.Wrap(base, "context")) }},
+		{"CustomError", func(base error) error { return tracerr.CustomError(base, nil) }},
+		{"ToPkgErrors", func(base error) error { return tracerr.ToPkgErrors(base) }},
+		{"Wrap2", func(base error) error {
+			_, err := tracerr.Wrap2(0, base)
+			return err
+		}},
+		{"sampled out", func(base error) error {
+			return tracerr.NewTracerr(10, 3, tracerr.WithSampling(0)).Wrap(base)
+		}},
+		{"lazy frames", func(base error) error {
+			return tracerr.NewTracerr(10, 3, tracerr.WithLazyFrames()).Wrap(base)
+		}},
+		{"disabled", func(base error) error {
+			tracerr.Disable()
+			defer tracerr.Enable()
+			return tracerr.Wrap(base)
+		}},
+		{"Group", func(base error) error {
+			var g tracerr.Group
+			g.Go(func() error { return base })
+			return g.Wait()
+		}},
+		{"Group of several", func(base error) error {
+			var g tracerr.Group
+			g.Go(func() error { return base })
+			g.Go(func() error { return errors.New("other") })
+			return g.Wait()
+		}},
+		{"Group panic", func(base error) error {
+			var g tracerr.Group
+			g.Go(func() error { panic(base) })
+			return g.Wait()
+		}},
+		{"Spawn", func(base error) error {
+			return <-tracerr.Spawn(context.Background(), func(ctx context.Context) error { return base })
+		}},
+		{"Must", func(base error) (err error) {
+			defer func() {
+				err = recover().(error)
+			}()
+			tracerr.Must(0, base)
+			return nil
+		}},
+	}
+
+	for i, c := range cases {
+		base := &statusError{Status: 404, Err: io.EOF}
+		err := c.Err(base)
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("case #%d %s: errors.Is(err, io.EOF) = false; want true", i, c.Name)
+		}
+		var target *statusError
+		if !errors.As(err, &target) || target != base {
+			t.Errorf("case #%d %s: errors.As(err, &target) = %v; want base error", i, c.Name, target)
+		}
+	}
+}
+
+func TestSpawnKeepsCustomError(t *testing.T) {
+	custom := &customTraced{tracerr.Wrap(io.EOF)}
+	err := <-tracerr.Spawn(context.Background(), func(ctx context.Context) error {
+		return custom
+	})
+	var target *customTraced
+	if !errors.As(err, &target) || target != custom {
+		t.Errorf("errors.As(err, &target) = false; want custom error kept")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(err, io.EOF) = false; want true")
+	}
+}
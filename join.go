@@ -0,0 +1,80 @@
+package tracerr
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Children returns errors joined by err or an error it wraps, such as by
+// errors.Join in another package, or nil if it doesn't join errors.
+// Errors wrapped with Unwrap() error are walked to find joined ones.
+func Children(err error) []error {
+	if isNilError(err) {
+		return nil
+	}
+	return slices.Clone(joinedErrors(err))
+}
+
+// WithJoinedErrors prints each error joined by the printed one,
+// see Children, with its own stack trace after the stack trace
+// of the printed error, as "--- joined error 2 of 3 ---" section.
+// Joined errors without stack traces or sharing the stack trace
+// of the printed error are skipped.
+func WithJoinedErrors() PrintOption {
+	return func(o *printOptions) {
+		o.joinedErrors = true
+	}
+}
+
+// joinedErrors returns errors joined by err or by an error it wraps.
+// Cancellation errors with their causes are not joined errors.
+func joinedErrors(err error) []error {
+	for err != nil {
+		switch u := err.(type) {
+		case *causeError:
+			return nil
+		case interface{ Unwrap() []error }:
+			return u.Unwrap()
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// childRows appends output of children of e with their own stack traces
+// by WithJoinedErrors, children sharing stack trace of e are skipped.
+func (o printOptions) childRows(rows []string, e Error, nums []int, colorized bool, width int) []string {
+	if !o.joinedErrors {
+		return rows
+	}
+	children := Children(e)
+	frames := RawFrames(e)
+	for i, child := range children {
+		traced, ok := AsError(child)
+		if !ok || sameFrames(RawFrames(traced), frames) {
+			continue
+		}
+		c, ok := child.(Error)
+		if !ok {
+			c = CustomError(child, RawFrames(traced))
+		}
+		header := fmt.Sprintf("--- joined error %d of %d ---", i+1, len(children))
+		if colorized {
+			header = colorize(header, o.theme.Context)
+		}
+		if rows[len(rows)-1] != "" {
+			rows = append(rows, "")
+		}
+		rows = append(rows, header, o.sprint(c, nums, colorized, width))
+	}
+	return rows
+}
+
+// sameFrames returns true if a and b are the same stack trace,
+// not just equal ones.
+func sameFrames(a, b []Frame) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
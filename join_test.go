@@ -0,0 +1,45 @@
+package tracerr_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestChildren(t *testing.T) {
+	first := tracerr.CustomError(errors.New("first"), diffFrames("read"))
+	second := tracerr.CustomError(errors.New("second"), diffFrames("write"))
+	joined := errors.Join(first, second, io.EOF)
+
+	err := tracerr.Wrap(joined)
+	if children := tracerr.Children(err); !reflect.DeepEqual(children, []error{first, second, io.EOF}) {
+		t.Errorf("tracerr.Children(err) = %#v; want joined errors", children)
+	}
+	if children := tracerr.Children(tracerr.Wrapf(joined, "saving")); len(children) != 3 {
+		t.Errorf("tracerr.Children(tracerr.Wrapf(joined)) = %#v; want joined errors", children)
+	}
+	if children := tracerr.Children(tracerr.New("single")); children != nil {
+		t.Errorf("tracerr.Children(tracerr.New()) = %#v; want nil", children)
+	}
+
+	if output := tracerr.Sprint(err); strings.Contains(output, "joined error") {
+		t.Errorf("tracerr.Sprint(err) = %#v; want no joined errors by default", output)
+	}
+
+	tracerr.SetPrintOptions(tracerr.WithJoinedErrors())
+	defer tracerr.SetPrintOptions()
+	output := tracerr.Sprint(err)
+	if strings.Contains(output, "--- joined error 1 of 3 ---") {
+		t.Errorf("tracerr.Sprint(err) = %#v; want no child sharing stack trace", output)
+	}
+	if !strings.Contains(output, "--- joined error 2 of 3 ---\nsecond\n/src/main.go:10 main.write()") {
+		t.Errorf("tracerr.Sprint(err) = %#v; want second child with stack trace", output)
+	}
+	if strings.Contains(output, "joined error 3 of 3") {
+		t.Errorf("tracerr.Sprint(err) = %#v; want no child without stack trace", output)
+	}
+}
@@ -0,0 +1,114 @@
+package tracerr
+
+import (
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// LazyFramesMaxDepth is a maximum depth of stack trace
+// captured with WithLazyFrames.
+const LazyFramesMaxDepth = 128
+
+var pcPool = sync.Pool{
+	New: func() interface{} {
+		return new([LazyFramesMaxDepth]uintptr)
+	},
+}
+
+func (t *tracerr) traceLazy(err error, extraSkip int) Error {
+	buf := pcPool.Get().(*[LazyFramesMaxDepth]uintptr)
+	defer pcPool.Put(buf)
+	pcs := t.callers(buf, extraSkip)
+	return &errorData{
+		err:             err,
+		pcs:             append(make([]uintptr, 0, len(pcs)), pcs...),
+		trimEntryPoints: t.trimEntryPoints,
+	}
+}
+
+// callers fills buf with program counters of the caller of trace
+// by the same rules as traceFrames and returns the filled part.
+func (t *tracerr) callers(buf *[LazyFramesMaxDepth]uintptr, extraSkip int) []uintptr {
+	// Skip runtime.Callers, callers and its caller.
+	n := runtime.Callers(t.stackFrameSkipCount+2, buf[:])
+	pcs := buf[:n]
+	for len(pcs) > 0 {
+		// Return address points to the next instruction after call.
+		name := funcName(pcs[0] - 1)
+		pkg, _, _ := splitFuncName(name)
+		if pkg == packageName || t.skipPackages[pkg] {
+			pcs = pcs[1:]
+			continue
+		}
+		if extraSkip > 0 {
+			extraSkip--
+			pcs = pcs[1:]
+			continue
+		}
+		break
+	}
+	return pcs
+}
+
+// resolveFrames resolves program counters to frames,
+// trimEntryPoints stops them at entry point, see WithTrimEntryPoints.
+func resolveFrames(pcs []uintptr, trimEntryPoints bool) []Frame {
+	frames := make([]Frame, 0, len(pcs))
+	for frame := range pcFrames(pcs, trimEntryPoints) {
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// pcFrames returns iterator resolving program counters to frames
+// by the same rules as resolveFrames.
+func pcFrames(pcs []uintptr, trimEntryPoints bool) iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		if len(pcs) == 0 {
+			return
+		}
+		if symbolizer.Load() == nil {
+			yieldCallers(runtime.CallersFrames(pcs), trimEntryPoints, yield)
+			return
+		}
+		for i, pc := range pcs {
+			// Return address points to the next instruction after call.
+			if frame, ok := symbolize(pc - 1); ok {
+				if !yieldFrame(frame, trimEntryPoints, yield) {
+					return
+				}
+				continue
+			}
+			if !yieldCallers(runtime.CallersFrames(pcs[i:i+1]), trimEntryPoints, yield) {
+				return
+			}
+		}
+	}
+}
+
+// yieldCallers passes frames of callers to yield by the same rules
+// as yieldFrame and reports whether to continue with the next frames.
+func yieldCallers(callers *runtime.Frames, trimEntryPoints bool, yield func(Frame) bool) bool {
+	for {
+		f, more := callers.Next()
+		if !yieldFrame(newCapturedFrame(f.PC, f.Entry, f.Function, f.File, f.Line), trimEntryPoints, yield) {
+			return false
+		}
+		if !more {
+			return true
+		}
+	}
+}
+
+// yieldFrame passes frame to yield unless it's past entry point
+// and reports whether to continue with the next frames.
+func yieldFrame(frame Frame, trimEntryPoints bool, yield func(Frame) bool) bool {
+	if trimEntryPoints && isBootstrapFunc(frame.Func) {
+		return false
+	}
+	if !yield(frame) {
+		return false
+	}
+	return !trimEntryPoints || frame.Func != "main.main"
+}
@@ -0,0 +1,46 @@
+package tracerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithMaxOutputBytes limits size of output of print functions to about n bytes,
+// so a pathological error can't flood a log stream.
+// Frames exceeding the limit are replaced with a truncation notice
+// and a too long error message is cut. Zero means no limit.
+func WithMaxOutputBytes(n int) PrintOption {
+	return func(o *printOptions) {
+		o.maxOutputBytes = n
+	}
+}
+
+// truncateMessage cuts message to limit bytes keeping it valid UTF-8.
+func truncateMessage(message string, limit int) string {
+	if len(message) <= limit {
+		return message
+	}
+	omitted := len(message) - limit
+	return fmt.Sprintf("%s... (%d bytes truncated)", strings.ToValidUTF8(message[:limit], ""), omitted)
+}
+
+// truncatedString returns truncation notice of frames left out of output.
+func truncatedString(frames []outputFrame) string {
+	n := 0
+	for _, frame := range frames {
+		n += frame.Repeated + frame.Omitted
+	}
+	if n == 1 {
+		return "... output truncated, 1 frame omitted ..."
+	}
+	return fmt.Sprintf("... output truncated, %d frames omitted ...", n)
+}
+
+// outputSize returns size of rows joined by newlines.
+func outputSize(rows []string) int {
+	size := 0
+	for _, row := range rows {
+		size += len(row) + 1
+	}
+	return size
+}
@@ -0,0 +1,61 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithMaxOutputBytes(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+		tracerr.NewFrame("runtime.main", "/go/src/runtime/proc.go", 250),
+	})
+	cases := []struct {
+		Error    error
+		Limit    int
+		Expected string
+	}{
+		{
+			Error:    err,
+			Limit:    0,
+			Expected: "some error\n/src/main.go:42 main.read()\n/src/main.go:10 main.main()\n/go/src/runtime/proc.go:250 runtime.main()",
+		},
+		{
+			Error:    err,
+			Limit:    110,
+			Expected: "some error\n/src/main.go:42 main.read()\n/src/main.go:10 main.main()\n/go/src/runtime/proc.go:250 runtime.main()",
+		},
+		{
+			Error:    err,
+			Limit:    80,
+			Expected: "some error\n/src/main.go:42 main.read()\n/src/main.go:10 main.main()\n... output truncated, 1 frame omitted ...",
+		},
+		{
+			Error:    err,
+			Limit:    20,
+			Expected: "some error\n... output truncated, 3 frames omitted ...",
+		},
+		{
+			Error:    err,
+			Limit:    4,
+			Expected: "some... (6 bytes truncated)\n... output truncated, 3 frames omitted ...",
+		},
+		{
+			Error:    tracerr.CustomError(errors.New("ошибка"), nil),
+			Limit:    5,
+			Expected: "ош... (7 bytes truncated)",
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.SetPrintOptions(tracerr.WithMaxOutputBytes(c.Limit))
+		output := tracerr.Sprint(c.Error)
+		tracerr.SetPrintOptions()
+		if output != c.Expected {
+			t.Errorf("case #%d: tracerr.Sprint(err) = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
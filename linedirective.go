@@ -0,0 +1,146 @@
+package tracerr
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LineDirectives is a handling of //line directives of generated files,
+// see WithLineDirectives.
+type LineDirectives int
+
+const (
+	// LineDirectivesIgnored prints frames as they are captured,
+	// i.e. at positions in original files, to which the compiler
+	// has already mapped generated code by //line directives.
+	LineDirectivesIgnored LineDirectives = iota
+	// LineDirectivesGenerated prints frames of original files
+	// at positions in generated files, which map to them
+	// by //line directives, and source fragments of generated files.
+	LineDirectivesGenerated
+	// LineDirectivesBoth prints frames as they are captured,
+	// each followed by its position in the generated file.
+	LineDirectivesBoth
+)
+
+// lineDirectivePrefix starts a line directive, it must be at the beginning of a line.
+const lineDirectivePrefix = "//line "
+
+// WithLineDirectives finds positions in generated files, such as by goyacc
+// or templates, of frames of original files. The compiler applies //line
+// directives of generated files, so frames point to original files,
+// e.g. parser.y, which may be missing or hide the code actually run.
+//
+// Generated files are Go files in the directory of the original file,
+// which are listed and read by the source provider, if it can list
+// directories, as the default one and NewFSSourceProvider do.
+// Frames of Go files are printed as they are captured.
+//
+// Relative file names of directives are relative to the directory
+// of the generated file.
+func WithLineDirectives(mode LineDirectives) PrintOption {
+	return func(o *printOptions) {
+		o.lineDirectives = mode
+	}
+}
+
+// generatedFrame returns frame at position in a generated file,
+// which a //line directive maps to the position of frame,
+// and reports false if there is none.
+func (o *printOptions) generatedFrame(frame Frame) (Frame, bool) {
+	if frame.IsMarker() || frame.Path == "" || strings.HasSuffix(frame.Path, ".go") {
+		return frame, false
+	}
+	dir := filepath.Dir(frame.Path)
+	names, err := readDir(o.rewritePath(dir))
+	if err != nil {
+		return frame, false
+	}
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		generated := filepath.Join(dir, name)
+		lines, err := readLines(o.rewritePath(generated))
+		if err != nil {
+			continue
+		}
+		if line, ok := generatedLine(lines, dir, frame); ok {
+			frame.Path = generated
+			frame.Line = line
+			return frame, true
+		}
+	}
+	return frame, false
+}
+
+// generatedLine returns the first line of a generated file in dir,
+// which its //line directives map to the position of frame.
+func generatedLine(lines []string, dir string, frame Frame) (int, bool) {
+	file, offset := "", 0
+	for i, text := range lines {
+		if name, line, ok := parseLineDirective(text); ok {
+			// Directive sets position of the line following it,
+			// an empty file name keeps the file of the previous one.
+			if name != "" {
+				if !filepath.IsAbs(name) {
+					name = filepath.Join(dir, name)
+				}
+				file = filepath.Clean(name)
+			}
+			offset = line - i - 1
+			continue
+		}
+		if file != "" && file == filepath.Clean(frame.Path) && i+offset == frame.Line {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// parseLineDirective parses "//line file:line" or "//line file:line:col".
+func parseLineDirective(text string) (string, int, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimRight(text, "\r"), lineDirectivePrefix)
+	if !ok {
+		return "", 0, false
+	}
+	i := strings.LastIndex(rest, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(rest[i+1:])
+	if err != nil || n < 1 {
+		return "", 0, false
+	}
+	file, line := rest[:i], n
+	// The last number is a column, if it's preceded by a line.
+	if j := strings.LastIndex(file, ":"); j >= 0 {
+		if n, err := strconv.Atoi(file[j+1:]); err == nil && n > 0 {
+			file, line = file[:j], n
+		}
+	}
+	return file, line, true
+}
+
+// lineDirectiveFrames finds generated frames by WithLineDirectives,
+// frames are changed in place.
+func (o *printOptions) lineDirectiveFrames(frames []outputFrame) {
+	if o.lineDirectives == LineDirectivesIgnored {
+		return
+	}
+	for i, frame := range frames {
+		if frame.Omitted > 0 {
+			continue
+		}
+		generated, ok := o.generatedFrame(frame.Frame)
+		if !ok {
+			continue
+		}
+		if o.lineDirectives == LineDirectivesBoth {
+			frames[i].Generated = &generated
+			continue
+		}
+		frames[i].Frame = generated
+	}
+}
@@ -0,0 +1,96 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithLineDirectives(t *testing.T) {
+	fsys := fstest.MapFS{
+		"parser.go": &fstest.MapFile{
+			Data: []byte("package main\n//line parser.y:10\nfunc parse() {\n\treturn fail()\n}\n//line :20:5\nfunc lex() {}\n"),
+		},
+		"parser.y": &fstest.MapFile{
+			Data: []byte(strings.Repeat("\n", 9) + "parse:\n\tfail()\n"),
+		},
+		"main.go": &fstest.MapFile{
+			Data: []byte("package main\n"),
+		},
+	}
+	tracerr.SetSourceProvider(tracerr.NewFSSourceProvider(fsys, "/src/app"))
+	defer tracerr.SetSourceProvider(nil)
+	defer tracerr.SetPrintOptions()
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.parse", "/src/app/parser.y", 11),
+		tracerr.NewFrame("main.lex", "/src/app/parser.y", 20),
+		tracerr.NewFrame("main.yyParse", "/src/app/parser.y", 30),
+		tracerr.NewFrame("main.main", "/src/app/main.go", 1),
+	})
+
+	cases := []struct {
+		mode     tracerr.LineDirectives
+		expected []string
+	}{
+		{
+			mode: tracerr.LineDirectivesIgnored,
+			expected: []string{
+				"/src/app/parser.y:11 main.parse()",
+				"/src/app/parser.y:20 main.lex()",
+				"/src/app/parser.y:30 main.yyParse()",
+				"/src/app/main.go:1 main.main()",
+			},
+		},
+		{
+			mode: tracerr.LineDirectivesGenerated,
+			expected: []string{
+				"/src/app/parser.go:4 main.parse()",
+				"/src/app/parser.go:7 main.lex()",
+				"/src/app/parser.y:30 main.yyParse()",
+				"/src/app/main.go:1 main.main()",
+			},
+		},
+		{
+			mode: tracerr.LineDirectivesBoth,
+			expected: []string{
+				"/src/app/parser.y:11 main.parse()",
+				"generated at /src/app/parser.go:4",
+				"/src/app/parser.y:20 main.lex()",
+				"generated at /src/app/parser.go:7",
+				"/src/app/parser.y:30 main.yyParse()",
+				"/src/app/main.go:1 main.main()",
+			},
+		},
+	}
+	for i, c := range cases {
+		tracerr.SetPrintOptions(tracerr.WithLineDirectives(c.mode))
+		output := tracerr.Sprint(err)
+		expected := "some error\n" + strings.Join(c.expected, "\n")
+		if output != expected {
+			t.Errorf("case #%d: tracerr.Sprint(err) = %#v; want %#v", i, output, expected)
+		}
+	}
+
+	tracerr.SetPrintOptions(tracerr.WithLineDirectives(tracerr.LineDirectivesGenerated))
+	output := tracerr.SprintSource(err, 0, 0)
+	if !strings.Contains(output, "/src/app/parser.go:4 main.parse()\n4\t\treturn fail()\n") {
+		t.Errorf("tracerr.SprintSource(err, 0, 0) = %#v; want source of the generated file", output)
+	}
+}
+
+func TestWithLineDirectivesNoDir(t *testing.T) {
+	tracerr.SetSourceProvider(tracerr.NewNopSourceProvider())
+	defer tracerr.SetSourceProvider(nil)
+	tracerr.SetPrintOptions(tracerr.WithLineDirectives(tracerr.LineDirectivesGenerated))
+	defer tracerr.SetPrintOptions()
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.parse", "/src/app/parser.y", 11),
+	})
+	expected := "some error\n/src/app/parser.y:11 main.parse()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+}
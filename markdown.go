@@ -0,0 +1,117 @@
+package tracerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SprintMarkdown returns error output formatted as Markdown,
+// which is suitable for issues and chats:
+// error message, list of frames and a fenced code block per source fragment.
+//
+// By default source lines follow the same rules as in PrintSource,
+// see WithSourceLines to change it.
+func SprintMarkdown(err error, options ...PrintOption) string {
+	if err == nil {
+		return ""
+	}
+	e, ok := err.(Error)
+	if !ok {
+		return fmt.Sprintf("**%s**\n", markdownEscape(err.Error()))
+	}
+	o := mergePrintOptions(options)
+	o.inlineSource = inlineSourceOf(e)
+	before, after, withSource := calcRows(o.lines)
+	frames := o.outputFrames(e)
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n", markdownEscape(e.Error()))
+	if len(frames) > 0 {
+		b.WriteString("\n")
+	}
+	for i, frame := range frames {
+		if separator, ok := separatorString(frame); ok {
+			fmt.Fprintf(&b, "%d. _%s_\n", i+1, separator)
+			continue
+		}
+		fmt.Fprintf(&b, "%d. %s%s\n", i+1, markdownFrame(frame.Frame, &o), repeatedSuffix(frame))
+	}
+	if !withSource {
+		return b.String()
+	}
+	for _, frame := range frames {
+		if separator, ok := separatorString(frame); ok {
+			fmt.Fprintf(&b, "\n_%s_\n", separator)
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", markdownFrame(frame.Frame, &o))
+		before, after := o.frameRows(frame.Top, frame.Frame, before, after)
+		fragment, err := o.sourceFragment(frame.Frame, before, after)
+		if err != nil {
+			fmt.Fprintf(&b, "_%s_\n", err.Error())
+			continue
+		}
+		if sourceChanged(frame.Frame, fragment) {
+			fmt.Fprintf(&b, "_%s_\n\n", staleSourceWarning)
+		}
+		rows := make([]string, 0, len(fragment))
+		for _, line := range fragment {
+			marker := " "
+			if line.Current {
+				marker = ">"
+			}
+			rows = append(rows, fmt.Sprintf("%s %d\t%s", marker, line.Number, line.Text))
+		}
+		code := strings.Join(rows, "\n")
+		fence := markdownFence(code)
+		fmt.Fprintf(&b, "%sgo\n%s\n%s\n", fence, code, fence)
+	}
+	return b.String()
+}
+
+func markdownFrame(frame Frame, o *printOptions) string {
+	if frame.CreatedBy {
+		return fmt.Sprintf("_%s_ %s %s", createdBy, markdownCode(o.location(frame)), markdownCode(frame.Func+"()"))
+	}
+	return fmt.Sprintf("%s %s", markdownCode(o.location(frame)), markdownCode(frame.Func+"()"))
+}
+
+// markdownCode returns code span of text delimited by backticks,
+// which are longer than any sequence of backticks in text.
+func markdownCode(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	// Code span strips one space at both ends, if text starts
+	// or ends with a backtick, it must be separated from the fence.
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
+
+// markdownEscaper escapes Markdown metacharacters,
+// so a message is rendered as is.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_",
+	"[", "\\[", "]", "\\]", "<", "\\<", ">", "\\>",
+	"#", "\\#", "|", "\\|", "~", "\\~",
+)
+
+// markdownEscape returns message with escaped Markdown metacharacters.
+// Line breaks are replaced by spaces to keep the message bold.
+func markdownEscape(message string) string {
+	message = strings.ReplaceAll(message, "\r\n", " ")
+	message = strings.ReplaceAll(message, "\n", " ")
+	return markdownEscaper.Replace(message)
+}
+
+// markdownFence returns code fence, which is longer than
+// any sequence of backticks in code.
+func markdownFence(code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence
+}
@@ -0,0 +1,98 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintMarkdown(t *testing.T) {
+	if output := tracerr.SprintMarkdown(nil); output != "" {
+		t.Errorf("tracerr.SprintMarkdown(nil) = %#v; want %#v", output, "")
+	}
+
+	output := tracerr.SprintMarkdown(errors.New("regular error"))
+	if output != "**regular error**\n" {
+		t.Errorf(
+			"tracerr.SprintMarkdown(err) = %#v; want %#v",
+			output, "**regular error**\n",
+		)
+	}
+
+	output = tracerr.SprintMarkdown(errors.New("*bad* `input` [x](y)\nnext"))
+	escaped := "**\\*bad\\* \\`input\\` \\[x\\](y) next**\n"
+	if output != escaped {
+		t.Errorf("tracerr.SprintMarkdown(err) = %#v; want %#v", output, escaped)
+	}
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 17,
+				Path: "error_helper_test.go",
+			},
+			{
+				Func: "main.Bar",
+				Line: 42,
+				Path: "/tmp/not_exists.go",
+			},
+		},
+	)
+	output = tracerr.SprintMarkdown(err, tracerr.WithSourceLines(1, 1))
+	expectedRows := []string{
+		"**some error**",
+		"",
+		"1. `error_helper_test.go:17` `main.Foo()`",
+		"2. `/tmp/not_exists.go:42` `main.Bar()`",
+		"",
+		"`error_helper_test.go:17` `main.Foo()`",
+		"",
+		"```go",
+		"  16\tfunc addFrameC(message string) error {",
+		"> 17\t\treturn tracerr.New(message)",
+		"  18\t}",
+		"```",
+		"",
+		"`/tmp/not_exists.go:42` `main.Bar()`",
+		"",
+		"_tracerr: file /tmp/not_exists.go not found_",
+		"",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf(
+			"tracerr.SprintMarkdown(err) = %#v; want %#v",
+			output, expected,
+		)
+	}
+
+	output = tracerr.SprintMarkdown(err, tracerr.WithSourceLines(0))
+	expected = strings.Join(expectedRows[:5], "\n")
+	if output != expected {
+		t.Errorf(
+			"tracerr.SprintMarkdown(err) = %#v; want %#v",
+			output, expected,
+		)
+	}
+}
+
+func TestSprintMarkdownBackticks(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.Foo", "/src/a`b.go", 17),
+		tracerr.NewFrame("main.Bar", "/src/``.go", 42),
+	})
+	output := tracerr.SprintMarkdown(err, tracerr.WithSourceLines(0))
+	expected := []string{
+		"``/src/a`b.go:17`` `main.Foo()`",
+		"```/src/``.go:42``` `main.Bar()`",
+	}
+	for i, row := range expected {
+		if !strings.Contains(output, row) {
+			t.Errorf("case #%d: tracerr.SprintMarkdown(err) = %#v; want to contain %#v", i, output, row)
+		}
+	}
+}
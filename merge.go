@@ -0,0 +1,91 @@
+package tracerr
+
+import (
+	"slices"
+)
+
+// MarkerFunc is a function name of a marker frame, which separates
+// segments of stack trace combined by Merge. Marker frame has no path and line.
+const MarkerFunc = "<merged>"
+
+// IsMarker reports whether f is a marker frame added by Merge,
+// or a marker frame separating goroutines, see WithGoroutineIDs.
+func (f Frame) IsMarker() bool {
+	return (f.Func == MarkerFunc || isReceivedMarker(f.Func)) && f.Path == "" && f.Line == 0
+}
+
+// Merge returns err with extra frames appended to its stack trace
+// after a marker frame, e.g. frames of a client propagated with a request,
+// which are callers of the local code. See MarkerFunc.
+//
+// It returns err as is if extra is empty, or nil if err is nil,
+// the same as Wrap.
+func Merge(err Error, extra []Frame) Error {
+	if isNilError(err) {
+		return nil
+	}
+	if len(extra) == 0 {
+		return err
+	}
+	return withFrames(err, mergeFrames(RawFrames(err), extra))
+}
+
+// mergeFrames returns a new stack trace of local and extra frames
+// separated by a marker frame.
+func mergeFrames(local, extra []Frame) []Frame {
+	frames := make([]Frame, 0, len(local)+1+len(extra))
+	frames = append(frames, local...)
+	frames = append(frames, Frame{Func: MarkerFunc})
+	return append(frames, slices.Clone(extra)...)
+}
+
+// WithFrames returns a new error with a copy of frames as stack trace,
+// which keeps the wrapped error, fields and other attributes,
+// err itself is not modified. It returns nil for nil error.
+func WithFrames(err Error, frames []Frame) Error {
+	if isNilError(err) {
+		return nil
+	}
+	if frames == nil {
+		frames = []Frame{}
+	}
+	return withFrames(err, slices.Clone(frames))
+}
+
+// MapFrames returns a new error with stack trace of err rewritten by fn,
+// frames, for which it returns false, are dropped. It keeps the wrapped
+// error, fields and other attributes, err itself is not modified.
+// It returns nil for nil error.
+func MapFrames(err Error, fn func(frame Frame) (Frame, bool)) Error {
+	if isNilError(err) {
+		return nil
+	}
+	frames := RedactFrames(RawFrames(err), fn)
+	if frames == nil {
+		frames = []Frame{}
+	}
+	return withFrames(err, frames)
+}
+
+// withFrames returns e with another stack trace. Own wrapper of tracerr
+// is replaced keeping its attributes, other implementations of Error
+// are wrapped, so errors.As still finds them.
+func withFrames(e Error, frames []Frame) Error {
+	d, ok := e.(*errorData)
+	if !ok {
+		return CustomError(e, frames)
+	}
+	return d.with(d.err, frames)
+}
+
+// separatorString returns a text of output frame, which is not a frame of code,
+// such as omitted frames or a marker frame, and reports whether it's one.
+func separatorString(frame outputFrame) (string, bool) {
+	switch {
+	case frame.Omitted > 0:
+		return omittedString(frame), true
+	case frame.IsMarker():
+		return frame.Func, true
+	}
+	return "", false
+}
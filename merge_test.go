@@ -0,0 +1,65 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestMerge(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), diffFrames("handle", "serve"))
+	remote := []tracerr.Frame{
+		tracerr.NewFrame("client.call", "/client/main.go", 5),
+		tracerr.NewFrame("client.main", "/client/main.go", 1),
+	}
+	merged := tracerr.Merge(err, remote)
+	frames := merged.StackTrace()
+	if len(frames) != 5 || !frames[2].IsMarker() || frames[2].Func != tracerr.MarkerFunc {
+		t.Fatalf("merged.StackTrace() = %#v; want local frames, marker and remote frames", frames)
+	}
+	if !errors.Is(merged, err.Unwrap()) {
+		t.Errorf("errors.Is(merged, original) = false; want true")
+	}
+
+	cases := []struct {
+		Output   string
+		Expected string
+	}{
+		{
+			Output: tracerr.Sprint(merged),
+			Expected: "some error\n" +
+				"/src/main.go:10 main.handle()\n" +
+				"/src/main.go:20 main.serve()\n" +
+				"<merged>\n" +
+				"/client/main.go:5 client.call()\n" +
+				"/client/main.go:1 client.main()",
+		},
+		{
+			Output: tracerr.SprintCompact(merged),
+			Expected: `err="some error" at=main.handle file=/src/main.go:10 | ` +
+				"main.serve /src/main.go:20 | <merged> | " +
+				"client.call /client/main.go:5 | client.main /client/main.go:1",
+		},
+		{
+			Output:   frames[2].String(),
+			Expected: "<merged>",
+		},
+	}
+
+	for i, c := range cases {
+		if c.Output != c.Expected {
+			t.Errorf("case #%d: output = %#v; want %#v", i, c.Output, c.Expected)
+		}
+	}
+}
+
+func TestMergeNoFrames(t *testing.T) {
+	err := tracerr.New("some error")
+	if merged := tracerr.Merge(err, nil); merged != err {
+		t.Errorf("tracerr.Merge(err, nil) = %#v; want err", merged)
+	}
+	if merged := tracerr.Merge(nil, diffFrames("main")); merged != nil {
+		t.Errorf("tracerr.Merge(nil, frames) = %#v; want nil", merged)
+	}
+}
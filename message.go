@@ -0,0 +1,117 @@
+package tracerr
+
+import (
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// MessageOption configures messages returned by Error() of traced errors,
+// see SetMessageOptions.
+type MessageOption func(o *messageOptions)
+
+type messageOptions struct {
+	// join separates messages prepended by Wrapf from wrapped messages.
+	join string
+	// maxLength is a maximum number of runes, zero means no limit.
+	maxLength int
+	// code is true if message starts with code of an error.
+	code bool
+}
+
+// globalMessageOptions are set by SetMessageOptions, nil means defaults.
+var globalMessageOptions atomic.Pointer[messageOptions]
+
+// SetMessageOptions configures how Error() of traced errors composes
+// messages layered by Wrapf, so they match conventions of logs without
+// custom error types. It replaces options set before, call it with no
+// options to restore defaults: ": " separator, no code, no limit.
+func SetMessageOptions(options ...MessageOption) {
+	if len(options) == 0 {
+		globalMessageOptions.Store(nil)
+		return
+	}
+	o := messageOptions{join: ": "}
+	for _, option := range options {
+		option(&o)
+	}
+	globalMessageOptions.Store(&o)
+}
+
+// WithMessageJoin sets a separator of messages prepended by Wrapf
+// and wrapped messages, such as "\n", default is ": ".
+func WithMessageJoin(separator string) MessageOption {
+	return func(o *messageOptions) {
+		o.join = separator
+	}
+}
+
+// WithMessageMaxLength limits a number of runes of a message,
+// longer messages are cut and end with "...". Zero means no limit.
+func WithMessageMaxLength(n int) MessageOption {
+	return func(o *messageOptions) {
+		o.maxLength = n
+	}
+}
+
+// WithMessageCode prepends code of an error returned by Code
+// in brackets to a message, such as "[E42] not found".
+func WithMessageCode(enabled bool) MessageOption {
+	return func(o *messageOptions) {
+		o.code = enabled
+	}
+}
+
+// message returns message of e composed by options set by SetMessageOptions.
+func (e *errorData) message() string {
+	o := globalMessageOptions.Load()
+	if o == nil {
+		return e.err.Error()
+	}
+	message := rawMessage(e.err)
+	if o.code {
+		if code := Code(e.err); code != "" {
+			message = "[" + code + "] " + message
+		}
+	}
+	return limitMessage(message, o.maxLength)
+}
+
+// rawMessage returns message of err without code prefix and limit,
+// so they are applied only once to the outermost traced error.
+func rawMessage(err error) string {
+	for {
+		e, ok := err.(*errorData)
+		if !ok {
+			break
+		}
+		if e == nil || isNilError(e.err) {
+			return ""
+		}
+		err = e.err
+	}
+	if c, ok := err.(*contextError); ok {
+		return c.message + messageJoin() + rawMessage(c.err)
+	}
+	return err.Error()
+}
+
+// messageJoin returns separator of messages prepended by Wrapf.
+func messageJoin() string {
+	if o := globalMessageOptions.Load(); o != nil {
+		return o.join
+	}
+	return ": "
+}
+
+// limitMessage cuts message to maxLength runes ending with "...".
+func limitMessage(message string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(message) <= maxLength {
+		return message
+	}
+	const ellipsis = "..."
+	if maxLength <= len(ellipsis) {
+		return ellipsis[:maxLength]
+	}
+	runes := []rune(message)
+	return string(runes[:maxLength-len(ellipsis)]) + ellipsis
+}
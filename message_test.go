@@ -0,0 +1,61 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSetMessageOptions(t *testing.T) {
+	defer tracerr.SetMessageOptions()
+
+	inner := tracerr.Wrapf(&codeError{code: "E42"}, "loading %s", "users")
+	err := tracerr.Wrapf(inner, "starting server")
+
+	cases := []struct {
+		options  []tracerr.MessageOption
+		expected string
+	}{
+		{
+			expected: "starting server: loading users: code E42",
+		},
+		{
+			options:  []tracerr.MessageOption{tracerr.WithMessageJoin("\n")},
+			expected: "starting server\nloading users\ncode E42",
+		},
+		{
+			options:  []tracerr.MessageOption{tracerr.WithMessageCode(true)},
+			expected: "[E42] starting server: loading users: code E42",
+		},
+		{
+			options:  []tracerr.MessageOption{tracerr.WithMessageMaxLength(20)},
+			expected: "starting server: ...",
+		},
+		{
+			options:  []tracerr.MessageOption{tracerr.WithMessageMaxLength(2)},
+			expected: "..",
+		},
+		{
+			options: []tracerr.MessageOption{
+				tracerr.WithMessageJoin(" | "),
+				tracerr.WithMessageCode(true),
+				tracerr.WithMessageMaxLength(30),
+			},
+			expected: "[E42] starting server | loa...",
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.SetMessageOptions(c.options...)
+		if message := err.Error(); message != c.expected {
+			t.Errorf("case #%d: err.Error() = %#v; want %#v", i, message, c.expected)
+		}
+	}
+
+	tracerr.SetMessageOptions(tracerr.WithMessageCode(true))
+	plain := tracerr.Wrap(errors.New("some error"))
+	if message := plain.Error(); message != "some error" {
+		t.Errorf("plain.Error() = %#v; want %#v", message, "some error")
+	}
+}
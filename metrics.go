@@ -0,0 +1,32 @@
+package tracerr
+
+// CaptureMetrics receives events of captured errors, see WithMetrics.
+// It can be implemented by an application on top of its metrics library,
+// e.g. by a Prometheus CounterVec.
+type CaptureMetrics interface {
+	// ErrorCaptured is called for every captured error with a function
+	// of its top frame, which is where the error comes from,
+	// and its code returned by Code.
+	ErrorCaptured(function, code string)
+}
+
+// CaptureMetricsFunc is an adapter to use a function as CaptureMetrics.
+type CaptureMetricsFunc func(function, code string)
+
+// ErrorCaptured calls f(function, code).
+func (f CaptureMetricsFunc) ErrorCaptured(function, code string) {
+	f(function, code)
+}
+
+// WithMetrics makes Tracerr report every captured error to metrics,
+// so hot error sites are visible without parsing logs.
+// Errors skipped by sampling are not reported, see WithOnCapture.
+func WithMetrics(metrics CaptureMetrics) Option {
+	return WithOnCapture(func(err Error) {
+		function := ""
+		if frames := RawFrames(err); len(frames) > 0 {
+			function = frames[0].Func
+		}
+		metrics.ErrorCaptured(function, Code(err))
+	})
+}
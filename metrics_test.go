@@ -0,0 +1,57 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithMetrics(t *testing.T) {
+	counts := make(map[string]int)
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithMetrics(tracerr.CaptureMetricsFunc(func(function, code string) {
+			counts[function+" "+code]++
+		})),
+	)
+	for i := 0; i < 2; i++ {
+		tr.New("some error")
+	}
+	tr.Wrap(fmt.Errorf("wrapped: %w", &codeError{code: "E42"}))
+	expected := map[string]int{
+		"github.com/kadaan/tracerr_test.TestWithMetrics ":    2,
+		"github.com/kadaan/tracerr_test.TestWithMetrics E42": 1,
+	}
+	if fmt.Sprint(counts) != fmt.Sprint(expected) {
+		t.Errorf("counts = %v; want %v", counts, expected)
+	}
+}
+
+func TestCode(t *testing.T) {
+	cases := []struct {
+		Error    error
+		Expected string
+	}{
+		{
+			Error:    nil,
+			Expected: "",
+		},
+		{
+			Error:    tracerr.New("some error"),
+			Expected: "",
+		},
+		{
+			Error:    tracerr.Wrap(fmt.Errorf("wrapped: %w", &codeError{code: "E42"})),
+			Expected: "E42",
+		},
+	}
+
+	for i, c := range cases {
+		code := tracerr.Code(c.Error)
+		if code != c.Expected {
+			t.Errorf("case #%d: tracerr.Code(err) = %#v; want %#v", i, code, c.Expected)
+		}
+	}
+}
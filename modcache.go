@@ -0,0 +1,114 @@
+package tracerr
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"unicode"
+)
+
+// NewModuleCacheSourceProvider creates source provider, which reads files
+// by provider and, if they are missing, looks for files of dependencies
+// in the local Go module cache.
+//
+// Versions of dependencies are taken from the path if it has one,
+// such as in -trimpath builds, otherwise from build info of the binary.
+func NewModuleCacheSourceProvider(provider SourceProvider) SourceProvider {
+	return &moduleCacheSourceProvider{
+		provider: provider,
+	}
+}
+
+type moduleCacheSourceProvider struct {
+	provider SourceProvider
+}
+
+func (p *moduleCacheSourceProvider) ReadFile(path string) ([]byte, error) {
+	b, err := p.provider.ReadFile(path)
+	if err == nil {
+		return b, nil
+	}
+	rel, ok := moduleCachePath(filepath.ToSlash(path))
+	if !ok {
+		return nil, err
+	}
+	dir := moduleCacheDir()
+	if dir == "" {
+		return nil, err
+	}
+	b, cacheErr := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if cacheErr != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (p *moduleCacheSourceProvider) ReadDir(path string) ([]string, error) {
+	if r, ok := p.provider.(dirReader); ok {
+		return r.ReadDir(path)
+	}
+	return nil, errNoDir
+}
+
+// moduleCachePath returns path of a file relative to the module cache.
+func moduleCachePath(path string) (string, bool) {
+	// Path recorded by a regular build is already inside of a module cache.
+	if i := strings.LastIndex(path, "/pkg/mod/"); i >= 0 {
+		return path[i+len("/pkg/mod/"):], true
+	}
+	// Path recorded by -trimpath build, e.g. "github.com/foo/bar@v1.0.0/bar.go".
+	if at := strings.Index(path, "@"); at > 0 && !strings.HasPrefix(path, "/") {
+		end := strings.Index(path[at:], "/")
+		if end < 0 {
+			return "", false
+		}
+		end += at
+		return escapeModulePath(path[:end]) + path[end:], true
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", false
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		i := strings.Index(path, dep.Path+"/")
+		if i < 0 || (i > 0 && path[i-1] != '/') {
+			continue
+		}
+		rest := path[i+len(dep.Path):]
+		return escapeModulePath(dep.Path+"@"+dep.Version) + rest, true
+	}
+	return "", false
+}
+
+// moduleCacheDir returns the local module cache directory.
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "go", "pkg", "mod")
+}
+
+// escapeModulePath escapes upper case letters the same way
+// as module cache does, e.g. "github.com/Foo" becomes "github.com/!foo".
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package tracerr_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type ModuleCacheTestCase struct {
+	Path     string
+	Expected string
+}
+
+func TestModuleCacheSourceProvider(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOMODCACHE", dir)
+	files := map[string]string{
+		"github.com/!foo/bar@v1.2.3/bar.go":                                          "package bar // trimpath",
+		"github.com/logrusorgru/aurora@v0.0.0-20181002194514-a7b3b318ed4e/aurora.go": "package aurora",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider := tracerr.NewModuleCacheSourceProvider(tracerr.NewNopSourceProvider())
+	cases := []ModuleCacheTestCase{
+		{
+			Path:     "/home/ci/go/pkg/mod/github.com/!foo/bar@v1.2.3/bar.go",
+			Expected: "package bar // trimpath",
+		},
+		{
+			Path:     "github.com/Foo/bar@v1.2.3/bar.go",
+			Expected: "package bar // trimpath",
+		},
+		{
+			Path:     "/builder/src/github.com/logrusorgru/aurora/aurora.go",
+			Expected: "package aurora",
+		},
+	}
+	for i, c := range cases {
+		b, err := provider.ReadFile(c.Path)
+		if err != nil || string(b) != c.Expected {
+			t.Errorf(
+				"case #%d: provider.ReadFile(%#v) = %#v, %#v; want %#v, %#v",
+				i, c.Path, string(b), err, c.Expected, nil,
+			)
+		}
+	}
+
+	if _, err := provider.ReadFile("/src/app/main.go"); err == nil {
+		t.Errorf("provider.ReadFile(%#v) error = nil; want error", "/src/app/main.go")
+	}
+}
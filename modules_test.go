@@ -0,0 +1,41 @@
+package tracerr_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ModuleTestCase is an integration package, which has its own module,
+// so its dependency isn't required by users of the root module.
+type ModuleTestCase struct {
+	Dir        string
+	Dependency string
+}
+
+func TestIntegrationModules(t *testing.T) {
+	root, err := os.ReadFile("go.mod")
+	if err != nil {
+		t.Fatalf("os.ReadFile(go.mod) error = %#v; want nil", err)
+	}
+	cases := []ModuleTestCase{
+		{Dir: "tracerrcheck", Dependency: "golang.org/x/tools"},
+		{Dir: "tracerrgin", Dependency: "github.com/gin-gonic/gin"},
+		{Dir: "tracerrecho", Dependency: "github.com/labstack/echo/v4"},
+		{Dir: "tracerrfiber", Dependency: "github.com/gofiber/fiber/v2"},
+	}
+	for i, c := range cases {
+		if strings.Contains(string(root), c.Dependency+" ") {
+			t.Errorf("case #%d: go.mod requires %#v; want it in %s/go.mod only", i, c.Dependency, c.Dir)
+		}
+		b, err := os.ReadFile(filepath.Join(c.Dir, "go.mod"))
+		if err != nil {
+			t.Errorf("case #%d: os.ReadFile(%s/go.mod) error = %#v; want nil", i, c.Dir, err)
+			continue
+		}
+		if !strings.Contains(string(b), c.Dependency+" ") {
+			t.Errorf("case #%d: %s/go.mod doesn't require %#v", i, c.Dir, c.Dependency)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package tracerr
+
+// Must returns v if err is nil, otherwise it panics with err
+// wrapped by stack trace of the caller.
+//
+// It is intended for use in init paths and tests.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(Wrap(err))
+	}
+	return v
+}
+
+// Check panics with err wrapped by stack trace of the caller
+// if err is not nil.
+func Check(err error) {
+	if err != nil {
+		panic(Wrap(err))
+	}
+}
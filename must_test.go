@@ -0,0 +1,60 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestMust(t *testing.T) {
+	value := tracerr.Must(42, nil)
+	if value != 42 {
+		t.Errorf("tracerr.Must(42, nil) = %#v; want %#v", value, 42)
+	}
+
+	err := recoverError(func() {
+		tracerr.Must(42, errors.New("must error"))
+	})
+	assertCallerFrame(t, "tracerr.Must", err, "must error", "TestMust.func1")
+}
+
+func TestCheck(t *testing.T) {
+	err := recoverError(func() {
+		tracerr.Check(nil)
+	})
+	if err != nil {
+		t.Errorf("tracerr.Check(nil) panics with %#v; want no panic", err)
+	}
+
+	err = recoverError(func() {
+		tracerr.Check(errors.New("check error"))
+	})
+	assertCallerFrame(t, "tracerr.Check", err, "check error", "TestCheck.func2")
+}
+
+func recoverError(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func assertCallerFrame(t *testing.T, name string, err error, message, caller string) {
+	if err == nil {
+		t.Fatalf("%s: err = nil; want error", name)
+	}
+	if err.Error() != message {
+		t.Errorf("%s: err.Error() = %#v; want %#v", name, err.Error(), message)
+	}
+	frames := tracerr.StackTrace(err)
+	if len(frames) == 0 {
+		t.Fatalf("%s: len(tracerr.StackTrace(err)) = 0; want > 0", name)
+	}
+	if frames[0].Name != caller {
+		t.Errorf("%s: frames[0].Name = %#v; want %#v", name, frames[0].Name, caller)
+	}
+}
@@ -0,0 +1,47 @@
+package tracerr
+
+import "reflect"
+
+// IsNil reports whether err is nil, including an interface holding
+// a typed nil, e.g. nil *MyError returned as error:
+//
+//	func find() error {
+//		var err *MyError
+//		return err // Not equal to nil.
+//	}
+//
+// Wrap and the like treat such errors as nil only if their Error method
+// panics, typed nils with nil-safe Error method are valid errors.
+func IsNil(err error) bool {
+	if err == nil {
+		return true
+	}
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isNilError reports whether err is nil, a nil Error of this package,
+// or a typed nil pointer, which Error method panics,
+// so Wrap and the like treat it as nil.
+func isNilError(err error) (isNil bool) {
+	if err == nil {
+		return true
+	}
+	if d, ok := err.(*errorData); ok {
+		return d == nil
+	}
+	if v := reflect.ValueOf(err); v.Kind() != reflect.Pointer || !v.IsNil() {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			isNil = true
+		}
+	}()
+	_ = err.Error()
+	return false
+}
@@ -0,0 +1,185 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type nilError struct{}
+
+func (e *nilError) Error() string {
+	return "nil error"
+}
+
+// panickingError panics on Error of typed nil.
+type panickingError struct {
+	message string
+}
+
+func (e *panickingError) Error() string {
+	return e.message
+}
+
+func TestIsNil(t *testing.T) {
+	var typed *nilError
+	var traced tracerr.Error
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, true},
+		{typed, true},
+		{traced, true},
+		{io.EOF, false},
+		{&nilError{}, false},
+		{tracerr.New("some error"), false},
+	}
+	for i, c := range cases {
+		if isNil := tracerr.IsNil(c.err); isNil != c.expected {
+			t.Errorf("case #%d: tracerr.IsNil(%#v) = %#v; want %#v", i, c.err, isNil, c.expected)
+		}
+	}
+}
+
+func TestWrapTypedNil(t *testing.T) {
+	var typed *panickingError
+	if err := tracerr.Wrap(typed); err != nil {
+		t.Errorf("tracerr.Wrap(typed nil) = %#v; want nil", err)
+	}
+	if err := tracerr.WrapAlways(typed); err != nil {
+		t.Errorf("tracerr.WrapAlways(typed nil) = %#v; want nil", err)
+	}
+	if err := tracerr.Wrapf(typed, "failed"); err != nil {
+		t.Errorf("tracerr.Wrapf(typed nil) = %#v; want nil", err)
+	}
+	var safe *nilError
+	if err := tracerr.Wrap(safe); err == nil || err.Error() != "nil error" {
+		t.Errorf("tracerr.Wrap(nil-safe typed nil) = %#v; want error %#v", err, "nil error")
+	}
+	errorType := reflect.TypeOf(tracerr.New("some error"))
+	nilTraced := reflect.Zero(errorType).Interface().(tracerr.Error)
+	if err := tracerr.Wrap(nilTraced); err != nil {
+		t.Errorf("tracerr.Wrap(nil tracerr.Error) = %#v; want nil", err)
+	}
+}
+
+func TestNilInnerError(t *testing.T) {
+	errorType := reflect.TypeOf(tracerr.New("some error"))
+	errs := []tracerr.Error{
+		reflect.Zero(errorType).Interface().(tracerr.Error),
+		reflect.New(errorType.Elem()).Interface().(tracerr.Error),
+		tracerr.Errorf("failed: %w", nil),
+	}
+	for i, err := range errs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("case #%d: panic = %v; want none", i, r)
+				}
+			}()
+			_ = err.Error()
+			_ = err.StackTrace()
+			for range tracerr.Frames(err) {
+			}
+			_ = err.Unwrap()
+			_ = fmt.Sprintf("%v %+v", err, err)
+			_ = tracerr.Sprint(err)
+			_ = tracerr.SprintSource(err)
+			_ = tracerr.Fields(err)
+			_ = tracerr.Code(err)
+			_ = tracerr.Fingerprint(err)
+			_ = tracerr.Tree(err)
+			_, _ = tracerr.Env(err)
+			_ = tracerr.Clone(err)
+			_ = tracerr.Merge(err, []tracerr.Frame{tracerr.NewFrame("main.main", "/src/main.go", 10)})
+			_ = errors.Is(err, io.EOF)
+			if traced, ok := err.(tracerr.TracedError); ok {
+				_ = traced.Code()
+				_ = traced.Chain()
+			}
+		}()
+	}
+}
+
+func TestNilReceiver(t *testing.T) {
+	errorType := reflect.TypeOf(tracerr.New("some error"))
+	err := reflect.Zero(errorType).Interface().(tracerr.Error)
+	var asError error = err
+	if asError == nil {
+		t.Fatalf("typed nil error is nil interface")
+	}
+	cases := []struct {
+		Name string
+		Call func() interface{}
+	}{
+		{"Error", func() interface{} { return err.Error() }},
+		{"StackTrace", func() interface{} { return err.StackTrace() }},
+		{"RawFrames", func() interface{} { return tracerr.RawFrames(err) }},
+		{"PCs", func() interface{} { return tracerr.PCs(err) }},
+		{"CallersFrames", func() interface{} { return tracerr.CallersFrames(err) == nil }},
+		{"Unwrap", func() interface{} { return err.Unwrap() }},
+		{"WithFrames", func() interface{} { return tracerr.WithFrames(err, nil) == nil }},
+		{"MapFrames", func() interface{} { return tracerr.MapFrames(err, nil) == nil }},
+		{"RuntimeStackString", func() interface{} { return tracerr.RuntimeStackString(err) }},
+		{"Fields", func() interface{} { return len(err.(tracerr.TracedError).Fields()) }},
+		{"Fingerprint", func() interface{} { return err.(tracerr.TracedError).Fingerprint() != "" }},
+		{"Chain", func() interface{} { return len(err.(tracerr.TracedError).Chain()) }},
+		{"Format", func() interface{} { return fmt.Sprintf("%v|%+v|%s|%q", err, err, err, err) }},
+		{"MarshalBinary", func() interface{} {
+			_, marshalErr := err.(interface{ MarshalBinary() ([]byte, error) }).MarshalBinary()
+			return marshalErr
+		}},
+		{"GobEncode", func() interface{} {
+			_, encodeErr := err.(interface{ GobEncode() ([]byte, error) }).GobEncode()
+			return encodeErr
+		}},
+		{"UnmarshalBinary", func() interface{} {
+			return err.(interface{ UnmarshalBinary([]byte) error }).UnmarshalBinary(nil) != nil
+		}},
+		{"GobDecode", func() interface{} {
+			return err.(interface{ GobDecode([]byte) error }).GobDecode(nil) != nil
+		}},
+		{"SprintCompact", func() interface{} { return tracerr.SprintCompact(err) }},
+		{"SprintYAML", func() interface{} { return tracerr.SprintYAML(err) }},
+		{"SprintMarkdown", func() interface{} { return tracerr.SprintMarkdown(err) }},
+		{"SprintHTML", func() interface{} { return tracerr.SprintHTML(err) }},
+		{"DiffFrames", func() interface{} { return tracerr.DiffFrames(tracerr.RawFrames(err), nil) }},
+	}
+	expected := map[string]interface{}{
+		"Error":              "",
+		"StackTrace":         []tracerr.Frame(nil),
+		"RawFrames":          []tracerr.Frame(nil),
+		"PCs":                []uintptr(nil),
+		"CallersFrames":      true,
+		"Unwrap":             nil,
+		"WithFrames":         true,
+		"MapFrames":          true,
+		"RuntimeStackString": "",
+		"Fields":             0,
+		"Fingerprint":        true,
+		"Chain":              1,
+		"Format":             `|||""`,
+		"MarshalBinary":      nil,
+		"GobEncode":          nil,
+		"UnmarshalBinary":    true,
+		"GobDecode":          true,
+	}
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s on nil receiver: panic = %v; want none", c.Name, r)
+				}
+			}()
+			result := c.Call()
+			if want, ok := expected[c.Name]; ok && !reflect.DeepEqual(result, want) {
+				t.Errorf("%s on nil receiver = %#v; want %#v", c.Name, result, want)
+			}
+		}()
+	}
+}
@@ -0,0 +1,19 @@
+//go:build tracerr_notrace
+
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestNoTrace(t *testing.T) {
+	tracerr.Enable()
+	if tracerr.Enabled() {
+		t.Errorf("tracerr.Enabled() = true; want false")
+	}
+	if frames := tracerr.New("some error").StackTrace(); len(frames) != 0 {
+		t.Errorf("err.StackTrace() = %v; want empty", frames)
+	}
+}
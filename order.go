@@ -0,0 +1,36 @@
+package tracerr
+
+import "slices"
+
+// FrameOrder is an order of frames in output, see WithFrameOrder.
+type FrameOrder int
+
+const (
+	// FrameOrderNewest prints the innermost frame first,
+	// the same way as Go panics do. It's the default.
+	FrameOrderNewest FrameOrder = iota
+	// FrameOrderOldest prints the outermost frame first,
+	// the same way as Sentry and Python tracebacks do.
+	FrameOrderOldest
+)
+
+// WithFrameOrder sets order of frames in output of all printers
+// and in frames of Tree and SprintTemplate.
+// Frames are limited by WithMaxFrames and WithFrameWindow before
+// they are ordered, so the innermost frames are kept either way.
+func WithFrameOrder(order FrameOrder) PrintOption {
+	return func(o *printOptions) {
+		o.frameOrder = order
+	}
+}
+
+// orderFrames returns frames in order set by WithFrameOrder,
+// frames are the innermost first. They may be shared, so they're copied
+// if they're reordered.
+func orderFrames[T any](o *printOptions, frames []T) []T {
+	if o.frameOrder == FrameOrderOldest {
+		frames = slices.Clone(frames)
+		slices.Reverse(frames)
+	}
+	return frames
+}
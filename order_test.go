@@ -0,0 +1,50 @@
+package tracerr_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithFrameOrder(t *testing.T) {
+	frames := diffFrames("read", "load", "main")
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	oldest := tracerr.WithFrameOrder(tracerr.FrameOrderOldest)
+
+	tracerr.SetPrintOptions(oldest)
+	defer tracerr.SetPrintOptions()
+	output := tracerr.Sprint(err)
+	expected := "some error\n/src/main.go:30 main.main()\n/src/main.go:20 main.load()\n/src/main.go:10 main.read()"
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+	tracerr.SetPrintOptions()
+
+	compact := tracerr.SprintCompact(err, oldest)
+	if !strings.HasSuffix(compact, "at=main.read file=/src/main.go:10") {
+		t.Errorf("tracerr.SprintCompact(err) = %#v; want the innermost frame last", compact)
+	}
+	markdown := tracerr.SprintMarkdown(err, oldest, tracerr.WithSourceLines(0))
+	if strings.Index(markdown, "main.main") > strings.Index(markdown, "main.read") {
+		t.Errorf("tracerr.SprintMarkdown(err) = %#v; want the outermost frame first", markdown)
+	}
+	tree := tracerr.Tree(err, oldest, tracerr.WithMaxFrames(2))
+	expectedFrames := []string{"/src/main.go:20 main.load()", "/src/main.go:10 main.read()"}
+	if !reflect.DeepEqual(tree.Frames, expectedFrames) {
+		t.Errorf("tree.Frames = %#v; want %#v", tree.Frames, expectedFrames)
+	}
+	if !reflect.DeepEqual(tracerr.RawFrames(err), frames) {
+		t.Errorf("tracerr.RawFrames(err) = %#v; want unchanged %#v", tracerr.RawFrames(err), frames)
+	}
+}
+
+func TestWithFrameOrderNewest(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), diffFrames("read", "main"))
+	newest := tracerr.SprintCompact(err, tracerr.WithFrameOrder(tracerr.FrameOrderNewest))
+	if newest != tracerr.SprintCompact(err) {
+		t.Errorf("tracerr.SprintCompact(err) = %#v; want the default order", newest)
+	}
+}
@@ -0,0 +1,118 @@
+package tracerr
+
+import (
+	"path"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+)
+
+// FrameOrigin is a classification of code of a frame, see Frame.Origin.
+type FrameOrigin int
+
+const (
+	// FrameOriginUnknown is an origin of marker frames
+	// and frames of code without symbols, such as C code.
+	FrameOriginUnknown FrameOrigin = iota
+	// FrameOriginApp is an origin of packages of the main module.
+	FrameOriginApp
+	// FrameOriginDep is an origin of packages of dependencies.
+	FrameOriginDep
+	// FrameOriginStd is an origin of packages of the standard library.
+	FrameOriginStd
+	// FrameOriginGenerated is an origin of generated code, such as
+	// protobuf messages or wrapper methods generated by the compiler.
+	FrameOriginGenerated
+)
+
+// String returns a name of origin, such as "app".
+func (origin FrameOrigin) String() string {
+	switch origin {
+	case FrameOriginApp:
+		return "app"
+	case FrameOriginDep:
+		return "dep"
+	case FrameOriginStd:
+		return "std"
+	case FrameOriginGenerated:
+		return "generated"
+	}
+	return "unknown"
+}
+
+// generatedSuffixes are suffixes of names of generated files.
+var generatedSuffixes = []string{".pb.go", ".pb.gw.go", "_gen.go", ".gen.go", "_generated.go"}
+
+// Origin returns classification of code of the frame.
+//
+// Packages of the main module of the binary are application code,
+// packages with no dot in the first element of import path are standard
+// library, and the others are dependencies. If the binary has no module info,
+// files in module cache and vendor directories are dependencies.
+// Files named like generated ones, such as "*.pb.go" or "zz_generated*.go",
+// are generated code of any package.
+func (f Frame) Origin() FrameOrigin {
+	if f.IsMarker() || f.Func == cgoFuncName || f.Package == "" {
+		return FrameOriginUnknown
+	}
+	base := path.Base(strings.ReplaceAll(f.Path, "\\", "/"))
+	if base == "<autogenerated>" || strings.HasPrefix(base, "zz_generated") {
+		return FrameOriginGenerated
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return FrameOriginGenerated
+		}
+	}
+	pkg := strings.TrimSuffix(f.Package, "_test")
+	if module := mainModulePath(); pkg == "main" || module != "" && (pkg == module || strings.HasPrefix(pkg, module+"/")) {
+		return FrameOriginApp
+	}
+	if first, _, _ := strings.Cut(pkg, "/"); !strings.Contains(first, ".") {
+		return FrameOriginStd
+	}
+	if mainModulePath() != "" || strings.Contains(f.Path, "/pkg/mod/") || strings.Contains(f.Path, "/vendor/") {
+		return FrameOriginDep
+	}
+	return FrameOriginApp
+}
+
+// Origin returns the innermost frame of application code, see Frame.Origin,
+// of err or the first traced error it wraps, e.g. for a concise log line:
+//
+//	if frame, ok := tracerr.Origin(err); ok {
+//		log.Printf("err=%v origin=%s", err, frame.Format("%f:%l"))
+//	}
+//
+// It reports false if err has no stack trace or no frame of application.
+// Lazily captured frames are resolved only up to the returned one.
+func Origin(err error) (Frame, bool) {
+	e, ok := AsError(err)
+	if !ok {
+		return Frame{}, false
+	}
+	for frame := range Frames(e) {
+		if frame.Origin() == FrameOriginApp {
+			return frame, true
+		}
+	}
+	return Frame{}, false
+}
+
+// WithDimNonAppFrames prints frames of code other than application,
+// see Frame.Origin, in Dimmed color of the theme in colored output
+// and grayed out in SprintHTML, so frames of the application stand out
+// in long stack traces.
+func WithDimNonAppFrames() PrintOption {
+	return func(o *printOptions) {
+		o.dimNonApp = true
+	}
+}
+
+// pathColor returns color of frame line of frame.
+func (o *printOptions) pathColor(frame Frame, theme *Theme) aurora.Color {
+	if o.dimNonApp && frame.Origin() != FrameOriginApp {
+		return theme.Dimmed
+	}
+	return theme.Path
+}
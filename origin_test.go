@@ -0,0 +1,84 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestFrameOrigin(t *testing.T) {
+	cases := []struct {
+		frame    tracerr.Frame
+		expected tracerr.FrameOrigin
+	}{
+		{frame: tracerr.NewFrame("github.com/kadaan/tracerr.New", "/src/tracerr/error.go", 10), expected: tracerr.FrameOriginApp},
+		{frame: tracerr.NewFrame("github.com/kadaan/tracerr/report.Send", "/src/tracerr/report/report.go", 10), expected: tracerr.FrameOriginApp},
+		{frame: tracerr.NewFrame("github.com/kadaan/tracerr_test.TestFrameOrigin", "/src/tracerr/origin_test.go", 10), expected: tracerr.FrameOriginApp},
+		{frame: tracerr.NewFrame("main.main", "/src/main.go", 10), expected: tracerr.FrameOriginApp},
+		{frame: tracerr.NewFrame("github.com/kadaan/tracerrx.Do", "/go/pkg/mod/github.com/kadaan/tracerrx@v1.0.0/x.go", 10), expected: tracerr.FrameOriginDep},
+		{frame: tracerr.NewFrame("net/http.HandlerFunc.ServeHTTP", "/go/src/net/http/server.go", 10), expected: tracerr.FrameOriginStd},
+		{frame: tracerr.NewFrame("runtime.goexit", "/go/src/runtime/asm_amd64.s", 10), expected: tracerr.FrameOriginStd},
+		{frame: tracerr.NewFrame("github.com/foo/api.(*User).Reset", "/src/api/user.pb.go", 10), expected: tracerr.FrameOriginGenerated},
+		{frame: tracerr.NewFrame("github.com/kadaan/tracerr.(*Thing).Do", "<autogenerated>", 1), expected: tracerr.FrameOriginGenerated},
+		{frame: tracerr.NewFrame("<cgo>", "?", 0), expected: tracerr.FrameOriginUnknown},
+		{frame: tracerr.NewFrame("<merged>", "", 0), expected: tracerr.FrameOriginUnknown},
+	}
+	for i, c := range cases {
+		if origin := c.frame.Origin(); origin != c.expected {
+			t.Errorf("case #%d: %v.Origin() = %v; want %v", i, c.frame, origin, c.expected)
+		}
+	}
+	if s := tracerr.FrameOriginGenerated.String(); s != "generated" {
+		t.Errorf("tracerr.FrameOriginGenerated.String() = %#v; want %#v", s, "generated")
+	}
+}
+
+func TestWithDimNonAppFrames(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("github.com/kadaan/tracerr_test.read", "/src/main.go", 10),
+		tracerr.NewFrame("net/http.HandlerFunc.ServeHTTP", "/go/src/net/http/server.go", 20),
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	tracerr.SetPrintOptions(tracerr.WithDimNonAppFrames())
+	defer tracerr.SetPrintOptions()
+
+	output := tracerr.SprintSourceColor(err, 0)
+	dimmed := "\x1b[2m/go/src/net/http/server.go:20 net/http.HandlerFunc.ServeHTTP()\x1b[0m"
+	if !strings.Contains(output, "\x1b[1m/src/main.go:10 github.com/kadaan/tracerr_test.read()\x1b[0m") || !strings.Contains(output, dimmed) {
+		t.Errorf("tracerr.SprintSourceColor(err) = %#v; want dimmed dependency frame", output)
+	}
+	if html := tracerr.SprintHTML(err, tracerr.WithSourceLines(0)); strings.Count(html, `class="tracerr-frame tracerr-dimmed"`) != 1 {
+		t.Errorf("tracerr.SprintHTML(err) = %#v; want one dimmed frame", html)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("net/http.HandlerFunc.ServeHTTP", "/go/src/net/http/server.go", 20),
+		tracerr.NewFrame("github.com/kadaan/tracerr_test.read", "/src/main.go", 10),
+		tracerr.NewFrame("main.main", "/src/main.go", 30),
+	}
+	err := fmt.Errorf("context: %w", tracerr.CustomError(errors.New("some error"), frames))
+	if frame, ok := tracerr.Origin(err); !ok || frame != frames[1] {
+		t.Errorf("tracerr.Origin(err) = %#v, %v; want %#v, true", frame, ok, frames[1])
+	}
+
+	lazy := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithLazyFrames())
+	frame, ok := tracerr.Origin(lazy.New("some error"))
+	if !ok || frame.Func != "github.com/kadaan/tracerr_test.TestOrigin" {
+		t.Errorf("tracerr.Origin(lazy) = %#v, %v; want frame of the test", frame, ok)
+	}
+
+	for i, err := range []error{
+		nil,
+		errors.New("some error"),
+		tracerr.CustomError(errors.New("some error"), frames[:1]),
+	} {
+		if frame, ok := tracerr.Origin(err); ok {
+			t.Errorf("case #%d: tracerr.Origin(err) = %#v, true; want false", i, frame)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tracerr
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// Printer renders errors printed by Print, PrintSource and PrintSourceColor.
+//
+// It is set by SetPrinter and replaces the default rendering,
+// so number of source lines passed to print functions is ignored.
+type Printer interface {
+	Print(err Error)
+}
+
+var (
+	output      io.Writer
+	printer     Printer
+	outputMutex sync.RWMutex
+)
+
+// SetOutput sets destination of package level print functions.
+// Pass nil to restore the default, which is os.Stdout.
+func SetOutput(w io.Writer) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	output = w
+}
+
+// SetPrinter sets printer which package level print functions delegate to.
+// Pass nil to restore the default rendering.
+func SetPrinter(p Printer) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	printer = p
+}
+
+// printOutput delegates err to the printer if any,
+// otherwise it calls fn with the current output.
+func printOutput(err error, fn func(w io.Writer)) {
+	outputMutex.RLock()
+	w, p := output, printer
+	outputMutex.RUnlock()
+	if p != nil {
+		if err == nil {
+			return
+		}
+		e, ok := err.(Error)
+		if !ok {
+			e = CustomError(err, nil)
+		}
+		p.Print(e)
+		return
+	}
+	if w == nil {
+		w = os.Stdout
+	}
+	fn(w)
+}
+
+// currentOutput returns destination set by SetOutput or os.Stdout.
+func currentOutput() io.Writer {
+	outputMutex.RLock()
+	defer outputMutex.RUnlock()
+	if output == nil {
+		return os.Stdout
+	}
+	return output
+}
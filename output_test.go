@@ -0,0 +1,70 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type recordingPrinter struct {
+	errors []tracerr.Error
+}
+
+func (p *recordingPrinter) Print(err tracerr.Error) {
+	p.errors = append(p.errors, err)
+}
+
+func TestSetOutput(t *testing.T) {
+	forceColor(t)
+	var buf bytes.Buffer
+	tracerr.SetOutput(&buf)
+	defer tracerr.SetOutput(nil)
+
+	err := addFrameA("output error")
+	tracerr.Print(err)
+	tracerr.PrintSource(err, 1)
+	tracerr.PrintSourceColor(err, 1)
+	expected := tracerr.Sprint(err) + "\n" +
+		tracerr.SprintSource(err, 1) + "\n" +
+		tracerr.SprintSourceColor(err, 1) + "\n"
+	if buf.String() != expected {
+		t.Errorf("output = %#v; want %#v", buf.String(), expected)
+	}
+}
+
+func TestSetPrinter(t *testing.T) {
+	p := &recordingPrinter{}
+	tracerr.SetPrinter(p)
+	defer tracerr.SetPrinter(nil)
+
+	traced := addFrameA("printer error")
+	regular := errors.New("regular error")
+	output := captureOutput(func() {
+		tracerr.Print(traced)
+		tracerr.PrintSource(regular)
+		tracerr.PrintSourceColor(nil)
+	})
+	if output != "" {
+		t.Errorf("output = %#v; want %#v", output, "")
+	}
+	if len(p.errors) != 2 {
+		t.Fatalf("len(p.errors) = %#v; want %#v", len(p.errors), 2)
+	}
+	if p.errors[0] != traced {
+		t.Errorf("p.errors[0] = %#v; want %#v", p.errors[0], traced)
+	}
+	if p.errors[1].Unwrap() != regular {
+		t.Errorf(
+			"p.errors[1].Unwrap() = %#v; want %#v",
+			p.errors[1].Unwrap(), regular,
+		)
+	}
+	if len(p.errors[1].StackTrace()) != 0 {
+		t.Errorf(
+			"p.errors[1].StackTrace() = %#v; want empty",
+			p.errors[1].StackTrace(),
+		)
+	}
+}
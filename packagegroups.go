@@ -0,0 +1,33 @@
+package tracerr
+
+// packageGroupIndent indents frames under the header of their package.
+const packageGroupIndent = "    "
+
+// WithPackageGroups groups consecutive frames of the same package
+// under a header with package path, such as "github.com/foo/bar:",
+// followed by its frames indented, so long stack traces are scannable.
+// It applies to both plain and colored output.
+func WithPackageGroups() PrintOption {
+	return func(o *printOptions) {
+		o.packageGroups = true
+	}
+}
+
+// packageHeader returns header of the group of frame,
+// it reports false if frame is in the same group as the previous one.
+func packageHeader(frame Frame, previous string) (string, bool) {
+	pkg := frame.Package
+	if pkg == "" {
+		pkg = "?"
+	}
+	return pkg + ":", pkg+":" != previous
+}
+
+// indentRows indents non-empty rows of a frame group.
+func indentRows(rows []string) {
+	for i, row := range rows {
+		if row != "" {
+			rows[i] = packageGroupIndent + row
+		}
+	}
+}
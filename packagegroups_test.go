@@ -0,0 +1,39 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithPackageGroups(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("github.com/mycorp/service/user.(*Store).Get", "/src/user/store.go", 10),
+		tracerr.NewFrame("github.com/mycorp/service/user.Handle", "/src/user/handler.go", 20),
+		tracerr.NewFrame("net/http.HandlerFunc.ServeHTTP", "/go/src/net/http/server.go", 30),
+		tracerr.NewFrame("github.com/mycorp/service/user.main", "/src/user/main.go", 40),
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	tracerr.SetPrintOptions(tracerr.WithPackageGroups())
+	defer tracerr.SetPrintOptions()
+
+	expected := "some error\n" +
+		"github.com/mycorp/service/user:\n" +
+		"    /src/user/store.go:10 github.com/mycorp/service/user.(*Store).Get()\n" +
+		"    /src/user/handler.go:20 github.com/mycorp/service/user.Handle()\n" +
+		"net/http:\n" +
+		"    /go/src/net/http/server.go:30 net/http.HandlerFunc.ServeHTTP()\n" +
+		"github.com/mycorp/service/user:\n" +
+		"    /src/user/main.go:40 github.com/mycorp/service/user.main()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	expected = "some error\n" +
+		"github.com/mycorp/service/user:\n" +
+		"    \x1b[1m/src/user/store.go:10 github.com/mycorp/service/user.(*Store).Get()\x1b[0m\n"
+	if output := tracerr.SprintSourceColor(err, 0); len(output) < len(expected) || output[:len(expected)] != expected {
+		t.Errorf("tracerr.SprintSourceColor(err) = %#v; want to start with %#v", output, expected)
+	}
+}
@@ -0,0 +1,75 @@
+package tracerr
+
+import (
+	"errors"
+	"strings"
+)
+
+// ParsePanic parses panic output of Go runtime, such as output
+// of a crashed program, or a goroutine dump of runtime.Stack,
+// into an error, which can be printed with source fragments.
+//
+// Message is a panic value, or a header of the first goroutine
+// if there is no panic. Only stack trace of the first goroutine is parsed,
+// which is the panicking one.
+func ParsePanic(text string) (Error, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	start := strings.Index(text, "goroutine ")
+	if start < 0 {
+		return nil, errors.New("tracerr: no goroutine in panic output")
+	}
+	message := panicMessage(text[:start])
+	goroutine := text[start:]
+	if end := strings.Index(goroutine, "\n\ngoroutine "); end >= 0 {
+		goroutine = goroutine[:end]
+	}
+	header := goroutine
+	if end := strings.Index(header, "\n"); end >= 0 {
+		header = header[:end]
+	}
+	if message == "" {
+		message = strings.TrimSuffix(header, ":")
+	}
+	frames := parseStack(goroutine, false)
+	if len(frames) == 0 {
+		return nil, errors.New("tracerr: no frames in panic output")
+	}
+	return CustomError(errors.New(message), frames), nil
+}
+
+// panicMessage returns value of the first panic in text,
+// or message of fatal error.
+func panicMessage(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		for _, prefix := range []string{"panic: ", "fatal error: "} {
+			if !strings.HasPrefix(line, prefix) {
+				continue
+			}
+			message := strings.TrimPrefix(line, prefix)
+			if end := strings.LastIndex(message, " [recovered"); end >= 0 {
+				message = message[:end]
+			}
+			return message
+		}
+	}
+	return ""
+}
+
+// FramesFromStack parses stack trace of the first goroutine in buf,
+// such as filled by runtime.Stack, into frames, so a traced error
+// can be created for another goroutine, e.g. a stuck one found by a watchdog:
+//
+//	err := tracerr.CustomError(errStuck, tracerr.FramesFromStack(stack))
+//
+// Frame of the goroutine creator is the last one with CreatedBy set.
+// Frames have no program counters. It returns nil if there are no frames.
+func FramesFromStack(buf []byte) []Frame {
+	stack := strings.ReplaceAll(string(buf), "\r\n", "\n")
+	if start := strings.Index(stack, "goroutine "); start >= 0 {
+		stack = stack[start:]
+		if end := strings.Index(stack, "\n\ngoroutine "); end >= 0 {
+			stack = stack[:end]
+		}
+	}
+	return parseStack(stack, true)
+}
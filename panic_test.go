@@ -0,0 +1,145 @@
+package tracerr_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+const panicOutput = `panic: runtime error: index out of range [5] with length 3 [recovered]
+	panic: runtime error: index out of range [5] with length 3
+
+goroutine 1 [running]:
+main.handle.func1()
+	/src/main.go:15 +0x6a
+panic({0x4a1b20?, 0xc000012345?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.(*Store).Get(...)
+	/src/store.go:42
+main.handle()
+	/src/main.go:20 +0x3b
+main.main()
+	/src/main.go:8 +0x1d
+
+goroutine 6 [chan receive]:
+main.worker()
+	/src/worker.go:11 +0x25
+created by main.main in goroutine 1
+	/src/main.go:6 +0x1a
+exit status 2
+`
+
+type ParsePanicTestCase struct {
+	Text            string
+	ExpectedMessage string
+	ExpectedFrames  []string
+}
+
+func TestParsePanic(t *testing.T) {
+	cases := []ParsePanicTestCase{
+		{
+			Text:            panicOutput,
+			ExpectedMessage: "runtime error: index out of range [5] with length 3",
+			ExpectedFrames: []string{
+				"/src/store.go:42 main.(*Store).Get()",
+				"/src/main.go:20 main.handle()",
+				"/src/main.go:8 main.main()",
+			},
+		},
+		{
+			Text:            "fatal error: all goroutines are asleep - deadlock!\n\n" + strings.SplitN(panicOutput, "\n\n", 3)[2],
+			ExpectedMessage: "all goroutines are asleep - deadlock!",
+			ExpectedFrames: []string{
+				"/src/worker.go:11 main.worker()",
+			},
+		},
+		{
+			Text:            strings.SplitN(panicOutput, "\n\n", 2)[1],
+			ExpectedMessage: "goroutine 1 [running]",
+			ExpectedFrames: []string{
+				"/src/store.go:42 main.(*Store).Get()",
+				"/src/main.go:20 main.handle()",
+				"/src/main.go:8 main.main()",
+			},
+		},
+	}
+
+	for i, c := range cases {
+		err, parseErr := tracerr.ParsePanic(c.Text)
+		if parseErr != nil {
+			t.Errorf("case #%d: tracerr.ParsePanic() error = %#v; want nil", i, parseErr)
+			continue
+		}
+		if err.Error() != c.ExpectedMessage {
+			t.Errorf("case #%d: err.Error() = %#v; want %#v", i, err.Error(), c.ExpectedMessage)
+		}
+		frames := err.StackTrace()
+		if len(frames) != len(c.ExpectedFrames) {
+			t.Errorf("case #%d: len(frames) = %#v; want %#v", i, len(frames), len(c.ExpectedFrames))
+			continue
+		}
+		for j, frame := range frames {
+			if frame.String() != c.ExpectedFrames[j] {
+				t.Errorf("case #%d: frames[%d] = %#v; want %#v", i, j, frame.String(), c.ExpectedFrames[j])
+			}
+		}
+	}
+}
+
+func TestParsePanicRuntimeStack(t *testing.T) {
+	buf := make([]byte, 4096)
+	n := runtime.Stack(buf, false)
+	err, parseErr := tracerr.ParsePanic(string(buf[:n]))
+	if parseErr != nil {
+		t.Fatalf("tracerr.ParsePanic() error = %#v; want nil", parseErr)
+	}
+	frames := err.StackTrace()
+	if len(frames) == 0 || frames[0].Name != "TestParsePanicRuntimeStack" {
+		t.Errorf("frames = %#v; want to start at TestParsePanicRuntimeStack", frames)
+	}
+}
+
+func TestParsePanicInvalid(t *testing.T) {
+	for _, text := range []string{"", "some text", "goroutine 1 [running]:\n"} {
+		if _, err := tracerr.ParsePanic(text); err == nil {
+			t.Errorf("tracerr.ParsePanic(%#v) error = nil; want error", text)
+		}
+	}
+}
+
+func TestFramesFromStack(t *testing.T) {
+	start := strings.Index(panicOutput, "goroutine 6")
+	frames := tracerr.FramesFromStack([]byte(panicOutput[start:]))
+	expected := []string{
+		"/src/worker.go:11 main.worker()",
+		"/src/main.go:6 main.main()",
+	}
+	if len(frames) != len(expected) {
+		t.Fatalf("tracerr.FramesFromStack() = %#v; want %d frames", frames, len(expected))
+	}
+	for i, frame := range frames {
+		if frame.String() != expected[i] {
+			t.Errorf("frames[%d] = %#v; want %#v", i, frame.String(), expected[i])
+		}
+	}
+	if frames[0].CreatedBy || !frames[1].CreatedBy {
+		t.Errorf("frames = %#v; want the last one created by", frames)
+	}
+	if frames := tracerr.FramesFromStack([]byte("some text")); frames != nil {
+		t.Errorf("tracerr.FramesFromStack(text) = %#v; want nil", frames)
+	}
+}
+
+func TestFramesFromStackGoroutine(t *testing.T) {
+	stack := make(chan []byte)
+	go func() {
+		buf := make([]byte, 4096)
+		stack <- buf[:runtime.Stack(buf, false)]
+	}()
+	frames := tracerr.FramesFromStack(<-stack)
+	if len(frames) != 2 || frames[1].Name != "TestFramesFromStackGoroutine" || !frames[1].CreatedBy {
+		t.Errorf("frames = %#v; want goroutine created by TestFramesFromStackGoroutine", frames)
+	}
+}
@@ -0,0 +1,48 @@
+package tracerr
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// WithPanicLayout prints frames of text output in the layout of Go panics:
+//
+//	main.read(...)
+//		/src/main.go:42 +0x1d
+//
+// IDE consoles, such as of GoLand and VS Code, hyperlink such frames
+// to source lines. Offset of program counter is printed only
+// for frames captured in the current process.
+func WithPanicLayout() PrintOption {
+	return func(o *printOptions) {
+		o.panicLayout = true
+	}
+}
+
+// panicFrameString formats frame in the layout of Go panics,
+// see WithPanicLayout.
+func (o *printOptions) panicFrameString(frame Frame) string {
+	name := frame.Func + "(...)"
+	if frame.CreatedBy {
+		name = createdBy + " " + frame.Func
+	}
+	return name + "\n\t" + o.location(frame) + pcOffset(frame)
+}
+
+// pcOffset returns offset of program counter of frame from the start
+// of its function as " +0x1d", or empty string if it's unknown.
+func pcOffset(frame Frame) string {
+	if frame.PC == 0 {
+		return ""
+	}
+	entry := frame.Entry
+	if entry == 0 {
+		if fn := runtime.FuncForPC(frame.PC); fn != nil {
+			entry = fn.Entry()
+		}
+	}
+	if entry == 0 || entry > frame.PC {
+		return ""
+	}
+	return fmt.Sprintf(" +0x%x", frame.PC-entry)
+}
@@ -0,0 +1,38 @@
+package tracerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithPanicLayout(t *testing.T) {
+	frames := diffFrames("read", "main")
+	frames[1].CreatedBy = true
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	tracerr.SetPrintOptions(tracerr.WithPanicLayout())
+	defer tracerr.SetPrintOptions()
+
+	output := tracerr.Sprint(err)
+	expected := "some error\nmain.read(...)\n\t/src/main.go:10\ncreated by main.main\n\t/src/main.go:20"
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	captured := tracerr.Sprint(tracerr.New("some error"))
+	if !regexp.MustCompile(`\ngithub.com/kadaan/tracerr_test.TestWithPanicLayout\(...\)\n\t\S+/panicstyle_test.go:\d+ \+0x[0-9a-f]+\n`).MatchString(captured) {
+		t.Errorf("tracerr.Sprint(captured) = %#v; want frames with offsets", captured)
+	}
+
+	parsed, parseErr := tracerr.ParsePanic("goroutine 1 [running]:\n" + strings.SplitN(output, "\n", 2)[1])
+	if parseErr != nil {
+		t.Fatalf("tracerr.ParsePanic() error = %v", parseErr)
+	}
+	// Frame of a goroutine creator is skipped as in panics.
+	if parsedFrames := parsed.StackTrace(); len(parsedFrames) != 1 || parsedFrames[0] != frames[0] {
+		t.Errorf("parsed.StackTrace() = %#v; want the printed frame", parsedFrames)
+	}
+}
@@ -0,0 +1,96 @@
+package tracerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// panicValue is an error converted from a recovered panic.
+type panicValue struct {
+	value interface{}
+}
+
+func (p *panicValue) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+// Unwrap returns the panic value if it's an error,
+// so errors.Is and errors.As see through the panic.
+func (p *panicValue) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// PanicValue returns the original value passed to panic.
+func (p *panicValue) PanicValue() interface{} {
+	return p.value
+}
+
+// PanicValue returns the original value of a panic converted to err
+// by Group, Go, Spawn or PanicError. It reports false if err isn't caused by panic.
+//
+// Use errors.As to get the value if it's an error.
+func PanicValue(err error) (interface{}, bool) {
+	var p interface{ PanicValue() interface{} }
+	if errors.As(err, &p) {
+		return p.PanicValue(), true
+	}
+	return nil, false
+}
+
+// PanicError returns recovered value r as an error
+// with stack trace starting at the panic, or nil if r is nil.
+// It must be called by the deferred function, which recovered r:
+//
+//	defer func() {
+//		if err := tracerr.PanicError(recover()); err != nil {
+//			tracerr.Print(err)
+//		}
+//	}()
+func PanicError(r interface{}) Error {
+	if r == nil {
+		return nil
+	}
+	err := &panicValue{value: r}
+	e := WrapAlways(err)
+	if frames, ok := trimPanicFrames(RawFrames(e)); ok {
+		return CustomError(err, frames)
+	}
+	return e
+}
+
+// CapturePanicStack returns stack trace starting at the panic site,
+// it's intended to be called by the deferred function recovering the panic,
+// so frames of the deferred function and of runtime panicking are trimmed:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			log.Printf("panic: %v\n%v", r, tracerr.CapturePanicStack())
+//		}
+//	}()
+//
+// If there is no panic in progress, stack trace starts at the caller.
+func CapturePanicStack() []Frame {
+	frames := callers(1, 0)
+	if trimmed, ok := trimPanicFrames(frames); ok {
+		return trimmed
+	}
+	return frames
+}
+
+// trimPanicFrames returns frames below runtime.gopanic,
+// it reports false if there is no panic in frames.
+func trimPanicFrames(frames []Frame) ([]Frame, bool) {
+	for i, frame := range frames {
+		if frame.Func != "runtime.gopanic" {
+			continue
+		}
+		// Runtime errors, such as nil dereference, panic inside runtime.
+		i++
+		for i < len(frames) && frames[i].Package == "runtime" {
+			i++
+		}
+		return frames[i:], true
+	}
+	return nil, false
+}
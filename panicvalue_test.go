@@ -0,0 +1,89 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type panicCode int
+
+type panicTestError struct {
+	Code int
+}
+
+func (e *panicTestError) Error() string {
+	return "panic test error"
+}
+
+func TestPanicValue(t *testing.T) {
+	var g tracerr.Group
+	g.Go(func() error {
+		panic(panicCode(42))
+	})
+	err := g.Wait()
+	if err.Error() != "panic: 42" {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), "panic: 42")
+	}
+	value, ok := tracerr.PanicValue(err)
+	if !ok || value != panicCode(42) {
+		t.Errorf("tracerr.PanicValue(err) = %#v, %v; want %#v, true", value, ok, panicCode(42))
+	}
+}
+
+func TestPanicValueError(t *testing.T) {
+	payload := &panicTestError{Code: 7}
+	var g tracerr.Group
+	g.Go(func() error {
+		panic(payload)
+	})
+	err := g.Wait()
+	var target *panicTestError
+	if !errors.As(err, &target) || target != payload {
+		t.Errorf("errors.As(err, &target) = %v; want payload", target)
+	}
+	if !errors.Is(err, payload) {
+		t.Errorf("errors.Is(err, payload) = false; want true")
+	}
+	value, ok := tracerr.PanicValue(err)
+	if !ok || value != payload {
+		t.Errorf("tracerr.PanicValue(err) = %#v, %v; want %#v, true", value, ok, payload)
+	}
+}
+
+func TestPanicValueNoPanic(t *testing.T) {
+	value, ok := tracerr.PanicValue(tracerr.New("some error"))
+	if ok || value != nil {
+		t.Errorf("tracerr.PanicValue(err) = %#v, %v; want nil, false", value, ok)
+	}
+}
+
+//go:noinline
+func panicWithCode() {
+	panic(panicCode(1))
+}
+
+func recoverPanic() (err error) {
+	defer func() {
+		if e := tracerr.PanicError(recover()); e != nil {
+			err = e
+		}
+	}()
+	panicWithCode()
+	return nil
+}
+
+func TestPanicError(t *testing.T) {
+	err := recoverPanic()
+	frames := tracerr.StackTrace(err)
+	if len(frames) == 0 || frames[0].Name != "panicWithCode" {
+		t.Errorf("tracerr.StackTrace(err) = %#v; want to start at panicWithCode", frames)
+	}
+	if value, ok := tracerr.PanicValue(err); !ok || value != panicCode(1) {
+		t.Errorf("tracerr.PanicValue(err) = %#v, %v; want %#v, true", value, ok, panicCode(1))
+	}
+	if err := tracerr.PanicError(nil); err != nil {
+		t.Errorf("tracerr.PanicError(nil) = %#v; want nil", err)
+	}
+}
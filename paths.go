@@ -0,0 +1,146 @@
+package tracerr
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WithTrimPaths shortens absolute paths of frames in output
+// to the same form as in -trimpath builds, e.g.
+// "/home/ci/builds/bar/baz/baz.go" becomes "github.com/foo/bar/baz/baz.go"
+// and files of dependencies keep their module version.
+//
+// It applies to Frame.String as well, if set by SetPrintOptions.
+func WithTrimPaths() PrintOption {
+	return func(o *printOptions) {
+		o.trimPaths = true
+	}
+}
+
+// WithPathRewrite replaces prefix from of frame paths with to,
+// both when source files are read and when paths are displayed.
+//
+// It's useful if binary is built in a sandbox, so recorded paths
+// don't exist on a developer machine. Multiple rewrites are applied
+// in order, the first matching one wins.
+func WithPathRewrite(from, to string) PrintOption {
+	return func(o *printOptions) {
+		// Rewrites may be shared with options set by SetPrintOptions.
+		rewrites := o.rewrites[:len(o.rewrites):len(o.rewrites)]
+		o.rewrites = append(rewrites, pathRewrite{
+			from: from,
+			to:   to,
+		})
+	}
+}
+
+type pathRewrite struct {
+	from string
+	to   string
+}
+
+// rewritePath applies the first matching rewrite to p.
+func (o *printOptions) rewritePath(p string) string {
+	for _, rewrite := range o.rewrites {
+		if !strings.HasPrefix(p, rewrite.from) {
+			continue
+		}
+		rest := p[len(rewrite.from):]
+		// Prefix must end at path separator, so "/src" doesn't match "/srcs".
+		if rest != "" && !strings.HasSuffix(rewrite.from, "/") &&
+			rest[0] != '/' && rest[0] != filepath.Separator {
+			continue
+		}
+		return rewrite.to + rest
+	}
+	return p
+}
+
+// displayPath returns path of frame for output.
+func (o *printOptions) displayPath(frame Frame) string {
+	frame.Path = o.rewritePath(frame.Path)
+	if o.trimPaths {
+		return trimPath(frame)
+	}
+	return frame.Path
+}
+
+// location formats path and line of frame for output.
+func (o *printOptions) location(frame Frame) string {
+	if o.maskLines {
+		return o.displayPath(frame) + ":NN"
+	}
+	return fmt.Sprintf("%s:%d", o.displayPath(frame), frame.Line)
+}
+
+// frameString formats frame the same way as Frame.String.
+func (o *printOptions) frameString(frame Frame) string {
+	if frame.IsMarker() {
+		return frame.Func
+	}
+	if o.frameLayout != "" {
+		line := strconv.Itoa(frame.Line)
+		if o.maskLines {
+			line = "NN"
+		}
+		return frame.format(o.frameLayout, o.displayPath(frame), line)
+	}
+	return fmt.Sprintf("%s %s()", o.location(frame), o.funcName(frame))
+}
+
+// trimPath returns path of frame in -trimpath form.
+func trimPath(frame Frame) string {
+	p := filepath.ToSlash(frame.Path)
+	if !path.IsAbs(p) && !filepath.IsAbs(frame.Path) {
+		// It's already trimmed.
+		return frame.Path
+	}
+	if i := strings.LastIndex(p, "/pkg/mod/"); i >= 0 {
+		return p[i+len("/pkg/mod/"):]
+	}
+	pkg := strings.TrimSuffix(frame.Package, "_test")
+	if pkg == "" {
+		return frame.Path
+	}
+	dir, file := path.Split(p)
+	if pkg == "main" {
+		return mainPackagePath(dir) + file
+	}
+	return pkg + "/" + file
+}
+
+var (
+	mainModule     string
+	mainModuleOnce sync.Once
+)
+
+// mainModulePath returns path of the main module of the binary,
+// or empty string if it has no module info.
+func mainModulePath() string {
+	mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModule = info.Main.Path
+		}
+	})
+	return mainModule
+}
+
+// mainPackagePath guesses import path of directory of package main
+// by a last element of the main module path, or returns empty string.
+func mainPackagePath(dir string) string {
+	mainModule := mainModulePath()
+	if mainModule == "" {
+		return ""
+	}
+	root := "/" + path.Base(mainModule) + "/"
+	i := strings.LastIndex(dir, root)
+	if i < 0 {
+		return ""
+	}
+	return mainModule + "/" + dir[i+len(root):]
+}
@@ -0,0 +1,121 @@
+package tracerr_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type TrimPathTestCase struct {
+	Frame    tracerr.Frame
+	Expected string
+}
+
+func TestWithTrimPaths(t *testing.T) {
+	tracerr.SetPrintOptions(tracerr.WithTrimPaths())
+	defer tracerr.SetPrintOptions()
+
+	cases := []TrimPathTestCase{
+		{
+			Frame:    tracerr.StackTrace(addFrameA("trim"))[0],
+			Expected: "github.com/kadaan/tracerr/error_helper_test.go:17 github.com/kadaan/tracerr_test.addFrameC()",
+		},
+		{
+			Frame: tracerr.Frame{
+				Func:    "testing.tRunner",
+				Line:    42,
+				Path:    "/usr/local/go/src/testing/testing.go",
+				Package: "testing",
+			},
+			Expected: "testing/testing.go:42 testing.tRunner()",
+		},
+		{
+			Frame: tracerr.Frame{
+				Func:    "github.com/foo/bar.Baz",
+				Line:    42,
+				Path:    "/home/ci/go/pkg/mod/github.com/foo/bar@v1.2.3/baz.go",
+				Package: "github.com/foo/bar",
+			},
+			Expected: "github.com/foo/bar@v1.2.3/baz.go:42 github.com/foo/bar.Baz()",
+		},
+		{
+			Frame: tracerr.Frame{
+				Func:    "github.com/foo/bar.Baz",
+				Line:    42,
+				Path:    "github.com/foo/bar/baz.go",
+				Package: "github.com/foo/bar",
+			},
+			Expected: "github.com/foo/bar/baz.go:42 github.com/foo/bar.Baz()",
+		},
+		{
+			Frame: tracerr.Frame{
+				Func: "main.main",
+				Line: 42,
+				Path: "/home/ci/builds/main.go",
+			},
+			Expected: "/home/ci/builds/main.go:42 main.main()",
+		},
+	}
+	for i, c := range cases {
+		if c.Frame.String() != c.Expected {
+			t.Errorf(
+				"case #%d: frame.String() = %#v; want %#v",
+				i, c.Frame.String(), c.Expected,
+			)
+		}
+	}
+
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{cases[1].Frame})
+	output := tracerr.Sprint(err)
+	expected := strings.Join([]string{"some error", cases[1].Expected}, "\n")
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+}
+
+func TestWithPathRewrite(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracerr.SetPrintOptions(
+		tracerr.WithPathRewrite("/builder/srcs", "/nowhere"),
+		tracerr.WithPathRewrite("/builder/src", wd),
+	)
+	defer tracerr.SetPrintOptions()
+
+	traced := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 17,
+				Path: "/builder/src/error_helper_test.go",
+			},
+			{
+				Func: "main.Bar",
+				Line: 42,
+				Path: "/builder/srcs/foo.go",
+			},
+		},
+	)
+	output := tracerr.SprintSource(traced, 1)
+	expectedRows := []string{
+		"some error",
+		"",
+		filepath.Join(wd, "error_helper_test.go") + ":17 main.Foo()",
+		"17\t\treturn tracerr.New(message)",
+		"",
+		"/nowhere/foo.go:42 main.Bar()",
+		"tracerr: file /nowhere/foo.go not found",
+		"",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf("tracerr.SprintSource(err, 1) = %#v; want %#v", output, expected)
+	}
+}
@@ -0,0 +1,95 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// pkgStackTracer is implemented by errors of github.com/pkg/errors.
+type pkgStackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// ToPkgErrors returns error, which implements StackTrace() errors.StackTrace
+// of github.com/pkg/errors, so libraries and loggers expecting it
+// can print stack trace of err.
+// Error output with "%+v" is the same as of github.com/pkg/errors.
+//
+// It returns nil if err is nil.
+func ToPkgErrors(err error) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(Error)
+	if !ok {
+		e = CustomError(err, nil)
+	}
+	return &pkgError{err: e}
+}
+
+// PkgErrorsFrame converts frame to a frame of github.com/pkg/errors,
+// which is useless for custom frames with no program counter.
+//
+// Frames of github.com/pkg/errors are program counters only,
+// so a frame of function inlined into its caller is reported as the callee.
+func (f Frame) PkgErrorsFrame() pkgerrors.Frame {
+	if f.PC == 0 {
+		return 0
+	}
+	// Frame of github.com/pkg/errors is a return address,
+	// while PC points to the call instruction.
+	return pkgerrors.Frame(f.PC + 1)
+}
+
+// pkgError adapts Error to interfaces of github.com/pkg/errors.
+type pkgError struct {
+	err Error
+}
+
+// Error returns error message.
+func (e *pkgError) Error() string {
+	return e.err.Error()
+}
+
+// StackTrace returns stack trace in github.com/pkg/errors form.
+func (e *pkgError) StackTrace() pkgerrors.StackTrace {
+	frames := RawFrames(e.err)
+	stack := make(pkgerrors.StackTrace, 0, len(frames))
+	for _, frame := range frames {
+		stack = append(stack, frame.PkgErrorsFrame())
+	}
+	return stack
+}
+
+// Unwrap returns traced error, so it can be found by errors.As.
+func (e *pkgError) Unwrap() error {
+	return e.err
+}
+
+// Format formats error the same way as github.com/pkg/errors.
+func (e *pkgError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.err.Error())
+			e.StackTrace().Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.err.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.err.Error())
+	}
+}
+
+// pkgErrorsFrames converts stack trace of github.com/pkg/errors to frames.
+func pkgErrorsFrames(stack pkgerrors.StackTrace) []Frame {
+	pcs := make([]uintptr, 0, len(stack))
+	for _, frame := range stack {
+		pcs = append(pcs, uintptr(frame))
+	}
+	return resolveFrames(pcs, false)
+}
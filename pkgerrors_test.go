@@ -0,0 +1,73 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/kadaan/tracerr"
+)
+
+func pkgErrorsNew() error {
+	return pkgerrors.New("pkg error")
+}
+
+func TestWrapPkgErrors(t *testing.T) {
+	err := pkgerrors.Wrap(pkgErrorsNew(), "context")
+	wrapped := tracerr.Wrap(err)
+	if wrapped.Unwrap() != err {
+		t.Errorf("wrapped.Unwrap() = %#v; want %#v", wrapped.Unwrap(), err)
+	}
+	frames := wrapped.StackTrace()
+	if len(frames) == 0 {
+		t.Fatalf("len(frames) = 0; want > 0")
+	}
+	if frames[0].Name != "pkgErrorsNew" {
+		t.Errorf("frames[0].Name = %#v; want %#v", frames[0].Name, "pkgErrorsNew")
+	}
+	if frames[0].Line != 15 {
+		t.Errorf("frames[0].Line = %#v; want %#v", frames[0].Line, 15)
+	}
+}
+
+func TestToPkgErrors(t *testing.T) {
+	if tracerr.ToPkgErrors(nil) != nil {
+		t.Errorf("tracerr.ToPkgErrors(nil) = %#v; want nil", tracerr.ToPkgErrors(nil))
+	}
+	// Interface call isn't inlined, so program counter of the frame
+	// doesn't belong to tracerr.New.
+	tr := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount)
+	err := tr.New("some error")
+	converted := tracerr.ToPkgErrors(err)
+	if converted.Error() != "some error" {
+		t.Errorf("converted.Error() = %#v; want %#v", converted.Error(), "some error")
+	}
+	var e tracerr.Error
+	if !errors.As(converted, &e) || e != err {
+		t.Errorf("errors.As(converted, &e) = %#v; want %#v", e, err)
+	}
+	tracer, ok := converted.(interface {
+		StackTrace() pkgerrors.StackTrace
+	})
+	if !ok {
+		t.Fatalf("converted doesn't implement StackTrace() errors.StackTrace")
+	}
+	stack := tracer.StackTrace()
+	if len(stack) != len(err.StackTrace()) {
+		t.Fatalf("len(stack) = %#v; want %#v", len(stack), len(err.StackTrace()))
+	}
+	want := fmt.Sprintf("TestToPkgErrors %s:%d", "pkgerrors_test.go", err.StackTrace()[0].Line)
+	if got := fmt.Sprintf("%n %s:%d", stack[0], stack[0], stack[0]); got != want {
+		t.Errorf("stack[0] = %#v; want %#v", got, want)
+	}
+	output := fmt.Sprintf("%+v", converted)
+	if !strings.HasPrefix(output, "some error\n") || !strings.Contains(output, "TestToPkgErrors") {
+		t.Errorf("fmt.Sprintf(\"%%+v\", converted) = %#v; want message and stack trace", output)
+	}
+	if output := fmt.Sprintf("%v", converted); output != "some error" {
+		t.Errorf("fmt.Sprintf(\"%%v\", converted) = %#v; want %#v", output, "some error")
+	}
+}
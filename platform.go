@@ -0,0 +1,7 @@
+//go:build !js && !wasip1 && !tinygo
+
+package tracerr
+
+// limitedRuntime is true on platforms, where there are no source files
+// to read and runtime may have limited symbol data, see platform_limited.go.
+const limitedRuntime = false
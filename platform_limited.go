@@ -0,0 +1,10 @@
+//go:build js || wasip1 || tinygo
+
+package tracerr
+
+// limitedRuntime is true on WASM and TinyGo, where there are no source
+// files to read and runtime may have limited symbol data, so sources
+// aren't read by default and frames are printed without warnings
+// of missing sources. Sources can be provided by SetSourceProvider,
+// e.g. embedded, and symbols by SetSymbolizer.
+const limitedRuntime = true
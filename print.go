@@ -2,9 +2,10 @@ package tracerr
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/logrusorgru/aurora"
 )
@@ -15,13 +16,171 @@ var DefaultLinesAfter = 2
 // DefaultLinesBefore is number of source lines before traced line to display.
 var DefaultLinesBefore = 3
 
-var cache = map[string][]string{}
+var cache = newSourceCache[[]string](DefaultSourceCacheEntries, DefaultSourceCacheBytes)
 
+// mutex guards sourceProvider.
 var mutex sync.RWMutex
 
+// PrintOption configures output of print functions, see SetPrintOptions.
+type PrintOption func(o *printOptions)
+
+type printOptions struct {
+	theme Theme
+	// lines is a number of source lines by the same rules as in PrintSource,
+	// nil means default number of lines.
+	lines []int
+	// topLines is a number of source lines of the top frame,
+	// nil means the same as of other frames.
+	topLines []int
+	// enclosingMaxLines is a maximum number of lines of enclosing function
+	// shown for the top frame, see WithEnclosingFunction.
+	enclosingMaxLines int
+	// smartSnippets is true if source fragments follow code,
+	// see WithSmartSnippets.
+	smartSnippets bool
+	// hyperlink is a URL template of frame locations, see WithHyperlinks.
+	hyperlink string
+	// trimPaths is true if paths are shortened, see WithTrimPaths.
+	trimPaths bool
+	// rewrites are applied to paths in order, see WithPathRewrite.
+	rewrites []pathRewrite
+	// redactors are applied to frames in order, see WithRedactor.
+	redactors []Redactor
+	// maskLines is true if line numbers of frames are replaced with "NN".
+	maskLines bool
+	// maxFrames is a maximum number of frames, zero means no limit.
+	maxFrames int
+	// window is true if only windowFirst and windowLast frames are printed.
+	window      bool
+	windowFirst int
+	windowLast  int
+	// compactSeparator separates frames of SprintCompact output.
+	compactSeparator string
+	// maxWidth is a maximum width of frame rows, see WithMaxWidth.
+	maxWidth int
+	// maxOutputBytes is a maximum size of output, see WithMaxOutputBytes.
+	maxOutputBytes int
+	// genericNames is a style of names of generic functions.
+	genericNames GenericNames
+	// panicLayout is true if frames are printed as by Go panics.
+	panicLayout bool
+	// frameLayout is a layout of Frame.String, see WithFrameLayout.
+	frameLayout string
+	// frameOrder is an order of frames in output, see WithFrameOrder.
+	frameOrder FrameOrder
+	// dimNonApp is true if frames other than application are dimmed.
+	dimNonApp bool
+	// lineDirectives is a handling of //line directives.
+	lineDirectives LineDirectives
+	// packageGroups is true if frames are grouped by package.
+	packageGroups bool
+	// format is a version of output format, see WithFormat.
+	format FormatVersion
+	// joinedErrors is true if joined errors are printed, see WithJoinedErrors.
+	joinedErrors bool
+	// translator translates messages of SprintPublic.
+	translator func(code, message string, fields map[string]interface{}) string
+	// inlineSource is source copied into printed error at capture,
+	// it's used if source files are not available.
+	inlineSource inlineSource
+}
+
+func defaultPrintOptions() printOptions {
+	return printOptions{
+		theme: DefaultTheme,
+	}
+}
+
+var (
+	globalPrintOptions = defaultPrintOptions()
+	printOptionsMutex  sync.RWMutex
+)
+
+// SetPrintOptions configures output of all print and sprint functions.
+// It replaces options set before, call it with no options to restore defaults.
+func SetPrintOptions(options ...PrintOption) {
+	o := defaultPrintOptions()
+	for _, option := range options {
+		option(&o)
+	}
+	printOptionsMutex.Lock()
+	defer printOptionsMutex.Unlock()
+	globalPrintOptions = o
+}
+
+func currentPrintOptions() printOptions {
+	printOptionsMutex.RLock()
+	defer printOptionsMutex.RUnlock()
+	return globalPrintOptions
+}
+
+// mergePrintOptions applies options on top of options set by SetPrintOptions.
+func mergePrintOptions(options []PrintOption) printOptions {
+	o := currentPrintOptions()
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// WithSourceLines sets a number of source lines to display
+// for renderers accepting options, such as SprintHTML.
+// Numbers follow the same rules as in PrintSource.
+func WithSourceLines(nums ...int) PrintOption {
+	return func(o *printOptions) {
+		o.lines = nums
+	}
+}
+
+// WithContextLines sets a number of source lines to display
+// before and after traced line. It applies to PrintSource and other
+// functions printing source fragments if number of lines isn't passed.
+func WithContextLines(before, after int) PrintOption {
+	return func(o *printOptions) {
+		o.lines = []int{before, after}
+	}
+}
+
+// WithTopFrameContextLines sets a number of source lines to display
+// before and after traced line of the top frame, which is usually
+// the most interesting one, while other frames follow WithContextLines.
+// It has no effect if output has no source fragments.
+func WithTopFrameContextLines(before, after int) PrintOption {
+	return func(o *printOptions) {
+		o.topLines = []int{before, after}
+	}
+}
+
+// frameRows returns a number of source lines of frame, which is the top one
+// if top is true, given before and after lines of the other frames.
+func (o *printOptions) frameRows(top bool, frame Frame, before, after int) (int, int) {
+	if !top {
+		return before, after
+	}
+	if o.topLines != nil {
+		before, after, _ = calcRows(o.topLines)
+	}
+	if o.enclosingMaxLines > 0 {
+		if start, end, ok := o.enclosingFunc(frame); ok && end-start < o.enclosingMaxLines {
+			return frame.Line - start, end - frame.Line
+		}
+	}
+	return before, after
+}
+
 // Print prints error message with stack trace.
+//
+// Output goes to os.Stdout, see SetOutput and SetPrinter to change it.
 func Print(err error) {
-	fmt.Println(Sprint(err))
+	printOutput(err, func(w io.Writer) {
+		Fprint(w, err)
+	})
+}
+
+// Fprint writes error message with stack trace to w.
+func Fprint(w io.Writer, err error) (int, error) {
+	o := currentPrintOptions()
+	return fmt.Fprintln(w, sprint(err, []int{0}, false, o.outputWidth(w)))
 }
 
 // PrintSource prints error message with stack trace and source fragments.
@@ -33,30 +192,54 @@ func Print(err error) {
 //
 // Pass two numbers to specify exactly how many lines should be shown
 // before and after traced line.
+//
+// If no numbers are passed, number of lines set by WithContextLines is used.
 func PrintSource(err error, nums ...int) {
-	fmt.Println(SprintSource(err, nums...))
+	printOutput(err, func(w io.Writer) {
+		FprintSource(w, err, nums...)
+	})
+}
+
+// FprintSource writes error output to w by the same rules as PrintSource.
+func FprintSource(w io.Writer, err error, nums ...int) (int, error) {
+	o := currentPrintOptions()
+	return fmt.Fprintln(w, sprint(err, nums, false, o.outputWidth(w)))
 }
 
 // PrintSourceColor prints error message with stack trace and source fragments,
 // which are in color.
 // Output rules are the same as in PrintSource.
+//
+// Color is used only if output is a terminal,
+// set NO_COLOR or FORCE_COLOR environment variable to override it.
 func PrintSourceColor(err error, nums ...int) {
-	fmt.Println(SprintSourceColor(err, nums...))
+	printOutput(err, func(w io.Writer) {
+		FprintSourceColor(w, err, nums...)
+	})
+}
+
+// FprintSourceColor writes error output to w by the same rules as PrintSourceColor.
+func FprintSourceColor(w io.Writer, err error, nums ...int) (int, error) {
+	if !colorEnabled(w) {
+		return FprintSource(w, err, nums...)
+	}
+	o := currentPrintOptions()
+	return fmt.Fprintln(w, sprint(err, nums, true, o.outputWidth(w)))
 }
 
 // Sprint returns error output by the same rules as Print.
 func Sprint(err error) string {
-	return sprint(err, []int{0}, false)
+	return sprint(err, []int{0}, false, 0)
 }
 
 // SprintSource returns error output by the same rules as PrintSource.
 func SprintSource(err error, nums ...int) string {
-	return sprint(err, nums, false)
+	return sprint(err, nums, false, 0)
 }
 
 // SprintSourceColor returns error output by the same rules as PrintSourceColor.
 func SprintSourceColor(err error, nums ...int) string {
-	return sprint(err, nums, true)
+	return sprint(err, nums, true, 0)
 }
 
 func calcRows(nums []int) (before, after int, withSource bool) {
@@ -88,69 +271,138 @@ func calcRows(nums []int) (before, after int, withSource bool) {
 }
 
 func readLines(path string) ([]string, error) {
-	mutex.RLock()
-	lines, ok := cache[path]
-	mutex.RUnlock()
-	if ok {
+	if lines, ok := cache.get(path); ok {
 		return lines, nil
 	}
+	mutex.RLock()
+	provider := sourceProvider
+	mutex.RUnlock()
 
-	b, err := ioutil.ReadFile(path)
+	b, err := provider.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("tracerr: file %s not found", path)
 	}
-	lines = strings.Split(string(b), "\n")
-	mutex.Lock()
-	defer mutex.Unlock()
-	cache[path] = lines
+	lines := strings.Split(string(b), "\n")
+	cache.add(path, lines, len(b))
 	return lines, nil
 }
 
-func sourceRows(rows []string, frame Frame, before, after int, colorized bool) []string {
-	lines, err := readLines(frame.Path)
+// sourceLine is a single line of source fragment.
+type sourceLine struct {
+	// Number is a line number starting from 1.
+	Number int
+	// Text is a line content.
+	Text string
+	// Current is true for traced line.
+	Current bool
+	// Start and End are byte offsets of called expression in traced line,
+	// End is zero if it's unknown.
+	Start, End int
+}
+
+// sourceFragment returns source lines around traced line of frame.
+func (o *printOptions) sourceFragment(frame Frame, before, after int) ([]sourceLine, error) {
+	if frame.Line < 1 {
+		return nil, fmt.Errorf("tracerr: unknown line of %s", frame.Func)
+	}
+	lines, err := readLines(o.rewritePath(frame.Path))
 	if err != nil {
-		message := err.Error()
-		if colorized {
-			message = aurora.Brown(message).String()
+		if fragment, ok := o.inlineSource.fragment(frame, before, after); ok {
+			return fragment, nil
 		}
-		return append(rows, message, "")
+		return nil, err
 	}
 	if len(lines) < frame.Line {
-		message := fmt.Sprintf(
+		return nil, fmt.Errorf(
 			"tracerr: too few lines, got %d, want %d",
 			len(lines), frame.Line,
 		)
-		if colorized {
-			message = aurora.Brown(message).String()
-		}
-		return append(rows, message, "")
 	}
 	current := frame.Line - 1
 	start := current - before
 	end := current + after
+	if o.smartSnippets {
+		start, end = smartSnippet(lines, current, max(start, 0), min(end, len(lines)-1))
+	}
+	fragment := make([]sourceLine, 0, end-start+1)
 	for i := start; i <= end; i++ {
 		if i < 0 || i >= len(lines) {
 			continue
 		}
-		line := lines[i]
+		fragment = append(fragment, sourceLine{
+			Number:  i + 1,
+			Text:    lines[i],
+			Current: i == current,
+		})
+	}
+	return fragment, nil
+}
+
+// sourceRows appends source fragment of frame to rows,
+// theme is nil for output without color.
+// Call expression of callee is highlighted in colored output.
+func (o *printOptions) sourceRows(rows []string, frame Frame, callee string, before, after int, theme *Theme) []string {
+	fragment, err := o.sourceFragment(frame, before, after)
+	if err != nil {
+		if limitedRuntime && hasNoSources() {
+			// Missing sources are expected, so they aren't reported.
+			return append(rows, "")
+		}
+		message := err.Error()
+		if theme != nil {
+			message = colorize(message, theme.Warning)
+		}
+		return append(rows, message, "")
+	}
+	if sourceChanged(frame, fragment) {
+		message := staleSourceWarning
+		if theme != nil {
+			message = colorize(message, theme.Warning)
+		}
+		rows = append(rows, message)
+	}
+	if theme != nil && theme.Expression != 0 {
+		highlightExpression(fragment, callee)
+	}
+	for _, line := range fragment {
 		var message string
 		// TODO Pad to the same length.
-		if i == frame.Line-1 {
-			message = fmt.Sprintf("%d\t%s", i+1, string(line))
-			if colorized {
-				message = aurora.Red(message).String()
+		if line.Current && theme != nil && line.End > 0 {
+			message = colorize(fmt.Sprintf("%d\t%s", line.Number, line.Text[:line.Start]), theme.Highlight) +
+				colorize(line.Text[line.Start:line.End], theme.Expression)
+			if rest := line.Text[line.End:]; rest != "" {
+				message += colorize(rest, theme.Highlight)
+			}
+		} else if line.Current {
+			message = fmt.Sprintf("%d\t%s", line.Number, line.Text)
+			if theme != nil {
+				message = colorize(message, theme.Highlight)
 			}
-		} else if colorized {
-			message = aurora.Sprintf("%d\t%s", aurora.Black(i+1), string(line))
+		} else if theme != nil && theme.hasSyntax() {
+			message = colorize(fmt.Sprint(line.Number), theme.LineNumber) + "\t" + highlightSyntax(line.Text, theme)
+		} else if theme != nil {
+			message = aurora.Sprintf(
+				"%d\t%s",
+				aurora.Colorize(line.Number, theme.LineNumber),
+				aurora.Colorize(line.Text, theme.Context),
+			)
 		} else {
-			message = fmt.Sprintf("%d\t%s", i+1, string(line))
+			message = fmt.Sprintf("%d\t%s", line.Number, line.Text)
 		}
 		rows = append(rows, message)
 	}
 	return append(rows, "")
 }
 
-func sprint(err error, nums []int, colorized bool) string {
+// sprint returns error output by options set by SetPrintOptions,
+// width is a maximum width of frame rows, zero means the one set by WithMaxWidth.
+func sprint(err error, nums []int, colorized bool, width int) string {
+	o := currentPrintOptions()
+	return o.sprint(err, nums, colorized, width)
+}
+
+// sprint returns error output by o by the same rules as sprint function.
+func (o printOptions) sprint(err error, nums []int, colorized bool, width int) string {
 	if err == nil {
 		return ""
 	}
@@ -158,26 +410,128 @@ func sprint(err error, nums []int, colorized bool) string {
 	if !ok {
 		return err.Error()
 	}
+	o.inlineSource = inlineSourceOf(e)
+	if width == 0 {
+		width = o.maxWidth
+	}
+	var theme *Theme
+	if colorized {
+		theme = &o.theme
+	}
+	if len(nums) == 0 && o.lines != nil {
+		nums = o.lines
+	}
 	before, after, withSource := calcRows(nums)
-	frames := e.StackTrace()
+	frames := o.outputFrames(e)
 	expectedRows := len(frames) + 1
 	if withSource {
 		expectedRows = (before+after+3)*len(frames) + 2
 	}
 	rows := make([]string, 0, expectedRows)
-	rows = append(rows, e.Error())
+	message := e.Error()
+	if o.maxOutputBytes > 0 {
+		message = truncateMessage(message, o.maxOutputBytes)
+	}
+	if theme != nil {
+		message = colorize(message, theme.Message)
+	}
+	rows = append(rows, message)
+	contextRows := fieldRows(e)
+	if trace, ok := Trace(e); ok {
+		contextRows = append(contextRows, "trace_id="+trace.TraceID+" span_id="+trace.SpanID)
+	}
+	for _, message := range contextRows {
+		if theme != nil {
+			message = colorize(message, theme.Context)
+		}
+		rows = append(rows, message)
+	}
 	if withSource {
 		rows = append(rows, "")
 	}
-	for _, frame := range frames {
-		message := frame.String()
-		if colorized {
-			message = aurora.Bold(message).String()
+	size := outputSize(rows)
+	group := ""
+	for i, frame := range frames {
+		start := len(rows)
+		separator, isSeparator := separatorString(frame)
+		if o.packageGroups && !isSeparator {
+			if header, ok := packageHeader(frame.Frame, group); ok {
+				group = header
+				if theme != nil {
+					header = colorize(header, theme.Context)
+				}
+				rows = append(rows, header)
+			}
 		}
-		rows = append(rows, message)
-		if withSource {
-			rows = sourceRows(rows, frame, before, after, colorized)
+		frameStart := len(rows)
+		if isSeparator {
+			group = ""
+			message := separator
+			if theme != nil {
+				message = colorize(message, theme.Context)
+			}
+			rows = append(rows, message)
+			if withSource {
+				rows = append(rows, "")
+			}
+		} else if o.panicLayout {
+			message := o.panicFrameString(frame.Frame)
+			if theme != nil {
+				message = colorize(message, o.pathColor(frame.Frame, theme))
+			}
+			rows = append(rows, message+repeatedSuffix(frame))
+			if withSource {
+				before, after := o.frameRows(frame.Top, frame.Frame, before, after)
+				rows = o.sourceRows(rows, frame.Frame, frame.Callee, before, after, theme)
+			}
+		} else {
+			if frame.CreatedBy {
+				message := createdBy
+				if theme != nil {
+					message = colorize(message, theme.Context)
+				}
+				rows = append(rows, message)
+			}
+			suffix := repeatedSuffix(frame)
+			location, fn := o.location(frame.Frame), o.funcName(frame.Frame)
+			if width > 0 {
+				location, fn = fitFrame(location, fn, width-utf8.RuneCountInString(suffix))
+			}
+			message := fmt.Sprintf("%s %s()", location, fn)
+			if theme != nil {
+				if o.hyperlink != "" {
+					message = o.hyperlinkFrame(frame.Frame, location, fn)
+				}
+				message = colorize(message, o.pathColor(frame.Frame, theme))
+			}
+			rows = append(rows, message+suffix)
+			if frame.Generated != nil {
+				message := "generated at " + o.location(*frame.Generated)
+				if theme != nil {
+					message = colorize(message, theme.Context)
+				}
+				rows = append(rows, message)
+			}
+			if withSource {
+				before, after := o.frameRows(frame.Top, frame.Frame, before, after)
+				rows = o.sourceRows(rows, frame.Frame, frame.Callee, before, after, theme)
+			}
+		}
+		if o.packageGroups && !isSeparator {
+			indentRows(rows[frameStart:])
+		}
+		if o.maxOutputBytes > 0 {
+			size += outputSize(rows[start:])
+			if size > o.maxOutputBytes {
+				message := truncatedString(frames[i:])
+				if theme != nil {
+					message = colorize(message, theme.Context)
+				}
+				rows = append(rows[:start], message)
+				break
+			}
 		}
 	}
+	rows = o.childRows(rows, e, nums, colorized, width)
 	return strings.Join(rows, "\n")
 }
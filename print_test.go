@@ -242,22 +242,22 @@ func TestPrint(t *testing.T) {
 				"",
 				aurora.Bold("/src/github.com/kadaan/tracerr/error_helper_test.go:17 github.com/kadaan/tracerr_test.addFrameC()").String(),
 				aurora.Black("16").String() + "\tfunc addFrameC(message string) error {",
-				aurora.Red("17\t\treturn tracerr.New(message)").String(),
+				highlighted("17\t\treturn ", "tracerr.New(message)"),
 				aurora.Black("18").String() + "\t}",
 				"",
 				aurora.Bold("/src/github.com/kadaan/tracerr/error_helper_test.go:13 github.com/kadaan/tracerr_test.addFrameB()").String(),
 				aurora.Black("12").String() + "\tfunc addFrameB(message string) error {",
-				aurora.Red("13\t\treturn addFrameC(message)").String(),
+				highlighted("13\t\treturn ", "addFrameC(message)"),
 				aurora.Black("14").String() + "\t}",
 				"",
 				aurora.Bold("/src/github.com/kadaan/tracerr/error_helper_test.go:9 github.com/kadaan/tracerr_test.addFrameA()").String(),
 				aurora.Black("8").String() + "\tfunc addFrameA(message string) error {",
-				aurora.Red("9\t\treturn addFrameB(message)").String(),
+				highlighted("9\t\treturn ", "addFrameB(message)"),
 				aurora.Black("10").String() + "\t}",
 				"",
 				aurora.Bold("/src/github.com/kadaan/tracerr/print_test.go:26 github.com/kadaan/tracerr_test.TestPrint()").String(),
 				aurora.Black("25").String() + "\t\tmessage := \"runtime error: index out of range\"",
-				aurora.Red("26\t\terr := addFrameA(message)").String(),
+				highlighted("26\t\terr := ", "addFrameA(message)"),
 				aurora.Black("27").String() + "\t",
 				"",
 			},
@@ -265,6 +265,8 @@ func TestPrint(t *testing.T) {
 		},
 	}
 
+	// Output is captured by pipe, which is not a terminal.
+	forceColor(t)
 	for i, c := range cases {
 		assertRows(t, i, c.Output, c.ExpectedRows, c.ExpectedMinExtraRows)
 		output := captureOutput(c.Printer)
@@ -439,6 +441,11 @@ func assertRows(t *testing.T, i int, output string, expectedRows []string, extra
 	}
 }
 
+func forceColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+}
+
 func captureOutput(fn func()) string {
 	r, w, err := os.Pipe()
 	if err != nil {
@@ -453,3 +460,121 @@ func captureOutput(fn func()) string {
 	io.Copy(&buf, r)
 	return buf.String()
 }
+
+type FprintTestCase struct {
+	Printer  func(w io.Writer) (int, error)
+	Expected string
+}
+
+func TestFprint(t *testing.T) {
+	forceColor(t)
+	err := addFrameA("fprint error")
+	cases := []FprintTestCase{
+		{
+			Printer: func(w io.Writer) (int, error) {
+				return tracerr.Fprint(w, err)
+			},
+			Expected: tracerr.Sprint(err) + "\n",
+		},
+		{
+			Printer: func(w io.Writer) (int, error) {
+				return tracerr.FprintSource(w, err, 2, 1)
+			},
+			Expected: tracerr.SprintSource(err, 2, 1) + "\n",
+		},
+		{
+			Printer: func(w io.Writer) (int, error) {
+				return tracerr.FprintSourceColor(w, err, 2, 1)
+			},
+			Expected: tracerr.SprintSourceColor(err, 2, 1) + "\n",
+		},
+	}
+
+	for i, c := range cases {
+		var buf bytes.Buffer
+		n, writeErr := c.Printer(&buf)
+		if writeErr != nil {
+			t.Errorf("case #%d: err = %#v; want nil", i, writeErr)
+		}
+		if n != len(c.Expected) {
+			t.Errorf("case #%d: n = %#v; want %#v", i, n, len(c.Expected))
+		}
+		if buf.String() != c.Expected {
+			t.Errorf(
+				"case #%d: output = %#v; want %#v",
+				i, buf.String(), c.Expected,
+			)
+		}
+	}
+}
+
+func TestWithContextLines(t *testing.T) {
+	tracerr.SetPrintOptions(
+		tracerr.WithContextLines(0, 0),
+		tracerr.WithTopFrameContextLines(1, 1),
+		tracerr.WithMaxFrames(2),
+	)
+	defer tracerr.SetPrintOptions()
+
+	err := addFrameB("some error")
+	output := tracerr.SprintSource(err)
+	rows := strings.Split(output, "\n")
+	expectedRows := []string{
+		"some error",
+		"",
+		rows[2],
+		"16\tfunc addFrameC(message string) error {",
+		"17\t\treturn tracerr.New(message)",
+		"18\t}",
+		"",
+		rows[7],
+		"13\t\treturn addFrameC(message)",
+		"",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf("tracerr.SprintSource(err) = %#v; want %#v", output, expected)
+	}
+
+	if output := tracerr.SprintSource(err, 0); output != tracerr.Sprint(err) {
+		t.Errorf("tracerr.SprintSource(err, 0) = %#v; want %#v", output, tracerr.Sprint(err))
+	}
+
+	markdown := tracerr.SprintMarkdown(err, tracerr.WithTopFrameContextLines(0, 0))
+	if strings.Contains(markdown, "16\tfunc addFrameC") {
+		t.Errorf("tracerr.SprintMarkdown(err) = %#v; want no context lines", markdown)
+	}
+}
+
+func TestWithEnclosingFunction(t *testing.T) {
+	tracerr.SetPrintOptions(
+		tracerr.WithEnclosingFunction(10),
+		tracerr.WithMaxFrames(2),
+	)
+	defer tracerr.SetPrintOptions()
+
+	err := addFrameB("some error")
+	output := tracerr.SprintSource(err, 0, 0)
+	rows := strings.Split(output, "\n")
+	expectedRows := []string{
+		"some error",
+		"",
+		rows[2],
+		"16\tfunc addFrameC(message string) error {",
+		"17\t\treturn tracerr.New(message)",
+		"18\t}",
+		"",
+		rows[7],
+		"13\t\treturn addFrameC(message)",
+		"",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf("tracerr.SprintSource(err) = %#v; want %#v", output, expected)
+	}
+
+	tracerr.SetPrintOptions(tracerr.WithEnclosingFunction(2), tracerr.WithMaxFrames(1))
+	if output := tracerr.SprintSource(err, 0, 0); strings.Contains(output, "16\t") {
+		t.Errorf("tracerr.SprintSource(err) = %#v; want no enclosing function", output)
+	}
+}
@@ -0,0 +1,83 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintAll prints errs with stack traces and source fragments,
+// each under a header with its index, e.g. errors accumulated
+// by a validation pipeline or a shutdown sequence. Nil errors are skipped.
+//
+// Bottom frames shared with a previous error, e.g. of the same loop,
+// are elided with a reference to it, so only frames which differ are printed.
+// Source lines follow WithSourceLines, see PrintSource for defaults.
+//
+// Output goes to os.Stdout, see SetOutput to change it.
+// Printer set by SetPrinter isn't used, as it prints a single error.
+func PrintAll(errs []error, options ...PrintOption) {
+	FprintAll(currentOutput(), errs, options...)
+}
+
+// FprintAll writes output of errs to w by the same rules as PrintAll.
+func FprintAll(w io.Writer, errs []error, options ...PrintOption) (int, error) {
+	o := mergePrintOptions(options)
+	return fmt.Fprintln(w, o.sprintAll(errs, o.outputWidth(w)))
+}
+
+// SprintAll returns output of errs by the same rules as PrintAll.
+func SprintAll(errs []error, options ...PrintOption) string {
+	o := mergePrintOptions(options)
+	return o.sprintAll(errs, 0)
+}
+
+func (o printOptions) sprintAll(errs []error, width int) string {
+	traced := make([]error, 0, len(errs))
+	stacks := make([][]Frame, 0, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(Error); !ok {
+			if e, ok := AsError(err); ok {
+				err = CustomError(err, RawFrames(e))
+			}
+		}
+		traced = append(traced, err)
+		stacks = append(stacks, StackTrace(err))
+	}
+	parts := make([]string, 0, len(traced))
+	for i, err := range traced {
+		header := fmt.Sprintf("=== %d of %d ===", i+1, len(traced))
+		shared, with := sharedFrames(stacks[i], stacks[:i])
+		if shared == 0 {
+			parts = append(parts, header+"\n"+o.sprint(err, nil, false, width))
+			continue
+		}
+		own := CustomError(err, stacks[i][:len(stacks[i])-shared])
+		elided := fmt.Sprintf("... %d frames shared with %d ...", shared, with+1)
+		if shared == 1 {
+			elided = fmt.Sprintf("... 1 frame shared with %d ...", with+1)
+		}
+		parts = append(parts, header+"\n"+o.sprint(own, nil, false, width)+"\n"+elided)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// sharedFrames returns the largest number of bottom frames of frames,
+// which are the same as of one of previous stack traces, and index of it.
+// At least the top frame isn't shared.
+func sharedFrames(frames []Frame, previous [][]Frame) (shared, with int) {
+	for i, other := range previous {
+		n := 0
+		for n < len(frames)-1 && n < len(other) &&
+			sameFrame(frames[len(frames)-1-n], other[len(other)-1-n]) {
+			n++
+		}
+		if n > shared {
+			shared, with = n, i
+		}
+	}
+	return shared, with
+}
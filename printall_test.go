@@ -0,0 +1,55 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintAll(t *testing.T) {
+	errs := []error{
+		tracerr.CustomError(errors.New("first"), diffFrames("validate", "load", "main")),
+		nil,
+		errors.New("plain"),
+		fmt.Errorf("context: %w", tracerr.CustomError(errors.New("second"), []tracerr.Frame{
+			tracerr.NewFrame("main.check", "/src/check.go", 5),
+			tracerr.NewFrame("main.load", "/src/main.go", 20),
+			tracerr.NewFrame("main.main", "/src/main.go", 30),
+		})),
+		tracerr.CustomError(errors.New("third"), diffFrames("validate", "load", "main")),
+	}
+	expected := "=== 1 of 4 ===\n" +
+		"first\n" +
+		"/src/main.go:10 main.validate()\n" +
+		"/src/main.go:20 main.load()\n" +
+		"/src/main.go:30 main.main()\n" +
+		"\n" +
+		"=== 2 of 4 ===\n" +
+		"plain\n" +
+		"\n" +
+		"=== 3 of 4 ===\n" +
+		"context: second\n" +
+		"/src/check.go:5 main.check()\n" +
+		"... 2 frames shared with 1 ...\n" +
+		"\n" +
+		"=== 4 of 4 ===\n" +
+		"third\n" +
+		"/src/main.go:10 main.validate()\n" +
+		"... 2 frames shared with 1 ..."
+	if output := tracerr.SprintAll(errs, tracerr.WithSourceLines(0)); output != expected {
+		t.Errorf("tracerr.SprintAll(errs) = %#v; want %#v", output, expected)
+	}
+
+	var b bytes.Buffer
+	tracerr.FprintAll(&b, errs[:1])
+	if output := b.String(); !strings.HasPrefix(output, "=== 1 of 1 ===\nfirst\n\n/src/main.go:10 main.validate()\n") {
+		t.Errorf("tracerr.FprintAll(w, errs) = %#v; want output with source", output)
+	}
+	if output := tracerr.SprintAll(nil); output != "" {
+		t.Errorf("tracerr.SprintAll(nil) = %#v; want empty", output)
+	}
+}
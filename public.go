@@ -0,0 +1,76 @@
+package tracerr
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// debugOutput is true if SprintPublic renders debug output, see SetDebugOutput.
+var debugOutput atomic.Bool
+
+// SetDebugOutput makes SprintPublic return the same output as SprintDebug,
+// e.g. in development, so it's the single place deciding whether
+// stack traces are shown to users of an API. It's off by default.
+func SetDebugOutput(enabled bool) {
+	debugOutput.Store(enabled)
+}
+
+// SprintPublic returns output of err, which is safe to present to end users
+// of an API: the message and the code of err, see Code, such as
+// "user not found (code E404)". Stack trace, source, paths and fields
+// are never included, unless debug output is turned on by SetDebugOutput.
+//
+// Message is rendered as is, so it must not contain internal details,
+// wrap such errors into an error with a public message.
+// It's translated by WithMessageTranslator, if any.
+func SprintPublic(err error, options ...PrintOption) string {
+	if err == nil {
+		return ""
+	}
+	if debugOutput.Load() {
+		return SprintDebug(err)
+	}
+	o := mergePrintOptions(options)
+	code := Code(err)
+	message := err.Error()
+	if o.translator != nil {
+		message = o.translator(code, message, Fields(err))
+	}
+	if code != "" {
+		return fmt.Sprintf("%s (code %s)", message, code)
+	}
+	return message
+}
+
+// WithMessageTranslator makes SprintPublic translate messages of errors
+// for end users, e.g. to their language by code of the error, see Code,
+// with fields of the error, see Fields, as arguments of the translation.
+// Message is the original one if translate returns it.
+//
+// Other renderers keep the original message for developers.
+func WithMessageTranslator(translate func(code, message string, fields map[string]interface{}) string) PrintOption {
+	return func(o *printOptions) {
+		o.translator = translate
+	}
+}
+
+// SprintDebug returns output of err with everything known about it:
+// the message, fields, stack trace with source by print options,
+// see SprintSource, and the code, see Code.
+// Stack trace of a traced error wrapped by another error is rendered as well.
+func SprintDebug(err error) string {
+	if err == nil {
+		return ""
+	}
+	traced := err
+	if _, ok := err.(Error); !ok {
+		if e, ok := AsError(err); ok {
+			traced = CustomError(err, RawFrames(e))
+		}
+	}
+	output := sprint(traced, nil, false, 0)
+	if code := Code(err); code != "" {
+		output += "\nCode: " + code
+	}
+	return output
+}
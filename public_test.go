@@ -0,0 +1,82 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintPublic(t *testing.T) {
+	traced := tracerr.Wrap(&codeError{code: "E404"})
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{errors.New("some error"), "some error"},
+		{tracerr.New("some error"), "some error"},
+		{traced, "code E404 (code E404)"},
+		{fmt.Errorf("load user: %w", traced), "load user: code E404 (code E404)"},
+	}
+	for i, c := range cases {
+		if output := tracerr.SprintPublic(c.err); output != c.expected {
+			t.Errorf("case #%d: tracerr.SprintPublic(%v) = %#v; want %#v", i, c.err, output, c.expected)
+		}
+	}
+}
+
+func TestSprintDebug(t *testing.T) {
+	traced := tracerr.Wrap(&codeError{code: "E404"})
+	err := fmt.Errorf("load user: %w", traced)
+	output := tracerr.SprintDebug(err)
+	for _, part := range []string{"load user: code E404", "TestSprintDebug()", "public_test.go", "Code: E404"} {
+		if !strings.Contains(output, part) {
+			t.Errorf("tracerr.SprintDebug(err) = %#v; want to contain %#v", output, part)
+		}
+	}
+	if output := tracerr.SprintDebug(nil); output != "" {
+		t.Errorf("tracerr.SprintDebug(nil) = %#v; want empty", output)
+	}
+}
+
+func TestSetDebugOutput(t *testing.T) {
+	err := tracerr.New("some error")
+	tracerr.SetDebugOutput(true)
+	defer tracerr.SetDebugOutput(false)
+	if output := tracerr.SprintPublic(err); output != tracerr.SprintDebug(err) {
+		t.Errorf("tracerr.SprintPublic(err) = %#v; want debug output %#v", output, tracerr.SprintDebug(err))
+	}
+	tracerr.SetDebugOutput(false)
+	if output := tracerr.SprintPublic(err); output != "some error" {
+		t.Errorf("tracerr.SprintPublic(err) = %#v; want %#v", output, "some error")
+	}
+}
+
+func TestWithMessageTranslator(t *testing.T) {
+	translate := tracerr.WithMessageTranslator(func(code, message string, fields map[string]interface{}) string {
+		if code == "E404" {
+			return fmt.Sprintf("utilisateur %v introuvable", fields["user"])
+		}
+		return message
+	})
+	traced := tracerr.WrapFields(tracerr.Wrap(&codeError{code: "E404"}), map[string]interface{}{"user": 42})
+	if output := tracerr.SprintPublic(traced, translate); output != "utilisateur 42 introuvable (code E404)" {
+		t.Errorf("tracerr.SprintPublic(err) = %#v; want translated message", output)
+	}
+	if output := tracerr.SprintPublic(errors.New("some error"), translate); output != "some error" {
+		t.Errorf("tracerr.SprintPublic(err) = %#v; want original message", output)
+	}
+
+	// Internal output keeps the original message.
+	tracerr.SetPrintOptions(translate)
+	defer tracerr.SetPrintOptions()
+	if output := tracerr.SprintPublic(traced); output != "utilisateur 42 introuvable (code E404)" {
+		t.Errorf("tracerr.SprintPublic(err) = %#v; want translated message", output)
+	}
+	if output := tracerr.SprintDebug(traced); !strings.HasPrefix(output, "code E404\n") {
+		t.Errorf("tracerr.SprintDebug(err) = %#v; want original message", output)
+	}
+}
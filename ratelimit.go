@@ -0,0 +1,90 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimitedPrinter is a Printer, which writes full output with source
+// fragments of errors with the same fingerprint at most once per interval,
+// other errors are written as a single line summary by SprintCompact.
+// So a flapping error can't saturate logs with repeated source fragments.
+//
+// It's safe for concurrent use.
+type RateLimitedPrinter struct {
+	w        io.Writer
+	interval time.Duration
+	nums     []int
+	mutex    sync.Mutex
+	// printed is a time of the last full output per fingerprint.
+	printed map[string]time.Time
+	// equaler matches errors instead of fingerprints, if it's set.
+	equaler Equaler
+	// errs are errors of printed fingerprints, if equaler is set.
+	errs map[string]Error
+}
+
+// NewRateLimitedPrinter creates RateLimitedPrinter writing to w,
+// nums is a number of source lines by the same rules as in PrintSource.
+func NewRateLimitedPrinter(w io.Writer, interval time.Duration, nums ...int) *RateLimitedPrinter {
+	return &RateLimitedPrinter{
+		w:        w,
+		interval: interval,
+		nums:     nums,
+		printed:  map[string]time.Time{},
+		errs:     map[string]Error{},
+	}
+}
+
+// SetEqualer makes errors equal by eq repeats, e.g. by EqualMessageAndCode,
+// instead of errors with the same fingerprint. Nil eq restores fingerprints.
+func (p *RateLimitedPrinter) SetEqualer(eq Equaler) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.equaler = eq
+	clear(p.errs)
+}
+
+// Print writes full output of err or its summary
+// if an error with the same fingerprint was printed within interval.
+func (p *RateLimitedPrinter) Print(err Error) {
+	fingerprint := Fingerprint(err)
+	now := time.Now()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.equaler != nil {
+		fingerprint = p.equalKey(fingerprint, err)
+	}
+	if last, ok := p.printed[fingerprint]; ok && now.Sub(last) < p.interval {
+		fmt.Fprintf(p.w, "%s (repeated, fingerprint=%s)\n", SprintCompact(err), fingerprint)
+		return
+	}
+	// Forget expired fingerprints, so they don't pile up.
+	for fp, last := range p.printed {
+		if now.Sub(last) >= p.interval {
+			delete(p.printed, fp)
+			delete(p.errs, fp)
+		}
+	}
+	p.printed[fingerprint] = now
+	if p.equaler != nil {
+		p.errs[fingerprint] = err
+	}
+	FprintSource(p.w, err, p.nums...)
+}
+
+// equalKey returns fingerprint of a printed error equal to err,
+// or a fingerprint not taken by other printed errors.
+func (p *RateLimitedPrinter) equalKey(fingerprint string, err Error) string {
+	for key, printed := range p.errs {
+		if p.equaler.Equal(printed, err) {
+			return key
+		}
+	}
+	return uniqueKey(fingerprint, func(key string) bool {
+		_, ok := p.printed[key]
+		return ok
+	})
+}
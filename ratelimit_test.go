@@ -0,0 +1,53 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRateLimitedPrinter(t *testing.T) {
+	first := tracerr.CustomError(errors.New("first"), []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+	})
+	second := tracerr.CustomError(errors.New("second"), []tracerr.Frame{
+		tracerr.NewFrame("main.write", "/src/main.go", 50),
+	})
+	var buf bytes.Buffer
+	p := tracerr.NewRateLimitedPrinter(&buf, 50*time.Millisecond, 0)
+	p.Print(first)
+	p.Print(second)
+	p.Print(first)
+	expected := "first\n/src/main.go:42 main.read()\n" +
+		"second\n/src/main.go:50 main.write()\n" +
+		`err="first" at=main.read file=/src/main.go:42 (repeated, fingerprint=` + tracerr.Fingerprint(first) + ")\n"
+	if buf.String() != expected {
+		t.Errorf("output = %#v; want %#v", buf.String(), expected)
+	}
+
+	buf.Reset()
+	time.Sleep(60 * time.Millisecond)
+	p.Print(first)
+	expected = "first\n/src/main.go:42 main.read()\n"
+	if buf.String() != expected {
+		t.Errorf("output after interval = %#v; want %#v", buf.String(), expected)
+	}
+}
+
+func TestRateLimitedPrinterSetEqualer(t *testing.T) {
+	first := tracerr.CustomError(errors.New("failed"), diffFrames("read"))
+	second := tracerr.CustomError(errors.New("failed"), diffFrames("write"))
+	var buf bytes.Buffer
+	p := tracerr.NewRateLimitedPrinter(&buf, time.Minute, 0)
+	p.SetEqualer(tracerr.EqualerFunc(tracerr.EqualMessageAndCode))
+	p.Print(first)
+	p.Print(second)
+	expected := "failed\n/src/main.go:10 main.read()\n" +
+		`err="failed" at=main.write file=/src/main.go:10 (repeated, fingerprint=` + tracerr.Fingerprint(first) + ")\n"
+	if buf.String() != expected {
+		t.Errorf("output = %#v; want %#v", buf.String(), expected)
+	}
+}
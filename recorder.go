@@ -0,0 +1,173 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordedError is an error kept by Recorder.
+type RecordedError struct {
+	// ID is a sequence number of the error in Recorder starting from 1.
+	ID uint64
+	// Time is when the error was added.
+	Time time.Time
+	// Err is the error.
+	Err Error
+}
+
+// Recorder keeps the last added errors with timestamps,
+// so recent errors of a long-running process can be inspected.
+// Feed it with captured errors by WithRecorder.
+//
+// Recorder is an http.Handler, so it can be served at a debug endpoint.
+// It's safe for concurrent use.
+type Recorder struct {
+	mutex sync.Mutex
+	// errors is a ring buffer of count errors starting at start.
+	errors []RecordedError
+	sizes  []int
+	start  int
+	count  int
+	size   int
+	lastID uint64
+	retain retention
+}
+
+// NewRecorder creates Recorder keeping the last n errors,
+// opts bound them further, see WithTTL and WithMaxBytes.
+func NewRecorder(n int, opts ...RetentionOption) *Recorder {
+	if n < 1 {
+		n = 1
+	}
+	return &Recorder{
+		errors: make([]RecordedError, n),
+		sizes:  make([]int, n),
+		retain: newRetention(opts),
+	}
+}
+
+// WithRecorder adds every captured error to r.
+// Errors skipped by sampling are not added.
+func WithRecorder(r *Recorder) Option {
+	return WithOnCapture(r.Add)
+}
+
+// Add adds err, the oldest errors are dropped if Recorder is full
+// or over its memory bound.
+func (r *Recorder) Add(err Error) {
+	now := time.Now()
+	size := errorSize(err)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.expire(now)
+	if r.count == len(r.errors) {
+		r.dropOldest()
+	}
+	r.lastID++
+	i := (r.start + r.count) % len(r.errors)
+	r.errors[i] = RecordedError{ID: r.lastID, Time: now, Err: err}
+	r.sizes[i] = size
+	r.count++
+	r.size += size
+	captureStats.recorderBytes.Add(int64(size))
+	for r.count > 1 && r.retain.exceeded(r.size) {
+		r.dropOldest()
+	}
+}
+
+// expire drops errors expired at now.
+func (r *Recorder) expire(now time.Time) {
+	for r.count > 0 && r.retain.expired(r.errors[r.start].Time, now) {
+		r.dropOldest()
+	}
+}
+
+func (r *Recorder) dropOldest() {
+	r.size -= r.sizes[r.start]
+	captureStats.recorderBytes.Add(-int64(r.sizes[r.start]))
+	r.errors[r.start] = RecordedError{}
+	r.start = (r.start + 1) % len(r.errors)
+	r.count--
+}
+
+// Errors returns kept errors from the oldest to the newest.
+func (r *Recorder) Errors() []RecordedError {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.expire(time.Now())
+	errs := make([]RecordedError, 0, r.count)
+	for i := 0; i < r.count; i++ {
+		errs = append(errs, r.errors[(r.start+i)%len(r.errors)])
+	}
+	return errs
+}
+
+// Get returns kept error by its ID and reports whether it's found.
+func (r *Recorder) Get(id uint64) (RecordedError, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.expire(time.Now())
+	for _, e := range r.errors {
+		if e.Err != nil && e.ID == id {
+			return e, true
+		}
+	}
+	return RecordedError{}, false
+}
+
+// Reset drops all kept errors, IDs of new errors continue the sequence.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	clear(r.errors)
+	captureStats.recorderBytes.Add(-int64(r.size))
+	r.start = 0
+	r.count = 0
+	r.size = 0
+}
+
+// recordedErrorJSON is a JSON form of RecordedError served by Recorder.
+type recordedErrorJSON struct {
+	ID           uint64        `json:"id"`
+	Time         time.Time     `json:"time"`
+	Fingerprint  string        `json:"fingerprint"`
+	Error        *TreeNode     `json:"error"`
+	Environment  *Environment  `json:"environment,omitempty"`
+	RuntimeStats *RuntimeStats `json:"runtimeStats,omitempty"`
+}
+
+func newRecordedErrorJSON(e RecordedError) recordedErrorJSON {
+	env, _ := Env(e.Err)
+	stats, _ := Stats(e.Err)
+	return recordedErrorJSON{
+		ID:           e.ID,
+		Time:         e.Time,
+		Fingerprint:  Fingerprint(e.Err),
+		Error:        Tree(e.Err),
+		Environment:  env,
+		RuntimeStats: stats,
+	}
+}
+
+// ServeHTTP writes kept errors from the newest to the oldest as JSON array
+// of objects with id, time, fingerprint, error tree, see Tree,
+// environment, see WithEnvironment, and runtime stats, see WithRuntimeStats.
+// Frames follow options set by SetPrintOptions.
+//
+// See DebugHandler for a browsable endpoint.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	recorded := r.Errors()
+	list := make([]recordedErrorJSON, len(recorded))
+	for i, e := range recorded {
+		list[len(list)-1-i] = newRecordedErrorJSON(e)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
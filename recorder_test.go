@@ -0,0 +1,60 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRecorder(t *testing.T) {
+	r := tracerr.NewRecorder(2)
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithRecorder(r),
+	)
+	if errs := r.Errors(); len(errs) != 0 {
+		t.Errorf("r.Errors() = %v; want empty", errs)
+	}
+	start := time.Now()
+	tr.New("first")
+	second := tr.New("second")
+	third := tr.New("third")
+
+	errs := r.Errors()
+	if len(errs) != 2 || errs[0].Err != second || errs[1].Err != third {
+		t.Fatalf("r.Errors() = %v; want second and third errors", errs)
+	}
+	for i, e := range errs {
+		if e.Time.Before(start) || e.Time.After(time.Now()) {
+			t.Errorf("r.Errors()[%d].Time = %v; want time of capture", i, e.Time)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Content-Type = %#v; want %#v", contentType, "application/json")
+	}
+	var served []struct {
+		Fingerprint string            `json:"fingerprint"`
+		Error       *tracerr.TreeNode `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", w.Body.Bytes(), err)
+	}
+	if len(served) != 2 || served[0].Error.Message != "third" || served[1].Error.Message != "second" {
+		t.Fatalf("served %s; want third and second errors", w.Body.Bytes())
+	}
+	if served[0].Fingerprint != tracerr.Fingerprint(third) || len(served[0].Error.Frames) == 0 {
+		t.Errorf("served[0] = %#v; want fingerprint and frames", served[0])
+	}
+
+	r.Reset()
+	if errs := r.Errors(); len(errs) != 0 {
+		t.Errorf("r.Errors() after r.Reset() = %v; want empty", errs)
+	}
+}
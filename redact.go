@@ -0,0 +1,114 @@
+package tracerr
+
+import (
+	"regexp"
+	"sync/atomic"
+)
+
+// Redactor rewrites a frame before it's printed or serialized,
+// it returns false to drop the frame.
+type Redactor func(frame Frame) (Frame, bool)
+
+// WithRedactor adds redactor applied to each frame of output of printers.
+// Redactors are applied in order, source files are read by redacted paths.
+// Serializers and reporters apply redactors set by SetFrameRedactors instead.
+func WithRedactor(redactor Redactor) PrintOption {
+	return func(o *printOptions) {
+		// Redactors may be shared with options set by SetPrintOptions.
+		redactors := o.redactors[:len(o.redactors):len(o.redactors)]
+		o.redactors = append(redactors, redactor)
+	}
+}
+
+// RedactPaths replaces parts of frame paths matching pattern with replacement,
+// which may contain references like $1, see regexp.Regexp.ReplaceAllString.
+func RedactPaths(pattern *regexp.Regexp, replacement string) Redactor {
+	return func(frame Frame) (Frame, bool) {
+		frame.Path = pattern.ReplaceAllString(frame.Path, replacement)
+		return frame, true
+	}
+}
+
+// DropFrames drops frames, which path or function name matches pattern.
+func DropFrames(pattern *regexp.Regexp) Redactor {
+	return func(frame Frame) (Frame, bool) {
+		if pattern.MatchString(frame.Path) || pattern.MatchString(frame.Func) {
+			return frame, false
+		}
+		return frame, true
+	}
+}
+
+// frameRedactors are set by SetFrameRedactors.
+var frameRedactors atomic.Pointer[[]Redactor]
+
+// SetFrameRedactors sets redactors applied in order to frames of errors
+// by the same serializers and reporters as scrubbers of SetMessageScrubbers.
+// Call it with no redactors to turn it off.
+func SetFrameRedactors(redactors ...Redactor) {
+	frameRedactors.Store(&redactors)
+}
+
+// RedactStackTrace returns copy of stack trace of err with redactors set
+// by SetFrameRedactors applied, so other serializers can apply them as well.
+func RedactStackTrace(err error) []Frame {
+	e, ok := err.(Error)
+	if !ok {
+		return nil
+	}
+	return RedactFrames(RawFrames(e), currentFrameRedactors()...)
+}
+
+// redactStackTrace returns frames with redactors set by SetFrameRedactors
+// applied, frames are returned as is if there are no redactors.
+func redactStackTrace(frames []Frame) []Frame {
+	redactors := currentFrameRedactors()
+	if len(redactors) == 0 {
+		return frames
+	}
+	return RedactFrames(frames, redactors...)
+}
+
+func currentFrameRedactors() []Redactor {
+	if redactors := frameRedactors.Load(); redactors != nil {
+		return *redactors
+	}
+	return nil
+}
+
+// RedactFrames returns copy of frames with redactors applied.
+func RedactFrames(frames []Frame, redactors ...Redactor) []Frame {
+	if frames == nil {
+		return nil
+	}
+	redacted := make([]Frame, 0, len(frames))
+	for _, frame := range frames {
+		keep := true
+		for _, redactor := range redactors {
+			if frame, keep = redactor(frame); !keep {
+				break
+			}
+		}
+		if keep {
+			redacted = append(redacted, frame)
+		}
+	}
+	return redacted
+}
+
+// frames returns stack trace of e prepared for output.
+func (o *printOptions) frames(e Error) []Frame {
+	redactors := o.redactors
+	if config := currentFrameConfig(); config != nil && len(config.Hide) > 0 {
+		// Frames are hidden by their original paths.
+		hide := func(frame Frame) (Frame, bool) {
+			return frame, !config.hidden(frame)
+		}
+		redactors = append([]Redactor{hide}, o.redactors...)
+	}
+	frames := RedactFrames(o.genericFrames(RawFrames(e)), redactors...)
+	if o.maxFrames > 0 && len(frames) > o.maxFrames {
+		frames = frames[:o.maxFrames]
+	}
+	return frames
+}
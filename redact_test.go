@@ -0,0 +1,115 @@
+package tracerr_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithRedactor(t *testing.T) {
+	tracerr.SetPrintOptions(
+		tracerr.WithRedactor(tracerr.RedactPaths(regexp.MustCompile(`^/home/[^/]+/`), "~/")),
+		tracerr.WithRedactor(tracerr.DropFrames(regexp.MustCompile(`internal\.corp`))),
+	)
+	defer tracerr.SetPrintOptions()
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 42,
+				Path: "/home/john/app/main.go",
+			},
+			{
+				Func: "internal.corp/secret.Bar",
+				Line: 43,
+				Path: "/home/john/secret/bar.go",
+			},
+			{
+				Func: "main.main",
+				Line: 44,
+				Path: "/opt/app/main.go",
+			},
+		},
+	)
+	output := tracerr.Sprint(err)
+	expectedRows := []string{
+		"some error",
+		"~/app/main.go:42 main.Foo()",
+		"/opt/app/main.go:44 main.main()",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	if frames := tracerr.RedactFrames(nil, tracerr.DropFrames(regexp.MustCompile(`.`))); frames != nil {
+		t.Errorf("tracerr.RedactFrames(nil) = %#v; want nil", frames)
+	}
+
+	frames := err.StackTrace()
+	copied := tracerr.RedactFrames(frames)
+	copied[0].Func = "main.Changed"
+	if frames[0].Func != "main.Foo" {
+		t.Errorf("tracerr.RedactFrames(frames) returned frames instead of copy")
+	}
+}
+
+func TestSetFrameRedactors(t *testing.T) {
+	tracerr.SetFrameRedactors(
+		tracerr.RedactPaths(regexp.MustCompile(`^/home/[^/]+/`), "~/"),
+		tracerr.DropFrames(regexp.MustCompile(`internal\.corp`)),
+	)
+	defer tracerr.SetFrameRedactors()
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{Func: "main.Foo", Line: 42, Path: "/home/john/app/main.go"},
+			{Func: "internal.corp/secret.Bar", Line: 43, Path: "/home/john/secret/bar.go"},
+		},
+	)
+	expected := []tracerr.Frame{{Func: "main.Foo", Line: 42, Path: "~/app/main.go"}}
+	if frames := tracerr.RedactStackTrace(err); !reflect.DeepEqual(frames, expected) {
+		t.Errorf("tracerr.RedactStackTrace(err) = %#v; want %#v", frames, expected)
+	}
+	if frames := tracerr.RedactStackTrace(errors.New("some error")); frames != nil {
+		t.Errorf("tracerr.RedactStackTrace(err) = %#v; want nil", frames)
+	}
+
+	// Printers keep frames as is.
+	if output := tracerr.Sprint(err); !strings.Contains(output, "/home/john/secret/bar.go") {
+		t.Errorf("tracerr.Sprint(err) = %#v; want unredacted frames", output)
+	}
+
+	data, encodeErr := tracerr.MarshalBinary(err)
+	if encodeErr != nil {
+		t.Fatalf("tracerr.MarshalBinary(err) error = %#v; want nil", encodeErr)
+	}
+	decoded, decodeErr := tracerr.UnmarshalBinary(data)
+	if decodeErr != nil {
+		t.Fatalf("tracerr.UnmarshalBinary() error = %#v; want nil", decodeErr)
+	}
+	if frames := decoded.StackTrace(); len(frames) != 1 || frames[0].Path != expected[0].Path {
+		t.Errorf("decoded.StackTrace() = %#v; want %#v", frames, expected)
+	}
+
+	node := tracerr.Tree(err)
+	if len(node.Frames) != 1 || !strings.HasPrefix(node.Frames[0], "~/app/main.go:42") {
+		t.Errorf("tracerr.Tree(err).Frames = %#v; want redacted frames", node.Frames)
+	}
+
+	text, _ := err.StackTrace()[1].MarshalText()
+	if string(text) != "<redacted>" {
+		t.Errorf("frame.MarshalText() = %#v; want %#v", string(text), "<redacted>")
+	}
+	text, _ = err.StackTrace()[0].MarshalJSON()
+	if want := `{"func":"main.Foo","path":"~/app/main.go","line":42}`; string(text) != want {
+		t.Errorf("frame.MarshalJSON() = %#v; want %#v", string(text), want)
+	}
+}
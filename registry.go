@@ -0,0 +1,88 @@
+package tracerr
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	registryMutex sync.RWMutex
+	registry      map[string]Tracerr
+	// registered is true if registry isn't empty,
+	// so package functions don't look up the caller otherwise.
+	registered atomic.Bool
+)
+
+// Register makes package functions, such as New, Wrap and Errorf,
+// use Tracerr configured by options, instead of Default, when they are
+// called from packages of module, which is an import path prefix,
+// such as "github.com/mycorp/lib". The longest registered prefix wins.
+//
+// So applications can tune capture of libraries, e.g. skip their helper
+// packages or sample their errors, without forking them:
+//
+//	tracerr.Register("github.com/mycorp/lib", tracerr.WithSampling(0.1))
+//
+// Registering the same module again replaces its options, see Unregister.
+// Calls of tracerr functions by other functions of this package,
+// such as WrapContext, are attributed to their caller.
+func Register(module string, options ...Option) {
+	t := NewTracerr(DefaultFrameCapacity, DefaultFrameSkipCount, options...)
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if registry == nil {
+		registry = make(map[string]Tracerr)
+	}
+	registry[module] = t
+	registered.Store(true)
+}
+
+// Unregister removes options registered for module by Register.
+func Unregister(module string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	delete(registry, module)
+	registered.Store(len(registry) > 0)
+}
+
+// callerTracerr returns Tracerr registered for package of the first caller
+// outside of this package, or Default if there is none.
+func callerTracerr() Tracerr {
+	if !registered.Load() {
+		return Default
+	}
+	var pcs [16]uintptr
+	// Skip runtime.Callers and callerTracerr itself.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		pkg, _, _ := splitFuncName(frame.Function)
+		if pkg != packageName {
+			return registeredTracerr(pkg)
+		}
+		if !more {
+			return Default
+		}
+	}
+}
+
+// registeredTracerr returns Tracerr registered for the longest
+// prefix of pkg, or Default if there is none.
+func registeredTracerr(pkg string) Tracerr {
+	pkg = strings.TrimSuffix(pkg, "_test")
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	for {
+		if t, ok := registry[pkg]; ok {
+			return t
+		}
+		i := strings.LastIndex(pkg, "/")
+		if i < 0 {
+			return Default
+		}
+		pkg = pkg[:i]
+	}
+}
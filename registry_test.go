@@ -0,0 +1,42 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRegister(t *testing.T) {
+	var captured, other []string
+	tracerr.Register("github.com", tracerr.WithOnCapture(func(err tracerr.Error) {
+		other = append(other, err.Error())
+	}))
+	defer tracerr.Unregister("github.com")
+	tracerr.Register("github.com/kadaan/tracerr", tracerr.WithOnCapture(func(err tracerr.Error) {
+		captured = append(captured, err.Error())
+	}))
+
+	err := tracerr.New("some error")
+	tracerr.Errorf("formatted %s", "error")
+	tracerr.WrapContext(context.Background(), errors.New("wrapped error"))
+	if len(err.StackTrace()) == 0 || err.StackTrace()[0].Func != "github.com/kadaan/tracerr_test.TestRegister" {
+		t.Errorf("err.StackTrace() = %#v; want the test first", err.StackTrace())
+	}
+	expected := []string{"some error", "formatted error", "wrapped error"}
+	if len(captured) != len(expected) || captured[0] != expected[0] || captured[1] != expected[1] || captured[2] != expected[2] {
+		t.Errorf("captured = %#v; want %#v", captured, expected)
+	}
+	if len(other) != 0 {
+		t.Errorf("other = %#v; want errors captured by the longest prefix only", other)
+	}
+
+	tracerr.Unregister("github.com/kadaan/tracerr")
+	tracerr.Unregister("github.com")
+	captured = nil
+	tracerr.New("some error")
+	if len(captured) != 0 || len(other) != 0 {
+		t.Errorf("captured = %#v, other = %#v; want none after tracerr.Unregister", captured, other)
+	}
+}
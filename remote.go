@@ -0,0 +1,184 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewRemoteSourceProvider creates source provider, which reads files
+// by provider and, if they are missing, fetches files of the main module
+// from a VCS host.
+//
+// In urlTemplate {revision} is replaced with a VCS revision from build info,
+// or "HEAD" if it's unknown, and {path} with a file path relative to
+// the module root, e.g.
+//
+//	https://raw.githubusercontent.com/foo/bar/{revision}/{path}
+//
+// A path is mapped to the module root by the module path in it,
+// so files are found for builds with -trimpath, in GOPATH
+// and in the module cache, e.g. installed by "go install module@version",
+// where the version is used as the revision. Files of other builds
+// have paths of the build machine without the module path and
+// aren't fetched.
+//
+// Fetched files are cached up to DefaultSourceCacheEntries files
+// and DefaultSourceCacheBytes, failures are cached for a minute.
+// Each request is limited by timeout, files over 8 MiB aren't fetched.
+func NewRemoteSourceProvider(provider SourceProvider, urlTemplate string, timeout time.Duration) SourceProvider {
+	p := &remoteSourceProvider{
+		provider:    provider,
+		urlTemplate: urlTemplate,
+		revision:    "HEAD",
+		client:      &http.Client{Timeout: timeout},
+		files:       newSourceCache[*remoteFile](DefaultSourceCacheEntries, DefaultSourceCacheBytes),
+		pending:     map[string]*remoteFile{},
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		p.module = info.Main.Path
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				p.revision = setting.Value
+			}
+		}
+	}
+	return p
+}
+
+type remoteSourceProvider struct {
+	provider    SourceProvider
+	urlTemplate string
+	module      string
+	revision    string
+	client      *http.Client
+	files       *sourceCache[*remoteFile]
+	// mutex guards pending, which are files being fetched,
+	// so a file is fetched once by concurrent reads.
+	mutex   sync.Mutex
+	pending map[string]*remoteFile
+}
+
+// remoteFailureTTL is a time, for which a failure to fetch a file is cached.
+const remoteFailureTTL = time.Minute
+
+// remoteMaxBytes is a maximum size of a fetched file.
+const remoteMaxBytes = 8 << 20
+
+type remoteFile struct {
+	content []byte
+	err     error
+	fetched time.Time
+	// done is closed when the file is fetched.
+	done chan struct{}
+}
+
+// expired returns true if fetching of the file should be retried.
+func (f *remoteFile) expired() bool {
+	return f.err != nil && time.Since(f.fetched) > remoteFailureTTL
+}
+
+func (p *remoteSourceProvider) ReadFile(path string) ([]byte, error) {
+	b, err := p.provider.ReadFile(path)
+	if err == nil {
+		return b, nil
+	}
+	rel, revision, ok := p.modulePath(filepath.ToSlash(path))
+	if !ok {
+		return nil, err
+	}
+	url := strings.NewReplacer(
+		"{revision}", revision,
+		"{path}", rel,
+	).Replace(p.urlTemplate)
+
+	p.mutex.Lock()
+	if file, ok := p.files.get(url); ok && !file.expired() {
+		p.mutex.Unlock()
+		return file.content, file.err
+	}
+	if file, ok := p.pending[url]; ok {
+		p.mutex.Unlock()
+		<-file.done
+		return file.content, file.err
+	}
+	file := &remoteFile{done: make(chan struct{})}
+	p.pending[url] = file
+	p.mutex.Unlock()
+
+	file.content, file.err = p.fetch(url)
+	file.fetched = time.Now()
+	p.mutex.Lock()
+	delete(p.pending, url)
+	p.files.add(url, file, len(file.content))
+	p.mutex.Unlock()
+	close(file.done)
+	return file.content, file.err
+}
+
+func (p *remoteSourceProvider) ReadDir(path string) ([]string, error) {
+	if r, ok := p.provider.(dirReader); ok {
+		return r.ReadDir(path)
+	}
+	return nil, errNoDir
+}
+
+// modulePath returns path relative to the main module root
+// and revision of the file.
+func (p *remoteSourceProvider) modulePath(path string) (rel, revision string, ok bool) {
+	if p.module == "" {
+		return "", "", false
+	}
+	// Path in the module cache or of -trimpath build of a module version.
+	for _, module := range []string{escapeModulePath(p.module), p.module} {
+		i := strings.Index(path, module+"@")
+		if i < 0 || (i > 0 && path[i-1] != '/') {
+			continue
+		}
+		if version, rel, ok := strings.Cut(path[i+len(module)+1:], "/"); ok {
+			return rel, moduleRevision(version), true
+		}
+	}
+	i := strings.Index(path, p.module+"/")
+	if i < 0 || (i > 0 && path[i-1] != '/') {
+		return "", "", false
+	}
+	return path[i+len(p.module)+1:], p.revision, true
+}
+
+// moduleRevision returns VCS revision of a module version,
+// which is a commit hash for pseudo-versions and the version otherwise.
+func moduleRevision(version string) string {
+	version = strings.TrimSuffix(version, "+incompatible")
+	parts := strings.Split(version, "-")
+	if len(parts) >= 3 {
+		if hash := parts[len(parts)-1]; len(hash) == 12 {
+			return hash
+		}
+	}
+	return version
+}
+
+func (p *remoteSourceProvider) fetch(url string) ([]byte, error) {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tracerr: fetching %s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, remoteMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > remoteMaxBytes {
+		return nil, fmt.Errorf("tracerr: fetching %s: file is over %d bytes", url, remoteMaxBytes)
+	}
+	return b, nil
+}
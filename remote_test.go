@@ -0,0 +1,115 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRemoteSourceProvider(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.URL.Path != "/HEAD/pkg/foo.go" && r.URL.Path != "/v0.3.0/pkg/foo.go" && r.URL.Path != "/abcdef123456/pkg/foo.go" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("package pkg"))
+	}))
+	defer server.Close()
+
+	provider := tracerr.NewRemoteSourceProvider(
+		tracerr.NewNopSourceProvider(),
+		server.URL+"/{revision}/{path}",
+		time.Second,
+	)
+	for i := 0; i < 2; i++ {
+		b, err := provider.ReadFile("github.com/kadaan/tracerr/pkg/foo.go")
+		if err != nil || string(b) != "package pkg" {
+			t.Errorf(
+				"provider.ReadFile() = %#v, %#v; want %#v, %#v",
+				string(b), err, "package pkg", nil,
+			)
+		}
+		for _, path := range []string{
+			"/root/go/pkg/mod/github.com/kadaan/tracerr@v0.3.0/pkg/foo.go",
+			"/root/go/pkg/mod/github.com/kadaan/tracerr@v0.0.0-20190315000000-abcdef123456/pkg/foo.go",
+		} {
+			b, err := provider.ReadFile(path)
+			if err != nil || string(b) != "package pkg" {
+				t.Errorf(
+					"provider.ReadFile(%#v) = %#v, %#v; want %#v, %#v",
+					path, string(b), err, "package pkg", nil,
+				)
+			}
+		}
+		if _, err := provider.ReadFile("/builder/github.com/kadaan/tracerr/pkg/bar.go"); err == nil {
+			t.Errorf("provider.ReadFile() error = nil; want error")
+		}
+		if _, err := provider.ReadFile("/src/other/module/foo.go"); err == nil {
+			t.Errorf("provider.ReadFile() error = nil; want error")
+		}
+	}
+	for path, count := range requests {
+		if count != 1 {
+			t.Errorf("requests[%#v] = %#v; want %#v", path, count, 1)
+		}
+	}
+	if len(requests) != 4 {
+		t.Errorf("len(requests) = %#v; want %#v", len(requests), 4)
+	}
+}
+
+func TestRemoteSourceProviderConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/HEAD/pkg/slow.go" {
+			<-release
+		}
+		w.Write([]byte("package pkg"))
+	}))
+	defer server.Close()
+
+	provider := tracerr.NewRemoteSourceProvider(
+		tracerr.NewNopSourceProvider(),
+		server.URL+"/{revision}/{path}",
+		time.Second,
+	)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		provider.ReadFile("github.com/kadaan/tracerr/pkg/slow.go")
+	}()
+	// Another file is read while the slow one is being fetched.
+	if _, err := provider.ReadFile("github.com/kadaan/tracerr/pkg/fast.go"); err != nil {
+		t.Errorf("provider.ReadFile() error = %#v; want %#v", err, nil)
+	}
+	close(release)
+	<-done
+	if requests != 2 {
+		t.Errorf("requests = %#v; want %#v", requests, 2)
+	}
+}
+
+func TestRemoteSourceProviderMaxBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("/"), 8<<20+1))
+	}))
+	defer server.Close()
+
+	provider := tracerr.NewRemoteSourceProvider(
+		tracerr.NewNopSourceProvider(),
+		server.URL+"/{revision}/{path}",
+		time.Second,
+	)
+	if b, err := provider.ReadFile("github.com/kadaan/tracerr/pkg/huge.go"); err == nil {
+		t.Errorf("provider.ReadFile() = %d bytes, nil; want error", len(b))
+	}
+}
@@ -0,0 +1,253 @@
+// Package report converts traced errors to payloads of error trackers,
+// such as Datadog, Rollbar and Bugsnag.
+//
+// Only payload structures are built, so they can be sent
+// by any HTTP client or merged into payloads of vendor libraries.
+// Frames and messages are redacted and scrubbed, see
+// tracerr.SetFrameRedactors and tracerr.SetMessageScrubbers.
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kadaan/tracerr"
+)
+
+// errorClass returns type of the original error, e.g. "*fs.PathError".
+func errorClass(err error) string {
+	return fmt.Sprintf("%T", tracerr.Unwrap(err))
+}
+
+// DatadogError is an error attribute of a log entry
+// recognized by Datadog Error Tracking.
+type DatadogError struct {
+	Kind        string `json:"kind"`
+	Message     string `json:"message"`
+	Stack       string `json:"stack"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Datadog converts err to an error attribute of Datadog log entry,
+// it should be set as "error" attribute, see DatadogAttributes.
+// Stack is formatted the same way as Go panic output.
+// It returns zero value for nil err.
+func Datadog(err error) DatadogError {
+	if err == nil {
+		return DatadogError{}
+	}
+	frames := tracerr.RedactStackTrace(err)
+	rows := make([]string, 0, len(frames)*2)
+	for _, frame := range frames {
+		rows = append(rows, frame.Func+"()", fmt.Sprintf("\t%s:%d", frame.Path, frame.Line))
+	}
+	return DatadogError{
+		Kind:        errorClass(err),
+		Message:     tracerr.ScrubMessage(err.Error()),
+		Stack:       strings.Join(rows, "\n"),
+		Fingerprint: tracerr.Fingerprint(err),
+	}
+}
+
+// DatadogAttributes converts err to attributes of Datadog log entry:
+// fields of err, see tracerr.Fields, "error" attribute, see Datadog,
+// and trace context, see tracerr.Trace, as "dd.trace_id" and "dd.span_id"
+// in Datadog form of the lower 64 bits of IDs, so the entry is connected
+// to its trace. It returns nil for nil err.
+func DatadogAttributes(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	attributes := map[string]interface{}{}
+	for key, value := range tracerr.Fields(err) {
+		attributes[key] = value
+	}
+	attributes["error"] = Datadog(err)
+	if trace, ok := tracerr.Trace(err); ok {
+		dd := map[string]interface{}{}
+		if id, ok := datadogID(trace.TraceID); ok {
+			dd["trace_id"] = id
+		}
+		if id, ok := datadogID(trace.SpanID); ok {
+			dd["span_id"] = id
+		}
+		if len(dd) > 0 {
+			attributes["dd"] = dd
+		}
+	}
+	return attributes
+}
+
+// datadogID returns the lower 64 bits of hex ID as a decimal number.
+func datadogID(id string) (string, bool) {
+	if len(id) > 16 {
+		id = id[len(id)-16:]
+	}
+	n, err := strconv.ParseUint(id, 16, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatUint(n, 10), true
+}
+
+// RollbarData is a data of Rollbar item.
+type RollbarData struct {
+	Environment string      `json:"environment,omitempty"`
+	Level       string      `json:"level"`
+	Language    string      `json:"language"`
+	Fingerprint string      `json:"fingerprint,omitempty"`
+	Body        RollbarBody `json:"body"`
+	// Custom are fields of the error, see tracerr.Fields,
+	// and "trace_id" and "span_id" of its trace context.
+	Custom map[string]interface{} `json:"custom,omitempty"`
+}
+
+// RollbarBody is a body of Rollbar item with a single trace.
+type RollbarBody struct {
+	Trace RollbarTrace `json:"trace"`
+}
+
+// RollbarTrace is a trace of Rollbar item.
+type RollbarTrace struct {
+	Frames    []RollbarFrame   `json:"frames"`
+	Exception RollbarException `json:"exception"`
+}
+
+// RollbarFrame is a frame of Rollbar trace.
+type RollbarFrame struct {
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	Method   string `json:"method"`
+}
+
+// RollbarException is an exception of Rollbar trace.
+type RollbarException struct {
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// Rollbar converts err to a data of Rollbar item of error level,
+// it should be sent as "data" of item.
+// Frames are in Rollbar order, the most recent call goes last.
+// It returns zero value for nil err.
+func Rollbar(err error, environment string) RollbarData {
+	if err == nil {
+		return RollbarData{}
+	}
+	frames := tracerr.RedactStackTrace(err)
+	rollbarFrames := make([]RollbarFrame, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		rollbarFrames = append(rollbarFrames, RollbarFrame{
+			Filename: frames[i].Path,
+			Lineno:   frames[i].Line,
+			Method:   frames[i].Func,
+		})
+	}
+	return RollbarData{
+		Environment: environment,
+		Level:       "error",
+		Language:    "go",
+		Fingerprint: tracerr.Fingerprint(err),
+		Body: RollbarBody{
+			Trace: RollbarTrace{
+				Frames: rollbarFrames,
+				Exception: RollbarException{
+					Class:   errorClass(err),
+					Message: tracerr.ScrubMessage(err.Error()),
+				},
+			},
+		},
+		Custom: rollbarCustom(err),
+	}
+}
+
+func rollbarCustom(err error) map[string]interface{} {
+	custom := tracerr.Fields(err)
+	if trace, ok := tracerr.Trace(err); ok {
+		if custom == nil {
+			custom = map[string]interface{}{}
+		}
+		custom["trace_id"] = trace.TraceID
+		custom["span_id"] = trace.SpanID
+	}
+	return custom
+}
+
+// BugsnagEvent is an event of Bugsnag notification.
+type BugsnagEvent struct {
+	Exceptions   []BugsnagException `json:"exceptions"`
+	Severity     string             `json:"severity"`
+	GroupingHash string             `json:"groupingHash,omitempty"`
+	// MetaData has fields of the error, see tracerr.Fields, in "fields" tab.
+	MetaData map[string]map[string]interface{} `json:"metaData,omitempty"`
+	// Correlation is trace context of the error, see tracerr.Trace.
+	Correlation *BugsnagCorrelation `json:"correlation,omitempty"`
+}
+
+// BugsnagCorrelation connects Bugsnag event to a trace.
+type BugsnagCorrelation struct {
+	TraceID string `json:"traceId"`
+	SpanID  string `json:"spanId"`
+}
+
+// BugsnagException is an exception of Bugsnag event.
+type BugsnagException struct {
+	ErrorClass string         `json:"errorClass"`
+	Message    string         `json:"message"`
+	Stacktrace []BugsnagFrame `json:"stacktrace"`
+}
+
+// BugsnagFrame is a frame of Bugsnag stack trace.
+type BugsnagFrame struct {
+	File       string `json:"file"`
+	LineNumber int    `json:"lineNumber"`
+	Method     string `json:"method"`
+	InProject  bool   `json:"inProject"`
+}
+
+// Bugsnag converts err to an event of Bugsnag notification of error severity,
+// frames of packages with projectPackages prefixes are marked as in project.
+// It returns zero value for nil err.
+func Bugsnag(err error, projectPackages ...string) BugsnagEvent {
+	if err == nil {
+		return BugsnagEvent{}
+	}
+	frames := tracerr.RedactStackTrace(err)
+	stacktrace := make([]BugsnagFrame, 0, len(frames))
+	for _, frame := range frames {
+		stacktrace = append(stacktrace, BugsnagFrame{
+			File:       frame.Path,
+			LineNumber: frame.Line,
+			Method:     frame.Func,
+			InProject:  inProject(frame, projectPackages),
+		})
+	}
+	event := BugsnagEvent{
+		Exceptions: []BugsnagException{
+			{
+				ErrorClass: errorClass(err),
+				Message:    tracerr.ScrubMessage(err.Error()),
+				Stacktrace: stacktrace,
+			},
+		},
+		Severity:     "error",
+		GroupingHash: tracerr.Fingerprint(err),
+	}
+	if fields := tracerr.Fields(err); fields != nil {
+		event.MetaData = map[string]map[string]interface{}{"fields": fields}
+	}
+	if trace, ok := tracerr.Trace(err); ok {
+		event.Correlation = &BugsnagCorrelation{TraceID: trace.TraceID, SpanID: trace.SpanID}
+	}
+	return event
+}
+
+func inProject(frame tracerr.Frame, projectPackages []string) bool {
+	for _, pkg := range projectPackages {
+		if frame.Package == pkg || strings.HasPrefix(frame.Package, pkg+"/") {
+			return true
+		}
+	}
+	return false
+}
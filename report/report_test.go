@@ -0,0 +1,162 @@
+package report_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/report"
+)
+
+var testError = tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+	tracerr.NewFrame("github.com/foo/bar.(*Store).Get", "/src/bar/store.go", 42),
+	tracerr.NewFrame("main.main", "/src/main.go", 10),
+})
+
+func TestDatadog(t *testing.T) {
+	expected := report.DatadogError{
+		Kind:        "*errors.errorString",
+		Message:     "some error",
+		Stack:       "github.com/foo/bar.(*Store).Get()\n\t/src/bar/store.go:42\nmain.main()\n\t/src/main.go:10",
+		Fingerprint: tracerr.Fingerprint(testError),
+	}
+	if payload := report.Datadog(testError); payload != expected {
+		t.Errorf("report.Datadog(err) = %#v; want %#v", payload, expected)
+	}
+}
+
+var testTrace = tracerr.TraceContext{
+	TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+	SpanID:  "00f067aa0ba902b7",
+}
+
+var testContextError = tracerr.WrapTrace(
+	tracerr.WrapFields(testError, map[string]interface{}{"user": "u1"}),
+	testTrace,
+)
+
+func TestDatadogAttributes(t *testing.T) {
+	attributes := report.DatadogAttributes(testContextError)
+	expected := map[string]interface{}{
+		"user":  "u1",
+		"error": report.Datadog(testContextError),
+		"dd": map[string]interface{}{
+			"trace_id": "11803532876627986230",
+			"span_id":  "67667974448284343",
+		},
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("report.DatadogAttributes(err) = %#v; want %#v", attributes, expected)
+	}
+}
+
+func TestNilError(t *testing.T) {
+	if payload := report.Datadog(nil); payload != (report.DatadogError{}) {
+		t.Errorf("report.Datadog(nil) = %#v; want zero value", payload)
+	}
+	if attributes := report.DatadogAttributes(nil); attributes != nil {
+		t.Errorf("report.DatadogAttributes(nil) = %#v; want nil", attributes)
+	}
+	if payload := report.Rollbar(nil, "production"); !reflect.DeepEqual(payload, report.RollbarData{}) {
+		t.Errorf("report.Rollbar(nil) = %#v; want zero value", payload)
+	}
+	if payload := report.Bugsnag(nil); !reflect.DeepEqual(payload, report.BugsnagEvent{}) {
+		t.Errorf("report.Bugsnag(nil) = %#v; want zero value", payload)
+	}
+}
+
+func TestRollbarContext(t *testing.T) {
+	expected := map[string]interface{}{
+		"user":     "u1",
+		"trace_id": testTrace.TraceID,
+		"span_id":  testTrace.SpanID,
+	}
+	if custom := report.Rollbar(testContextError, "").Custom; !reflect.DeepEqual(custom, expected) {
+		t.Errorf("report.Rollbar(err).Custom = %#v; want %#v", custom, expected)
+	}
+}
+
+func TestBugsnagContext(t *testing.T) {
+	event := report.Bugsnag(testContextError)
+	expected := map[string]map[string]interface{}{"fields": {"user": "u1"}}
+	if !reflect.DeepEqual(event.MetaData, expected) {
+		t.Errorf("report.Bugsnag(err).MetaData = %#v; want %#v", event.MetaData, expected)
+	}
+	correlation := &report.BugsnagCorrelation{TraceID: testTrace.TraceID, SpanID: testTrace.SpanID}
+	if !reflect.DeepEqual(event.Correlation, correlation) {
+		t.Errorf("report.Bugsnag(err).Correlation = %#v; want %#v", event.Correlation, correlation)
+	}
+}
+
+func TestRollbar(t *testing.T) {
+	payload := report.Rollbar(testError, "production")
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"environment": "production",
+		"level":       "error",
+		"language":    "go",
+		"fingerprint": tracerr.Fingerprint(testError),
+		"body": map[string]interface{}{
+			"trace": map[string]interface{}{
+				"frames": []interface{}{
+					map[string]interface{}{"filename": "/src/main.go", "lineno": 10.0, "method": "main.main"},
+					map[string]interface{}{"filename": "/src/bar/store.go", "lineno": 42.0, "method": "github.com/foo/bar.(*Store).Get"},
+				},
+				"exception": map[string]interface{}{
+					"class":   "*errors.errorString",
+					"message": "some error",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("report.Rollbar(err) = %#v; want %#v", data, expected)
+	}
+}
+
+func TestBugsnag(t *testing.T) {
+	expected := report.BugsnagEvent{
+		Exceptions: []report.BugsnagException{
+			{
+				ErrorClass: "*errors.errorString",
+				Message:    "some error",
+				Stacktrace: []report.BugsnagFrame{
+					{File: "/src/bar/store.go", LineNumber: 42, Method: "github.com/foo/bar.(*Store).Get", InProject: true},
+					{File: "/src/main.go", LineNumber: 10, Method: "main.main", InProject: false},
+				},
+			},
+		},
+		Severity:     "error",
+		GroupingHash: tracerr.Fingerprint(testError),
+	}
+	if payload := report.Bugsnag(testError, "github.com/foo"); !reflect.DeepEqual(payload, expected) {
+		t.Errorf("report.Bugsnag(err) = %#v; want %#v", payload, expected)
+	}
+}
+
+func TestRedactedFrames(t *testing.T) {
+	tracerr.SetFrameRedactors(tracerr.DropFrames(regexp.MustCompile(`^main\.`)))
+	defer tracerr.SetFrameRedactors()
+
+	stacktrace := report.Bugsnag(testError).Exceptions[0].Stacktrace
+	if len(stacktrace) != 1 || stacktrace[0].Method != "github.com/foo/bar.(*Store).Get" {
+		t.Errorf("report.Bugsnag(err) stack trace = %#v; want redacted frames", stacktrace)
+	}
+	if frames := report.Rollbar(testError, "").Body.Trace.Frames; len(frames) != 1 {
+		t.Errorf("report.Rollbar(err) frames = %#v; want redacted frames", frames)
+	}
+	if stack := report.Datadog(testError).Stack; strings.Contains(stack, "main.main") {
+		t.Errorf("report.Datadog(err).Stack = %#v; want redacted frames", stack)
+	}
+}
@@ -0,0 +1,64 @@
+package tracerr
+
+import (
+	"time"
+	"unsafe"
+)
+
+// RetentionOption bounds errors retained by Recorder and Aggregator,
+// so a long-running process doesn't grow unbounded.
+type RetentionOption func(*retention)
+
+type retention struct {
+	ttl      time.Duration
+	maxBytes int
+}
+
+func newRetention(opts []RetentionOption) retention {
+	var r retention
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// WithTTL drops errors of Recorder added more than ttl ago,
+// and groups of Aggregator with no errors added within ttl.
+// Expired errors are dropped on the next call of any method.
+func WithTTL(ttl time.Duration) RetentionOption {
+	return func(r *retention) {
+		r.ttl = ttl
+	}
+}
+
+// WithMaxBytes bounds estimated memory of retained errors by n bytes,
+// the oldest errors of Recorder and the least recently added groups
+// of Aggregator are dropped to fit. The newest error is always kept.
+//
+// Estimate counts messages and frames of errors, but not function names
+// and paths, which are shared by errors, and not fields or values
+// of original errors.
+func WithMaxBytes(n int) RetentionOption {
+	return func(r *retention) {
+		r.maxBytes = n
+	}
+}
+
+// expired reports whether an error added at t is expired at now.
+func (r retention) expired(t, now time.Time) bool {
+	return r.ttl > 0 && now.Sub(t) >= r.ttl
+}
+
+// exceeded reports whether size is over the memory bound.
+func (r retention) exceeded(size int) bool {
+	return r.maxBytes > 0 && size > r.maxBytes
+}
+
+// errorSize returns estimated memory of err, see WithMaxBytes.
+func errorSize(err Error) int {
+	size := int(unsafe.Sizeof(errorData{})) + len(err.Error())
+	if d, ok := err.(*errorData); ok && d.pcs != nil && !d.resolved() {
+		return size + len(d.pcs)*int(unsafe.Sizeof(uintptr(0)))
+	}
+	return size + len(RawFrames(err))*int(unsafe.Sizeof(Frame{}))
+}
@@ -0,0 +1,79 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRecorderRetention(t *testing.T) {
+	r := tracerr.NewRecorder(10, tracerr.WithTTL(20*time.Millisecond))
+	r.Add(tracerr.New("first"))
+	time.Sleep(40 * time.Millisecond)
+	second := tracerr.New("second")
+	r.Add(second)
+	if errs := r.Errors(); len(errs) != 1 || errs[0].Err != second || errs[0].ID != 2 {
+		t.Errorf("r.Errors() = %v; want only second error", errs)
+	}
+	if _, ok := r.Get(1); ok {
+		t.Errorf("r.Get(1) = _, true; want expired")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if errs := r.Errors(); len(errs) != 0 {
+		t.Errorf("r.Errors() = %v; want empty after ttl", errs)
+	}
+
+	errs := []tracerr.Error{
+		tracerr.CustomError(errors.New("first"), diffFrames("a", "b", "c")),
+		tracerr.CustomError(errors.New("second"), diffFrames("a", "b", "c")),
+		tracerr.CustomError(errors.New("third"), diffFrames("a", "b", "c", "d", "e", "f", "g", "h")),
+	}
+	r = tracerr.NewRecorder(10, tracerr.WithMaxBytes(1000))
+	for _, err := range errs {
+		r.Add(err)
+	}
+	if recorded := r.Errors(); len(recorded) == 0 || len(recorded) == len(errs) || recorded[len(recorded)-1].Err != errs[2] {
+		t.Errorf("r.Errors() = %v; want the newest errors within bound", recorded)
+	}
+	r = tracerr.NewRecorder(10, tracerr.WithMaxBytes(1))
+	r.Add(errs[0])
+	r.Add(errs[1])
+	if recorded := r.Errors(); len(recorded) != 1 || recorded[0].Err != errs[1] {
+		t.Errorf("r.Errors() = %v; want the newest error kept", recorded)
+	}
+
+	r = tracerr.NewRecorder(2)
+	for _, err := range errs {
+		r.Add(err)
+	}
+	if recorded := r.Errors(); len(recorded) != 2 || recorded[0].Err != errs[1] || recorded[1].Err != errs[2] {
+		t.Errorf("r.Errors() = %v; want the last two errors", recorded)
+	}
+}
+
+func TestAggregatorRetention(t *testing.T) {
+	first := tracerr.CustomError(errors.New("first"), diffFrames("a"))
+	second := tracerr.CustomError(errors.New("second"), diffFrames("b"))
+
+	a := tracerr.NewAggregator(tracerr.WithTTL(20 * time.Millisecond))
+	a.Add(first)
+	a.Add(second)
+	time.Sleep(40 * time.Millisecond)
+	if count := a.Add(second); count != 1 {
+		t.Errorf("a.Add(second) = %#v; want %#v after ttl", count, 1)
+	}
+	if groups := a.Groups(); len(groups) != 1 || groups[0].Err.Error() != "second" {
+		t.Errorf("a.Groups() = %v; want only second group", groups)
+	}
+
+	a = tracerr.NewAggregator(tracerr.WithMaxBytes(1))
+	a.Add(first)
+	a.Add(second)
+	a.Add(first)
+	groups := a.Groups()
+	if len(groups) != 1 || groups[0].Err.Error() != "first" || groups[0].Count != 1 || groups[0].LastSeen.IsZero() {
+		t.Errorf("a.Groups() = %v; want only the last added group", groups)
+	}
+}
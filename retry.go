@@ -0,0 +1,157 @@
+package tracerr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Fields of attempt errors joined by Retry, see Fields.
+const (
+	// RetryAttemptField is a number of the attempt starting from 1.
+	RetryAttemptField = "retry.attempt"
+	// RetryElapsedField is time.Duration since the first attempt started
+	// until the attempt failed.
+	RetryElapsedField = "retry.elapsed"
+)
+
+// RetryPolicy configures attempts of Retry.
+type RetryPolicy struct {
+	// MaxAttempts is a maximum number of attempts,
+	// at least one attempt is made.
+	MaxAttempts int
+	// Delay is a delay before the second attempt.
+	Delay time.Duration
+	// Multiplier multiplies delay after each attempt,
+	// values below 1 keep it constant.
+	Multiplier float64
+	// MaxDelay bounds delay, if it's positive.
+	MaxDelay time.Duration
+}
+
+// retryError marks an error as retryable or permanent.
+type retryError struct {
+	err       error
+	retryable bool
+}
+
+func (e *retryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryError) Unwrap() error {
+	return e.err
+}
+
+// Retryable reports whether operation failed with the error can be retried.
+func (e *retryError) Retryable() bool {
+	return e.retryable
+}
+
+// MarkRetryable adds stacktrace to err by the same rules as Wrap
+// and marks it as retryable, the mark survives further wrapping.
+// It returns nil if err is nil. See IsRetryable.
+func MarkRetryable(err error) Error {
+	return markRetry(err, true)
+}
+
+// MarkPermanent adds stacktrace to err by the same rules as Wrap
+// and marks it as not retryable, the mark survives further wrapping.
+// It returns nil if err is nil. See IsRetryable.
+func MarkPermanent(err error) Error {
+	return markRetry(err, false)
+}
+
+func markRetry(err error, retryable bool) Error {
+	e := Wrap(err)
+	if e == nil {
+		return nil
+	}
+	d, ok := e.(*errorData)
+	if !ok {
+		return CustomError(&retryError{err: e, retryable: retryable}, RawFrames(e))
+	}
+	return d.with(&retryError{err: d.err, retryable: retryable}, d.RawFrames())
+}
+
+// IsRetryable reports whether operation failed with err can be retried.
+// It returns result of the first error in err tree, which is marked by
+// MarkRetryable or MarkPermanent or has Retryable() bool method.
+// Tree is walked in the same order as by AsError.
+// Unmarked transient errors are retryable: timeouts, e.g. of net.Error,
+// io.ErrUnexpectedEOF and context.DeadlineExceeded.
+func IsRetryable(err error) bool {
+	retryable, _ := classifyRetry(err)
+	return retryable
+}
+
+// classifyRetry returns retryability of err and whether it is known.
+func classifyRetry(err error) (retryable, ok bool) {
+	if err == nil {
+		return false, false
+	}
+	if r, ok := err.(interface{ Retryable() bool }); ok {
+		return r.Retryable(), true
+	}
+	if err == io.ErrUnexpectedEOF || err == context.DeadlineExceeded {
+		return true, true
+	}
+	if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+		return true, true
+	}
+	for _, err := range unwrapAll(err) {
+		if retryable, ok := classifyRetry(err); ok {
+			return retryable, true
+		}
+	}
+	return false, false
+}
+
+// Retry calls fn until it succeeds, fails with an error marked
+// by MarkPermanent, or policy.MaxAttempts is reached.
+//
+// Error of each attempt keeps its own stack trace, so intermittent
+// root causes are not hidden by the last attempt, and has
+// RetryAttemptField and RetryElapsedField attached.
+// On final failure Retry returns errors of all attempts joined
+// by errors.Join with stack trace of the last attempt, see PrintChain
+// to print them all. If ctx is done while waiting for the next attempt,
+// its cause is joined as well. It returns nil if an attempt succeeds.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) Error {
+	start := time.Now()
+	delay := policy.Delay
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		e := withFields(Wrap(err), map[string]interface{}{
+			RetryAttemptField: attempt,
+			RetryElapsedField: time.Since(start),
+		})
+		errs = append(errs, e)
+		if retryable, ok := classifyRetry(err); attempt >= policy.MaxAttempts || ok && !retryable {
+			return retryFailure(errs, e)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retryFailure(append(errs, context.Cause(ctx)), e)
+		case <-timer.C:
+		}
+		if policy.Multiplier > 1 {
+			delay = time.Duration(float64(delay) * policy.Multiplier)
+		}
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
+
+// retryFailure returns errs joined with stack trace of the last attempt.
+func retryFailure(errs []error, last Error) Error {
+	return withFrames(Wrap(errors.Join(errs...)), RawFrames(last))
+}
@@ -0,0 +1,136 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{tracerr.New("failed"), false},
+		{io.ErrUnexpectedEOF, true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{timeoutError{}, true},
+		{&net.OpError{Op: "dial", Err: timeoutError{}}, true},
+		{os.ErrDeadlineExceeded, true},
+		{tracerr.Wrap(io.ErrUnexpectedEOF), true},
+		{tracerr.MarkRetryable(io.EOF), true},
+		{tracerr.MarkPermanent(io.ErrUnexpectedEOF), false},
+		{fmt.Errorf("call: %w", tracerr.MarkRetryable(errors.New("busy"))), true},
+		{tracerr.Wrap(fmt.Errorf("call: %w", tracerr.MarkPermanent(timeoutError{}))), false},
+		{tracerr.MarkPermanent(tracerr.MarkRetryable(io.EOF)), false},
+		{errors.Join(io.EOF, tracerr.MarkRetryable(io.EOF)), true},
+	}
+	for i, c := range cases {
+		if retryable := tracerr.IsRetryable(c.err); retryable != c.expected {
+			t.Errorf("case #%d: tracerr.IsRetryable(%v) = %#v; want %#v", i, c.err, retryable, c.expected)
+		}
+	}
+}
+
+func TestMarkRetryable(t *testing.T) {
+	if err := tracerr.MarkRetryable(nil); err != nil {
+		t.Errorf("tracerr.MarkRetryable(nil) = %#v; want nil", err)
+	}
+	err := tracerr.MarkRetryable(io.EOF)
+	if err.Error() != io.EOF.Error() {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), io.EOF.Error())
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(err, io.EOF) = false; want true")
+	}
+	if frames := err.StackTrace(); len(frames) == 0 || frames[0].Name != "TestMarkRetryable" {
+		t.Errorf("err.StackTrace() = %#v; want to start at the caller", frames)
+	}
+
+	traced := tracerr.New("failed")
+	permanent := tracerr.MarkPermanent(traced)
+	if frames := permanent.StackTrace(); len(frames) != len(traced.StackTrace()) || frames[0] != traced.StackTrace()[0] {
+		t.Errorf("permanent.StackTrace() = %#v; want the original one", frames)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := tracerr.RetryPolicy{MaxAttempts: 3, Delay: time.Millisecond, Multiplier: 2}
+	calls := 0
+	err := tracerr.Retry(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return tracerr.New("busy")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("tracerr.Retry() = %v after %d calls; want nil after 3 calls", err, calls)
+	}
+
+	calls = 0
+	err = tracerr.Retry(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		if calls == 2 {
+			return tracerr.Errorf("connection reset")
+		}
+		return tracerr.New("busy")
+	})
+	attempts, ok := tracerr.Unwrap(err).(interface{ Unwrap() []error })
+	if !ok || len(attempts.Unwrap()) != 3 {
+		t.Fatalf("tracerr.Retry() = %#v; want 3 joined attempts", err)
+	}
+	for i, attempt := range attempts.Unwrap() {
+		fields := tracerr.Fields(attempt)
+		if fields[tracerr.RetryAttemptField] != i+1 {
+			t.Errorf("attempt #%d: field %s = %#v; want %#v", i, tracerr.RetryAttemptField, fields[tracerr.RetryAttemptField], i+1)
+		}
+		if _, ok := fields[tracerr.RetryElapsedField].(time.Duration); !ok {
+			t.Errorf("attempt #%d: field %s = %#v; want time.Duration", i, tracerr.RetryElapsedField, fields[tracerr.RetryElapsedField])
+		}
+	}
+	first, second := tracerr.StackTrace(attempts.Unwrap()[0]), tracerr.StackTrace(attempts.Unwrap()[1])
+	if len(first) == 0 || len(second) == 0 || first[0].Line == second[0].Line {
+		t.Errorf("stack traces of attempts = %v, %v; want different", first, second)
+	}
+	if last := tracerr.StackTrace(attempts.Unwrap()[2]); !reflect.DeepEqual(err.StackTrace(), last) {
+		t.Errorf("err.StackTrace() = %v; want stack trace of the last attempt %v", err.StackTrace(), last)
+	}
+
+	calls = 0
+	err = tracerr.Retry(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		return tracerr.MarkPermanent(errors.New("not found"))
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("tracerr.Retry() = %v after %d calls; want permanent error after 1 call", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = tracerr.Retry(ctx, tracerr.RetryPolicy{MaxAttempts: 3, Delay: time.Hour}, func(ctx context.Context) error {
+		cancel()
+		return tracerr.New("busy")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(err, context.Canceled) = false; want true")
+	}
+}
@@ -0,0 +1,57 @@
+package tracerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuntimeStackString returns stack trace of an error in the format
+// of runtime/debug.Stack, so tooling parsing Go tracebacks, such as
+// log parsers and panicparse, consumes it unchanged.
+// See FromRuntimeStack for the reverse.
+// It returns empty string if err is nil or not of type Error.
+//
+// Header has ID of goroutine recorded by WithGoroutineIDs, or 1.
+// Arguments of functions are unknown, so they are printed as "(...)",
+// the same way as for inlined calls. Offsets are printed for frames
+// with program counters. Marker frames are skipped.
+func RuntimeStackString(err error) string {
+	if _, ok := err.(Error); !ok || isNilError(err) {
+		return ""
+	}
+	id, ok := GoroutineID(err)
+	if !ok {
+		id = 1
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "goroutine %d [running]:\n", id)
+	for _, frame := range RawFrames(err) {
+		if frame.IsMarker() {
+			continue
+		}
+		if frame.CreatedBy {
+			fmt.Fprintf(&b, "created by %s\n", frame.Func)
+		} else {
+			fmt.Fprintf(&b, "%s(...)\n", frame.Func)
+		}
+		fmt.Fprintf(&b, "\t%s:%d", frame.Path, frame.Line)
+		if frame.PC > frame.Entry && frame.Entry != 0 {
+			fmt.Fprintf(&b, " +0x%x", frame.PC-frame.Entry)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// FromRuntimeStack returns err with stack trace parsed from stack
+// in the format of runtime/debug.Stack, such as returned by
+// RuntimeStackString, see FramesFromStack.
+// It returns nil if err is nil.
+//
+//	err := tracerr.FromRuntimeStack(errCrashed, debug.Stack())
+func FromRuntimeStack(err error, stack []byte) Error {
+	if isNilError(err) {
+		return nil
+	}
+	return CustomError(err, FramesFromStack(stack))
+}
@@ -0,0 +1,51 @@
+package tracerr_test
+
+import (
+	"errors"
+	"runtime/debug"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestRuntimeStackString(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		{Func: tracerr.MarkerFunc},
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+	}
+	frames[2].CreatedBy = true
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	expected := "goroutine 1 [running]:\n" +
+		"main.read(...)\n" +
+		"\t/src/main.go:42\n" +
+		"created by main.main\n" +
+		"\t/src/main.go:10\n"
+	if stack := tracerr.RuntimeStackString(err); stack != expected {
+		t.Errorf("tracerr.RuntimeStackString(err) = %#v; want %#v", stack, expected)
+	}
+
+	captured := tracerr.New("some error")
+	parsed := tracerr.FromRuntimeStack(errors.New("some error"), []byte(tracerr.RuntimeStackString(captured)))
+	if len(tracerr.RawFrames(parsed)) != len(tracerr.RawFrames(captured)) {
+		t.Fatalf("len(parsed frames) = %d; want %d", len(tracerr.RawFrames(parsed)), len(tracerr.RawFrames(captured)))
+	}
+	for i, frame := range tracerr.RawFrames(parsed) {
+		original := tracerr.RawFrames(captured)[i]
+		if frame.Func != original.Func || frame.Path != original.Path || frame.Line != original.Line {
+			t.Errorf("parsed frame #%d = %v; want %v", i, frame, original)
+		}
+	}
+
+	if stack := tracerr.RuntimeStackString(errors.New("some error")); stack != "" {
+		t.Errorf("tracerr.RuntimeStackString(errors.New(...)) = %#v; want empty", stack)
+	}
+
+	fromDebug := tracerr.FromRuntimeStack(errors.New("some error"), debug.Stack())
+	if frames := tracerr.RawFrames(fromDebug); len(frames) == 0 || frames[0].Func != "github.com/kadaan/tracerr_test.TestRuntimeStackString" {
+		t.Errorf("tracerr.FromRuntimeStack(err, debug.Stack()) frames = %v; want to start at the caller", frames)
+	}
+	if tracerr.FromRuntimeStack(nil, debug.Stack()) != nil {
+		t.Errorf("tracerr.FromRuntimeStack(nil, stack) != nil; want nil")
+	}
+}
@@ -0,0 +1,82 @@
+package tracerr
+
+import (
+	"runtime"
+	"time"
+)
+
+// RuntimeStats is a snapshot of runtime stats taken at capture,
+// see WithRuntimeStats. It's suitable for encoding/json.
+type RuntimeStats struct {
+	// HeapInUse is a number of bytes in in-use heap spans.
+	HeapInUse uint64 `json:"heapInUse"`
+	// HeapObjects is a number of allocated heap objects.
+	HeapObjects uint64 `json:"heapObjects"`
+	// Goroutines is a number of goroutines.
+	Goroutines int `json:"goroutines"`
+	// NumGC is a number of completed GC cycles.
+	NumGC uint32 `json:"numGC"`
+	// LastGCPause is a pause of the last GC cycle, it's zero if none completed.
+	LastGCPause time.Duration `json:"lastGCPause"`
+}
+
+// WithRuntimeStats attaches a snapshot of runtime stats to captured errors:
+// heap in use, goroutine count and GC pause, so failures close to OOM
+// or goroutine leaks can be triaged by the state they happened in.
+//
+// Snapshot stops the world for a short time, so the option suits
+// a separate Tracerr for resource-related errors, such as exhausted pools
+// or failed allocations, rather than Default:
+//
+//	resources := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithRuntimeStats())
+//	return resources.Wrap(err)
+//
+// Snapshot is returned by Stats, and included in WriteReport
+// and JSON of Recorder.
+func WithRuntimeStats() Option {
+	return func(t *tracerr) {
+		t.runtimeStats = true
+	}
+}
+
+// snapshotRuntimeStats returns current runtime stats.
+func snapshotRuntimeStats() *RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	stats := &RuntimeStats{
+		HeapInUse:   m.HeapInuse,
+		HeapObjects: m.HeapObjects,
+		Goroutines:  runtime.NumGoroutine(),
+		NumGC:       m.NumGC,
+	}
+	if m.NumGC > 0 {
+		stats.LastGCPause = time.Duration(m.PauseNs[(m.NumGC+255)%256])
+	}
+	return stats
+}
+
+// clone returns a copy of stats, which is nil if stats is nil.
+func (stats *RuntimeStats) clone() *RuntimeStats {
+	if stats == nil {
+		return nil
+	}
+	c := *stats
+	return &c
+}
+
+// Stats returns runtime stats snapshot attached to err or any error it wraps,
+// see WithRuntimeStats. It reports false if there is none.
+func Stats(err error) (*RuntimeStats, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if d, ok := err.(*errorData); ok && d != nil && d.stats != nil {
+		return d.stats, true
+	}
+	for _, wrapped := range unwrapAll(err) {
+		if stats, ok := Stats(wrapped); ok {
+			return stats, true
+		}
+	}
+	return nil, false
+}
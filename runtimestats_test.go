@@ -0,0 +1,62 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithRuntimeStats(t *testing.T) {
+	runtime.GC()
+	tracer := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithRuntimeStats(),
+	)
+	err := fmt.Errorf("context: %w", tracer.New("out of connections"))
+
+	stats, ok := tracerr.Stats(err)
+	if !ok {
+		t.Fatalf("tracerr.Stats(err) = nil, false; want stats")
+	}
+	if stats.HeapInUse == 0 || stats.HeapObjects == 0 || stats.Goroutines == 0 || stats.NumGC == 0 || stats.LastGCPause == 0 {
+		t.Errorf("stats = %#v; want non-zero values", stats)
+	}
+
+	// Stats are kept by wrapping and copying.
+	for i, wrapped := range []error{
+		tracerr.Wrap(err),
+		tracerr.Clone(tracerr.Wrap(err)),
+	} {
+		if wrappedStats, ok := tracerr.Stats(wrapped); !ok || *wrappedStats != *stats {
+			t.Errorf("case #%d: tracerr.Stats(err) = %#v, %v; want %#v", i, wrappedStats, ok, stats)
+		}
+	}
+	for i, other := range []error{nil, tracerr.New("some error")} {
+		if otherStats, ok := tracerr.Stats(other); ok {
+			t.Errorf("case #%d: tracerr.Stats(err) = %#v, true; want nil, false", i, otherStats)
+		}
+	}
+
+	path, writeErr := tracerr.WriteReport(t.TempDir(), err)
+	if writeErr != nil {
+		t.Fatalf("tracerr.WriteReport() error = %v", writeErr)
+	}
+	report, _ := os.ReadFile(path)
+	if !strings.Contains(string(report), fmt.Sprintf("Heap in use: %d bytes\n", stats.HeapInUse)) {
+		t.Errorf("report = %s; want captured runtime stats", report)
+	}
+
+	r := tracerr.NewRecorder(1)
+	r.Add(tracerr.Wrap(err))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+	if body := w.Body.String(); !strings.Contains(body, fmt.Sprintf(`"runtimeStats":{"heapInUse":%d,`, stats.HeapInUse)) {
+		t.Errorf("body = %s; want runtime stats", body)
+	}
+}
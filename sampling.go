@@ -0,0 +1,36 @@
+package tracerr
+
+import (
+	"math/rand"
+)
+
+// WithSampling makes Tracerr capture stack trace only for a fraction
+// of errors, rate is between 0 and 1.
+// The rest of errors have empty stack trace, which is much cheaper.
+//
+// Use WrapAlways for call sites, where stack trace is always needed.
+func WithSampling(rate float64) Option {
+	return func(t *tracerr) {
+		if rate < 0 {
+			rate = 0
+		}
+		t.sampling = rate < 1
+		t.sampleRate = rate
+	}
+}
+
+// sample reports whether stack trace of err should be captured,
+// extraSkip is the same as in trace.
+// Skipped errors are counted, see CaptureStats.
+func (t *tracerr) sample(err error, extraSkip int) bool {
+	var allowed bool
+	if t.budget != nil {
+		allowed = t.budget.allow(err, extraSkip)
+	} else {
+		allowed = !t.sampling || rand.Float64() < t.sampleRate
+	}
+	if !allowed {
+		captureStats.sampled.Add(1)
+	}
+	return allowed
+}
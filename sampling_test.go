@@ -0,0 +1,62 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type SamplingTestCase struct {
+	Rate        float64
+	MinCaptured int
+	MaxCaptured int
+}
+
+func TestWithSampling(t *testing.T) {
+	cases := []SamplingTestCase{
+		{
+			Rate:        0,
+			MinCaptured: 0,
+			MaxCaptured: 0,
+		},
+		{
+			Rate:        0.5,
+			MinCaptured: 1,
+			MaxCaptured: 999,
+		},
+		{
+			Rate:        1,
+			MinCaptured: 1000,
+			MaxCaptured: 1000,
+		},
+	}
+
+	err := errors.New("some error")
+	for i, c := range cases {
+		tr := tracerr.NewTracerr(
+			tracerr.DefaultFrameCapacity,
+			tracerr.DefaultFrameSkipCount,
+			tracerr.WithSampling(c.Rate),
+		)
+		captured := 0
+		for j := 0; j < 1000; j++ {
+			wrapped := tr.Wrap(err)
+			if wrapped.StackTrace() == nil {
+				t.Fatalf("case #%d: wrapped.StackTrace() = nil; want not nil", i)
+			}
+			if len(wrapped.StackTrace()) > 0 {
+				captured++
+			}
+		}
+		if captured < c.MinCaptured || captured > c.MaxCaptured {
+			t.Errorf(
+				"case #%d: captured = %#v; want between %#v and %#v",
+				i, captured, c.MinCaptured, c.MaxCaptured,
+			)
+		}
+		if len(tr.WrapAlways(err).StackTrace()) == 0 {
+			t.Errorf("case #%d: len(tr.WrapAlways(err).StackTrace()) = 0; want > 0", i)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package tracerr
+
+import (
+	"regexp"
+	"sync/atomic"
+)
+
+// MessageScrubber rewrites an error message before it leaves the process,
+// e.g. to remove secrets, see SetMessageScrubbers.
+type MessageScrubber func(message string) string
+
+// messageScrubbers are set by SetMessageScrubbers.
+var messageScrubbers atomic.Pointer[[]MessageScrubber]
+
+// SetMessageScrubbers sets scrubbers applied in order to messages
+// of errors by serializers and reporters: Tree, SprintYAML, Recorder,
+// binary and gob encodings, and integration packages, such as report,
+// tracerrhttp, tracerrpb and tracerrslog. Error() and printers of
+// the process keep the raw message. Call it with no scrubbers to turn it off.
+func SetMessageScrubbers(scrubbers ...MessageScrubber) {
+	messageScrubbers.Store(&scrubbers)
+}
+
+// ScrubMessage returns message rewritten by scrubbers set
+// by SetMessageScrubbers, so other serializers can apply them as well.
+func ScrubMessage(message string) string {
+	scrubbers := messageScrubbers.Load()
+	if scrubbers == nil {
+		return message
+	}
+	for _, scrub := range *scrubbers {
+		message = scrub(message)
+	}
+	return message
+}
+
+// ScrubPattern returns scrubber replacing parts of a message matching
+// pattern with replacement, which may contain references like $1,
+// see regexp.Regexp.ReplaceAllString.
+func ScrubPattern(pattern *regexp.Regexp, replacement string) MessageScrubber {
+	return func(message string) string {
+		return pattern.ReplaceAllString(message, replacement)
+	}
+}
+
+var (
+	// ScrubEmails replaces email addresses with "[email]".
+	ScrubEmails = ScrubPattern(regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]")
+	// ScrubTokens replaces bearer tokens and values of query parameters
+	// and key-value pairs named like a secret, such as "token=abc",
+	// with "[redacted]".
+	ScrubTokens = ScrubPattern(
+		regexp.MustCompile(`(?i)(bearer\s+|\b(?:token|secret|password|passwd|api_?key|access_?key)\s*[=:]\s*)[^\s&,;"']+`),
+		"${1}[redacted]",
+	)
+)
@@ -0,0 +1,69 @@
+package tracerr_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestScrubbers(t *testing.T) {
+	cases := []struct {
+		Scrubber tracerr.MessageScrubber
+		Message  string
+		Expected string
+	}{
+		{
+			Scrubber: tracerr.ScrubEmails,
+			Message:  "user john.doe+test@example.com not found",
+			Expected: "user [email] not found",
+		},
+		{
+			Scrubber: tracerr.ScrubTokens,
+			Message:  `GET /api?token=abc123&page=2: header "Authorization: Bearer eyJhbGc.x.y" rejected, password: hunter2`,
+			Expected: `GET /api?token=[redacted]&page=2: header "Authorization: Bearer [redacted]" rejected, password: [redacted]`,
+		},
+		{
+			Scrubber: tracerr.ScrubPattern(regexp.MustCompile(`card \d{12}(\d{4})`), "card ****$1"),
+			Message:  "charge card 4111111111111111 failed",
+			Expected: "charge card ****1111 failed",
+		},
+	}
+	for i, c := range cases {
+		if message := c.Scrubber(c.Message); message != c.Expected {
+			t.Errorf("case #%d: scrubber(%#v) = %#v; want %#v", i, c.Message, message, c.Expected)
+		}
+	}
+}
+
+func TestSetMessageScrubbers(t *testing.T) {
+	tracerr.SetMessageScrubbers(tracerr.ScrubEmails)
+	defer tracerr.SetMessageScrubbers()
+
+	raw := "user john@example.com not found"
+	err := tracerr.New(raw)
+	if err.Error() != raw {
+		t.Errorf("err.Error() = %#v; want raw message %#v", err.Error(), raw)
+	}
+	expected := "user [email] not found"
+	if message := tracerr.Tree(err).Message; message != expected {
+		t.Errorf("tracerr.Tree(err).Message = %#v; want %#v", message, expected)
+	}
+	b, marshalErr := tracerr.MarshalBinary(err)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	decoded, unmarshalErr := tracerr.UnmarshalBinary(b)
+	if unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	if decoded.Error() != expected {
+		t.Errorf("decoded.Error() = %#v; want %#v", decoded.Error(), expected)
+	}
+
+	tracerr.SetMessageScrubbers()
+	if message := tracerr.Tree(errors.New(raw)).Message; message != raw {
+		t.Errorf("tracerr.Tree(err).Message without scrubbers = %#v; want %#v", message, raw)
+	}
+}
@@ -0,0 +1,94 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+// SignalDump keeps the last captured errors and writes them
+// with stack traces to a writer when the process receives a signal,
+// see WithSignalDump.
+type SignalDump struct {
+	sig      os.Signal
+	w        io.Writer
+	recorder *Recorder
+	start    sync.Once
+	stop     sync.Once
+	signals  chan os.Signal
+	done     chan struct{}
+	// err is the first error of writing, it's set before done is closed.
+	err error
+}
+
+// NewSignalDump creates SignalDump, which keeps the last n errors
+// and writes them to w on sig, e.g. syscall.SIGQUIT or syscall.SIGUSR1.
+// It starts listening to sig when it's applied by WithSignalDump.
+func NewSignalDump(sig os.Signal, w io.Writer, n int) *SignalDump {
+	return &SignalDump{
+		sig:      sig,
+		w:        w,
+		recorder: NewRecorder(n),
+		signals:  make(chan os.Signal, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// WithSignalDump keeps captured errors in d and starts listening
+// to its signal. It's useful to diagnose live services
+// without attaching a debugger.
+//
+// Receiving the signal no longer has its default effect, such as
+// termination, until d is stopped. Errors skipped by sampling are not kept.
+func WithSignalDump(d *SignalDump) Option {
+	return func(t *tracerr) {
+		d.start.Do(func() {
+			signal.Notify(d.signals, d.sig)
+			go d.run()
+		})
+		WithRecorder(d.recorder)(t)
+	}
+}
+
+func (d *SignalDump) run() {
+	defer close(d.done)
+	for range d.signals {
+		_, err := io.WriteString(d.w, sprintDump(d.recorder.Errors()))
+		if err != nil && d.err == nil {
+			d.err = err
+		}
+	}
+}
+
+// Stop stops listening to the signal, so it has its default effect again,
+// and returns the first error of writing errors, if any.
+func (d *SignalDump) Stop() error {
+	d.stop.Do(func() {
+		started := true
+		// Stopped dump is never started.
+		d.start.Do(func() {
+			started = false
+		})
+		if started {
+			signal.Stop(d.signals)
+			close(d.signals)
+			<-d.done
+		}
+	})
+	return d.err
+}
+
+// sprintDump returns output of errors written on signal.
+func sprintDump(errs []RecordedError) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "tracerr: %d recent errors\n", len(errs))
+	for _, err := range errs {
+		b.WriteString("\n")
+		b.WriteString(Sprint(err.Err))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
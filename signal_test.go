@@ -0,0 +1,85 @@
+//go:build unix
+
+package tracerr_test
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+type chanWriter chan string
+
+func (w chanWriter) Write(b []byte) (int, error) {
+	w <- string(b)
+	return len(b), nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWithSignalDump(t *testing.T) {
+	w := make(chanWriter, 1)
+	dump := tracerr.NewSignalDump(syscall.SIGUSR1, w, 2)
+	defer dump.Stop()
+	option := tracerr.WithSignalDump(dump)
+	tr := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		option,
+	)
+	tr.New("first")
+	second := tr.New("second")
+	third := tr.New("third")
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case output := <-w:
+		expected := "tracerr: 2 recent errors\n\n" + tracerr.Sprint(second) + "\n\n" + tracerr.Sprint(third) + "\n"
+		if output != expected {
+			t.Errorf("output = %#v; want %#v", output, expected)
+		}
+		if strings.Contains(output, "first") {
+			t.Errorf("output = %#v; want the oldest error dropped", output)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no output on signal")
+	}
+}
+
+func TestSignalDumpStop(t *testing.T) {
+	dump := tracerr.NewSignalDump(syscall.SIGUSR2, failingWriter{}, 2)
+	// Option isn't applied yet, so it doesn't listen to the signal.
+	option := tracerr.WithSignalDump(dump)
+	tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, option)
+
+	// Another listener tells the signal is delivered.
+	delivered := make(chan os.Signal, 1)
+	signal.Notify(delivered, syscall.SIGUSR2)
+	defer signal.Stop(delivered)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	<-delivered
+
+	if err := dump.Stop(); err == nil || err.Error() != "write failed" {
+		t.Errorf("dump.Stop() = %#v; want write error", err)
+	}
+	if err := dump.Stop(); err == nil {
+		t.Errorf("dump.Stop() again = nil; want write error")
+	}
+	if err := tracerr.NewSignalDump(syscall.SIGUSR2, failingWriter{}, 2).Stop(); err != nil {
+		t.Errorf("dump.Stop() of not applied dump = %#v; want nil", err)
+	}
+}
@@ -0,0 +1,110 @@
+package tracerr
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// smartSnippetMaxLines is a maximum number of lines, which
+// WithSmartSnippets adds to each side of a source fragment.
+const smartSnippetMaxLines = 10
+
+// WithSmartSnippets adjusts bounds of source fragments to code
+// instead of a fixed number of lines: fragments are expanded to whole
+// statements, so a multi-line call isn't split, and to the condition
+// of an if statement, such as "if err != nil {", whose body has traced line,
+// while trailing lines of only closing brackets are dropped.
+// Each side is expanded by at most 10 lines.
+//
+// It has no effect if output has no source fragments.
+func WithSmartSnippets() PrintOption {
+	return func(o *printOptions) {
+		o.smartSnippets = true
+	}
+}
+
+// smartSnippet returns bounds of source fragment of lines around current line
+// adjusted to code, start, end and current are indexes of lines.
+func smartSnippet(lines []string, current, start, end int) (int, int) {
+	fset := token.NewFileSet()
+	// Partial syntax tree is good enough for a file with errors.
+	file, _ := parser.ParseFile(fset, "", strings.Join(lines, "\n"), parser.SkipObjectResolution)
+	if file == nil {
+		return start, end
+	}
+	line := func(pos token.Pos) int {
+		return fset.Position(pos).Line - 1
+	}
+	expandStart := func(i int) {
+		if i < start && start-i <= smartSnippetMaxLines {
+			start = i
+		}
+	}
+	expandEnd := func(i int) {
+		if i > end && i-end <= smartSnippetMaxLines {
+			end = i
+		}
+	}
+
+	if cond, ok := enclosingIf(file, current, line); ok {
+		expandStart(cond)
+	}
+	stmtStart, stmtEnd, ok := enclosingStmt(file, current, line)
+	if ok {
+		expandStart(stmtStart)
+		expandEnd(stmtEnd)
+	} else {
+		stmtEnd = current
+	}
+	for end > stmtEnd && end < len(lines) && isClosingLine(lines[end]) {
+		end--
+	}
+	if first, _, ok := enclosingStmt(file, start, line); ok {
+		expandStart(first)
+	}
+	if _, last, ok := enclosingStmt(file, end, line); ok {
+		expandEnd(last)
+	}
+	return start, end
+}
+
+// enclosingIf returns line of the innermost if statement,
+// whose body has line i.
+func enclosingIf(file *ast.File, i int, line func(token.Pos) int) (cond int, ok bool) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil || i < line(node.Pos()) || i > line(node.End()) {
+			return false
+		}
+		if s, isIf := node.(*ast.IfStmt); isIf && i > line(s.Body.Lbrace) && i < line(s.Body.Rbrace) {
+			cond, ok = line(s.Pos()), true
+		}
+		return true
+	})
+	return cond, ok
+}
+
+// enclosingStmt returns the first and the last lines of the innermost
+// simple statement, such as a call or an assignment, spanning line i.
+func enclosingStmt(file *ast.File, i int, line func(token.Pos) int) (start, end int, ok bool) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil || i < line(node.Pos()) || i > line(node.End()) {
+			return false
+		}
+		switch node.(type) {
+		case *ast.BlockStmt, *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt,
+			*ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt,
+			*ast.CaseClause, *ast.CommClause, *ast.LabeledStmt:
+		case ast.Stmt:
+			start, end, ok = line(node.Pos()), line(node.End()), true
+		}
+		return true
+	})
+	return start, end, ok
+}
+
+// isClosingLine returns true if text is blank or has only closing brackets.
+func isClosingLine(text string) bool {
+	return strings.Trim(text, " \t})],;") == ""
+}
@@ -0,0 +1,80 @@
+package tracerr_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithSmartSnippets(t *testing.T) {
+	source := strings.Join([]string{
+		"package main",                   // 1
+		"",                               // 2
+		"func load(path string) error {", // 3
+		"	data, err := read(",            // 4
+		"		path,",                        // 5
+		"		0644,",                        // 6
+		"	)",                             // 7
+		"	if err != nil {",               // 8
+		"		log(",                         // 9
+		"			\"failed\",",                 // 10
+		"			err,",                        // 11
+		"		)",                            // 12
+		"		return err",                   // 13
+		"	}",                             // 14
+		"	return parse(data)",            // 15
+		"}",                              // 16
+		"",                               // 17
+	}, "\n")
+	fsys := fstest.MapFS{"snippet.go": &fstest.MapFile{Data: []byte(source)}}
+	tracerr.SetSourceProvider(tracerr.NewFSSourceProvider(fsys, "/src/app"))
+	defer tracerr.SetSourceProvider(nil)
+	defer tracerr.SetPrintOptions()
+
+	cases := []struct {
+		line          int
+		before, after int
+		expected      []int
+	}{
+		// Multi-line call isn't split.
+		{line: 5, before: 0, after: 0, expected: []int{4, 5, 6, 7}},
+		// Condition of if statement is shown.
+		{line: 13, before: 1, after: 0, expected: []int{8, 9, 10, 11, 12, 13}},
+		// Closing braces are dropped.
+		{line: 15, before: 1, after: 2, expected: []int{14, 15}},
+		// Call at the end of fragment isn't split.
+		{line: 8, before: 0, after: 1, expected: []int{8, 9, 10, 11, 12}},
+	}
+
+	tracerr.SetPrintOptions(tracerr.WithSmartSnippets())
+	for i, c := range cases {
+		err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+			tracerr.NewFrame("main.load", "/src/app/snippet.go", c.line),
+		})
+		output := tracerr.SprintSource(err, c.before, c.after)
+		var numbers []int
+		for _, row := range strings.Split(output, "\n") {
+			if number, _, ok := strings.Cut(row, "\t"); ok {
+				if n, err := strconv.Atoi(number); err == nil {
+					numbers = append(numbers, n)
+				}
+			}
+		}
+		if !slices.Equal(numbers, c.expected) {
+			t.Errorf("case #%d: lines of tracerr.SprintSource(err, %d, %d) = %v; want %v", i, c.before, c.after, numbers, c.expected)
+		}
+	}
+
+	tracerr.SetPrintOptions()
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.load", "/src/app/snippet.go", 15),
+	})
+	if output := tracerr.SprintSource(err, 1, 2); !strings.Contains(output, "17\t") {
+		t.Errorf("tracerr.SprintSource(err) = %#v; want fixed fragment without options", output)
+	}
+}
@@ -0,0 +1,164 @@
+package tracerr
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SourceProvider reads source files displayed by print functions.
+//
+// A provider can also implement ReadDir(path string) ([]string, error),
+// which returns names of files in a directory, to find generated files
+// by WithLineDirectives.
+type SourceProvider interface {
+	ReadFile(path string) ([]byte, error)
+}
+
+// dirReader is a source provider, which lists directories.
+type dirReader interface {
+	ReadDir(path string) ([]string, error)
+}
+
+var errNoDir = errors.New("tracerr: directories aren't listed by source provider")
+
+// readDir returns names of files in a directory by the source provider.
+func readDir(path string) ([]string, error) {
+	mutex.RLock()
+	provider := sourceProvider
+	mutex.RUnlock()
+	if r, ok := provider.(dirReader); ok {
+		return r.ReadDir(path)
+	}
+	return nil, errNoDir
+}
+
+// fileNames returns names of files, which aren't directories, of entries.
+func fileNames(entries []fs.DirEntry) []string {
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+var sourceProvider = defaultSourceProvider()
+
+// defaultSourceProvider reads from the OS file system
+// and looks for missing files of dependencies in the module cache.
+// It provides no sources on WASM and TinyGo.
+func defaultSourceProvider() SourceProvider {
+	if limitedRuntime {
+		return NewNopSourceProvider()
+	}
+	return NewModuleCacheSourceProvider(NewOSSourceProvider())
+}
+
+// hasNoSources returns true if sources are never provided.
+func hasNoSources() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	_, ok := sourceProvider.(nopSourceProvider)
+	return ok
+}
+
+// SetSourceProvider sets provider of source files for print functions
+// and clears cache of files read before.
+// Pass nil to restore the default, which reads from the OS file system
+// and the Go module cache, or provides no sources on WASM and TinyGo.
+func SetSourceProvider(provider SourceProvider) {
+	if provider == nil {
+		provider = defaultSourceProvider()
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	sourceProvider = provider
+	cache.clear()
+}
+
+// NewOSSourceProvider creates source provider reading from the OS file system.
+func NewOSSourceProvider() SourceProvider {
+	return osSourceProvider{}
+}
+
+type osSourceProvider struct{}
+
+func (osSourceProvider) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (osSourceProvider) ReadDir(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return fileNames(entries), nil
+}
+
+// NewFSSourceProvider creates source provider reading from fsys,
+// such as embed.FS with sources embedded at build time.
+//
+// Frame paths are resolved relative to root,
+// e.g. with root "/src/app" path "/src/app/main.go" is read as "main.go".
+// Empty root means the file system root.
+func NewFSSourceProvider(fsys fs.FS, root string) SourceProvider {
+	return &fsSourceProvider{
+		fsys: fsys,
+		root: root,
+	}
+}
+
+type fsSourceProvider struct {
+	fsys fs.FS
+	root string
+}
+
+func (p *fsSourceProvider) ReadFile(name string) ([]byte, error) {
+	name, err := p.name(name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(p.fsys, name)
+}
+
+func (p *fsSourceProvider) ReadDir(name string) ([]string, error) {
+	name, err := p.name(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(p.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return fileNames(entries), nil
+}
+
+// name returns name in fsys of a frame path.
+func (p *fsSourceProvider) name(name string) (string, error) {
+	if p.root != "" {
+		rel, err := filepath.Rel(p.root, name)
+		if err != nil {
+			return "", err
+		}
+		name = rel
+	}
+	return path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "/")), nil
+}
+
+// NewNopSourceProvider creates source provider, which never provides sources.
+func NewNopSourceProvider() SourceProvider {
+	return nopSourceProvider{}
+}
+
+type nopSourceProvider struct{}
+
+var errNoSource = errors.New("tracerr: no source provided")
+
+func (nopSourceProvider) ReadFile(path string) ([]byte, error) {
+	return nil, errNoSource
+}
@@ -0,0 +1,79 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kadaan/tracerr"
+)
+
+type SourceProviderTestCase struct {
+	Provider     tracerr.SourceProvider
+	ExpectedRows []string
+}
+
+func TestSetSourceProvider(t *testing.T) {
+	defer tracerr.SetSourceProvider(nil)
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 2,
+				Path: "/src/app/main.go",
+			},
+		},
+	)
+	fsys := fstest.MapFS{
+		"main.go": &fstest.MapFile{
+			Data: []byte("package main\nfunc Foo() {}\n"),
+		},
+	}
+	cases := []SourceProviderTestCase{
+		{
+			Provider: tracerr.NewFSSourceProvider(fsys, "/src/app"),
+			ExpectedRows: []string{
+				"some error",
+				"",
+				"/src/app/main.go:2 main.Foo()",
+				"2\tfunc Foo() {}",
+				"",
+			},
+		},
+		{
+			Provider: tracerr.NewNopSourceProvider(),
+			ExpectedRows: []string{
+				"some error",
+				"",
+				"/src/app/main.go:2 main.Foo()",
+				"tracerr: file /src/app/main.go not found",
+				"",
+			},
+		},
+		{
+			Provider: tracerr.NewOSSourceProvider(),
+			ExpectedRows: []string{
+				"some error",
+				"",
+				"/src/app/main.go:2 main.Foo()",
+				"tracerr: file /src/app/main.go not found",
+				"",
+			},
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.SetSourceProvider(c.Provider)
+		output := tracerr.SprintSource(err, 1)
+		expected := strings.Join(c.ExpectedRows, "\n")
+		if output != expected {
+			t.Errorf(
+				"case #%d: tracerr.SprintSource(err, 1) = %#v; want %#v",
+				i, output, expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tracerr
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// staleSourceWarning is printed above source fragment of a frame,
+// which traced line differs from the one at capture, see WithSourceHashes.
+const staleSourceWarning = "(source may have changed since capture)"
+
+// WithSourceHashes records a hash of traced line of each frame at capture
+// in Frame.SourceHash, so source printers warn if the file on disk
+// no longer matches, e.g. for errors of a deployed build printed
+// with a newer checkout.
+//
+// It reads source files at capture, which makes capture slower,
+// frames captured with WithLazyFrames are resolved at capture.
+func WithSourceHashes() Option {
+	return func(t *tracerr) {
+		t.sourceHashes = true
+	}
+}
+
+// hashSources sets hashes of traced lines to frames of newly captured e.
+func hashSources(e Error) {
+	// Frames are not shared yet.
+	frames := RawFrames(e)
+	for i := range frames {
+		lines, err := readLines(frames[i].Path)
+		if err != nil || frames[i].Line < 1 || frames[i].Line > len(lines) {
+			continue
+		}
+		frames[i].SourceHash = lineHash(lines[frames[i].Line-1])
+	}
+}
+
+// lineHash returns a hash of line ignoring indentation, it's never zero.
+func lineHash(line string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(strings.TrimSpace(line)))
+	if sum := h.Sum32(); sum != 0 {
+		return sum
+	}
+	return 1
+}
+
+// sourceChanged reports whether traced line in fragment of frame
+// differs from the one at capture.
+func sourceChanged(frame Frame, fragment []sourceLine) bool {
+	if frame.SourceHash == 0 {
+		return false
+	}
+	for _, line := range fragment {
+		if line.Current {
+			return lineHash(line.Text) != frame.SourceHash
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package tracerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithSourceHashes(t *testing.T) {
+	tracer := tracerr.NewTracerr(tracerr.DefaultFrameCapacity, tracerr.DefaultFrameSkipCount, tracerr.WithSourceHashes())
+	err := tracer.New("some error")
+	frames := err.StackTrace()
+	if frames[0].SourceHash == 0 {
+		t.Fatalf("frames[0].SourceHash = 0; want hash of traced line")
+	}
+	const warning = "(source may have changed since capture)"
+	if output := tracerr.SprintSource(err, 0, 0); strings.Contains(output, warning) {
+		t.Errorf("tracerr.SprintSource(err) = %#v; want no warning for unchanged source", output)
+	}
+
+	frames[0].SourceHash++
+	changed := tracerr.CustomError(err.Unwrap(), frames[:1])
+	outputs := []string{
+		tracerr.SprintSource(changed, 0, 0),
+		tracerr.SprintHTML(changed),
+		tracerr.SprintMarkdown(changed),
+	}
+	for i, output := range outputs {
+		if !strings.Contains(output, warning) {
+			t.Errorf("case #%d: output = %#v; want warning for changed source", i, output)
+		}
+	}
+}
+
+func TestWithoutSourceHashes(t *testing.T) {
+	err := tracerr.New("some error")
+	if hash := err.StackTrace()[0].SourceHash; hash != 0 {
+		t.Errorf("frames[0].SourceHash = %d; want 0 by default", hash)
+	}
+}
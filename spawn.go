@@ -0,0 +1,111 @@
+package tracerr
+
+import (
+	"context"
+	"os"
+	"runtime"
+)
+
+// createdBy is a separator of frames of goroutines in output,
+// see Frame.CreatedBy.
+const createdBy = "created by"
+
+// spawnKey is a context key of frames of spawning goroutines.
+type spawnKey struct{}
+
+// Go calls fn in a new goroutine like the go statement.
+//
+// If fn panics, the panic is converted to Error, which stack trace
+// is followed by frames of the caller of Go, so it doesn't dead-end
+// at goroutine start. The Error is printed to os.Stderr before panic
+// continues with it, as a panic of goroutine can't be recovered
+// by its creator, so stdout of a program is kept clean.
+func Go(fn func()) {
+	spawn := spawnFrames(context.Background())
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				e := withSpawnFrames(PanicError(r), spawn)
+				writePanicReport(e)
+				Fprint(os.Stderr, e)
+				panic(e)
+			}
+		}()
+		fn()
+	}()
+}
+
+// Spawn calls fn in a new goroutine and returns a channel,
+// which receives an error returned by fn or its panic converted to error,
+// the channel is closed when fn returns.
+//
+// Stack trace of the error is followed by frames of the caller of Spawn,
+// including callers of enclosing Spawn calls found in ctx,
+// which is passed to fn.
+func Spawn(ctx context.Context, fn func(ctx context.Context) error) <-chan error {
+	spawn := spawnFrames(ctx)
+	ctx = context.WithValue(ctx, spawnKey{}, spawn)
+	result := make(chan error, 1)
+	go func() {
+		defer close(result)
+		err, panicked := runRecover(func() error {
+			return fn(ctx)
+		})
+		if err == nil {
+			return
+		}
+		err = withSpawnFrames(err, spawn)
+		if panicked {
+			writePanicReport(err)
+		}
+		result <- err
+	}()
+	return result
+}
+
+// spawnFrames returns frames of the caller of a function starting goroutine
+// followed by frames of spawning goroutines found in ctx.
+// The first frame is marked by Frame.CreatedBy.
+func spawnFrames(ctx context.Context) []Frame {
+	if !Enabled() {
+		return nil
+	}
+	var pcs [LazyFramesMaxDepth]uintptr
+	// Skip runtime.Callers, spawnFrames and the function starting goroutine.
+	n := runtime.Callers(3, pcs[:])
+	frames := trimGoroutineStart(resolveFrames(pcs[:n], false))
+	if parent, ok := ctx.Value(spawnKey{}).([]Frame); ok {
+		frames = append(frames, parent...)
+	}
+	if len(frames) > 0 {
+		frames[0].CreatedBy = true
+	}
+	return frames
+}
+
+// withSpawnFrames appends frames of spawning goroutines to stack trace of err,
+// which is returned as is if it already crosses goroutines.
+func withSpawnFrames(err error, spawn []Frame) Error {
+	e := Wrap(err)
+	frames := RawFrames(e)
+	for _, frame := range frames {
+		if frame.CreatedBy {
+			return e
+		}
+	}
+	frames = trimGoroutineStart(frames)
+	return withFrames(e, append(frames[:len(frames):len(frames)], spawn...))
+}
+
+// trimGoroutineStart drops trailing frames of runtime and tracerr
+// starting goroutine.
+func trimGoroutineStart(frames []Frame) []Frame {
+	for len(frames) > 0 {
+		last := frames[len(frames)-1]
+		if last.Func != "runtime.goexit" && last.Package != packageName {
+			break
+		}
+		frames = frames[:len(frames)-1]
+	}
+	return frames
+}
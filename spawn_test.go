@@ -0,0 +1,103 @@
+package tracerr_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSpawn(t *testing.T) {
+	err := <-spawnFailing(context.Background())
+	frames := tracerr.StackTrace(err)
+	names := frameNames(frames)
+	expected := []string{"failInGoroutine", "spawnFailing.func1", "spawnFailing", "TestSpawn"}
+	if len(names) < len(expected) || strings.Join(names[:len(expected)], " ") != strings.Join(expected, " ") {
+		t.Fatalf("frames = %v; want prefix %v", names, expected)
+	}
+	if !frames[2].CreatedBy || frames[1].CreatedBy {
+		t.Errorf("frames[2].CreatedBy = %v; want only frame of spawnFailing marked", frames[2].CreatedBy)
+	}
+	output := tracerr.Sprint(err)
+	if !strings.Contains(output, "spawnFailing.func1()\ncreated by\n") {
+		t.Errorf("tracerr.Sprint(err) = %q; want created by separator", output)
+	}
+}
+
+func TestSpawnNested(t *testing.T) {
+	result := tracerr.Spawn(context.Background(), func(ctx context.Context) error {
+		return <-spawnFailing(ctx)
+	})
+	names := frameNames(tracerr.StackTrace(<-result))
+	expected := []string{"failInGoroutine", "spawnFailing.func1", "spawnFailing", "TestSpawnNested.func1", "TestSpawnNested"}
+	if len(names) < len(expected) || strings.Join(names[:len(expected)], " ") != strings.Join(expected, " ") {
+		t.Errorf("frames = %v; want prefix %v", names, expected)
+	}
+}
+
+func TestSpawnPanic(t *testing.T) {
+	err := <-tracerr.Spawn(context.Background(), func(ctx context.Context) error {
+		panicInWorker()
+		return nil
+	})
+	names := frameNames(tracerr.StackTrace(err))
+	expected := []string{"panicInWorker", "TestSpawnPanic.func1", "TestSpawnPanic"}
+	if len(names) < len(expected) || strings.Join(names[:len(expected)], " ") != strings.Join(expected, " ") {
+		t.Errorf("frames = %v; want prefix %v", names, expected)
+	}
+}
+
+func TestSpawnNoError(t *testing.T) {
+	result := tracerr.Spawn(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+	if err, ok := <-result; ok {
+		t.Errorf("<-result = %v; want closed channel", err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	if os.Getenv("TRACERR_TEST_GO") == "1" {
+		tracerr.Go(panicInWorker)
+		select {}
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGo$")
+	cmd.Env = append(os.Environ(), "TRACERR_TEST_GO=1")
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatalf("panic in tracerr.Go() didn't crash, output:\n%s", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "worker failed") {
+		t.Errorf("stdout = %q; want no error", stdout.String())
+	}
+	output := stderr.String()
+	for _, expected := range []string{"panic: worker failed\n", "panicInWorker()\ncreated by\n", "TestGo()"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("output = %q; want %q", output, expected)
+		}
+	}
+}
+
+//go:noinline
+func spawnFailing(ctx context.Context) <-chan error {
+	return tracerr.Spawn(ctx, func(ctx context.Context) error {
+		return failInGoroutine()
+	})
+}
+
+//go:noinline
+func failInGoroutine() error {
+	return tracerr.New("failed")
+}
+
+func frameNames(frames []tracerr.Frame) []string {
+	names := make([]string, len(frames))
+	for i, frame := range frames {
+		names[i] = frame.Func[strings.LastIndex(frame.Func, "tracerr_test.")+len("tracerr_test."):]
+	}
+	return names
+}
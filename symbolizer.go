@@ -0,0 +1,48 @@
+package tracerr
+
+import "sync/atomic"
+
+// Symbolizer resolves program counters to frames for environments,
+// where runtime has no or incomplete symbol data, such as TinyGo, WASM
+// or plugins loaded at runtime, see SetSymbolizer.
+type Symbolizer interface {
+	// Symbolize returns frame of pc, which is a program counter of a call
+	// as in Frame.PC, or false to resolve it by runtime.
+	Symbolize(pc uintptr) (Frame, bool)
+}
+
+// SymbolizerFunc is an adapter to use a function as Symbolizer.
+type SymbolizerFunc func(pc uintptr) (Frame, bool)
+
+// Symbolize returns f(pc).
+func (f SymbolizerFunc) Symbolize(pc uintptr) (Frame, bool) {
+	return f(pc)
+}
+
+// symbolizer is set by SetSymbolizer.
+var symbolizer atomic.Pointer[Symbolizer]
+
+// SetSymbolizer sets s, which resolves program counters to frames
+// before runtime does, both at capture and for frames resolved lazily,
+// see WithLazyFrames. Program counters it doesn't resolve fall back
+// to runtime. Call it with nil to use runtime only.
+func SetSymbolizer(s Symbolizer) {
+	if s == nil {
+		symbolizer.Store(nil)
+		return
+	}
+	symbolizer.Store(&s)
+}
+
+// symbolize returns frame of pc resolved by Symbolizer set by SetSymbolizer.
+func symbolize(pc uintptr) (Frame, bool) {
+	s := symbolizer.Load()
+	if s == nil {
+		return Frame{}, false
+	}
+	f, ok := (*s).Symbolize(pc)
+	if !ok {
+		return Frame{}, false
+	}
+	return newCapturedFrame(pc, f.Entry, f.Func, f.Path, f.Line), true
+}
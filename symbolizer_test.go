@@ -0,0 +1,46 @@
+package tracerr_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSetSymbolizer(t *testing.T) {
+	defer tracerr.SetSymbolizer(nil)
+	tracerr.SetSymbolizer(tracerr.SymbolizerFunc(func(pc uintptr) (tracerr.Frame, bool) {
+		fn := runtime.FuncForPC(pc)
+		if fn == nil || !strings.HasSuffix(fn.Name(), ".TestSetSymbolizer") {
+			return tracerr.Frame{}, false
+		}
+		return tracerr.NewFrame("wasm.handler", "handler.go", 7), true
+	}))
+
+	eager := tracerr.New("some error")
+	lazy := tracerr.NewTracerr(
+		tracerr.DefaultFrameCapacity,
+		tracerr.DefaultFrameSkipCount,
+		tracerr.WithLazyFrames(),
+	).New("some error")
+
+	for name, err := range map[string]tracerr.Error{"eager": eager, "lazy": lazy} {
+		frames := err.StackTrace()
+		if len(frames) < 2 {
+			t.Fatalf("%s: err.StackTrace() = %#v; want frames", name, frames)
+		}
+		top := frames[0]
+		if top.Func != "wasm.handler" || top.Path != "handler.go" || top.Line != 7 || top.PC == 0 {
+			t.Errorf("%s: err.StackTrace()[0] = %#v; want symbolized frame", name, top)
+		}
+		if frames[1].Func != "testing.tRunner" {
+			t.Errorf("%s: err.StackTrace()[1].Func = %#v; want frame resolved by runtime", name, frames[1].Func)
+		}
+	}
+
+	tracerr.SetSymbolizer(nil)
+	if frames := tracerr.New("some error").StackTrace(); frames[0].Func != "github.com/kadaan/tracerr_test.TestSetSymbolizer" {
+		t.Errorf("err.StackTrace()[0].Func = %#v; want frame resolved by runtime", frames[0].Func)
+	}
+}
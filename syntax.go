@@ -0,0 +1,83 @@
+package tracerr
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+)
+
+// SyntaxTheme is DefaultTheme with Go syntax highlighting of source lines.
+var SyntaxTheme = Theme{
+	Message:    DefaultTheme.Message,
+	Path:       DefaultTheme.Path,
+	LineNumber: DefaultTheme.LineNumber,
+	Highlight:  DefaultTheme.Highlight,
+	Expression: DefaultTheme.Expression,
+	Context:    DefaultTheme.Context,
+	Warning:    DefaultTheme.Warning,
+	Dimmed:     DefaultTheme.Dimmed,
+	Keyword:    aurora.MagentaFg,
+	String:     aurora.GreenFg,
+	Number:     aurora.CyanFg,
+	Comment:    aurora.BlackFg,
+}
+
+// hasSyntax reports whether theme has any syntax highlighting color.
+func (t *Theme) hasSyntax() bool {
+	return t.Keyword != 0 || t.String != 0 || t.Number != 0 || t.Comment != 0
+}
+
+// syntaxColor returns color of token by theme, or Context color.
+func (t *Theme) syntaxColor(tok token.Token) aurora.Color {
+	switch {
+	case tok.IsKeyword() && t.Keyword != 0:
+		return t.Keyword
+	case (tok == token.STRING || tok == token.CHAR) && t.String != 0:
+		return t.String
+	case (tok == token.INT || tok == token.FLOAT || tok == token.IMAG) && t.Number != 0:
+		return t.Number
+	case tok == token.COMMENT && t.Comment != 0:
+		return t.Comment
+	}
+	return t.Context
+}
+
+// highlightSyntax colors tokens of a source line by theme,
+// the rest of line has Context color.
+func highlightSyntax(text string, theme *Theme) string {
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(text))
+	// Errors are expected, e.g. for a line of multiline string.
+	s.Init(file, []byte(text), func(token.Position, string) {}, scanner.ScanComments)
+	var b strings.Builder
+	written := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		color := theme.syntaxColor(tok)
+		if color == theme.Context {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if lit == "" || end > len(text) {
+			end = start + len(tok.String())
+		}
+		if start < written || end > len(text) {
+			continue
+		}
+		if start > written {
+			b.WriteString(colorize(text[written:start], theme.Context))
+		}
+		b.WriteString(colorize(text[start:end], color))
+		written = end
+	}
+	if written < len(text) {
+		b.WriteString(colorize(text[written:], theme.Context))
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package tracerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/logrusorgru/aurora"
+)
+
+func TestSyntaxTheme(t *testing.T) {
+	forceColor(t)
+	tracerr.SetPrintOptions(tracerr.WithTheme(tracerr.SyntaxTheme), tracerr.WithMaxFrames(1))
+	defer tracerr.SetPrintOptions()
+
+	output := tracerr.SprintSourceColor(addFrameC("some error"), 2, 1)
+	expected := []string{
+		aurora.Black("16").String() + "\t" +
+			aurora.Magenta("func").String() + " addFrameC(message string) error {",
+		aurora.Black("18").String() + "\t}",
+	}
+	for _, row := range expected {
+		if !strings.Contains(output, row+"\n") {
+			t.Errorf("output = %#v; want to contain %#v", output, row)
+		}
+	}
+
+	theme := tracerr.Theme{
+		String:  aurora.GreenFg,
+		Number:  aurora.CyanFg,
+		Comment: aurora.BlackFg,
+	}
+	tracerr.SetPrintOptions(tracerr.WithTheme(theme), tracerr.WithMaxFrames(1))
+	output = tracerr.SprintSourceColor(syntaxSample(), 1, 1)
+	expectedRow := "\tx := strings.Repeat(" + aurora.Green(`"a"`).String() + ", " + aurora.Cyan("42").String() + ") " +
+		aurora.Black("// comment").String()
+	if !strings.Contains(output, expectedRow+"\n") {
+		t.Errorf("output = %#v; want to contain %#v", output, expectedRow)
+	}
+}
+
+func syntaxSample() error {
+	x := strings.Repeat("a", 42) // comment
+	return tracerr.New(x)
+}
+
+func TestSyntaxThemeDefaultColors(t *testing.T) {
+	theme := tracerr.SyntaxTheme
+	theme.Keyword, theme.String, theme.Number, theme.Comment = 0, 0, 0, 0
+	if theme != tracerr.DefaultTheme {
+		t.Errorf("SyntaxTheme without syntax colors = %#v; want %#v", theme, tracerr.DefaultTheme)
+	}
+}
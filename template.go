@@ -0,0 +1,94 @@
+package tracerr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is passed to a template executed by SprintTemplate.
+type TemplateData struct {
+	// Error is the original error.
+	Error error
+	// Message is an error message.
+	Message string
+	// Frames is a stack trace, it's empty if error is not of type Error.
+	Frames []Frame
+}
+
+// TemplateFuncs returns functions available in templates of SprintTemplate:
+//
+//	relpath   path relative to the working directory, e.g. {{relpath .Path}}
+//	shortfunc function name without package path, e.g. {{shortfunc .Func}}
+//	source    source fragment of a frame, e.g. {{range source . 2 1}}{{.Number}} {{.Text}}{{end}}
+//
+// Each line of source fragment has Number, Text and Current fields.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"relpath":   relPath,
+		"shortfunc": shortFunc,
+		"source":    templateSource,
+	}
+}
+
+// ParseTemplate parses text as a template for SprintTemplate
+// with TemplateFuncs available.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("tracerr").Funcs(TemplateFuncs()).Parse(text)
+}
+
+// SprintTemplate returns error output rendered by tmpl,
+// which is executed with TemplateData.
+func SprintTemplate(err error, tmpl *template.Template) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	data := TemplateData{
+		Error:   err,
+		Message: err.Error(),
+	}
+	if e, ok := err.(Error); ok {
+		o := currentPrintOptions()
+		data.Frames = orderFrames(&o, o.frames(e))
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func relPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
+
+// shortFunc returns function name without package path,
+// e.g. "(*Thing).Do" for "github.com/foo/bar.(*Thing).Do".
+func shortFunc(name string) string {
+	_, receiver, short := splitFuncName(name)
+	if strings.HasPrefix(receiver, "*") {
+		return "(" + receiver + ")." + short
+	}
+	if receiver != "" {
+		return receiver + "." + short
+	}
+	return short
+}
+
+func templateSource(frame Frame, before, after int) []sourceLine {
+	o := currentPrintOptions()
+	fragment, err := o.sourceFragment(frame, before, after)
+	if err != nil {
+		return nil
+	}
+	return fragment
+}
@@ -0,0 +1,76 @@
+package tracerr
+
+import (
+	"fmt"
+	"slices"
+)
+
+// templateError is an error formatted from template and args, see NewT.
+type templateError struct {
+	err      error
+	template string
+	args     []interface{}
+}
+
+func newTemplateError(template string, args []interface{}) *templateError {
+	return &templateError{
+		err:      fmt.Errorf(template, args...),
+		template: template,
+		args:     args,
+	}
+}
+
+func (e *templateError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns error formatted from template,
+// so errors wrapped with %w are found by errors.Is and errors.As.
+func (e *templateError) Unwrap() error {
+	return e.err
+}
+
+// Template returns format string of the error.
+func (e *templateError) Template() string {
+	return e.template
+}
+
+// Args returns a copy of arguments of the error.
+func (e *templateError) Args() []interface{} {
+	return slices.Clone(e.args)
+}
+
+// Template returns message template of the first error in err tree,
+// which has Template() string method, e.g. created by NewT,
+// or empty string. Tree is walked in the same order as by AsError.
+func Template(err error) string {
+	if t := templateOf(err); t != nil {
+		return t.Template()
+	}
+	return ""
+}
+
+// Args returns arguments of the same error as Template, or nil.
+// Args are returned only if the error has Args() []interface{} method.
+func Args(err error) []interface{} {
+	if a, ok := templateOf(err).(interface{ Args() []interface{} }); ok {
+		return a.Args()
+	}
+	return nil
+}
+
+// templateOf returns the first error in err tree with Template() string method.
+func templateOf(err error) interface{ Template() string } {
+	if err == nil {
+		return nil
+	}
+	if t, ok := err.(interface{ Template() string }); ok {
+		return t
+	}
+	for _, err := range unwrapAll(err) {
+		if t := templateOf(err); t != nil {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestNewT(t *testing.T) {
+	err := tracerr.NewT("failed to load user %d: %w", 42, io.EOF)
+	if err.Error() != "failed to load user 42: EOF" {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), "failed to load user 42: EOF")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(err, io.EOF) = false; want true")
+	}
+	if frames := err.StackTrace(); len(frames) == 0 || frames[0].Name != "TestNewT" {
+		t.Errorf("err.StackTrace() = %#v; want to start at the caller", frames)
+	}
+
+	wrapped := fmt.Errorf("handle: %w", err)
+	if template := tracerr.Template(wrapped); template != "failed to load user %d: %w" {
+		t.Errorf("tracerr.Template(wrapped) = %#v; want %#v", template, "failed to load user %d: %w")
+	}
+	expected := []interface{}{42, io.EOF}
+	args := tracerr.Args(wrapped)
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("tracerr.Args(wrapped) = %#v; want %#v", args, expected)
+	}
+	args[0] = 0
+	if !reflect.DeepEqual(tracerr.Args(wrapped), expected) {
+		t.Errorf("tracerr.Args(wrapped) = %#v after modification; want %#v", tracerr.Args(wrapped), expected)
+	}
+}
+
+func TestTemplateMissing(t *testing.T) {
+	for i, err := range []error{nil, io.EOF, tracerr.Errorf("user %d", 42)} {
+		if template := tracerr.Template(err); template != "" {
+			t.Errorf("case #%d: tracerr.Template(%v) = %#v; want empty", i, err, template)
+		}
+		if args := tracerr.Args(err); args != nil {
+			t.Errorf("case #%d: tracerr.Args(%v) = %#v; want nil", i, err, args)
+		}
+	}
+}
+
+func TestFingerprintTemplate(t *testing.T) {
+	a := tracerr.CustomError(tracerr.NewT("user %d", 1).Unwrap(), nil)
+	b := tracerr.CustomError(tracerr.NewT("user %d", 2).Unwrap(), nil)
+	if tracerr.Fingerprint(a) != tracerr.Fingerprint(b) {
+		t.Errorf("tracerr.Fingerprint(a) != tracerr.Fingerprint(b); want the same for the same template")
+	}
+	c := tracerr.CustomError(tracerr.NewT("group %d", 1).Unwrap(), nil)
+	if tracerr.Fingerprint(a) == tracerr.Fingerprint(c) {
+		t.Errorf("tracerr.Fingerprint(a) == tracerr.Fingerprint(c); want different for different templates")
+	}
+}
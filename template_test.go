@@ -0,0 +1,66 @@
+package tracerr_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintTemplate(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := tracerr.ParseTemplate(
+		`msg={{.Message}}{{range .Frames}} at={{relpath .Path}}:{{.Line}} fn={{shortfunc .Func}}` +
+			`{{range source . 0 0}} src={{printf "%q" .Text}}{{end}}{{end}}`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := tracerr.SprintTemplate(nil, tmpl)
+	if output != "" || err != nil {
+		t.Errorf(
+			"tracerr.SprintTemplate(nil, tmpl) = %#v, %#v; want %#v, %#v",
+			output, err, "", nil,
+		)
+	}
+
+	output, err = tracerr.SprintTemplate(errors.New("regular error"), tmpl)
+	if output != "msg=regular error" || err != nil {
+		t.Errorf(
+			"tracerr.SprintTemplate(err, tmpl) = %#v, %#v; want %#v, %#v",
+			output, err, "msg=regular error", nil,
+		)
+	}
+
+	traced := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "github.com/foo/bar.(*Thing).Do",
+				Line: 17,
+				Path: filepath.Join(wd, "error_helper_test.go"),
+			},
+			{
+				Func: "github.com/foo/bar.Baz",
+				Line: 42,
+				Path: "/tmp/not_exists.go",
+			},
+		},
+	)
+	output, err = tracerr.SprintTemplate(traced, tmpl)
+	expected := `msg=some error` +
+		` at=error_helper_test.go:17 fn=(*Thing).Do src="\treturn tracerr.New(message)"` +
+		` at=/tmp/not_exists.go:42 fn=Baz`
+	if output != expected || err != nil {
+		t.Errorf(
+			"tracerr.SprintTemplate(err, tmpl) = %#v, %#v; want %#v, %#v",
+			output, err, expected, nil,
+		)
+	}
+}
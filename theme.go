@@ -0,0 +1,69 @@
+package tracerr
+
+import (
+	"github.com/logrusorgru/aurora"
+)
+
+// FaintFm is a faint format of colored output, which aurora lacks.
+// Unlike black, it's readable on both dark and light terminals.
+// It can be combined with other colors, e.g. FaintFm | aurora.RedFg.
+const FaintFm aurora.Color = 1 << 30
+
+// faint is an escape sequence of FaintFm.
+const faint = "\x1b[2m"
+
+// Theme contains colors of output printed in color.
+// Zero color means no color.
+type Theme struct {
+	// Message is a color of error message.
+	Message aurora.Color
+	// Path is a color of frame line, which contains path and function.
+	Path aurora.Color
+	// LineNumber is a color of line number of source context lines.
+	LineNumber aurora.Color
+	// Highlight is a color of the whole traced source line.
+	Highlight aurora.Color
+	// Expression is a color of called expression in traced source line,
+	// which is found by the name of called function.
+	Expression aurora.Color
+	// Context is a color of source context lines.
+	Context aurora.Color
+	// Warning is a color of messages about unavailable source.
+	Warning aurora.Color
+	// Dimmed is a color of frame line of frames other than application,
+	// see WithDimNonAppFrames.
+	Dimmed aurora.Color
+	// Keyword, String, Number and Comment are colors of Go syntax
+	// of source context lines, see SyntaxTheme.
+	Keyword aurora.Color
+	String  aurora.Color
+	Number  aurora.Color
+	Comment aurora.Color
+}
+
+// DefaultTheme is a theme used by default for colored output.
+var DefaultTheme = Theme{
+	Path:       aurora.BoldFm,
+	LineNumber: aurora.BlackFg,
+	Highlight:  aurora.RedFg,
+	Expression: aurora.InverseFm | aurora.RedFg,
+	Warning:    aurora.BrownFg,
+	Dimmed:     FaintFm,
+}
+
+// WithTheme sets a theme for colored output.
+func WithTheme(theme Theme) PrintOption {
+	return func(o *printOptions) {
+		o.theme = theme
+	}
+}
+
+func colorize(message string, color aurora.Color) string {
+	if color&FaintFm == 0 {
+		return aurora.Colorize(message, color).String()
+	}
+	if color &^= FaintFm; color == 0 {
+		return faint + message + "\x1b[0m"
+	}
+	return faint + aurora.Colorize(message, color).String()
+}
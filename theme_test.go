@@ -0,0 +1,84 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithTheme(t *testing.T) {
+	tracerr.SetPrintOptions(tracerr.WithTheme(tracerr.Theme{
+		Message:    aurora.MagentaFg,
+		Path:       aurora.BlueFg,
+		LineNumber: aurora.GrayFg,
+		Highlight:  aurora.GreenFg,
+		Context:    aurora.CyanFg,
+		Warning:    aurora.InverseFm,
+	}))
+	defer tracerr.SetPrintOptions()
+
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 17,
+				Path: "error_helper_test.go",
+			},
+			{
+				Func: "main.Bar",
+				Line: 42,
+				Path: "/tmp/not_exists.go",
+			},
+		},
+	)
+	output := tracerr.SprintSourceColor(err, 1, 1)
+	expectedRows := []string{
+		aurora.Magenta("some error").String(),
+		"",
+		aurora.Blue("error_helper_test.go:17 main.Foo()").String(),
+		aurora.Gray("16").String() + "\t" + aurora.Cyan("func addFrameC(message string) error {").String(),
+		aurora.Green("17\t\treturn tracerr.New(message)").String(),
+		aurora.Gray("18").String() + "\t" + aurora.Cyan("}").String(),
+		"",
+		aurora.Blue("/tmp/not_exists.go:42 main.Bar()").String(),
+		aurora.Inverse("tracerr: file /tmp/not_exists.go not found").String(),
+		"",
+	}
+	expected := strings.Join(expectedRows, "\n")
+	if output != expected {
+		t.Errorf(
+			"tracerr.SprintSourceColor(err, 1, 1) = %#v; want %#v",
+			output, expected,
+		)
+	}
+}
+
+func TestWithThemeFaint(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), nil)
+	cases := []struct {
+		Color    aurora.Color
+		Expected string
+	}{
+		{
+			Color:    tracerr.FaintFm,
+			Expected: "\x1b[2msome error\x1b[0m",
+		},
+		{
+			Color:    tracerr.FaintFm | aurora.RedFg,
+			Expected: "\x1b[2m" + aurora.Red("some error").String(),
+		},
+	}
+	for i, c := range cases {
+		tracerr.SetPrintOptions(tracerr.WithTheme(tracerr.Theme{Message: c.Color}))
+		output := tracerr.SprintSourceColor(err)
+		tracerr.SetPrintOptions()
+		if !strings.HasPrefix(output, c.Expected) {
+			t.Errorf("case #%d: tracerr.SprintSourceColor(err) = %#v; want prefix %#v", i, output, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tracerr
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+)
+
+// TraceContext is IDs of a span of a distributed trace, in which an error
+// happened, such as of OpenTelemetry, as lowercase hex strings.
+type TraceContext struct {
+	// TraceID is a 16 bytes ID of the trace.
+	TraceID string `json:"traceId"`
+	// SpanID is an 8 bytes ID of the span.
+	SpanID string `json:"spanId"`
+}
+
+type contextTraceKey struct{}
+
+// traceExtractor extracts trace context of a tracing library,
+// see SetTraceExtractor.
+var traceExtractor atomic.Pointer[func(ctx context.Context) (TraceContext, bool)]
+
+// ParseTraceparent parses value of W3C traceparent header,
+// such as "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+// It reports false if the value is malformed or IDs are all zeros.
+func ParseTraceparent(traceparent string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		!isTraceID(parts[1], 32) || !isTraceID(parts[2], 16) || len(parts[3]) != 2 {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceID: parts[1], SpanID: parts[2]}, true
+}
+
+// isTraceID reports whether id is a non-zero lowercase hex of n digits.
+func isTraceID(id string, n int) bool {
+	if len(id) != n || strings.ToLower(id) != id || strings.Trim(id, "0") == "" {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// ContextWithTrace returns a copy of ctx with trace context,
+// e.g. parsed from traceparent header by ParseTraceparent.
+// Errors wrapped by WrapContext with the context record it, see Trace.
+func ContextWithTrace(ctx context.Context, trace TraceContext) context.Context {
+	return context.WithValue(ctx, contextTraceKey{}, trace)
+}
+
+// SetTraceExtractor sets a function returning trace context of ctx
+// set by a tracing library, so WrapContext records it with no plumbing,
+// e.g. for OpenTelemetry:
+//
+//	tracerr.SetTraceExtractor(func(ctx context.Context) (tracerr.TraceContext, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return tracerr.TraceContext{TraceID: sc.TraceID().String(), SpanID: sc.SpanID().String()}, sc.IsValid()
+//	})
+//
+// Trace context set by ContextWithTrace is used if extract reports false.
+// Nil extract removes the extractor.
+func SetTraceExtractor(extract func(ctx context.Context) (TraceContext, bool)) {
+	if extract == nil {
+		traceExtractor.Store(nil)
+		return
+	}
+	traceExtractor.Store(&extract)
+}
+
+// contextTrace returns trace context of ctx.
+func contextTrace(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	if extract := traceExtractor.Load(); extract != nil {
+		if trace, ok := (*extract)(ctx); ok && trace.TraceID != "" {
+			return trace, true
+		}
+	}
+	trace, ok := ctx.Value(contextTraceKey{}).(TraceContext)
+	return trace, ok && trace.TraceID != ""
+}
+
+// WrapTrace adds stacktrace to err by the same rules as Wrap,
+// and records trace context, unless err already has one, see Trace.
+// It's useful for errors decoded from another process.
+func WrapTrace(err error, trace TraceContext) Error {
+	e := Wrap(err)
+	if e == nil || trace.TraceID == "" {
+		return e
+	}
+	return withTrace(e, trace)
+}
+
+// withTrace returns e with trace context, unless it already has one.
+func withTrace(e Error, trace TraceContext) Error {
+	if _, ok := Trace(e); ok {
+		return e
+	}
+	d, ok := e.(*errorData)
+	if !ok {
+		return &errorData{
+			err:    e,
+			frames: RawFrames(e),
+			trace:  trace,
+		}
+	}
+	c := d.with(d.err, d.RawFrames())
+	c.trace = trace
+	return c
+}
+
+// Trace returns trace context recorded in err or any error it wraps,
+// see WrapContext. It reports false if there is none.
+func Trace(err error) (TraceContext, bool) {
+	if err == nil {
+		return TraceContext{}, false
+	}
+	if d, ok := err.(*errorData); ok && d != nil && d.trace.TraceID != "" {
+		return d.trace, true
+	}
+	for _, wrapped := range unwrapAll(err) {
+		if trace, ok := Trace(wrapped); ok {
+			return trace, true
+		}
+	}
+	return TraceContext{}, false
+}
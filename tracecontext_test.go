@@ -0,0 +1,149 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+var testTrace = tracerr.TraceContext{
+	TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+	SpanID:  "00f067aa0ba902b7",
+}
+
+func TestParseTraceparent(t *testing.T) {
+	cases := []struct {
+		traceparent string
+		expected    tracerr.TraceContext
+		ok          bool
+	}{
+		{
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expected:    testTrace,
+			ok:          true,
+		},
+		{
+			// Future versions may append fields.
+			traceparent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra",
+			expected:    testTrace,
+			ok:          true,
+		},
+		{traceparent: ""},
+		{traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{traceparent: "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01"},
+		{traceparent: "00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01"},
+	}
+	for i, c := range cases {
+		trace, ok := tracerr.ParseTraceparent(c.traceparent)
+		if trace != c.expected || ok != c.ok {
+			t.Errorf(
+				"case #%d: tracerr.ParseTraceparent(%#v) = %#v, %v; want %#v, %v",
+				i, c.traceparent, trace, ok, c.expected, c.ok,
+			)
+		}
+	}
+}
+
+func TestWrapContextTrace(t *testing.T) {
+	ctx := tracerr.ContextWithTrace(context.Background(), testTrace)
+	err := tracerr.WrapContext(ctx, io.EOF)
+	if trace, ok := tracerr.Trace(fmt.Errorf("context: %w", err)); trace != testTrace || !ok {
+		t.Errorf("tracerr.Trace(err) = %#v, %v; want %#v, true", trace, ok, testTrace)
+	}
+	if frames := err.StackTrace(); len(frames) == 0 || frames[0].Name != "TestWrapContextTrace" {
+		t.Errorf("err.StackTrace() = %#v; want to start at the caller", frames)
+	}
+
+	// Trace context of the error takes precedence over the context.
+	other := tracerr.ContextWithTrace(context.Background(), tracerr.TraceContext{TraceID: "1", SpanID: "2"})
+	if trace, _ := tracerr.Trace(tracerr.WrapContext(other, err)); trace != testTrace {
+		t.Errorf("tracerr.Trace(rewrapped) = %#v; want %#v", trace, testTrace)
+	}
+
+	if trace, ok := tracerr.Trace(tracerr.New("some error")); ok {
+		t.Errorf("tracerr.Trace(err) = %#v, true; want false", trace)
+	}
+}
+
+func TestSetTraceExtractor(t *testing.T) {
+	type spanKey struct{}
+	tracerr.SetTraceExtractor(func(ctx context.Context) (tracerr.TraceContext, bool) {
+		trace, ok := ctx.Value(spanKey{}).(tracerr.TraceContext)
+		return trace, ok
+	})
+	defer tracerr.SetTraceExtractor(nil)
+
+	ctx := context.WithValue(context.Background(), spanKey{}, testTrace)
+	if trace, _ := tracerr.Trace(tracerr.WrapContext(ctx, io.EOF)); trace != testTrace {
+		t.Errorf("tracerr.Trace(err) = %#v; want %#v", trace, testTrace)
+	}
+	// Trace context of ContextWithTrace is used if extractor has none.
+	ctx = tracerr.ContextWithTrace(context.Background(), testTrace)
+	if trace, _ := tracerr.Trace(tracerr.WrapContext(ctx, io.EOF)); trace != testTrace {
+		t.Errorf("tracerr.Trace(err) = %#v; want %#v", trace, testTrace)
+	}
+}
+
+func TestTraceOutput(t *testing.T) {
+	err := tracerr.WrapTrace(tracerr.CustomError(errors.New("some error"), diffFrames("read")), testTrace)
+	expected := "some error\n" +
+		"trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7\n" +
+		"/src/main.go:10 main.read()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	b, _ := json.Marshal(tracerr.Tree(err))
+	expectedJSON := `{"message":"some error","frames":["/src/main.go:10 main.read()"],"frameCount":1,` +
+		`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7"}`
+	if string(b) != expectedJSON {
+		t.Errorf("json.Marshal(tracerr.Tree(err)) = %s; want %s", b, expectedJSON)
+	}
+}
+
+func TestTraceSerializers(t *testing.T) {
+	err := tracerr.WrapTrace(tracerr.CustomError(errors.New("some error"), diffFrames("read")), testTrace)
+
+	b, _ := tracerr.MarshalBinary(err)
+	decoded, decodeErr := tracerr.UnmarshalBinary(b)
+	if decodeErr != nil {
+		t.Fatalf("tracerr.UnmarshalBinary(b) error = %v", decodeErr)
+	}
+	if trace, _ := tracerr.Trace(decoded); trace != testTrace {
+		t.Errorf("tracerr.Trace(binary) = %#v; want %#v", trace, testTrace)
+	}
+
+	b, _ = tracerr.MarshalBinaryBatch([]error{err, io.EOF})
+	batch, decodeErr := tracerr.UnmarshalBinaryBatch(b)
+	if decodeErr != nil {
+		t.Fatalf("tracerr.UnmarshalBinaryBatch(b) error = %v", decodeErr)
+	}
+	if trace, _ := tracerr.Trace(batch[0]); trace != testTrace {
+		t.Errorf("tracerr.Trace(batch[0]) = %#v; want %#v", trace, testTrace)
+	}
+	if trace, ok := tracerr.Trace(batch[1]); ok {
+		t.Errorf("tracerr.Trace(batch[1]) = %#v, true; want false", trace)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobReply{Err: err}); err != nil {
+		t.Fatalf("Encode() error = %#v; want nil", err)
+	}
+	var reply gobReply
+	if err := gob.NewDecoder(&buf).Decode(&reply); err != nil {
+		t.Fatalf("Decode() error = %#v; want nil", err)
+	}
+	if trace, _ := tracerr.Trace(reply.Err); trace != testTrace {
+		t.Errorf("tracerr.Trace(gob) = %#v; want %#v", trace, testTrace)
+	}
+}
@@ -0,0 +1,63 @@
+package tracerr
+
+// TracedError is Error with access to attributes of the error,
+// errors created by this package implement it, e.g.:
+//
+//	if e, ok := tracerr.AsError(err); ok {
+//		if t, ok := e.(tracerr.TracedError); ok {
+//			fields := t.Fields()
+//		}
+//	}
+//
+// Other libraries can implement it as well, then Fields and Fingerprint
+// use the methods of their errors. Such methods must not call
+// the functions of this package with the error itself.
+type TracedError interface {
+	Error
+	// Fields returns fields attached to the error and errors it wraps,
+	// see Fields.
+	Fields() map[string]interface{}
+	// Code returns code of the error or errors it wraps, see Code.
+	Code() string
+	// Fingerprint returns identifier of the place where the error
+	// was traced, see Fingerprint.
+	Fingerprint() string
+	// Chain returns the error and errors it wraps, see Chain.
+	Chain() []error
+}
+
+var _ TracedError = (*errorData)(nil)
+
+// Fields returns fields attached to the error and errors it wraps.
+func (e *errorData) Fields() map[string]interface{} {
+	return Fields(e)
+}
+
+// Code returns code of the first error it wraps, which has one.
+func (e *errorData) Code() string {
+	return Code(e.Unwrap())
+}
+
+// Fingerprint returns identifier of the place where the error was traced.
+func (e *errorData) Fingerprint() string {
+	return Fingerprint(e)
+}
+
+// Chain returns the error and errors it wraps.
+func (e *errorData) Chain() []error {
+	return Chain(e)
+}
+
+// Chain returns err and every error it wraps, both with Unwrap() error
+// and Unwrap() []error, in the same order as walked by AsError.
+// It returns nil if err is nil.
+func Chain(err error) []error {
+	if err == nil {
+		return nil
+	}
+	chain := []error{err}
+	for _, wrapped := range unwrapAll(err) {
+		chain = append(chain, Chain(wrapped)...)
+	}
+	return chain
+}
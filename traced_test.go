@@ -0,0 +1,93 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+// externalError is TracedError implemented by another library.
+type externalError struct {
+	err tracerr.Error
+}
+
+func (e externalError) Error() string               { return e.err.Error() }
+func (e externalError) StackTrace() []tracerr.Frame { return e.err.StackTrace() }
+func (e externalError) RawFrames() []tracerr.Frame  { return tracerr.RawFrames(e.err) }
+func (e externalError) Unwrap() error               { return e.err }
+
+func (e externalError) Fields() map[string]interface{} {
+	return map[string]interface{}{"library": "external"}
+}
+
+func (e externalError) Code() string {
+	return "EXTERNAL"
+}
+
+func (e externalError) Fingerprint() string {
+	return "external"
+}
+
+func (e externalError) Chain() []error {
+	return []error{e}
+}
+
+var _ tracerr.TracedError = externalError{}
+
+func TestTracedError(t *testing.T) {
+	ctx := tracerr.ContextWithFields(context.Background(), map[string]interface{}{"request_id": "abc"})
+	err := tracerr.WrapContext(ctx, &codeError{code: "E42"})
+	traced, ok := err.(tracerr.TracedError)
+	if !ok {
+		t.Fatalf("err.(tracerr.TracedError) ok = false; want true")
+	}
+	if fields := traced.Fields(); !reflect.DeepEqual(fields, tracerr.Fields(err)) {
+		t.Errorf("traced.Fields() = %#v; want %#v", fields, tracerr.Fields(err))
+	}
+	if code := traced.Code(); code != "E42" {
+		t.Errorf("traced.Code() = %#v; want %#v", code, "E42")
+	}
+	if fingerprint := traced.Fingerprint(); fingerprint != tracerr.Fingerprint(err) {
+		t.Errorf("traced.Fingerprint() = %#v; want %#v", fingerprint, tracerr.Fingerprint(err))
+	}
+	if chain := traced.Chain(); len(chain) != 2 || chain[0] != err || chain[1] != err.Unwrap() {
+		t.Errorf("traced.Chain() = %#v; want the error and its cause", chain)
+	}
+}
+
+func TestTracedErrorExternal(t *testing.T) {
+	err := fmt.Errorf("call: %w", externalError{tracerr.New("failed")})
+	if fields := tracerr.Fields(err); !reflect.DeepEqual(fields, map[string]interface{}{"library": "external"}) {
+		t.Errorf("tracerr.Fields(err) = %#v; want fields of external error", fields)
+	}
+	if fingerprint := tracerr.Fingerprint(externalError{tracerr.New("failed")}); fingerprint != "external" {
+		t.Errorf("tracerr.Fingerprint(err) = %#v; want %#v", fingerprint, "external")
+	}
+	if code := tracerr.Code(err); code != "EXTERNAL" {
+		t.Errorf("tracerr.Code(err) = %#v; want %#v", code, "EXTERNAL")
+	}
+}
+
+func TestChain(t *testing.T) {
+	inner := tracerr.Wrap(io.EOF)
+	joined := errors.Join(inner, io.ErrUnexpectedEOF)
+	cases := []struct {
+		err      error
+		expected []error
+	}{
+		{nil, nil},
+		{io.EOF, []error{io.EOF}},
+		{inner, []error{inner, io.EOF}},
+		{joined, []error{joined, inner, io.EOF, io.ErrUnexpectedEOF}},
+	}
+	for i, c := range cases {
+		if chain := tracerr.Chain(c.err); !reflect.DeepEqual(chain, c.expected) {
+			t.Errorf("case #%d: tracerr.Chain(%v) = %#v; want %#v", i, c.err, chain, c.expected)
+		}
+	}
+}
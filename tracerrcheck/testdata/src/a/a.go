@@ -0,0 +1,64 @@
+package a
+
+import (
+	"ext"
+
+	"github.com/kadaan/tracerr"
+)
+
+func direct() error {
+	return ext.Do() // want `error returned by ext.Do is not wrapped by tracerr`
+}
+
+func tuple() (int, error) {
+	return ext.Open() // want `error returned by ext.Open is not wrapped by tracerr`
+}
+
+func variable() (int, error) {
+	n, err := ext.Open()
+	if err != nil {
+		return 0, err // want `error returned by ext.Open is not wrapped by tracerr`
+	}
+	return n, nil
+}
+
+func ifInit() error {
+	if err := ext.Do(); err != nil {
+		return err // want `error returned by ext.Do is not wrapped by tracerr`
+	}
+	return nil
+}
+
+func method(r *ext.Reader) error {
+	_, err := r.Read()
+	return err // want `error returned by ext.Reader.Read is not wrapped by tracerr`
+}
+
+func closure() {
+	_ = func() error {
+		return ext.Do() // want `error returned by ext.Do is not wrapped by tracerr`
+	}
+}
+
+func wrapped() error {
+	if err := ext.Do(); err != nil {
+		return tracerr.Wrap(err)
+	}
+	return tracerr.New("failed")
+}
+
+func reassigned() error {
+	err := ext.Do()
+	if err != nil {
+		err = tracerr.Wrap(err)
+	}
+	return err
+}
+
+func local() error {
+	return direct()
+}
+
+func ignored() error {
+	return ext.Ignored()
+}
@@ -0,0 +1,19 @@
+package ext
+
+type Reader struct{}
+
+func (r *Reader) Read() (int, error) {
+	return 0, nil
+}
+
+func Do() error {
+	return nil
+}
+
+func Open() (int, error) {
+	return 0, nil
+}
+
+func Ignored() error {
+	return nil
+}
@@ -0,0 +1,13 @@
+package tracerr
+
+type Error interface {
+	Error() string
+}
+
+func New(message string) Error {
+	return nil
+}
+
+func Wrap(err error) Error {
+	return nil
+}
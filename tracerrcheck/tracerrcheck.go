@@ -0,0 +1,223 @@
+// Package tracerrcheck defines an analyzer, which reports functions
+// returning errors of calls to other packages without wrapping them
+// by tracerr, so their stack traces would be lost.
+//
+// It's run by go vet with command tracerrcheck:
+//
+//	go vet -vettool=$(which tracerrcheck) ./...
+package tracerrcheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// tracerrPackage is an import path of tracerr.
+const tracerrPackage = "github.com/kadaan/tracerr"
+
+const doc = `check that errors of external calls are wrapped by tracerr
+
+The tracerrcheck analyzer reports return statements of functions,
+which return an error got from a function or method of another package,
+unless it's wrapped by tracerr, e.g. by tracerr.Wrap. Packages of the same
+module and test files are not checked.`
+
+// Analyzer reports errors returned without wrapping by tracerr.
+var Analyzer = &analysis.Analyzer{
+	Name:     "tracerrcheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// ignore is a value of -ignore flag.
+var ignore string
+
+func init() {
+	Analyzer.Flags.StringVar(
+		&ignore, "ignore", "",
+		"comma-separated list of packages and functions, which errors may be returned as is, "+
+			"e.g. io,context.Context.Err",
+	)
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// assignment is an assignment of a variable.
+type assignment struct {
+	pos token.Pos
+	// call is a call producing the value, it's nil for other expressions.
+	call *ast.CallExpr
+}
+
+type checker struct {
+	pass    *analysis.Pass
+	ignored map[string]bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{
+		pass:    pass,
+		ignored: make(map[string]bool),
+	}
+	for _, name := range strings.Split(ignore, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			c.ignored[name] = true
+		}
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+	}
+	insp.Preorder(nodeFilter, func(n ast.Node) {
+		if strings.HasSuffix(pass.Fset.File(n.Pos()).Name(), "_test.go") {
+			return
+		}
+		var body *ast.BlockStmt
+		var sig *types.Signature
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if fn, ok := pass.TypesInfo.Defs[n.Name].(*types.Func); ok {
+				sig = fn.Type().(*types.Signature)
+			}
+			body = n.Body
+		case *ast.FuncLit:
+			sig, _ = pass.TypesInfo.TypeOf(n).(*types.Signature)
+			body = n.Body
+		}
+		if body == nil || sig == nil || !returnsError(sig) {
+			return
+		}
+		c.checkFunc(body, sig.Results().Len())
+	})
+	return nil, nil
+}
+
+func returnsError(sig *types.Signature) bool {
+	results := sig.Results()
+	return results.Len() > 0 && types.Identical(results.At(results.Len()-1).Type(), errorType)
+}
+
+// checkFunc reports return statements of function body,
+// which has n results.
+func (c *checker) checkFunc(body *ast.BlockStmt, n int) {
+	assignments := make(map[types.Object][]assignment)
+	var returns []*ast.ReturnStmt
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// Nested functions are checked on their own.
+			return false
+		case *ast.AssignStmt:
+			c.recordAssignments(assignments, node.Pos(), node.Lhs, node.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			c.recordAssignments(assignments, node.Pos(), lhs, node.Values)
+		case *ast.ReturnStmt:
+			returns = append(returns, node)
+		}
+		return true
+	})
+	for _, ret := range returns {
+		if len(ret.Results) == 0 {
+			continue
+		}
+		if len(ret.Results) == 1 && n > 1 {
+			// Results of a call, e.g. return os.Open(name).
+			if call, ok := ast.Unparen(ret.Results[0]).(*ast.CallExpr); ok {
+				c.checkCall(call, call)
+			}
+			continue
+		}
+		switch result := ast.Unparen(ret.Results[len(ret.Results)-1]).(type) {
+		case *ast.CallExpr:
+			c.checkCall(result, result)
+		case *ast.Ident:
+			obj := c.pass.TypesInfo.Uses[result]
+			var last *assignment
+			for i, a := range assignments[obj] {
+				if a.pos < ret.Pos() {
+					last = &assignments[obj][i]
+				}
+			}
+			if last != nil && last.call != nil {
+				c.checkCall(result, last.call)
+			}
+		}
+	}
+}
+
+func (c *checker) recordAssignments(assignments map[types.Object][]assignment, pos token.Pos, lhs, rhs []ast.Expr) {
+	for i, expr := range lhs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+		obj := c.pass.TypesInfo.ObjectOf(ident)
+		if obj == nil || !types.Identical(obj.Type(), errorType) {
+			continue
+		}
+		a := assignment{pos: pos}
+		switch {
+		case len(rhs) == 1 && len(lhs) > 1:
+			a.call, _ = ast.Unparen(rhs[0]).(*ast.CallExpr)
+		case i < len(rhs):
+			a.call, _ = ast.Unparen(rhs[i]).(*ast.CallExpr)
+		}
+		assignments[obj] = append(assignments[obj], a)
+	}
+}
+
+// checkCall reports node if error produced by call isn't wrapped.
+func (c *checker) checkCall(node ast.Node, call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return
+	}
+	pkg := fn.Pkg().Path()
+	if pkg == tracerrPackage || strings.HasPrefix(pkg, tracerrPackage+"/") || c.local(pkg) {
+		return
+	}
+	name := funcName(fn)
+	if c.ignored[pkg] || c.ignored[name] {
+		return
+	}
+	c.pass.Reportf(node.Pos(), "error returned by %s is not wrapped by tracerr", name)
+}
+
+// local reports whether pkg belongs to the same module as analyzed package.
+func (c *checker) local(pkg string) bool {
+	if pkg == c.pass.Pkg.Path() {
+		return true
+	}
+	module := c.pass.Module
+	return module != nil && module.Path != "" &&
+		(pkg == module.Path || strings.HasPrefix(pkg, module.Path+"/"))
+}
+
+// funcName returns a qualified name of fn, such as "os.Open"
+// or "context.Context.Err".
+func funcName(fn *types.Func) string {
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return fn.Pkg().Path() + "." + fn.Name()
+}
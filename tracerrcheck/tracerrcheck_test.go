@@ -0,0 +1,17 @@
+package tracerrcheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/kadaan/tracerr/tracerrcheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	if err := tracerrcheck.Analyzer.Flags.Set("ignore", "ext.Ignored"); err != nil {
+		t.Fatal(err)
+	}
+	defer tracerrcheck.Analyzer.Flags.Set("ignore", "")
+	analysistest.Run(t, analysistest.TestData(), tracerrcheck.Analyzer, "a")
+}
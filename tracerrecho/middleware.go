@@ -0,0 +1,39 @@
+// Package tracerrecho provides Echo middleware recovering panics of handlers
+// and wrapping their errors into traced errors.
+package tracerrecho
+
+import (
+	"net/http"
+
+	"github.com/kadaan/tracerr"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware returns Echo middleware, which converts panics of handlers
+// into traced errors and wraps errors returned by handlers with tracerr.Wrap,
+// so Echo error handler gets traced errors.
+// Stack trace of an error without one points to the middleware.
+//
+// *echo.HTTPError is returned as is, because Echo error handler
+// doesn't recognize it wrapped. Panics with http.ErrAbortHandler
+// are passed through to abort the request.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				rec := recover()
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				if e := tracerr.PanicError(rec); e != nil {
+					err = e
+				}
+			}()
+			err = next(c)
+			if _, ok := err.(*echo.HTTPError); ok {
+				return err
+			}
+			return tracerr.Wrap(err)
+		}
+	}
+}
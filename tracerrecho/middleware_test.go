@@ -0,0 +1,80 @@
+package tracerrecho_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrecho"
+	"github.com/labstack/echo/v4"
+)
+
+func panickingHandler(c echo.Context) error {
+	panic("handler failure")
+}
+
+func failingHandler(c echo.Context) error {
+	return errors.New("handler failure")
+}
+
+func notFoundHandler(c echo.Context) error {
+	return echo.NewHTTPError(http.StatusNotFound)
+}
+
+type MiddlewareTestCase struct {
+	Path    string
+	Code    int
+	Message string
+	Traced  bool
+}
+
+func TestMiddleware(t *testing.T) {
+	var got error
+	e := echo.New()
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		got = err
+		e.DefaultHTTPErrorHandler(err, c)
+	}
+	e.Use(tracerrecho.Middleware())
+	e.GET("/panic", panickingHandler)
+	e.GET("/error", failingHandler)
+	e.GET("/missing", notFoundHandler)
+
+	cases := []MiddlewareTestCase{
+		{
+			Path:    "/panic",
+			Code:    http.StatusInternalServerError,
+			Message: "panic: handler failure",
+			Traced:  true,
+		},
+		{
+			Path:    "/error",
+			Code:    http.StatusInternalServerError,
+			Message: "handler failure",
+			Traced:  true,
+		},
+		{
+			Path:    "/missing",
+			Code:    http.StatusNotFound,
+			Message: "code=404, message=Not Found",
+		},
+	}
+
+	for i, c := range cases {
+		got = nil
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.Path, nil))
+		if w.Code != c.Code {
+			t.Errorf("case #%d: w.Code = %d; want %d", i, w.Code, c.Code)
+		}
+		if got == nil || got.Error() != c.Message {
+			t.Errorf("case #%d: err = %#v; want %#v", i, got, c.Message)
+			continue
+		}
+		if _, traced := got.(tracerr.Error); traced != c.Traced {
+			t.Errorf("case #%d: err is traced = %v; want %v", i, traced, c.Traced)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package tracerrexec wraps errors of os/exec commands with stack traces,
+// command lines, exit codes and tails of stderr.
+package tracerrexec
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kadaan/tracerr"
+)
+
+// Field names of command metadata attached to errors, see tracerr.Fields.
+const (
+	// FieldCommand is a command line of the command.
+	FieldCommand = "exec.command"
+	// FieldExitCode is an exit code of the command,
+	// it's missing if the command didn't start.
+	FieldExitCode = "exec.exit_code"
+	// FieldStderr is a tail of stderr of the command, see MaxStderrTail.
+	FieldStderr = "exec.stderr"
+)
+
+// MaxStderrTail is a maximum number of the last bytes of stderr
+// kept by Wrap.
+var MaxStderrTail = 1024
+
+// Wrap returns err of cmd with stack trace of the caller and fields
+// of command line, exit code and a tail of stderr, or nil if err is nil.
+//
+// Stderr is taken from exec.ExitError, which has it if cmd was run
+// by Output, or from cmd.Stderr, if it's a *bytes.Buffer
+// or a *strings.Builder. Truncated stderr starts with "...".
+//
+//	cmd := exec.CommandContext(ctx, "git", "fetch")
+//	if _, err := cmd.Output(); err != nil {
+//		return tracerrexec.Wrap(cmd, err)
+//	}
+func Wrap(cmd *exec.Cmd, err error) error {
+	if tracerr.IsNil(err) {
+		return nil
+	}
+	fields := make(map[string]interface{}, 3)
+	var stderr []byte
+	if cmd != nil {
+		fields[FieldCommand] = cmd.String()
+		if cmd.ProcessState != nil {
+			fields[FieldExitCode] = cmd.ProcessState.ExitCode()
+		}
+		switch w := cmd.Stderr.(type) {
+		case *bytes.Buffer:
+			stderr = w.Bytes()
+		case *strings.Builder:
+			stderr = []byte(w.String())
+		}
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		fields[FieldExitCode] = exitErr.ExitCode()
+		if len(exitErr.Stderr) > 0 {
+			stderr = exitErr.Stderr
+		}
+	}
+	if tail := stderrTail(stderr); tail != "" {
+		fields[FieldStderr] = tail
+	}
+	return tracerr.WrapFields(tracerr.WrapSkip(err, 1), fields)
+}
+
+// stderrTail returns up to MaxStderrTail last bytes of stderr
+// with trailing whitespace trimmed, truncated stderr starts with "...".
+func stderrTail(stderr []byte) string {
+	stderr = bytes.TrimRight(stderr, " \t\r\n")
+	if MaxStderrTail <= 0 || len(stderr) <= MaxStderrTail {
+		return string(stderr)
+	}
+	stderr = stderr[len(stderr)-MaxStderrTail:]
+	// Don't cut a multibyte character in half.
+	for len(stderr) > 0 && !utf8.RuneStart(stderr[0]) {
+		stderr = stderr[1:]
+	}
+	return "..." + string(stderr)
+}
@@ -0,0 +1,73 @@
+package tracerrexec_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrexec"
+)
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("TRACERREXEC_HELPER") == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s\n", os.Getenv("TRACERREXEC_HELPER"))
+	os.Exit(3)
+}
+
+func helperCommand(stderr string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "TRACERREXEC_HELPER="+stderr)
+	return cmd
+}
+
+func TestWrap(t *testing.T) {
+	if err := tracerrexec.Wrap(exec.Command("true"), nil); err != nil {
+		t.Errorf("tracerrexec.Wrap(cmd, nil) = %#v; want nil", err)
+	}
+
+	cmd := helperCommand("fatal: not a repository")
+	_, err := cmd.Output()
+	err = tracerrexec.Wrap(cmd, err)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("errors.As(err, *exec.ExitError) = false; want true")
+	}
+	if frames := tracerr.StackTrace(err); len(frames) == 0 || frames[0].Func != "github.com/kadaan/tracerr/tracerrexec_test.TestWrap" {
+		t.Errorf("tracerr.StackTrace(err) = %#v; want to start at the caller", frames)
+	}
+	fields := tracerr.Fields(err)
+	expected := map[string]interface{}{
+		tracerrexec.FieldCommand:  cmd.String(),
+		tracerrexec.FieldExitCode: 3,
+		tracerrexec.FieldStderr:   "fatal: not a repository",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("tracerr.Fields(err)[%#v] = %#v; want %#v", name, fields[name], value)
+		}
+	}
+
+	var stderr bytes.Buffer
+	cmd = helperCommand(strings.Repeat("x", 10) + "tail")
+	cmd.Stderr = &stderr
+	defer func(n int) { tracerrexec.MaxStderrTail = n }(tracerrexec.MaxStderrTail)
+	tracerrexec.MaxStderrTail = 6
+	err = tracerrexec.Wrap(cmd, cmd.Run())
+	if tail := tracerr.Fields(err)[tracerrexec.FieldStderr]; tail != "...xxtail" {
+		t.Errorf("stderr tail = %#v; want %#v", tail, "...xxtail")
+	}
+
+	cmd = exec.Command("tracerrexec-missing-command")
+	err = tracerrexec.Wrap(cmd, cmd.Run())
+	fields = tracerr.Fields(err)
+	if _, ok := fields[tracerrexec.FieldExitCode]; ok || fields[tracerrexec.FieldCommand] != cmd.String() {
+		t.Errorf("tracerr.Fields(err) = %#v; want command without exit code", fields)
+	}
+}
@@ -0,0 +1,26 @@
+// Package tracerrfiber provides Fiber middleware recovering panics of handlers
+// and wrapping their errors into traced errors.
+package tracerrfiber
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/kadaan/tracerr"
+)
+
+// Middleware returns Fiber middleware, which converts panics of handlers
+// into traced errors and wraps errors returned by handlers with tracerr.Wrap,
+// so Fiber error handler gets traced errors.
+// Stack trace of an error without one points to the middleware.
+//
+// Wrapped *fiber.Error keeps its status code in fiber.DefaultErrorHandler,
+// which finds it with errors.As.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if e := tracerr.PanicError(recover()); e != nil {
+				err = e
+			}
+		}()
+		return tracerr.Wrap(c.Next())
+	}
+}
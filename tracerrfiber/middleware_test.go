@@ -0,0 +1,81 @@
+package tracerrfiber_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrfiber"
+)
+
+func panickingHandler(c *fiber.Ctx) error {
+	panic("handler failure")
+}
+
+func failingHandler(c *fiber.Ctx) error {
+	return errors.New("handler failure")
+}
+
+func notFoundHandler(c *fiber.Ctx) error {
+	return fiber.ErrNotFound
+}
+
+type MiddlewareTestCase struct {
+	Path    string
+	Code    int
+	Message string
+}
+
+func TestMiddleware(t *testing.T) {
+	var got error
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			got = err
+			return fiber.DefaultErrorHandler(c, err)
+		},
+	})
+	app.Use(tracerrfiber.Middleware())
+	app.Get("/panic", panickingHandler)
+	app.Get("/error", failingHandler)
+	app.Get("/missing", notFoundHandler)
+
+	cases := []MiddlewareTestCase{
+		{
+			Path:    "/panic",
+			Code:    http.StatusInternalServerError,
+			Message: "panic: handler failure",
+		},
+		{
+			Path:    "/error",
+			Code:    http.StatusInternalServerError,
+			Message: "handler failure",
+		},
+		{
+			Path:    "/missing",
+			Code:    http.StatusNotFound,
+			Message: "Not Found",
+		},
+	}
+
+	for i, c := range cases {
+		got = nil
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, c.Path, nil))
+		if err != nil {
+			t.Fatalf("case #%d: app.Test() error = %v", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.Code {
+			t.Errorf("case #%d: resp.StatusCode = %d; want %d", i, resp.StatusCode, c.Code)
+		}
+		if got == nil || got.Error() != c.Message {
+			t.Errorf("case #%d: err = %#v; want %#v", i, got, c.Message)
+			continue
+		}
+		if _, ok := got.(tracerr.Error); !ok {
+			t.Errorf("case #%d: err = %#v; want traced error", i, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package tracerrgin provides Gin middleware recovering panics of handlers
+// into traced errors.
+package tracerrgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kadaan/tracerr"
+)
+
+// Middleware returns Gin middleware, which converts panics of handlers
+// into traced errors added to c.Errors and aborts with
+// 500 Internal Server Error, so they are handled as other errors of handlers.
+//
+// Errors added to c.Errors by handlers are wrapped with tracerr.Wrap,
+// stack trace of an error without one points to the middleware.
+// Panics with http.ErrAbortHandler are passed through to abort the request.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			if err := tracerr.PanicError(rec); err != nil {
+				_ = c.Error(err)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+		for _, e := range c.Errors {
+			e.Err = tracerr.Wrap(e.Err)
+		}
+	}
+}
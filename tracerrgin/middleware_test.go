@@ -0,0 +1,70 @@
+package tracerrgin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrgin"
+)
+
+func panickingHandler(c *gin.Context) {
+	panic("handler failure")
+}
+
+func failingHandler(c *gin.Context) {
+	_ = c.Error(errors.New("handler failure"))
+	c.Status(http.StatusBadRequest)
+}
+
+type MiddlewareTestCase struct {
+	Path    string
+	Code    int
+	Message string
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got []error
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Next()
+		for _, e := range c.Errors {
+			got = append(got, e.Err)
+		}
+	}, tracerrgin.Middleware())
+	router.GET("/panic", panickingHandler)
+	router.GET("/error", failingHandler)
+
+	cases := []MiddlewareTestCase{
+		{
+			Path:    "/panic",
+			Code:    http.StatusInternalServerError,
+			Message: "panic: handler failure",
+		},
+		{
+			Path:    "/error",
+			Code:    http.StatusBadRequest,
+			Message: "handler failure",
+		},
+	}
+
+	for i, c := range cases {
+		got = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.Path, nil))
+		if w.Code != c.Code {
+			t.Errorf("case #%d: w.Code = %d; want %d", i, w.Code, c.Code)
+		}
+		if len(got) != 1 || got[0].Error() != c.Message {
+			t.Errorf("case #%d: c.Errors = %#v; want %#v", i, got, c.Message)
+			continue
+		}
+		if _, ok := got[0].(tracerr.Error); !ok || len(tracerr.StackTrace(got[0])) == 0 {
+			t.Errorf("case #%d: c.Errors[0] = %#v; want traced error", i, got[0])
+		}
+	}
+}
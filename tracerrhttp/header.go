@@ -0,0 +1,127 @@
+// Package tracerrhttp propagates traced errors between services over HTTP.
+//
+// Server sets error of a failed request in a response header or trailer,
+// client decodes it into an error chain of both client and server stack traces.
+// Middleware converts panics of handlers into traced errors.
+package tracerrhttp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kadaan/tracerr"
+)
+
+// Header is a name of response header or trailer with encoded error.
+const Header = "Tracerr-Error"
+
+// DefaultMaxFrames is a number of top frames sent in a header,
+// which keeps the header small.
+var DefaultMaxFrames = 10
+
+// headerError is a compact form of an error in a header.
+type headerError struct {
+	Message string        `json:"m"`
+	Frames  []headerFrame `json:"f,omitempty"`
+	TraceID string        `json:"t,omitempty"`
+	SpanID  string        `json:"s,omitempty"`
+}
+
+type headerFrame struct {
+	Func string `json:"fn"`
+	Path string `json:"p"`
+	Line int    `json:"l"`
+}
+
+// Encode returns compact representation of err message,
+// top DefaultMaxFrames frames and trace context, see tracerr.Trace,
+// suitable for a header value. It returns "" for nil err.
+func Encode(err error) string {
+	if err == nil {
+		return ""
+	}
+	frames := tracerr.RedactStackTrace(err)
+	if len(frames) > DefaultMaxFrames {
+		frames = frames[:DefaultMaxFrames]
+	}
+	data := headerError{
+		Message: tracerr.ScrubMessage(err.Error()),
+		Frames:  make([]headerFrame, 0, len(frames)),
+	}
+	for _, frame := range frames {
+		data.Frames = append(data.Frames, headerFrame{
+			Func: frame.Func,
+			Path: frame.Path,
+			Line: frame.Line,
+		})
+	}
+	if trace, ok := tracerr.Trace(err); ok {
+		data.TraceID, data.SpanID = trace.TraceID, trace.SpanID
+	}
+	// Marshaling of strings and ints never fails.
+	b, _ := json.Marshal(data)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode decodes error encoded by Encode,
+// original error is replaced with an error with the same message.
+func Decode(value string) (tracerr.Error, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("tracerrhttp: invalid encoding: %w", err)
+	}
+	var data headerError
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("tracerrhttp: invalid encoding: %w", err)
+	}
+	frames := make([]tracerr.Frame, 0, len(data.Frames))
+	for _, frame := range data.Frames {
+		frames = append(frames, tracerr.NewDecodedFrame(frame.Func, frame.Path, frame.Line))
+	}
+	e := tracerr.CustomError(errors.New(data.Message), frames)
+	return tracerr.WrapTrace(e, tracerr.TraceContext{TraceID: data.TraceID, SpanID: data.SpanID}), nil
+}
+
+// SetHeader sets err to Header of a response,
+// it must be called before response header is written.
+func SetHeader(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	w.Header().Set(Header, Encode(err))
+}
+
+// SetTrailer sets err to Header trailer of a response,
+// so it can be called after response body is written.
+func SetTrailer(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	w.Header().Set(http.TrailerPrefix+Header, Encode(err))
+}
+
+// CheckResponse returns error sent by server in Header header or trailer
+// of resp, or nil if there is none. Trailer is available after body is read.
+//
+// Returned error has stack trace of the caller
+// and wraps error of the server with stack trace of the server.
+func CheckResponse(resp *http.Response) error {
+	value := resp.Header.Get(Header)
+	if value == "" {
+		value = resp.Trailer.Get(Header)
+	}
+	if value == "" {
+		return nil
+	}
+	remote, err := Decode(value)
+	if err != nil {
+		return tracerr.WrapSkip(err, 1)
+	}
+	// Wrap would take stack trace of the server error,
+	// so stack trace of the client is captured separately.
+	frames := tracerr.NewSkip("", 1).StackTrace()
+	return tracerr.CustomError(fmt.Errorf("remote error: %w", remote), frames)
+}
@@ -0,0 +1,119 @@
+package tracerrhttp_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrhttp"
+)
+
+func failingHandler(w http.ResponseWriter, r *http.Request) {
+	err := tracerr.New("server failure")
+	if r.URL.Path == "/trailer" {
+		w.Header().Set("Trailer", tracerrhttp.Header)
+		io.WriteString(w, "partial body")
+		tracerrhttp.SetTrailer(w, err)
+		return
+	}
+	tracerrhttp.SetHeader(w, err)
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+type CheckResponseTestCase struct {
+	Path string
+}
+
+func TestCheckResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(failingHandler))
+	defer server.Close()
+
+	cases := []CheckResponseTestCase{
+		{Path: "/header"},
+		{Path: "/trailer"},
+	}
+
+	for _, c := range cases {
+		resp, err := http.Get(server.URL + c.Path)
+		if err != nil {
+			t.Fatalf("%s: http.Get() error = %#v; want nil", c.Path, err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		err = tracerrhttp.CheckResponse(resp)
+		if err == nil {
+			t.Fatalf("%s: tracerrhttp.CheckResponse() = nil; want error", c.Path)
+		}
+		if err.Error() != "remote error: server failure" {
+			t.Errorf("%s: err.Error() = %#v; want %#v", c.Path, err.Error(), "remote error: server failure")
+		}
+		frames := tracerr.StackTrace(err)
+		if len(frames) == 0 || frames[0].Name != "TestCheckResponse" {
+			t.Errorf("%s: client frames = %#v; want to start at TestCheckResponse", c.Path, frames)
+		}
+		var remote tracerr.Error
+		if !errors.As(errors.Unwrap(tracerr.Unwrap(err)), &remote) {
+			t.Fatalf("%s: remote error not found", c.Path)
+		}
+		if remote.Error() != "server failure" {
+			t.Errorf("%s: remote.Error() = %#v; want %#v", c.Path, remote.Error(), "server failure")
+		}
+		remoteFrames := remote.StackTrace()
+		if len(remoteFrames) == 0 || remoteFrames[0].Name != "failingHandler" {
+			t.Errorf("%s: server frames = %#v; want to start at failingHandler", c.Path, remoteFrames)
+		}
+	}
+}
+
+func TestCheckResponseNoError(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	if err := tracerrhttp.CheckResponse(resp); err != nil {
+		t.Errorf("tracerrhttp.CheckResponse() = %#v; want nil", err)
+	}
+	resp.Header.Set(tracerrhttp.Header, "!")
+	if err := tracerrhttp.CheckResponse(resp); err == nil {
+		t.Errorf("tracerrhttp.CheckResponse() = nil; want error")
+	}
+}
+
+func TestEncodeNil(t *testing.T) {
+	if encoded := tracerrhttp.Encode(nil); encoded != "" {
+		t.Errorf("tracerrhttp.Encode(nil) = %#v; want %#v", encoded, "")
+	}
+}
+
+func TestEncodeFrames(t *testing.T) {
+	frames := make([]tracerr.Frame, 20)
+	for i := range frames {
+		frames[i] = tracerr.NewFrame("main.main", "/src/main.go", i+1)
+	}
+	encoded := tracerrhttp.Encode(tracerr.CustomError(errors.New("some error"), frames))
+	decoded, err := tracerrhttp.Decode(encoded)
+	if err != nil {
+		t.Fatalf("tracerrhttp.Decode() error = %#v; want nil", err)
+	}
+	if len(decoded.StackTrace()) != tracerrhttp.DefaultMaxFrames {
+		t.Errorf(
+			"len(decoded.StackTrace()) = %#v; want %#v",
+			len(decoded.StackTrace()), tracerrhttp.DefaultMaxFrames,
+		)
+	}
+	if decoded.StackTrace()[1] != frames[1] {
+		t.Errorf("decoded.StackTrace()[1] = %#v; want %#v", decoded.StackTrace()[1], frames[1])
+	}
+}
+
+func TestEncodeTrace(t *testing.T) {
+	trace := tracerr.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	decoded, err := tracerrhttp.Decode(tracerrhttp.Encode(tracerr.WrapTrace(errors.New("some error"), trace)))
+	if err != nil {
+		t.Fatalf("tracerrhttp.Decode() error = %#v; want nil", err)
+	}
+	if got, _ := tracerr.Trace(decoded); got != trace {
+		t.Errorf("tracerr.Trace(decoded) = %#v; want %#v", got, trace)
+	}
+}
@@ -0,0 +1,42 @@
+package tracerrhttp
+
+import (
+	"net/http"
+
+	"github.com/kadaan/tracerr"
+)
+
+// ErrorHandler handles error of a failed request.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// Middleware returns net/http middleware, which converts panics of handlers
+// into traced errors and passes them to onError. It's compatible with chi
+// and other routers using func(http.Handler) http.Handler middleware.
+//
+// If onError is nil, error is printed with tracerr.Print
+// and response is 500 Internal Server Error.
+// Panics with http.ErrAbortHandler are passed through to abort the request.
+func Middleware(onError ErrorHandler) func(http.Handler) http.Handler {
+	if onError == nil {
+		onError = printError
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				if err := tracerr.PanicError(rec); err != nil {
+					onError(w, r, err)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func printError(w http.ResponseWriter, r *http.Request, err error) {
+	tracerr.Print(err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
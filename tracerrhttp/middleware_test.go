@@ -0,0 +1,46 @@
+package tracerrhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrhttp"
+)
+
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	panic("handler failure")
+}
+
+func TestMiddleware(t *testing.T) {
+	var got error
+	handler := tracerrhttp.Middleware(func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})(http.HandlerFunc(panickingHandler))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("w.Code = %d; want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if got == nil || got.Error() != "panic: handler failure" {
+		t.Fatalf("err = %#v; want panic: handler failure", got)
+	}
+	frames := tracerr.StackTrace(got)
+	if len(frames) == 0 || frames[0].Name != "panickingHandler" {
+		t.Errorf("tracerr.StackTrace(err) = %#v; want to start at panickingHandler", frames)
+	}
+}
+
+func TestMiddlewareAbortHandler(t *testing.T) {
+	handler := tracerrhttp.Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("recover() = %#v; want http.ErrAbortHandler", r)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,89 @@
+package tracerrhttp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/kadaan/tracerr"
+)
+
+// MaxBodySnippet is a maximum number of bytes of response body
+// kept by ResponseError.
+var MaxBodySnippet = 512
+
+// StatusError is an error of a response with a failure status,
+// see ResponseError.
+type StatusError struct {
+	// StatusCode is a status code of the response, e.g. 404.
+	StatusCode int
+	// Status is a status of the response, e.g. "404 Not Found".
+	Status string
+	// Method is a method of the request, e.g. "GET".
+	Method string
+	// URL is a URL of the request with password redacted.
+	URL string
+	// Body is a snippet of the response body, see MaxBodySnippet.
+	Body string
+}
+
+// Error returns method, URL and status of the response.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Status)
+}
+
+// ResponseError returns a traced error of resp with status 4xx or 5xx,
+// or nil for other statuses. Stack trace is of the caller, fields are
+// "status", "method", "url" and "body" of StatusError, see tracerr.Fields,
+// so errors.As finds StatusError as well.
+//
+// Up to MaxBodySnippet bytes of the body are read for the snippet,
+// the body isn't closed.
+func ResponseError(resp *http.Response) error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	statusErr := &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       bodySnippet(resp.Body),
+	}
+	if statusErr.Status == "" {
+		statusErr.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.Request != nil {
+		statusErr.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			statusErr.URL = resp.Request.URL.Redacted()
+		}
+	}
+	return tracerr.WrapFields(tracerr.WrapSkip(statusErr, 1), map[string]interface{}{
+		"status": statusErr.StatusCode,
+		"method": statusErr.Method,
+		"url":    statusErr.URL,
+		"body":   statusErr.Body,
+	})
+}
+
+// bodySnippet returns up to MaxBodySnippet bytes of body,
+// truncated body ends with "...".
+func bodySnippet(body io.Reader) string {
+	if body == nil || MaxBodySnippet <= 0 {
+		return ""
+	}
+	b, _ := io.ReadAll(io.LimitReader(body, int64(MaxBodySnippet)+1))
+	if len(b) <= MaxBodySnippet {
+		return string(b)
+	}
+	b = b[:MaxBodySnippet]
+	// Don't cut a multibyte character in half.
+	last := len(b) - 1
+	for last > 0 && len(b)-last < utf8.UTFMax && !utf8.RuneStart(b[last]) {
+		last--
+	}
+	if !utf8.FullRune(b[last:]) {
+		b = b[:last]
+	}
+	return string(b) + "..."
+}
@@ -0,0 +1,55 @@
+package tracerrhttp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrhttp"
+)
+
+func TestResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			return
+		}
+		http.Error(w, "x"+strings.Repeat("é", 400), http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/ok")
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if err := tracerrhttp.ResponseError(resp); err != nil {
+		t.Errorf("tracerrhttp.ResponseError(ok) = %v; want nil", err)
+	}
+
+	resp, err = http.Get(server.URL + "/users/1")
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	err = tracerrhttp.ResponseError(resp)
+	url := server.URL + "/users/1"
+	if err == nil || err.Error() != "GET "+url+": 404 Not Found" {
+		t.Fatalf("tracerrhttp.ResponseError(resp) = %v; want error of status", err)
+	}
+	var statusErr *tracerrhttp.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("errors.As(err, &statusErr) = %#v; want status 404", statusErr)
+	}
+	// Multibyte character cut by the limit is dropped.
+	body := "x" + strings.Repeat("é", tracerrhttp.MaxBodySnippet/2-1) + "..."
+	fields := tracerr.Fields(err)
+	if fields["status"] != http.StatusNotFound || fields["method"] != "GET" || fields["url"] != url || fields["body"] != body {
+		t.Errorf("tracerr.Fields(err) = %#v; want status, method, url and body", fields)
+	}
+	if frames := tracerr.StackTrace(err); len(frames) == 0 || frames[0].Name != "TestResponseError" {
+		t.Errorf("tracerr.StackTrace(err) = %v; want to start at the caller", frames)
+	}
+}
@@ -0,0 +1,85 @@
+// Package tracerrio wraps errors of io.Reader and io.Writer
+// with stack traces and byte counts.
+package tracerrio
+
+import (
+	"context"
+	"io"
+
+	"github.com/kadaan/tracerr"
+)
+
+// Field names of operation metadata attached to errors, see tracerr.Fields.
+const (
+	// FieldOperation is "read" or "write".
+	FieldOperation = "io.operation"
+	// FieldBytes is a number of bytes transferred before the error,
+	// including bytes of the failed call.
+	FieldBytes = "io.bytes"
+)
+
+// Reader wraps errors of io.Reader with stack trace starting
+// at the caller of Read and attaches the operation and the number
+// of bytes read as fields. io.EOF is returned as is,
+// since callers compare it with ==.
+type Reader struct {
+	r io.Reader
+	n int64
+}
+
+// NewReader returns Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Read reads from the underlying reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, wrap("read", r.n, err)
+}
+
+// N returns a number of bytes read so far.
+func (r *Reader) N() int64 {
+	return r.n
+}
+
+// Writer wraps errors of io.Writer with stack trace starting
+// at the caller of Write and attaches the operation and the number
+// of bytes written as fields.
+type Writer struct {
+	w io.Writer
+	n int64
+}
+
+// NewWriter returns Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes to the underlying writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, wrap("write", w.n, err)
+}
+
+// N returns a number of bytes written so far.
+func (w *Writer) N() int64 {
+	return w.n
+}
+
+// wrap adds stack trace starting at the caller of Read or Write
+// and operation metadata to err. It returns nil and io.EOF as is.
+func wrap(operation string, n int64, err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	// Skip wrap and the method calling it.
+	e := tracerr.WrapSkip(err, 2)
+	ctx := tracerr.ContextWithFields(context.Background(), map[string]interface{}{
+		FieldOperation: operation,
+		FieldBytes:     n,
+	})
+	return tracerr.WrapContext(ctx, e)
+}
@@ -0,0 +1,80 @@
+package tracerrio_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrio"
+)
+
+var errDisk = errors.New("disk failure")
+
+// failingWriter writes at most limit bytes and fails afterwards.
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errDisk
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestReader(t *testing.T) {
+	r := tracerrio.NewReader(io.MultiReader(strings.NewReader("hello"), iotest.ErrReader(errDisk)))
+	_, err := io.ReadAll(r)
+	if !errors.Is(err, errDisk) {
+		t.Fatalf("errors.Is(err, errDisk) = false; want true")
+	}
+	if frames := tracerr.StackTrace(err); len(frames) == 0 || frames[0].Func != "io.ReadAll" {
+		t.Errorf("tracerr.StackTrace(err) = %#v; want to start at the caller of Read", frames)
+	}
+	fields := tracerr.Fields(err)
+	if fields[tracerrio.FieldOperation] != "read" || fields[tracerrio.FieldBytes] != int64(5) {
+		t.Errorf("tracerr.Fields(err) = %#v; want read of 5 bytes", fields)
+	}
+
+	b, err := io.ReadAll(tracerrio.NewReader(strings.NewReader("hello")))
+	if err != nil || string(b) != "hello" {
+		t.Errorf("io.ReadAll() = %#v, %v; want %#v, nil", string(b), err, "hello")
+	}
+	r = tracerrio.NewReader(strings.NewReader(""))
+	if _, err := r.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("r.Read() error = %#v; want io.EOF as is", err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	w := tracerrio.NewWriter(&failingWriter{limit: 7})
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("w.Write() error = %v; want nil", err)
+	}
+	_, err := w.Write([]byte("world"))
+	if !errors.Is(err, errDisk) {
+		t.Fatalf("errors.Is(err, errDisk) = false; want true")
+	}
+	if frames := tracerr.StackTrace(err); len(frames) == 0 || frames[0].Name != "TestWriter" {
+		t.Errorf("tracerr.StackTrace(err) = %#v; want to start at the caller", frames)
+	}
+	fields := tracerr.Fields(err)
+	if fields[tracerrio.FieldOperation] != "write" || fields[tracerrio.FieldBytes] != int64(7) {
+		t.Errorf("tracerr.Fields(err) = %#v; want write of 7 bytes", fields)
+	}
+	if w.N() != 7 {
+		t.Errorf("w.N() = %d; want 7", w.N())
+	}
+
+	var b bytes.Buffer
+	if _, err := io.Copy(tracerrio.NewWriter(&b), strings.NewReader("hello")); err != nil || b.String() != "hello" {
+		t.Errorf("io.Copy() = %#v, %v; want %#v, nil", b.String(), err, "hello")
+	}
+}
@@ -0,0 +1,98 @@
+// Package tracerrpb provides protobuf schema of traced errors,
+// so they can be attached to gRPC status details, sent in messages
+// or stored with a stable schema.
+//
+// Go code is generated from tracerr.proto by protoc-gen-go.
+package tracerrpb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kadaan/tracerr"
+)
+
+// ToProto converts err to a message, stack trace is empty
+// if err is not of type tracerr.Error. It returns nil if err is nil.
+//
+// Fields are fields of the error, see tracerr.Fields, strings as is
+// and other values encoded as JSON, and trace context as "trace_id"
+// and "span_id", see tracerr.Trace, which take precedence over fields
+// of the same names.
+func ToProto(err error) *TracedError {
+	if err == nil {
+		return nil
+	}
+	frames := tracerr.RedactStackTrace(err)
+	msg := &TracedError{
+		Message: tracerr.ScrubMessage(err.Error()),
+		Frames:  make([]*Frame, 0, len(frames)),
+	}
+	for _, frame := range frames {
+		msg.Frames = append(msg.Frames, &Frame{
+			Func: frame.Func,
+			Line: int64(frame.Line),
+			Path: frame.Path,
+		})
+	}
+	for key, value := range tracerr.Fields(err) {
+		if msg.Fields == nil {
+			msg.Fields = map[string]string{}
+		}
+		msg.Fields[key] = fieldString(value)
+	}
+	if trace, ok := tracerr.Trace(err); ok {
+		if msg.Fields == nil {
+			msg.Fields = map[string]string{}
+		}
+		msg.Fields["trace_id"] = trace.TraceID
+		msg.Fields["span_id"] = trace.SpanID
+	}
+	return msg
+}
+
+// fieldString returns string as is and other values encoded as JSON,
+// or formatted by fmt if they can't be encoded.
+func fieldString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+// FromProto converts msg to an error with stack trace, fields and trace
+// context, original error is replaced with an error with the same message.
+// Values of fields are strings, as they are stored by ToProto.
+// It returns nil if msg is nil.
+func FromProto(msg *TracedError) tracerr.Error {
+	if msg == nil {
+		return nil
+	}
+	frames := make([]tracerr.Frame, 0, len(msg.GetFrames()))
+	for _, frame := range msg.GetFrames() {
+		frames = append(frames, tracerr.NewDecodedFrame(
+			frame.GetFunc(), frame.GetPath(), int(frame.GetLine()),
+		))
+	}
+	e := tracerr.CustomError(errors.New(msg.GetMessage()), frames)
+	var fields map[string]interface{}
+	for key, value := range msg.GetFields() {
+		if key == "trace_id" || key == "span_id" {
+			continue
+		}
+		if fields == nil {
+			fields = map[string]interface{}{}
+		}
+		fields[key] = value
+	}
+	e = tracerr.WrapFields(e, fields)
+	return tracerr.WrapTrace(e, tracerr.TraceContext{
+		TraceID: msg.GetFields()["trace_id"],
+		SpanID:  msg.GetFields()["span_id"],
+	})
+}
@@ -0,0 +1,97 @@
+package tracerrpb_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrpb"
+)
+
+func TestToProtoFromProto(t *testing.T) {
+	err := tracerr.Wrap(errors.New("some error"))
+
+	b, marshalErr := proto.Marshal(tracerrpb.ToProto(err))
+	if marshalErr != nil {
+		t.Fatalf("proto.Marshal() error = %#v; want nil", marshalErr)
+	}
+	var msg tracerrpb.TracedError
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		t.Fatalf("proto.Unmarshal() error = %#v; want nil", err)
+	}
+	decoded := tracerrpb.FromProto(&msg)
+
+	if decoded.Error() != "some error" {
+		t.Errorf("decoded.Error() = %#v; want %#v", decoded.Error(), "some error")
+	}
+	frames := err.StackTrace()
+	decodedFrames := decoded.StackTrace()
+	if len(decodedFrames) != len(frames) {
+		t.Fatalf("len(decodedFrames) = %#v; want %#v", len(decodedFrames), len(frames))
+	}
+	for i, frame := range frames {
+		frame.PC, frame.Entry = 0, 0
+		if decodedFrames[i] != frame {
+			t.Errorf("decodedFrames[%d] = %#v; want %#v", i, decodedFrames[i], frame)
+		}
+	}
+}
+
+func TestFromProtoNotInterned(t *testing.T) {
+	msg := &tracerrpb.TracedError{
+		Message: "some error",
+		Frames:  []*tracerrpb.Frame{{Func: "decoded.proto", Path: "/decoded/proto.go", Line: 1}},
+	}
+	before := tracerr.ReadCaptureStats().InternedStrings
+	tracerrpb.FromProto(msg)
+	if after := tracerr.ReadCaptureStats().InternedStrings; after != before {
+		t.Errorf("InternedStrings = %#v; want %#v", after, before)
+	}
+}
+
+func TestToProtoNil(t *testing.T) {
+	if msg := tracerrpb.ToProto(nil); msg != nil {
+		t.Errorf("tracerrpb.ToProto(nil) = %#v; want nil", msg)
+	}
+	if err := tracerrpb.FromProto(nil); err != nil {
+		t.Errorf("tracerrpb.FromProto(nil) = %#v; want nil", err)
+	}
+	msg := tracerrpb.ToProto(errors.New("plain"))
+	if msg.GetMessage() != "plain" || len(msg.GetFrames()) != 0 {
+		t.Errorf("tracerrpb.ToProto(plain) = %#v; want message without frames", msg)
+	}
+}
+
+func TestToProtoFromProtoTrace(t *testing.T) {
+	trace := tracerr.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	msg := tracerrpb.ToProto(tracerr.WrapTrace(errors.New("some error"), trace))
+	if msg.GetFields()["trace_id"] != trace.TraceID || msg.GetFields()["span_id"] != trace.SpanID {
+		t.Errorf("msg.GetFields() = %#v; want trace_id and span_id", msg.GetFields())
+	}
+	if decoded, _ := tracerr.Trace(tracerrpb.FromProto(msg)); decoded != trace {
+		t.Errorf("tracerr.Trace(decoded) = %#v; want %#v", decoded, trace)
+	}
+}
+
+func TestToProtoFromProtoFields(t *testing.T) {
+	err := tracerr.WrapFields(errors.New("some error"), map[string]interface{}{
+		"user":  "alice",
+		"id":    42,
+		"roles": []string{"admin"},
+	})
+	b, marshalErr := proto.Marshal(tracerrpb.ToProto(err))
+	if marshalErr != nil {
+		t.Fatalf("proto.Marshal() error = %#v; want nil", marshalErr)
+	}
+	var msg tracerrpb.TracedError
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		t.Fatalf("proto.Unmarshal() error = %#v; want nil", err)
+	}
+	expected := map[string]interface{}{"user": "alice", "id": "42", "roles": `["admin"]`}
+	if fields := tracerr.Fields(tracerrpb.FromProto(&msg)); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("tracerr.Fields(decoded) = %#v; want %#v", fields, expected)
+	}
+}
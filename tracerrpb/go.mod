@@ -0,0 +1,14 @@
+module github.com/kadaan/tracerr/tracerrpb
+
+go 1.23
+
+require (
+	github.com/kadaan/tracerr v0.4.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/logrusorgru/aurora v0.0.0-20181002194514-a7b3b318ed4e // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
+)
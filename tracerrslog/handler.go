@@ -0,0 +1,109 @@
+// Package tracerrslog expands traced errors in records of log/slog.
+//
+// Wrap a handler, so existing calls like slog.Error("failed", "err", err)
+// log message, fingerprint and stack trace of traced errors:
+//
+//	logger := slog.New(tracerrslog.NewHandler(slog.NewJSONHandler(os.Stderr, nil)))
+package tracerrslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kadaan/tracerr"
+)
+
+// Handler is a slog.Handler, which replaces attributes with traced errors
+// by groups of "message", "fingerprint" and "frames" attributes,
+// as well as "trace_id" and "span_id" if errors have trace context,
+// and passes records to the wrapped handler.
+type Handler struct {
+	handler slog.Handler
+	options []tracerr.PrintOption
+}
+
+// NewHandler returns Handler wrapping h.
+// Frames are formatted by print options, such as tracerr.WithMaxFrames
+// and tracerr.WithTrimPaths, on top of options set by tracerr.SetPrintOptions.
+func NewHandler(h slog.Handler, options ...tracerr.PrintOption) *Handler {
+	return &Handler{
+		handler: h,
+		options: options,
+	}
+}
+
+// Enabled reports whether the wrapped handler handles records of level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle expands traced errors of r and passes it to the wrapped handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(h.expand(a))
+		return true
+	})
+	return h.handler.Handle(ctx, expanded)
+}
+
+// WithAttrs returns Handler wrapping the handler with expanded attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = h.expand(a)
+	}
+	return &Handler{
+		handler: h.handler.WithAttrs(expanded),
+		options: h.options,
+	}
+}
+
+// WithGroup returns Handler wrapping the handler with group name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{
+		handler: h.handler.WithGroup(name),
+		options: h.options,
+	}
+}
+
+// expand replaces traced error of a with a group,
+// attributes of groups are expanded recursively.
+func (h *Handler) expand(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		expanded := make([]slog.Attr, len(attrs))
+		for i, attr := range attrs {
+			expanded[i] = h.expand(attr)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny:
+		err, ok := a.Value.Any().(error)
+		if !ok {
+			return a
+		}
+		e, ok := tracerr.AsError(err)
+		if !ok {
+			return a
+		}
+		frames := tracerr.Tree(e, h.options...).Frames
+		if frames == nil {
+			frames = []string{}
+		}
+		attrs := []any{
+			slog.String("message", tracerr.ScrubMessage(err.Error())),
+			slog.String("fingerprint", tracerr.Fingerprint(err)),
+			slog.Any("frames", frames),
+		}
+		if trace, ok := tracerr.Trace(err); ok {
+			attrs = append(attrs,
+				slog.String("trace_id", trace.TraceID),
+				slog.String("span_id", trace.SpanID),
+			)
+		}
+		return slog.Group(a.Key, attrs...)
+	}
+	return a
+}
@@ -0,0 +1,89 @@
+package tracerrslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrslog"
+)
+
+func TestHandler(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+	}
+	traced := tracerr.CustomError(errors.New("some error"), frames)
+	wrapped := fmt.Errorf("read: %w", traced)
+
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := slog.New(tracerrslog.NewHandler(h, tracerr.WithMaxFrames(1))).
+		With("base", traced).
+		WithGroup("request")
+	logger.Error("failed", "err", wrapped, "plain", errors.New("plain"), slog.Group("nested", "err", traced))
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", buf.Bytes(), err)
+	}
+	fingerprint := tracerr.Fingerprint(traced)
+	expected := map[string]interface{}{
+		"level": "ERROR",
+		"msg":   "failed",
+		"base": map[string]interface{}{
+			"message":     "some error",
+			"fingerprint": fingerprint,
+			"frames":      []interface{}{"/src/main.go:42 main.read()"},
+		},
+		"request": map[string]interface{}{
+			"err": map[string]interface{}{
+				"message":     "read: some error",
+				"fingerprint": tracerr.Fingerprint(wrapped),
+				"frames":      []interface{}{"/src/main.go:42 main.read()"},
+			},
+			"plain": "plain",
+			"nested": map[string]interface{}{
+				"err": map[string]interface{}{
+					"message":     "some error",
+					"fingerprint": fingerprint,
+					"frames":      []interface{}{"/src/main.go:42 main.read()"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("logged %s; want %v", buf.Bytes(), expected)
+	}
+}
+
+func TestHandlerTrace(t *testing.T) {
+	trace := tracerr.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	traced := tracerr.WrapTrace(tracerr.CustomError(errors.New("some error"), nil), trace)
+
+	var buf bytes.Buffer
+	logger := slog.New(tracerrslog.NewHandler(slog.NewJSONHandler(&buf, nil)))
+	logger.Error("failed", "err", traced)
+
+	var got struct {
+		Err map[string]interface{} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", buf.Bytes(), err)
+	}
+	if got.Err["trace_id"] != trace.TraceID || got.Err["span_id"] != trace.SpanID {
+		t.Errorf("logged %s; want trace_id %s and span_id %s", buf.Bytes(), trace.TraceID, trace.SpanID)
+	}
+}
@@ -0,0 +1,93 @@
+// Package tracerrsql wraps errors of database/sql operations
+// with stack traces and the executed query.
+package tracerrsql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kadaan/tracerr"
+)
+
+// Field names of operation metadata attached to errors, see tracerr.Fields.
+const (
+	// FieldOperation is a name of failed method, e.g. "ExecContext".
+	FieldOperation = "sql.operation"
+	// FieldQuery is a query of failed method.
+	FieldQuery = "sql.query"
+)
+
+// Conn executes queries, it's implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Querier executes queries by Conn and wraps returned errors with
+// tracerr.WrapContext, so stack trace starts at the caller and
+// the operation and the query are attached as fields.
+// Errors of returned *sql.Rows are not wrapped.
+type Querier struct {
+	conn Conn
+}
+
+// New returns Querier executing queries by conn.
+func New(conn Conn) *Querier {
+	return &Querier{conn: conn}
+}
+
+// ExecContext executes query by Conn.ExecContext.
+func (q *Querier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := q.conn.ExecContext(ctx, query, args...)
+	return result, wrap(ctx, "ExecContext", query, err)
+}
+
+// QueryContext executes query by Conn.QueryContext.
+func (q *Querier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.conn.QueryContext(ctx, query, args...)
+	return rows, wrap(ctx, "QueryContext", query, err)
+}
+
+// QueryRowContext executes query by Conn.QueryRowContext,
+// errors are returned by Row.Scan and Row.Err.
+func (q *Querier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	return &Row{
+		row:   q.conn.QueryRowContext(ctx, query, args...),
+		ctx:   ctx,
+		query: query,
+	}
+}
+
+// Row is a result of Querier.QueryRowContext.
+type Row struct {
+	row   *sql.Row
+	ctx   context.Context
+	query string
+}
+
+// Scan copies columns of the row into dest by sql.Row.Scan.
+// Wrapped sql.ErrNoRows is still found by errors.Is.
+func (r *Row) Scan(dest ...interface{}) error {
+	return wrap(r.ctx, "QueryRowContext", r.query, r.row.Scan(dest...))
+}
+
+// Err returns error of the query by sql.Row.Err.
+func (r *Row) Err() error {
+	return wrap(r.ctx, "QueryRowContext", r.query, r.row.Err())
+}
+
+// wrap adds stack trace starting at the caller of Querier or Row method
+// and operation metadata to err. It returns nil if err is nil.
+func wrap(ctx context.Context, operation, query string, err error) error {
+	if err == nil {
+		return nil
+	}
+	// Skip wrap and the method calling it.
+	e := tracerr.WrapSkip(err, 2)
+	ctx = tracerr.ContextWithFields(ctx, map[string]interface{}{
+		FieldOperation: operation,
+		FieldQuery:     query,
+	})
+	return tracerr.WrapContext(ctx, e)
+}
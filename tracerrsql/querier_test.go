@@ -0,0 +1,76 @@
+package tracerrsql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrsql"
+)
+
+var errConnection = errors.New("connection refused")
+
+// failingDriver fails every query.
+type failingDriver struct{}
+
+func (failingDriver) Open(name string) (driver.Conn, error) {
+	return failingConn{}, nil
+}
+
+type failingConn struct{}
+
+func (failingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errConnection
+}
+
+func (failingConn) Close() error {
+	return nil
+}
+
+func (failingConn) Begin() (driver.Tx, error) {
+	return nil, errConnection
+}
+
+func init() {
+	sql.Register("tracerrsql-failing", failingDriver{})
+}
+
+func TestQuerier(t *testing.T) {
+	db, err := sql.Open("tracerrsql-failing", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := tracerrsql.New(db)
+	ctx := context.Background()
+
+	_, execErr := q.ExecContext(ctx, "DELETE FROM users")
+	_, queryErr := q.QueryContext(ctx, "SELECT * FROM users")
+	var name string
+	scanErr := q.QueryRowContext(ctx, "SELECT name FROM users WHERE id = ?", 1).Scan(&name)
+	cases := []struct {
+		err       error
+		operation string
+		query     string
+	}{
+		{execErr, "ExecContext", "DELETE FROM users"},
+		{queryErr, "QueryContext", "SELECT * FROM users"},
+		{scanErr, "QueryRowContext", "SELECT name FROM users WHERE id = ?"},
+	}
+	for i, c := range cases {
+		if !errors.Is(c.err, errConnection) {
+			t.Errorf("case #%d: errors.Is(err, errConnection) = false; want true", i)
+		}
+		frames := tracerr.StackTrace(c.err)
+		if len(frames) == 0 || frames[0].Name != "TestQuerier" {
+			t.Errorf("case #%d: tracerr.StackTrace(err) = %#v; want to start at the caller", i, frames)
+		}
+		fields := tracerr.Fields(c.err)
+		if fields[tracerrsql.FieldOperation] != c.operation || fields[tracerrsql.FieldQuery] != c.query {
+			t.Errorf("case #%d: tracerr.Fields(err) = %#v; want operation %#v and query %#v", i, fields, c.operation, c.query)
+		}
+	}
+}
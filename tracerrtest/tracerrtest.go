@@ -0,0 +1,155 @@
+// Package tracerrtest provides helpers for testing code using tracerr.
+package tracerrtest
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+// AssertStackContains reports an error if stack trace of err
+// has no frame of function name.
+//
+// Name may be fully qualified, e.g. "github.com/foo/bar.Baz",
+// or with package name only, e.g. "bar.Baz" or "bar.(*Thing).Do".
+func AssertStackContains(t testing.TB, err error, name string) bool {
+	t.Helper()
+	frames := tracerr.StackTrace(err)
+	for _, frame := range frames {
+		if frame.Func == name || strings.HasSuffix(frame.Func, "/"+name) {
+			return true
+		}
+	}
+	t.Errorf(
+		"stack trace has no frame of %s:\n%s",
+		name, framesString(frames),
+	)
+	return false
+}
+
+// LogMarker prefixes frames of the file calling Log in its output.
+const LogMarker = "--> "
+
+// Log writes err with stack trace and source fragments to t.Log,
+// the same as tracerr.SprintSource. Frames of the testing package
+// and below are trimmed, and frames of the file calling Log
+// are prefixed with LogMarker, so the failing line of the test is easy to spot.
+func Log(t testing.TB, err error) {
+	t.Helper()
+	if err == nil {
+		t.Log("<nil>")
+		return
+	}
+	_, file, _, _ := runtime.Caller(1)
+	frames := tracerr.StackTrace(err)
+	for i, frame := range frames {
+		if strings.HasPrefix(frame.Func, "testing.") || frame.Func == "runtime.goexit" {
+			frames = frames[:i]
+			break
+		}
+	}
+	output := tracerr.SprintSource(tracerr.CustomError(err, frames))
+	t.Log("\n" + markFrames(output, frames, file))
+}
+
+// markFrames prefixes rows of frames of file in output of SprintSource
+// with LogMarker. Each frame starts after an empty row,
+// so the last len(frames) of such rows are frames.
+func markFrames(output string, frames []tracerr.Frame, file string) string {
+	rows := strings.Split(output, "\n")
+	var starts []int
+	for i := 1; i < len(rows); i++ {
+		if rows[i-1] == "" && rows[i] != "" {
+			starts = append(starts, i)
+		}
+	}
+	if len(starts) < len(frames) {
+		return output
+	}
+	starts = starts[len(starts)-len(frames):]
+	for i, frame := range frames {
+		if frame.Path == file {
+			rows[starts[i]] = LogMarker + rows[starts[i]]
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// AssertWrapped reports an error if err has no stack trace
+// or target is not in its chain, see errors.Is.
+func AssertWrapped(t testing.TB, err, target error) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("error is nil; want error wrapping %v", target)
+		return false
+	}
+	ok := true
+	if tracerr.StackTrace(err) == nil {
+		t.Errorf("error %v has no stack trace", err)
+		ok = false
+	}
+	if !errors.Is(err, target) {
+		t.Errorf("error %v doesn't wrap %v", err, target)
+		ok = false
+	}
+	return ok
+}
+
+// DiffTraces returns line diff of two stack traces, which is empty
+// if they are equal. Frames are compared by function, path and line.
+//
+// Lines of frames missing in b are prefixed with "-",
+// lines of frames missing in a are prefixed with "+".
+func DiffTraces(a, b []tracerr.Frame) string {
+	// lcs[i][j] is a length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if equalFrames(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	if lcs[0][0] == len(a) && len(a) == len(b) {
+		return ""
+	}
+	var rows []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && equalFrames(a[i], b[j]):
+			rows = append(rows, "  "+a[i].String())
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			rows = append(rows, "- "+a[i].String())
+			i++
+		default:
+			rows = append(rows, "+ "+b[j].String())
+			j++
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func equalFrames(a, b tracerr.Frame) bool {
+	return a.Func == b.Func && a.Path == b.Path && a.Line == b.Line
+}
+
+func framesString(frames []tracerr.Frame) string {
+	rows := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		rows = append(rows, "\t"+frame.String())
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,121 @@
+package tracerrtest_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+	"github.com/kadaan/tracerr/tracerrtest"
+)
+
+type recordingT struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Log(args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func TestAssertStackContains(t *testing.T) {
+	err := tracerr.New("some error")
+	rt := &recordingT{TB: t}
+	for _, name := range []string{
+		"github.com/kadaan/tracerr/tracerrtest_test.TestAssertStackContains",
+		"tracerrtest_test.TestAssertStackContains",
+	} {
+		if !tracerrtest.AssertStackContains(rt, err, name) {
+			t.Errorf("tracerrtest.AssertStackContains(t, err, %#v) = false; want true", name)
+		}
+	}
+	if tracerrtest.AssertStackContains(rt, err, "tracerrtest_test.Missing") {
+		t.Errorf("tracerrtest.AssertStackContains(t, err, %#v) = true; want false", "tracerrtest_test.Missing")
+	}
+	if len(rt.errors) != 1 || !strings.HasPrefix(rt.errors[0], "stack trace has no frame of tracerrtest_test.Missing:\n\t") {
+		t.Errorf("errors = %#v; want a single error about missing frame", rt.errors)
+	}
+}
+
+type AssertWrappedTestCase struct {
+	Error    error
+	Expected bool
+	Errors   int
+}
+
+func TestAssertWrapped(t *testing.T) {
+	target := errors.New("target")
+	cases := []AssertWrappedTestCase{
+		{Error: tracerr.Wrap(fmt.Errorf("context: %w", target)), Expected: true, Errors: 0},
+		{Error: fmt.Errorf("context: %w", target), Expected: false, Errors: 1},
+		{Error: tracerr.New("other"), Expected: false, Errors: 1},
+		{Error: nil, Expected: false, Errors: 1},
+	}
+	for i, c := range cases {
+		rt := &recordingT{TB: t}
+		if ok := tracerrtest.AssertWrapped(rt, c.Error, target); ok != c.Expected {
+			t.Errorf("case #%d: tracerrtest.AssertWrapped() = %#v; want %#v", i, ok, c.Expected)
+		}
+		if len(rt.errors) != c.Errors {
+			t.Errorf("case #%d: errors = %#v; want %d errors", i, rt.errors, c.Errors)
+		}
+	}
+}
+
+func TestDiffTraces(t *testing.T) {
+	a := []tracerr.Frame{
+		{Func: "main.foo", Line: 1, Path: "main.go"},
+		{Func: "main.bar", Line: 2, Path: "main.go"},
+		{Func: "main.main", Line: 3, Path: "main.go"},
+	}
+	b := []tracerr.Frame{
+		{Func: "main.foo", Line: 1, Path: "main.go"},
+		{Func: "main.baz", Line: 5, Path: "main.go"},
+		{Func: "main.main", Line: 3, Path: "main.go"},
+	}
+	if diff := tracerrtest.DiffTraces(a, a); diff != "" {
+		t.Errorf("tracerrtest.DiffTraces(a, a) = %#v; want %#v", diff, "")
+	}
+	expected := strings.Join([]string{
+		"  main.go:1 main.foo()",
+		"- main.go:2 main.bar()",
+		"+ main.go:5 main.baz()",
+		"  main.go:3 main.main()",
+	}, "\n")
+	if diff := tracerrtest.DiffTraces(a, b); diff != expected {
+		t.Errorf("tracerrtest.DiffTraces(a, b) = %#v; want %#v", diff, expected)
+	}
+}
+
+func TestLog(t *testing.T) {
+	err := tracerr.New("some error")
+	rt := &recordingT{TB: t}
+	tracerrtest.Log(rt, err)
+	if len(rt.logs) != 1 {
+		t.Fatalf("logs = %#v; want a single log", rt.logs)
+	}
+	output := rt.logs[0]
+	if !strings.HasPrefix(output, "\nsome error\n\n"+tracerrtest.LogMarker) {
+		t.Errorf("output = %#v; want the test frame marked", output)
+	}
+	if !strings.Contains(output, "tracerrtest_test.TestLog()\n") || !strings.Contains(output, `tracerr.New("some error")`) {
+		t.Errorf("output = %#v; want the test frame with source", output)
+	}
+	if strings.Contains(output, "testing.tRunner") || strings.Contains(output, "runtime.goexit") {
+		t.Errorf("output = %#v; want testing frames trimmed", output)
+	}
+
+	rt = &recordingT{TB: t}
+	tracerrtest.Log(rt, nil)
+	if len(rt.logs) != 1 || rt.logs[0] != "<nil>" {
+		t.Errorf("logs = %#v; want <nil>", rt.logs)
+	}
+}
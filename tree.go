@@ -0,0 +1,87 @@
+package tracerr
+
+import "maps"
+
+// TreeNode is a node of error tree returned by Tree,
+// it's suitable for encoding/json.
+type TreeNode struct {
+	// Message is a message of the error.
+	Message string `json:"message"`
+	// Code is a code of the error, see Tree.
+	Code string `json:"code,omitempty"`
+	// Frames are frames of stack trace formatted as Frame.String.
+	Frames []string `json:"frames,omitempty"`
+	// FrameCount is a number of frames of stack trace before
+	// they are limited or redacted by print options.
+	FrameCount int `json:"frameCount,omitempty"`
+	// Fields are attached to the error itself, see FieldLayers.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// TraceID and SpanID are trace context recorded in the error itself,
+	// see Trace.
+	TraceID string `json:"traceId,omitempty"`
+	SpanID  string `json:"spanId,omitempty"`
+	// Children are errors wrapped by the error,
+	// there are several of them for errors joined by errors.Join.
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// Tree returns a hierarchy of causes of err, so frontends can display it.
+// Errors wrapped both with Unwrap() error and Unwrap() []error are children,
+// while tracerr errors are merged with the wrapped error of the same message.
+//
+// Code is returned by Code() string method of the error, if it has one.
+// Frames follow print options, such as WithMaxFrames and WithTrimPaths.
+func Tree(err error, options ...PrintOption) *TreeNode {
+	if err == nil {
+		return nil
+	}
+	o := mergePrintOptions(options)
+	return o.treeNode(err)
+}
+
+func (o *printOptions) treeNode(err error) *TreeNode {
+	e, ok := err.(Error)
+	if !ok {
+		node := &TreeNode{
+			Message: ScrubMessage(err.Error()),
+			Code:    errorCode(err),
+		}
+		for _, child := range unwrapAll(err) {
+			node.Children = append(node.Children, o.treeNode(child))
+		}
+		return node
+	}
+	var node *TreeNode
+	if inner := e.Unwrap(); inner != nil && inner.Error() == e.Error() {
+		node = o.treeNode(inner)
+	} else {
+		node = &TreeNode{Message: ScrubMessage(e.Error())}
+		if inner != nil {
+			node.Children = []*TreeNode{o.treeNode(inner)}
+		}
+	}
+	if node.Code == "" {
+		node.Code = errorCode(err)
+	}
+	if d, ok := e.(*errorData); ok && d != nil && len(d.fields) > 0 {
+		// Node merged with the wrapped error of the same message
+		// is a single layer, so their fields are merged as well.
+		if len(node.Fields) > 0 {
+			merged := maps.Clone(node.Fields)
+			maps.Copy(merged, d.fields)
+			node.Fields = merged
+		} else {
+			node.Fields = d.fields
+		}
+	}
+	if d, ok := e.(*errorData); ok && d != nil && d.trace.TraceID != "" && node.TraceID == "" {
+		node.TraceID, node.SpanID = d.trace.TraceID, d.trace.SpanID
+	}
+	if node.FrameCount == 0 {
+		node.FrameCount = len(RawFrames(e))
+		for _, frame := range orderFrames(o, redactStackTrace(o.frames(e))) {
+			node.Frames = append(node.Frames, o.frameString(frame))
+		}
+	}
+	return node
+}
@@ -0,0 +1,70 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+type codeError struct {
+	code string
+}
+
+func (e *codeError) Error() string {
+	return "code " + e.code
+}
+
+func (e *codeError) Code() string {
+	return e.code
+}
+
+func TestTree(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+	}
+	err := errors.Join(
+		tracerr.CustomError(errors.New("first"), frames),
+		fmt.Errorf("second: %w", tracerr.CustomError(&codeError{code: "E42"}, frames[1:])),
+	)
+	cases := []struct {
+		Error    error
+		Options  []tracerr.PrintOption
+		Expected string
+	}{
+		{
+			Error:    nil,
+			Expected: `null`,
+		},
+		{
+			Error:    errors.New("plain"),
+			Expected: `{"message":"plain"}`,
+		},
+		{
+			Error: err,
+			Expected: `{"message":"first\nsecond: code E42","children":[` +
+				`{"message":"first","frames":["/src/main.go:42 main.read()","/src/main.go:10 main.main()"],"frameCount":2},` +
+				`{"message":"second: code E42","children":[` +
+				`{"message":"code E42","code":"E42","frames":["/src/main.go:10 main.main()"],"frameCount":1}]}]}`,
+		},
+		{
+			Error:   tracerr.CustomError(fmt.Errorf("outer: %w", errors.New("inner")), frames),
+			Options: []tracerr.PrintOption{tracerr.WithMaxFrames(1)},
+			Expected: `{"message":"outer: inner","frames":["/src/main.go:42 main.read()"],"frameCount":2,` +
+				`"children":[{"message":"inner"}]}`,
+		},
+	}
+
+	for i, c := range cases {
+		b, err := json.Marshal(tracerr.Tree(c.Error, c.Options...))
+		if err != nil {
+			t.Fatalf("case #%d: json.Marshal() error: %v", i, err)
+		}
+		if string(b) != c.Expected {
+			t.Errorf("case #%d: json.Marshal(tracerr.Tree(err)) = %s; want %s", i, b, c.Expected)
+		}
+	}
+}
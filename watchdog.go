@@ -0,0 +1,96 @@
+package tracerr
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Watchdog snapshots stack of the goroutine, which created it,
+// if an operation overruns a deadline, so a timeout error shows
+// where the code was stuck, not just where the timeout was noticed:
+//
+//	w := tracerr.NewWatchdog(time.Now().Add(5 * time.Second))
+//	err := slowOperation(ctx)
+//	return w.Stop(err)
+type Watchdog struct {
+	// goroutine is a header prefix of the watched goroutine in stack dumps.
+	goroutine []byte
+	timer     *time.Timer
+	// fired is closed when the stack is snapshotted.
+	fired chan struct{}
+	stop  sync.Once
+	stuck []Frame
+}
+
+// NewWatchdog starts watching the calling goroutine till deadline.
+func NewWatchdog(deadline time.Time) *Watchdog {
+	w := &Watchdog{
+		goroutine: goroutineHeader(),
+		fired:     make(chan struct{}),
+	}
+	w.timer = time.AfterFunc(time.Until(deadline), w.snapshot)
+	return w
+}
+
+// Stop stops watching and returns err with stack trace by the same rules
+// as Wrap. If the deadline was overrun, stack trace of the goroutine
+// at the deadline is appended after a marker frame, see Merge.
+// It returns nil if err is nil.
+func (w *Watchdog) Stop(err error) Error {
+	w.stop.Do(func() {
+		if !w.timer.Stop() {
+			// Snapshot is in progress or done.
+			<-w.fired
+		}
+	})
+	e := Wrap(err)
+	if e == nil {
+		return nil
+	}
+	return Merge(e, w.stuck)
+}
+
+// Stuck returns stack trace of the goroutine at the deadline,
+// it reports false if the deadline hasn't been overrun.
+// It's safe to call it concurrently with the watched goroutine.
+func (w *Watchdog) Stuck() ([]Frame, bool) {
+	select {
+	case <-w.fired:
+		return w.stuck, w.stuck != nil
+	default:
+		return nil, false
+	}
+}
+
+// snapshot parses stack of the watched goroutine from a dump of all goroutines.
+func (w *Watchdog) snapshot() {
+	defer close(w.fired)
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	start := bytes.Index(buf, w.goroutine)
+	if start < 0 {
+		// The goroutine has exited.
+		return
+	}
+	w.stuck = FramesFromStack(buf[start:])
+}
+
+// goroutineHeader returns prefix of header of the calling goroutine
+// in stack dumps, such as "goroutine 12 [".
+func goroutineHeader() []byte {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	if end := bytes.IndexByte(header, '['); end >= 0 {
+		header = header[:end+1]
+	}
+	return header
+}
@@ -0,0 +1,63 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadaan/tracerr"
+)
+
+//go:noinline
+func stuckOperation(release <-chan struct{}) error {
+	<-release
+	return context.DeadlineExceeded
+}
+
+func TestWatchdog(t *testing.T) {
+	release := make(chan struct{})
+	w := tracerr.NewWatchdog(time.Now().Add(10 * time.Millisecond))
+	go func() {
+		for {
+			if _, ok := w.Stuck(); ok {
+				close(release)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	err := w.Stop(stuckOperation(release))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors.Is(err, context.DeadlineExceeded) = false; want true")
+	}
+	var names []string
+	merged := -1
+	for i, frame := range err.StackTrace() {
+		if frame.IsMarker() && merged < 0 {
+			merged = i
+		}
+		names = append(names, frame.Name)
+	}
+	if merged < 1 || len(names) < merged+3 ||
+		names[0] != "TestWatchdog" || names[merged+1] != "stuckOperation" || names[merged+2] != "TestWatchdog" {
+		t.Errorf("frames = %v; want local frames, marker and stuck frames", names)
+	}
+	// Stop is idempotent.
+	if err := w.Stop(nil); err != nil {
+		t.Errorf("w.Stop(nil) = %#v; want nil", err)
+	}
+}
+
+func TestWatchdogInTime(t *testing.T) {
+	w := tracerr.NewWatchdog(time.Now().Add(time.Hour))
+	err := w.Stop(errors.New("some error"))
+	for _, frame := range err.StackTrace() {
+		if frame.IsMarker() {
+			t.Errorf("err.StackTrace() = %v; want no stuck frames", err.StackTrace())
+		}
+	}
+	if frames, ok := w.Stuck(); ok {
+		t.Errorf("w.Stuck() = %v, true; want false", frames)
+	}
+}
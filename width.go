@@ -0,0 +1,81 @@
+package tracerr
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithMaxWidth sets a maximum width of frame rows in runes,
+// longer paths and function names are truncated from the left,
+// so file names and function names remain visible.
+//
+// Width of a terminal is detected by Fprint, FprintSource
+// and FprintSourceColor, so it's needed for other writers only.
+// Zero means width of the terminal or no limit.
+func WithMaxWidth(n int) PrintOption {
+	return func(o *printOptions) {
+		o.maxWidth = n
+	}
+}
+
+// outputWidth returns a maximum width of frame rows written to w,
+// zero means no limit.
+func (o *printOptions) outputWidth(w io.Writer) int {
+	if o.maxWidth > 0 {
+		return o.maxWidth
+	}
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		return 0
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return terminalWidth(f)
+}
+
+// fitFrame truncates location and function name of frame row,
+// so row doesn't exceed width.
+// Location is shortened up to its file name first, then function name.
+func fitFrame(location, fn string, width int) (string, string) {
+	// Frame row is "location fn()".
+	over := utf8.RuneCountInString(location) + utf8.RuneCountInString(fn) + 3 - width
+	if over <= 0 {
+		return location, fn
+	}
+	n := utf8.RuneCountInString(location)
+	if i := strings.LastIndexAny(location, `/\`); i >= 0 {
+		if minimum := utf8.RuneCountInString(location[i+1:]) + 1; n > minimum {
+			cut := min(over, n-minimum)
+			location = truncateLeft(location, n-cut)
+			over -= cut
+		}
+	}
+	if over <= 0 {
+		return location, fn
+	}
+	n = utf8.RuneCountInString(fn)
+	cut := min(over, n-1)
+	fn = truncateLeft(fn, n-cut)
+	over -= cut
+	if over > 0 {
+		location = truncateLeft(location, max(utf8.RuneCountInString(location)-over, 1))
+	}
+	return location, fn
+}
+
+// truncateLeft shortens s to n runes replacing its beginning with an ellipsis.
+func truncateLeft(s string, n int) string {
+	count := utf8.RuneCountInString(s)
+	if count <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return "…" + string(runes[count-n+1:])
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package tracerr
+
+import (
+	"os"
+)
+
+// terminalWidth returns zero as width of terminals is unknown on this platform.
+func terminalWidth(f *os.File) int {
+	return 0
+}
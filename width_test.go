@@ -0,0 +1,55 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithMaxWidth(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/pkg/very/long/dir/main.go", 42),
+		tracerr.NewFrame("main.f", "/src/данные/файл.go", 7),
+	})
+	cases := []struct {
+		Width    int
+		Expected string
+	}{
+		{
+			Width:    0,
+			Expected: "some error\n/src/pkg/very/long/dir/main.go:42 main.read()\n/src/данные/файл.go:7 main.f()",
+		},
+		{
+			Width:    30,
+			Expected: "some error\n…ng/dir/main.go:42 main.read()\n/src/данные/файл.go:7 main.f()",
+		},
+		{
+			Width:    25,
+			Expected: "some error\n…r/main.go:42 main.read()\n…анные/файл.go:7 main.f()",
+		},
+		{
+			Width:    20,
+			Expected: "some error\n…main.go:42 ….read()\n…/файл.go:7 main.f()",
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.SetPrintOptions(tracerr.WithMaxWidth(c.Width))
+		output := tracerr.Sprint(err)
+		var buf bytes.Buffer
+		tracerr.Fprint(&buf, err)
+		tracerr.SetPrintOptions()
+		if output != c.Expected {
+			t.Errorf("case #%d: tracerr.Sprint(err) = %#v; want %#v", i, output, c.Expected)
+		}
+		if buf.String() != c.Expected+"\n" {
+			t.Errorf("case #%d: tracerr.Fprint(w, err) wrote %#v; want %#v", i, buf.String(), c.Expected+"\n")
+		}
+		if !utf8.ValidString(output) {
+			t.Errorf("case #%d: tracerr.Sprint(err) = %#v; want valid UTF-8", i, output)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package tracerr
+
+import (
+	"os"
+	"unsafe"
+)
+
+var procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+
+type consoleScreenBufferInfo struct {
+	size, cursorPosition   [2]int16
+	attributes             uint16
+	left, top, right, down int16
+	maximumWindowSize      [2]int16
+}
+
+// terminalWidth returns a number of columns of console f,
+// zero means it's unknown.
+func terminalWidth(f *os.File) int {
+	var info consoleScreenBufferInfo
+	r, _, _ := procGetConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0
+	}
+	return int(info.right-info.left) + 1
+}
@@ -0,0 +1,80 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWithFrames(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		tracerr.NewFrame("github.com/mycorp/middleware.Handle", "/src/middleware.go", 7),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+	}
+	base := errors.New("some error")
+	ctx := tracerr.ContextWithFields(context.Background(), map[string]interface{}{"user": 42})
+	err := tracerr.WrapContext(ctx, tracerr.CustomError(base, frames))
+
+	replaced := tracerr.WithFrames(err, frames[2:])
+	if !reflect.DeepEqual(replaced.StackTrace(), frames[2:]) {
+		t.Errorf("tracerr.WithFrames(err, frames).StackTrace() = %v; want %v", replaced.StackTrace(), frames[2:])
+	}
+	mapped := tracerr.MapFrames(err, func(frame tracerr.Frame) (tracerr.Frame, bool) {
+		if strings.Contains(frame.Func, "middleware") {
+			return frame, false
+		}
+		frame.Path = strings.TrimPrefix(frame.Path, "/src/")
+		return frame, true
+	})
+	expected := []string{"main.go:42 main.read()", "main.go:10 main.main()"}
+	var output []string
+	for _, frame := range tracerr.RawFrames(mapped) {
+		output = append(output, frame.String())
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("tracerr.MapFrames(err, fn) frames = %#v; want %#v", output, expected)
+	}
+	for i, e := range []tracerr.Error{replaced, mapped} {
+		if !errors.Is(e, base) || tracerr.Fields(e)["user"] != 42 {
+			t.Errorf("case #%d: error = %#v; want to keep wrapped error and fields", i, e)
+		}
+	}
+	if !reflect.DeepEqual(tracerr.RawFrames(err), frames) {
+		t.Errorf("tracerr.RawFrames(err) = %v; want original frames %v", tracerr.RawFrames(err), frames)
+	}
+
+	input := []tracerr.Frame{tracerr.NewFrame("main.main", "/src/main.go", 10)}
+	replaced = tracerr.WithFrames(err, input)
+	input[0].Line = 99
+	if tracerr.RawFrames(replaced)[0].Line != 10 {
+		t.Errorf("frames of tracerr.WithFrames(err, frames) are shared with frames")
+	}
+}
+
+func TestWithFramesCustom(t *testing.T) {
+	custom := &customTraced{tracerr.New("some error")}
+	frame := tracerr.NewFrame("main.main", "/src/main.go", 10)
+	cases := []tracerr.Error{
+		tracerr.WithFrames(custom, []tracerr.Frame{frame}),
+		tracerr.MapFrames(custom, func(tracerr.Frame) (tracerr.Frame, bool) {
+			return frame, true
+		}),
+	}
+	for i, err := range cases {
+		var target *customTraced
+		if !errors.As(err, &target) || target != custom {
+			t.Errorf("case #%d: errors.As(err, &target) = %#v; want %#v", i, target, custom)
+		}
+		if frames := tracerr.RawFrames(err); len(frames) == 0 || frames[0] != frame {
+			t.Errorf("case #%d: tracerr.RawFrames(err) = %v; want to start with %v", i, frames, frame)
+		}
+	}
+	if err := tracerr.WithFrames(nil, nil); err != nil {
+		t.Errorf("tracerr.WithFrames(nil, nil) = %#v; want nil", err)
+	}
+}
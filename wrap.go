@@ -0,0 +1,147 @@
+package tracerr
+
+import (
+	"io"
+	"io/fs"
+	"sync/atomic"
+)
+
+// Wrap2 adds stacktrace to err and passes v through,
+// so a function with two results can be wrapped in one expression:
+//
+//	return tracerr.Wrap2(db.Get(id))
+func Wrap2[T any](v T, err error) (T, error) {
+	if err == nil {
+		return v, nil
+	}
+	return v, Wrap(err)
+}
+
+// Wrap3 is the same as Wrap2, but for functions with three results.
+func Wrap3[T, U any](v1 T, v2 U, err error) (T, U, error) {
+	if err == nil {
+		return v1, v2, nil
+	}
+	return v1, v2, Wrap(err)
+}
+
+// WrapFunc0 returns f, which errors are wrapped by stack trace
+// of the caller of the returned function, e.g. of a library calling
+// the callback, so errors of callbacks get traces without editing them.
+// Control errors, see SetControlErrors, are returned as is.
+// See WrapFunc1 for an example.
+func WrapFunc0[R any](f func() (R, error)) func() (R, error) {
+	return func() (R, error) {
+		r, err := f()
+		if err == nil || isControlError(err) {
+			return r, err
+		}
+		return r, Wrap(err)
+	}
+}
+
+// WrapFunc1 is the same as WrapFunc0, but for functions with one argument:
+//
+//	decode := tracerr.WrapFunc1(func(b []byte) (*Config, error) {
+//		return parse(b)
+//	})
+func WrapFunc1[A, R any](f func(A) (R, error)) func(A) (R, error) {
+	return func(a A) (R, error) {
+		r, err := f(a)
+		if err == nil || isControlError(err) {
+			return r, err
+		}
+		return r, Wrap(err)
+	}
+}
+
+// WrapFunc2 is the same as WrapFunc0, but for functions with two arguments.
+func WrapFunc2[A, B, R any](f func(A, B) (R, error)) func(A, B) (R, error) {
+	return func(a A, b B) (R, error) {
+		r, err := f(a, b)
+		if err == nil || isControlError(err) {
+			return r, err
+		}
+		return r, Wrap(err)
+	}
+}
+
+// WrapErrFunc0 is the same as WrapFunc0, but for functions
+// returning only error.
+func WrapErrFunc0(f func() error) func() error {
+	return func() error {
+		err := f()
+		if err == nil || isControlError(err) {
+			return err
+		}
+		return Wrap(err)
+	}
+}
+
+// WrapErrFunc1 is the same as WrapErrFunc0, but for functions
+// with one argument, such as Scan of sql.Scanner.
+func WrapErrFunc1[A any](f func(A) error) func(A) error {
+	return func(a A) error {
+		err := f(a)
+		if err == nil || isControlError(err) {
+			return err
+		}
+		return Wrap(err)
+	}
+}
+
+// WrapErrFunc2 is the same as WrapErrFunc0, but for functions
+// with two arguments, such as HTTP handlers returning errors.
+func WrapErrFunc2[A, B any](f func(A, B) error) func(A, B) error {
+	return func(a A, b B) error {
+		err := f(a, b)
+		if err == nil || isControlError(err) {
+			return err
+		}
+		return Wrap(err)
+	}
+}
+
+// WrapErrFunc3 is the same as WrapErrFunc0, but for functions
+// with three arguments, such as filepath.WalkFunc:
+//
+//	err := filepath.Walk(root, tracerr.WrapErrFunc3(visit))
+func WrapErrFunc3[A, B, C any](f func(A, B, C) error) func(A, B, C) error {
+	return func(a A, b B, c C) error {
+		err := f(a, b, c)
+		if err == nil || isControlError(err) {
+			return err
+		}
+		return Wrap(err)
+	}
+}
+
+// controlErrors are set by SetControlErrors.
+var controlErrors atomic.Pointer[[]error]
+
+// defaultControlErrors are control errors used until SetControlErrors is called.
+var defaultControlErrors = []error{fs.SkipDir, fs.SkipAll, io.EOF}
+
+// SetControlErrors sets control errors, which WrapFunc and WrapErrFunc
+// decorators return as is, because libraries calling callbacks compare
+// them by identity rather than by errors.Is, e.g. filepath.Walk stops
+// walking a directory only if its callback returns fs.SkipDir itself.
+// They are fs.SkipDir, fs.SkipAll and io.EOF by default, pass them
+// along with others to keep them.
+func SetControlErrors(errs ...error) {
+	controlErrors.Store(&errs)
+}
+
+// isControlError returns true if err is one of control errors.
+func isControlError(err error) bool {
+	errs := defaultControlErrors
+	if p := controlErrors.Load(); p != nil {
+		errs = *p
+	}
+	for _, control := range errs {
+		if err == control {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package tracerr_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWrap2(t *testing.T) {
+	value, err := tracerr.Wrap2(42, nil)
+	if value != 42 || err != nil {
+		t.Errorf(
+			"tracerr.Wrap2(42, nil) = %#v, %#v; want %#v, %#v",
+			value, err, 42, nil,
+		)
+	}
+
+	value, err = tracerr.Wrap2(42, errors.New("wrap2 error"))
+	if value != 42 {
+		t.Errorf("value = %#v; want %#v", value, 42)
+	}
+	assertCallerFrame(t, "tracerr.Wrap2", err, "wrap2 error", "TestWrap2")
+}
+
+func TestWrap3(t *testing.T) {
+	value1, value2, err := tracerr.Wrap3(42, "foo", nil)
+	if value1 != 42 || value2 != "foo" || err != nil {
+		t.Errorf(
+			"tracerr.Wrap3(42, \"foo\", nil) = %#v, %#v, %#v; want %#v, %#v, %#v",
+			value1, value2, err, 42, "foo", nil,
+		)
+	}
+
+	value1, value2, err = tracerr.Wrap3(42, "foo", errors.New("wrap3 error"))
+	if value1 != 42 || value2 != "foo" {
+		t.Errorf(
+			"values = %#v, %#v; want %#v, %#v",
+			value1, value2, 42, "foo",
+		)
+	}
+	assertCallerFrame(t, "tracerr.Wrap3", err, "wrap3 error", "TestWrap3")
+}
+
+// callCallback calls callback the way a library would.
+func callCallback(callback func(string) (int, error)) (int, error) {
+	return callback("input")
+}
+
+func TestWrapFunc(t *testing.T) {
+	fail := errors.New("callback error")
+	wrapped := tracerr.WrapFunc1(func(s string) (int, error) {
+		if s == "ok" {
+			return len(s), nil
+		}
+		return 42, fail
+	})
+	if value, err := wrapped("ok"); value != 2 || err != nil {
+		t.Errorf("wrapped(\"ok\") = %#v, %#v; want %#v, %#v", value, err, 2, nil)
+	}
+	value, err := callCallback(wrapped)
+	if value != 42 || !errors.Is(err, fail) {
+		t.Errorf("callCallback(wrapped) = %#v, %v; want %#v, %v", value, err, 42, fail)
+	}
+	assertCallerFrame(t, "tracerr.WrapFunc1", err, "callback error", "callCallback")
+
+	cases := []struct {
+		Name string
+		Call func() error
+	}{
+		{"tracerr.WrapFunc0", func() error {
+			_, err := tracerr.WrapFunc0(func() (int, error) { return 0, fail })()
+			return err
+		}},
+		{"tracerr.WrapFunc2", func() error {
+			_, err := tracerr.WrapFunc2(func(int, int) (int, error) { return 0, fail })(1, 2)
+			return err
+		}},
+		{"tracerr.WrapErrFunc0", func() error {
+			return tracerr.WrapErrFunc0(func() error { return fail })()
+		}},
+		{"tracerr.WrapErrFunc1", func() error {
+			return tracerr.WrapErrFunc1(func(int) error { return fail })(1)
+		}},
+		{"tracerr.WrapErrFunc2", func() error {
+			return tracerr.WrapErrFunc2(func(int, int) error { return fail })(1, 2)
+		}},
+		{"tracerr.WrapErrFunc3", func() error {
+			return tracerr.WrapErrFunc3(func(int, int, int) error { return fail })(1, 2, 3)
+		}},
+	}
+	for _, c := range cases {
+		err := c.Call()
+		if !errors.Is(err, fail) || len(tracerr.StackTrace(err)) == 0 {
+			t.Errorf("%s: err = %#v; want traced callback error", c.Name, err)
+		}
+	}
+	if err := tracerr.WrapErrFunc1(func(int) error { return nil })(1); err != nil {
+		t.Errorf("tracerr.WrapErrFunc1(ok)(1) = %#v; want nil", err)
+	}
+}
+
+func TestWrapFuncControlErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"skip/a.txt": &fstest.MapFile{},
+		"keep/b.txt": &fstest.MapFile{},
+	}
+	var visited []string
+	err := fs.WalkDir(fsys, ".", tracerr.WrapErrFunc3(func(path string, d fs.DirEntry, err error) error {
+		if path == "skip" {
+			return fs.SkipDir
+		}
+		visited = append(visited, path)
+		return err
+	}))
+	expected := []string{".", "keep", "keep/b.txt"}
+	if err != nil || !reflect.DeepEqual(visited, expected) {
+		t.Errorf("fs.WalkDir(fsys, \".\", fn) = %#v, visited %#v; want nil, %#v", err, visited, expected)
+	}
+
+	read := tracerr.WrapFunc1(func(err error) (int, error) { return 0, err })
+	if _, err := read(io.EOF); err != io.EOF {
+		t.Errorf("read(io.EOF) = %#v; want io.EOF itself", err)
+	}
+
+	stop := errors.New("stop")
+	tracerr.SetControlErrors(stop)
+	defer tracerr.SetControlErrors(fs.SkipDir, fs.SkipAll, io.EOF)
+	if _, err := read(stop); err != stop {
+		t.Errorf("read(stop) = %#v; want stop itself", err)
+	}
+	if _, err := read(io.EOF); err == io.EOF || !errors.Is(err, io.EOF) {
+		t.Errorf("read(io.EOF) = %#v; want traced io.EOF", err)
+	}
+}
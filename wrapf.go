@@ -0,0 +1,36 @@
+package tracerr
+
+import "fmt"
+
+// contextError is an error with a message prepended by Wrapf.
+type contextError struct {
+	message string
+	err     error
+}
+
+func (e *contextError) Error() string {
+	return e.message + messageJoin() + rawMessage(e.err)
+}
+
+func (e *contextError) Unwrap() error {
+	return e.err
+}
+
+// Wrapf prepends message formatted by fmt.Sprintf to err, such as
+// "opening config.yaml: no such file", and adds stacktrace to it,
+// unless err already has one, which is reused then, the same as by Wrap.
+// Format must not contain %w, err is always wrapped, so errors.Is
+// and errors.As see through the result. It returns nil if err is nil.
+//
+// It replaces Wrap(fmt.Errorf("...: %w", err)), which captures
+// a new stack trace even if err already has one.
+func Wrapf(err error, format string, args ...interface{}) Error {
+	return callerTracerr().Wrapf(err, format, args...)
+}
+
+func (t *tracerr) Wrapf(err error, format string, args ...interface{}) Error {
+	if isNilError(err) {
+		return nil
+	}
+	return t.wrap(&contextError{message: fmt.Sprintf(format, args...), err: err}, 0, false)
+}
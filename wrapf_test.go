@@ -0,0 +1,39 @@
+package tracerr_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestWrapf(t *testing.T) {
+	err := tracerr.Wrapf(fs.ErrNotExist, "opening %s", "config.yaml")
+	if err.Error() != "opening config.yaml: file does not exist" {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), "opening config.yaml: file does not exist")
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("errors.Is(err, fs.ErrNotExist) = false; want true")
+	}
+	frames := err.StackTrace()
+	if len(frames) == 0 || frames[0].Func != "github.com/kadaan/tracerr_test.TestWrapf" {
+		t.Errorf("err.StackTrace() = %#v; want the test first", frames)
+	}
+
+	traced := tracerr.CustomError(errors.New("some error"), diffFrames("read"))
+	wrapped := tracerr.Wrapf(traced, "loading %d items", 3)
+	if wrapped.Error() != "loading 3 items: some error" {
+		t.Errorf("wrapped.Error() = %#v; want %#v", wrapped.Error(), "loading 3 items: some error")
+	}
+	if frames := wrapped.StackTrace(); len(frames) != 1 || frames[0] != diffFrames("read")[0] {
+		t.Errorf("wrapped.StackTrace() = %#v; want frames of the wrapped error", frames)
+	}
+	if !errors.Is(wrapped, traced) {
+		t.Errorf("errors.Is(wrapped, traced) = false; want true")
+	}
+
+	if err := tracerr.Wrapf(nil, "opening %s", "config.yaml"); err != nil {
+		t.Errorf("tracerr.Wrapf(nil) = %#v; want nil", err)
+	}
+}
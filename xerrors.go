@@ -0,0 +1,31 @@
+package tracerr
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// FormatError prints error message and, in detail mode, stack trace
+// by xerrors.Formatter protocol, so tools and loggers supporting it
+// print frames in the detail section.
+func (e *errorData) FormatError(p xerrors.Printer) error {
+	var next error
+	if f, ok := e.Unwrap().(xerrors.Formatter); ok && !IsNil(f) {
+		next = f.FormatError(p)
+	} else {
+		p.Print(e.Error())
+	}
+	if p.Detail() {
+		for _, frame := range e.RawFrames() {
+			p.Printf("%s\n    %s:%d\n", frame.Func, frame.Path, frame.Line)
+		}
+	}
+	return next
+}
+
+// Format formats error by xerrors.FormatError,
+// "%+v" prints stack trace after the message.
+func (e *errorData) Format(s fmt.State, verb rune) {
+	xerrors.FormatError(e, s, verb)
+}
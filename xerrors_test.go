@@ -0,0 +1,72 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"golang.org/x/xerrors"
+
+	"github.com/kadaan/tracerr"
+)
+
+// detailError prints detail by xerrors.Formatter protocol.
+type detailError struct{}
+
+func (e *detailError) Error() string {
+	return "detail error"
+}
+
+func (e *detailError) FormatError(p xerrors.Printer) error {
+	p.Print("detail error")
+	if p.Detail() {
+		p.Print("some detail\n")
+	}
+	return nil
+}
+
+func TestFormatError(t *testing.T) {
+	frames := diffFrames("read", "main")
+	cases := []struct {
+		Err      error
+		Format   string
+		Expected string
+	}{
+		{
+			Err:      tracerr.CustomError(errors.New("some error"), frames),
+			Format:   "%v",
+			Expected: "some error",
+		},
+		{
+			Err:    tracerr.CustomError(errors.New("some error"), frames),
+			Format: "%+v",
+			Expected: "some error:\n" +
+				"    main.read\n" +
+				"        /src/main.go:10\n" +
+				"    main.main\n" +
+				"        /src/main.go:20",
+		},
+		{
+			Err:    tracerr.CustomError(&detailError{}, frames),
+			Format: "%+v",
+			Expected: "detail error:\n" +
+				"    some detail\n" +
+				"    main.read\n" +
+				"        /src/main.go:10\n" +
+				"    main.main\n" +
+				"        /src/main.go:20",
+		},
+		{
+			Err:      fmt.Errorf("context: %w", tracerr.CustomError(errors.New("some error"), frames)),
+			Format:   "%v",
+			Expected: "context: some error",
+		},
+	}
+
+	for i, c := range cases {
+		output := fmt.Sprintf(c.Format, c.Err)
+		if output != c.Expected {
+			t.Errorf("case #%d: fmt.Sprintf(%#v, err) = %#v; want %#v", i, c.Format, output, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SprintYAML returns error tree of err, see Tree, formatted as YAML,
+// which is suitable for incident tooling and runbooks ingesting YAML.
+// Keys are the same as of Tree encoded by encoding/json,
+// so both forms are interchangeable.
+//
+// Strings are double-quoted and values of fields are rendered as JSON,
+// which is valid YAML as well. It returns an empty string if err is nil.
+func SprintYAML(err error, options ...PrintOption) string {
+	node := Tree(err, options...)
+	if node == nil {
+		return ""
+	}
+	var b strings.Builder
+	writeYAMLNode(&b, node, "")
+	return b.String()
+}
+
+// writeYAMLNode writes node as a YAML mapping, the first key
+// is written at the current position and the others after indent.
+func writeYAMLNode(b *strings.Builder, node *TreeNode, indent string) {
+	prefix := ""
+	key := func(name string) {
+		b.WriteString(prefix + name + ":")
+		prefix = indent
+	}
+	key("message")
+	b.WriteString(" " + yamlValue(node.Message) + "\n")
+	if node.Code != "" {
+		key("code")
+		b.WriteString(" " + yamlValue(node.Code) + "\n")
+	}
+	if len(node.Frames) > 0 {
+		key("frames")
+		b.WriteString("\n")
+		for _, frame := range node.Frames {
+			b.WriteString(indent + "  - " + yamlValue(frame) + "\n")
+		}
+	}
+	if node.FrameCount != 0 {
+		key("frameCount")
+		fmt.Fprintf(b, " %d\n", node.FrameCount)
+	}
+	if len(node.Fields) > 0 {
+		key("fields")
+		b.WriteString("\n")
+		names := make([]string, 0, len(node.Fields))
+		for name := range node.Fields {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			b.WriteString(indent + "  " + yamlValue(name) + ": " + yamlValue(node.Fields[name]) + "\n")
+		}
+	}
+	if node.TraceID != "" {
+		key("traceId")
+		b.WriteString(" " + yamlValue(node.TraceID) + "\n")
+	}
+	if node.SpanID != "" {
+		key("spanId")
+		b.WriteString(" " + yamlValue(node.SpanID) + "\n")
+	}
+	if len(node.Children) > 0 {
+		key("children")
+		b.WriteString("\n")
+		for _, child := range node.Children {
+			b.WriteString(indent + "  - ")
+			writeYAMLNode(b, child, indent+"    ")
+		}
+	}
+}
+
+// yamlValue returns v encoded as JSON, which is a valid YAML flow value,
+// or its text double-quoted if it can't be encoded.
+func yamlValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return string(b)
+}
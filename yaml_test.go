@@ -0,0 +1,67 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kadaan/tracerr"
+)
+
+func TestSprintYAML(t *testing.T) {
+	frames := []tracerr.Frame{
+		tracerr.NewFrame("main.read", "/src/main.go", 42),
+		tracerr.NewFrame("main.main", "/src/main.go", 10),
+	}
+	cases := []struct {
+		Error    error
+		Expected string
+	}{
+		{
+			Error:    nil,
+			Expected: "",
+		},
+		{
+			Error:    errors.New("plain"),
+			Expected: "message: \"plain\"\n",
+		},
+		{
+			Error: errors.Join(
+				tracerr.CustomError(errors.New("first"), frames),
+				fmt.Errorf("second: %w", tracerr.CustomError(&codeError{code: "E42"}, frames[1:])),
+			),
+			Expected: "message: \"first\\nsecond: code E42\"\n" +
+				"children:\n" +
+				"  - message: \"first\"\n" +
+				"    frames:\n" +
+				"      - \"/src/main.go:42 main.read()\"\n" +
+				"      - \"/src/main.go:10 main.main()\"\n" +
+				"    frameCount: 2\n" +
+				"  - message: \"second: code E42\"\n" +
+				"    children:\n" +
+				"      - message: \"code E42\"\n" +
+				"        code: \"E42\"\n" +
+				"        frames:\n" +
+				"          - \"/src/main.go:10 main.main()\"\n" +
+				"        frameCount: 1\n",
+		},
+		{
+			Error: tracerr.WrapFields(tracerr.CustomError(errors.New("failed"), frames[1:]), map[string]interface{}{
+				"user":  42,
+				"query": "a: b",
+			}),
+			Expected: "message: \"failed\"\n" +
+				"frames:\n" +
+				"  - \"/src/main.go:10 main.main()\"\n" +
+				"frameCount: 1\n" +
+				"fields:\n" +
+				"  \"query\": \"a: b\"\n" +
+				"  \"user\": 42\n",
+		},
+	}
+	for i, c := range cases {
+		if output := tracerr.SprintYAML(c.Error); output != c.Expected {
+			t.Errorf("case #%d: tracerr.SprintYAML(err) = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}